@@ -0,0 +1,261 @@
+package http_request_instant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditRecord adalah satu jejak audit untuk satu percobaan request keluar.
+type AuditRecord struct {
+	Time       time.Time         // Waktu percobaan dimulai
+	Principal  string            // Pemanggil, dari ctx
+	Method     string            // HTTP method
+	URL        string            // URL setelah penyamaran
+	Host       string            // Host tujuan
+	StatusCode int               // Status code, 0 jika gagal tanpa response
+	Duration   time.Duration     // Lama percobaan
+	Attempt    int               // Percobaan ke-n, mulai dari 1
+	RequestID  string            // Request ID, jika SetRequestID aktif
+	Headers    map[string]string // Header request yang dipilih AuditOptions.Headers
+	Error      string            // Error, jika percobaan gagal
+}
+
+// AuditSink adalah tujuan jejak audit, mis. file, database, atau SIEM.
+type AuditSink interface {
+	Audit(ctx context.Context, record AuditRecord) error
+}
+
+// AuditSinkFunc mengubah fungsi biasa menjadi AuditSink.
+type AuditSinkFunc func(ctx context.Context, record AuditRecord) error
+
+// Audit mengimplementasikan AuditSink.
+func (f AuditSinkFunc) Audit(ctx context.Context, record AuditRecord) error {
+	return f(ctx, record)
+}
+
+// Field AuditRedactRule selain "header.<Name>" dan "query.<name>".
+const (
+	AuditFieldURL       = "url"
+	AuditFieldPrincipal = "principal"
+	AuditFieldError     = "error"
+)
+
+// AuditRedactRule menyamarkan satu field AuditRecord. Field bisa
+// AuditFieldURL, AuditFieldPrincipal, AuditFieldError, "header.<Name>",
+// atau "query.<name>". Jika Pattern diisi, hanya bagian yang cocok yang
+// disamarkan, mis. nomor kartu di path URL.
+type AuditRedactRule struct {
+	Field   string
+	Pattern *regexp.Regexp
+}
+
+// AuditOptions menyimpan konfigurasi audit log.
+type AuditOptions struct {
+	Sink AuditSink
+
+	// Optional: menentukan pemanggil dari ctx, default PrincipalFromContext.
+	Principal func(ctx context.Context) string
+
+	// Header request yang ikut dicatat. Header rahasia tetap disamarkan
+	// memakai aturan debug client.
+	Headers []string
+
+	// Aturan penyamaran tambahan, dijalankan setelah aturan debug client.
+	Redact []AuditRedactRule
+
+	// Gagalkan Request jika Sink mengembalikan error. Default false: error
+	// sink hanya dicatat ke Logger supaya traffic tidak terganggu.
+	FailClosed bool
+}
+
+type principalKey struct{}
+
+// ContextWithPrincipal menyimpan identitas pemanggil (user, service) di ctx untuk audit.
+func ContextWithPrincipal(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext mengambil identitas pemanggil dari ctx.
+func PrincipalFromContext(ctx context.Context) string {
+	principal, _ := ctx.Value(principalKey{}).(string)
+	return principal
+}
+
+// EnableAudit mencatat setiap percobaan request, berhasil maupun gagal, ke
+// options.Sink.
+func (c *HttpRequest) EnableAudit(options AuditOptions) {
+	if options.Principal == nil {
+		options.Principal = PrincipalFromContext
+	}
+	a := &auditor{client: c, options: options}
+	c.OnAfterResponse(func(ctx context.Context, req *http.Request, resp *ApiResponse, info AttemptInfo) error {
+		return a.record(ctx, req, resp, info, nil)
+	})
+	c.OnError(func(ctx context.Context, req *http.Request, err error, info AttemptInfo) {
+		_ = a.record(ctx, req, nil, info, err)
+	})
+}
+
+type auditor struct {
+	client  *HttpRequest
+	options AuditOptions
+
+	mu   sync.Mutex
+	last *http.Request // Request terakhir, supaya error hook after-response tidak tercatat dua kali
+}
+
+func (a *auditor) record(ctx context.Context, req *http.Request, resp *ApiResponse, info AttemptInfo, err error) error {
+	a.mu.Lock()
+	duplicate := resp == nil && a.last == req
+	a.last = req
+	a.mu.Unlock()
+	if duplicate {
+		return nil
+	}
+
+	u := *req.URL
+	query := u.Query()
+	for _, rule := range a.options.Redact {
+		if name, ok := strings.CutPrefix(rule.Field, "query."); ok {
+			for key, values := range query {
+				if strings.EqualFold(key, name) {
+					query[key] = redactAuditValues(values, rule.Pattern)
+					u.RawQuery = query.Encode()
+				}
+			}
+		}
+	}
+
+	record := AuditRecord{
+		Time:      info.Start,
+		Principal: a.options.Principal(ctx),
+		Method:    req.Method,
+		URL:       a.client.redactURL(&u, a.client.requestApiKey(req)),
+		Host:      req.URL.Host,
+		Duration:  info.Duration,
+		Attempt:   info.Attempt,
+	}
+	if resp != nil {
+		record.StatusCode = resp.StatusCode
+		record.RequestID = resp.RequestID
+	} else if id, ok := RequestIDFromContext(ctx); ok {
+		record.RequestID = id
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	if len(a.options.Headers) > 0 {
+		record.Headers = make(map[string]string)
+		for _, name := range a.options.Headers {
+			if values := req.Header.Values(name); len(values) > 0 {
+				record.Headers[http.CanonicalHeaderKey(name)] = strings.Join(a.client.redactHeader(name, values, a.client.requestApiKey(req)), ", ")
+			}
+		}
+	}
+	a.redact(&record)
+
+	if err := a.options.Sink.Audit(ctx, record); err != nil {
+		if a.options.FailClosed {
+			return fmt.Errorf("error write audit record: %w", err)
+		}
+		a.client.logger().Errorf("audit: error write record for %s %s: %v", record.Method, record.URL, err)
+	}
+	return nil
+}
+
+// redact menjalankan AuditRedactRule untuk field selain query.
+func (a *auditor) redact(record *AuditRecord) {
+	for _, rule := range a.options.Redact {
+		switch {
+		case rule.Field == AuditFieldURL:
+			record.URL = redactAuditValue(record.URL, rule.Pattern)
+		case rule.Field == AuditFieldPrincipal && record.Principal != "":
+			record.Principal = redactAuditValue(record.Principal, rule.Pattern)
+		case rule.Field == AuditFieldError && record.Error != "":
+			record.Error = redactAuditValue(record.Error, rule.Pattern)
+		case strings.HasPrefix(rule.Field, "header."):
+			name := http.CanonicalHeaderKey(strings.TrimPrefix(rule.Field, "header."))
+			if v, ok := record.Headers[name]; ok {
+				record.Headers[name] = redactAuditValue(v, rule.Pattern)
+			}
+		}
+	}
+}
+
+func redactAuditValue(v string, pattern *regexp.Regexp) string {
+	if pattern == nil {
+		return redactedValue
+	}
+	return pattern.ReplaceAllString(v, redactedValue)
+}
+
+func redactAuditValues(values []string, pattern *regexp.Regexp) []string {
+	redacted := make([]string, len(values))
+	for i, v := range values {
+		redacted[i] = redactAuditValue(v, pattern)
+	}
+	return redacted
+}
+
+// NewJSONAuditSink membuat AuditSink yang menulis satu record JSON per baris
+// ke w (default os.Stdout), dengan duration dalam milidetik.
+func NewJSONAuditSink(w io.Writer) AuditSink {
+	return &jsonAuditSink{w: w}
+}
+
+// jsonAuditRecord adalah bentuk JSON dari AuditRecord.
+type jsonAuditRecord struct {
+	Time       string            `json:"time"`
+	Principal  string            `json:"principal,omitempty"`
+	Method     string            `json:"method"`
+	URL        string            `json:"url"`
+	Host       string            `json:"host"`
+	Status     int               `json:"status,omitempty"`
+	DurationMs float64           `json:"duration_ms"`
+	Attempt    int               `json:"attempt"`
+	RequestID  string            `json:"request_id,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Error      string            `json:"error,omitempty"`
+}
+
+type jsonAuditSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *jsonAuditSink) Audit(ctx context.Context, record AuditRecord) error {
+	line, err := json.Marshal(jsonAuditRecord{
+		Time:       record.Time.UTC().Format(time.RFC3339Nano),
+		Principal:  record.Principal,
+		Method:     record.Method,
+		URL:        record.URL,
+		Host:       record.Host,
+		Status:     record.StatusCode,
+		DurationMs: durationMs(record.Duration),
+		Attempt:    record.Attempt,
+		RequestID:  record.RequestID,
+		Headers:    record.Headers,
+		Error:      record.Error,
+	})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	w := s.w
+	if w == nil {
+		w = os.Stdout
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = w.Write(line)
+	return err
+}
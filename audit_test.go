@@ -0,0 +1,91 @@
+package http_request_instant
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestAuditRecordsCallsWithRedaction(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer ts.Close()
+
+	var records []AuditRecord
+	client := NewHttpRequest()
+	client.EnableAudit(AuditOptions{
+		Sink: AuditSinkFunc(func(ctx context.Context, record AuditRecord) error {
+			records = append(records, record)
+			return nil
+		}),
+		Headers: []string{"Authorization", "X-Merchant-Id"},
+		Redact: []AuditRedactRule{
+			{Field: AuditFieldURL, Pattern: regexp.MustCompile(`\d{16}`)},
+			{Field: "query.cvv"},
+			{Field: "header.X-Merchant-Id", Pattern: regexp.MustCompile(`\d{4}$`)},
+		},
+	})
+
+	ctx := ContextWithPrincipal(context.TODO(), "svc-checkout")
+	_, err := client.Request(ctx, RequestOptions{
+		Method:      "POST",
+		URL:         ts.URL + "/cards/4111111111111111/charge?cvv=123&amount=10",
+		BearerToken: "secret",
+		Headers:     map[string]string{"X-Merchant-Id": "M-00421234"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(records) != 1 {
+		t.Fatalf("expected 1 audit record, got %d", len(records))
+	}
+	r := records[0]
+	if r.Principal != "svc-checkout" || r.Method != "POST" || r.StatusCode != 201 || r.Attempt != 1 || r.Duration <= 0 {
+		t.Errorf("unexpected audit record: %+v", r)
+	}
+	if strings.Contains(r.URL, "4111111111111111") || strings.Contains(r.URL, "123") || !strings.Contains(r.URL, "amount=10") {
+		t.Errorf("expected card number and cvv redacted, got %s", r.URL)
+	}
+	if r.Headers["Authorization"] != "Bearer [REDACTED]" || r.Headers["X-Merchant-Id"] != "M-0042[REDACTED]" {
+		t.Errorf("unexpected audit headers: %v", r.Headers)
+	}
+}
+
+func TestAuditSinkErrors(t *testing.T) {
+	client := NewHttpRequest()
+	client.Logger = &recordLogger{}
+	var buf bytes.Buffer
+	client.EnableAudit(AuditOptions{Sink: NewJSONAuditSink(&buf)})
+
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: "http://127.0.0.1:1/"}); err == nil {
+		t.Fatalf("expected connection error")
+	}
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("invalid audit JSON %q: %v", buf.String(), err)
+	}
+	if record["error"] == nil || record["status"] != nil || record["duration_ms"] == nil {
+		t.Errorf("unexpected failed-call audit record: %v", record)
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+	failing := NewHttpRequest()
+	failing.EnableAudit(AuditOptions{
+		Sink: AuditSinkFunc(func(ctx context.Context, record AuditRecord) error {
+			return errors.New("sink down")
+		}),
+		FailClosed: true,
+	})
+	if _, err := failing.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err == nil || !strings.Contains(err.Error(), "sink down") {
+		t.Errorf("expected fail-closed audit error, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package http_request_instant
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// TokenProvider menyediakan access token untuk header "Authorization: Bearer".
+type TokenProvider interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenProviderFunc adalah adapter supaya fungsi biasa bisa dipakai sebagai TokenProvider.
+type TokenProviderFunc func(ctx context.Context) (string, error)
+
+// Token memanggil f(ctx).
+func (f TokenProviderFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// StaticToken adalah TokenProvider yang selalu mengembalikan token yang sama.
+type StaticToken string
+
+// Token mengembalikan token statis.
+func (t StaticToken) Token(ctx context.Context) (string, error) {
+	return string(t), nil
+}
+
+// applyBearerToken memasang header Authorization: Bearer. RequestOptions.BearerToken
+// selalu dipakai jika diisi; TokenProvider client hanya dipakai jika request
+// belum membawa kredensial lain (header Authorization atau BasicAuth).
+func (c *HttpRequest) applyBearerToken(ctx context.Context, req *http.Request, options RequestOptions) error {
+	if options.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+options.BearerToken)
+		return nil
+	}
+	if !c.usesTokenProvider(options) || req.Header.Get("Authorization") != "" {
+		return nil
+	}
+
+	token, err := c.TokenProvider.Token(ctx)
+	if err != nil {
+		return fmt.Errorf("error get bearer token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// usesTokenProvider mengecek apakah request akan memakai token dari TokenProvider client.
+func (c *HttpRequest) usesTokenProvider(options RequestOptions) bool {
+	if c.TokenProvider == nil || options.BearerToken != "" || options.BasicAuth != nil || c.authProvider(options) != nil {
+		return false
+	}
+	for key := range options.Headers {
+		if http.CanonicalHeaderKey(key) == "Authorization" {
+			return false
+		}
+	}
+	return true
+}
+
+// ApiKeyLocation menentukan di mana API key dikirim.
+type ApiKeyLocation int
+
+const (
+	// ApiKeyInHeader mengirim API key sebagai header (default X-Api-Key).
+	ApiKeyInHeader ApiKeyLocation = iota
+	// ApiKeyInQuery mengirim API key sebagai query param (default api_key).
+	ApiKeyInQuery
+)
+
+// ApiKeyAuth menyimpan informasi autentikasi API key.
+type ApiKeyAuth struct {
+	Name  string         // Nama header/query param, default X-Api-Key atau api_key
+	Value string         // Nilai API key
+	In    ApiKeyLocation // Lokasi API key, default header
+}
+
+// apply memasang API key ke request.
+func (a *ApiKeyAuth) apply(req *http.Request) {
+	name := a.Name
+	switch a.In {
+	case ApiKeyInQuery:
+		if name == "" {
+			name = "api_key"
+		}
+		req.URL.RawQuery = setQueryParam(req.URL.RawQuery, name, a.Value)
+	default:
+		if name == "" {
+			name = "X-Api-Key"
+		}
+		req.Header.Set(name, a.Value)
+	}
+}
+
+// setQueryParam memasang name=value di akhir rawQuery dan hanya membuang
+// param lain dengan nama yang sama. Urutan dan encoding param lain tidak
+// diubah, karena sebagian API (mis. signature) bergantung padanya.
+func setQueryParam(rawQuery, name, value string) string {
+	var parts []string
+	if rawQuery != "" {
+		for _, part := range strings.Split(rawQuery, "&") {
+			key, _, _ := strings.Cut(part, "=")
+			if unescaped, err := url.QueryUnescape(key); err == nil {
+				key = unescaped
+			}
+			if key != name {
+				parts = append(parts, part)
+			}
+		}
+	}
+	parts = append(parts, url.QueryEscape(name)+"="+url.QueryEscape(value))
+	return strings.Join(parts, "&")
+}
@@ -0,0 +1,118 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+)
+
+// AuthProvider memasang kredensial ke request sebelum dikirim. BasicAuth,
+// Bearer, API key, OAuth2, SigV4, maupun skema kustom bisa dipasang lewat
+// satu mekanisme ini di HttpRequest.Auth atau RequestOptions.Auth.
+type AuthProvider interface {
+	Apply(ctx context.Context, req *http.Request) error
+}
+
+// UnauthorizedHandler opsional diimplementasikan AuthProvider yang bisa
+// memulihkan diri dari 401, mis. dengan me-refresh token. Jika retry
+// bernilai true, request diulang satu kali.
+type UnauthorizedHandler interface {
+	OnUnauthorized(ctx context.Context, resp *ApiResponse) (retry bool, err error)
+}
+
+// AuthProviderFunc adalah adapter fungsi biasa menjadi AuthProvider.
+type AuthProviderFunc func(ctx context.Context, req *http.Request) error
+
+// Apply mengimplementasikan AuthProvider.
+func (f AuthProviderFunc) Apply(ctx context.Context, req *http.Request) error {
+	return f(ctx, req)
+}
+
+// BasicAuthProvider membuat AuthProvider untuk Basic Auth.
+func BasicAuthProvider(username, password string) AuthProvider {
+	return AuthProviderFunc(func(ctx context.Context, req *http.Request) error {
+		req.SetBasicAuth(username, password)
+		return nil
+	})
+}
+
+// BearerAuth membuat AuthProvider untuk token Bearer statis.
+func BearerAuth(token string) AuthProvider {
+	return AuthProviderFunc(func(ctx context.Context, req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
+
+// Apply mengimplementasikan AuthProvider.
+func (a *ApiKeyAuth) Apply(ctx context.Context, req *http.Request) error {
+	a.apply(req)
+	return nil
+}
+
+// TokenAuth membuat AuthProvider Bearer dari TokenProvider (mis.
+// OAuth2TokenProvider). Jika provider juga TokenInvalidator, token dibuang
+// dan request diulang ketika server menolak token.
+func TokenAuth(provider TokenProvider) AuthProvider {
+	return &tokenAuth{provider: provider}
+}
+
+type tokenAuth struct {
+	provider TokenProvider
+}
+
+func (a *tokenAuth) Apply(ctx context.Context, req *http.Request) error {
+	token, err := a.provider.Token(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+func (a *tokenAuth) OnUnauthorized(ctx context.Context, resp *ApiResponse) (bool, error) {
+	invalidator, ok := a.provider.(TokenInvalidator)
+	if !ok || !isInvalidTokenChallenge(resp.Headers["Www-Authenticate"]) {
+		return false, nil
+	}
+	invalidator.InvalidateToken()
+	return true, nil
+}
+
+// SignerAuth membuat AuthProvider dari RequestSigner (mis. SigV4Signer atau
+// HMACSigner). Letakkan paling akhir di ChainAuth supaya semua header sudah terpasang.
+func SignerAuth(signer RequestSigner) AuthProvider {
+	return AuthProviderFunc(signer.SignRequest)
+}
+
+// ChainAuth menjalankan beberapa AuthProvider sesuai urutan, mis. API key
+// lalu SigV4. Saat 401, semua UnauthorizedHandler di dalamnya dipanggil.
+func ChainAuth(providers ...AuthProvider) AuthProvider {
+	return authChain(providers)
+}
+
+type authChain []AuthProvider
+
+func (c authChain) Apply(ctx context.Context, req *http.Request) error {
+	for _, provider := range c {
+		if err := provider.Apply(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c authChain) OnUnauthorized(ctx context.Context, resp *ApiResponse) (bool, error) {
+	retry := false
+	for _, provider := range c {
+		handler, ok := provider.(UnauthorizedHandler)
+		if !ok {
+			continue
+		}
+		ok, err := handler.OnUnauthorized(ctx, resp)
+		if err != nil {
+			return false, err
+		}
+		retry = retry || ok
+	}
+	return retry, nil
+}
@@ -0,0 +1,80 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// rotatingToken mengembalikan token baru setiap kali di-invalidate.
+type rotatingToken struct {
+	version atomic.Int32
+}
+
+func (r *rotatingToken) Token(ctx context.Context) (string, error) {
+	if r.version.Load() == 0 {
+		return "stale", nil
+	}
+	return "fresh", nil
+}
+
+func (r *rotatingToken) InvalidateToken() { r.version.Add(1) }
+
+func TestAuthProviderPerRequestOverride(t *testing.T) {
+	ts := newAuthEchoServer()
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.Auth = BearerAuth("client-token")
+	client.TokenProvider = StaticToken("ignored")
+
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(resp.Body) != "Bearer client-token" {
+		t.Errorf("unexpected Authorization: %q", string(resp.Body))
+	}
+
+	resp, err = client.Request(context.TODO(), RequestOptions{
+		Method: "GET",
+		URL:    ts.URL,
+		Auth:   BasicAuthProvider("user", "pass"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(resp.Body) != "Basic dXNlcjpwYXNz" {
+		t.Errorf("unexpected Authorization: %q", string(resp.Body))
+	}
+}
+
+func TestChainAuthRefreshOnUnauthorized(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("X-Api-Key") != "key-1" {
+			t.Errorf("missing api key")
+		}
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.Auth = ChainAuth(&ApiKeyAuth{Value: "key-1"}, TokenAuth(&rotatingToken{}))
+
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || calls != 2 {
+		t.Errorf("expected replay after refresh, got status %d after %d calls", resp.StatusCode, calls)
+	}
+}
@@ -0,0 +1,125 @@
+package http_request_instant
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newAuthEchoServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+}
+
+func TestBearerTokenOption(t *testing.T) {
+	ts := newAuthEchoServer()
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.TokenProvider = StaticToken("client-token")
+
+	resp, err := client.Request(context.TODO(), RequestOptions{
+		Method:      "GET",
+		URL:         ts.URL,
+		BearerToken: "request-token",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(resp.Body) != "Bearer request-token" {
+		t.Errorf("expected request token, got %s", string(resp.Body))
+	}
+}
+
+func TestTokenProvider(t *testing.T) {
+	ts := newAuthEchoServer()
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.TokenProvider = TokenProviderFunc(func(ctx context.Context) (string, error) {
+		return "provided", nil
+	})
+
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(resp.Body) != "Bearer provided" {
+		t.Errorf("expected provided token, got %s", string(resp.Body))
+	}
+
+	// BasicAuth per request tidak ditimpa TokenProvider
+	resp, err = client.Request(context.TODO(), RequestOptions{
+		Method:    "GET",
+		URL:       ts.URL,
+		BasicAuth: &BasicAuth{Username: "admin", Password: "secret"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(resp.Body) != "Basic YWRtaW46c2VjcmV0" {
+		t.Errorf("expected basic auth header, got %s", string(resp.Body))
+	}
+}
+
+func TestTokenProviderError(t *testing.T) {
+	client := NewHttpRequest()
+	client.TokenProvider = TokenProviderFunc(func(ctx context.Context) (string, error) {
+		return "", errors.New("boom")
+	})
+
+	_, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: "http://example.invalid"})
+	if err == nil {
+		t.Fatal("expected token provider error, got nil")
+	}
+}
+
+func TestApiKeyAuth(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("X-Api-Key") + "|" + r.URL.Query().Get("api_key") + "|" + r.URL.Query().Get("q")))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.ApiKey = &ApiKeyAuth{Value: "client-key"}
+
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL + "?q=go"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(resp.Body) != "client-key||go" {
+		t.Errorf("expected header api key, got %s", string(resp.Body))
+	}
+
+	resp, err = client.Request(context.TODO(), RequestOptions{
+		Method: "GET",
+		URL:    ts.URL + "?q=go",
+		ApiKey: &ApiKeyAuth{Value: "query-key", In: ApiKeyInQuery},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(resp.Body) != "|query-key|go" {
+		t.Errorf("expected query api key, got %s", string(resp.Body))
+	}
+}
+
+func TestApiKeyAuthQueryPreservesQuery(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.RawQuery))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.ApiKey = &ApiKeyAuth{Name: "api key", Value: "a&b", In: ApiKeyInQuery}
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL + "?z=1&sig=a%2Fb&api+key=old&a=x,y"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := string(resp.Body), "z=1&sig=a%2Fb&a=x,y&api+key=a%26b"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
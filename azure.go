@@ -0,0 +1,222 @@
+package http_request_instant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	azureIMDSEndpoint     = "http://169.254.169.254/metadata/identity/oauth2/token"
+	azureDefaultAuthority = "https://login.microsoftonline.com/"
+	azureAssertionTypeJWT = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+)
+
+// AzureIdentityConfig menyimpan konfigurasi managed identity Azure.
+type AzureIdentityConfig struct {
+	// Resource (audience) token, mis. "https://management.azure.com/"
+	// atau "api://my-app". Wajib diisi.
+	Resource string
+
+	// Optional: pilih user-assigned identity lewat salah satu ID berikut.
+	// Untuk workload identity, ClientID default dari env AZURE_CLIENT_ID.
+	ClientID   string
+	ObjectID   string
+	ResourceID string
+
+	// Optional: endpoint IMDS, default http://169.254.169.254/metadata/identity/oauth2/token.
+	// Diabaikan jika env IDENTITY_ENDPOINT (App Service/Functions) atau
+	// AZURE_FEDERATED_TOKEN_FILE (AKS workload identity) tersedia.
+	Endpoint string
+
+	// Token dianggap kadaluarsa lebih awal sebesar ExpiryDelta, default 5 menit.
+	ExpiryDelta time.Duration
+
+	// Optional: client untuk endpoint token, default NewHttpRequest().
+	Client *HttpRequest
+}
+
+// AzureManagedIdentityProvider adalah TokenProvider yang mengambil token dari
+// managed identity Azure (IMDS di VM/AKS, App Service, atau AKS workload identity)
+// dan menyimpannya di cache sampai mendekati kadaluarsa.
+type AzureManagedIdentityProvider struct {
+	config AzureIdentityConfig
+
+	mu          sync.Mutex
+	accessToken string
+	expiry      time.Time
+}
+
+// azureToken adalah response token managed identity. IMDS mengirim angka
+// sebagai string, sedangkan Microsoft Entra mengirim angka biasa.
+type azureToken struct {
+	AccessToken string      `json:"access_token"`
+	ExpiresIn   json.Number `json:"expires_in"`
+	ExpiresOn   json.Number `json:"expires_on"`
+}
+
+// NewAzureManagedIdentityProvider membuat AzureManagedIdentityProvider baru.
+func NewAzureManagedIdentityProvider(config AzureIdentityConfig) (*AzureManagedIdentityProvider, error) {
+	if config.Resource == "" {
+		return nil, errors.New("azure: Resource is required")
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = azureIMDSEndpoint
+	}
+	if config.ExpiryDelta <= 0 {
+		config.ExpiryDelta = 5 * time.Minute
+	}
+	if config.Client == nil {
+		config.Client = NewHttpRequest()
+	}
+	return &AzureManagedIdentityProvider{config: config}, nil
+}
+
+// Token mengembalikan access token yang masih berlaku, mengambil baru jika perlu.
+func (p *AzureManagedIdentityProvider) Token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.accessToken != "" && time.Now().Add(p.config.ExpiryDelta).Before(p.expiry) {
+		return p.accessToken, nil
+	}
+
+	var options RequestOptions
+	var err error
+	switch {
+	case os.Getenv("AZURE_FEDERATED_TOKEN_FILE") != "":
+		options, err = p.workloadIdentityRequest()
+	case os.Getenv("IDENTITY_ENDPOINT") != "" && os.Getenv("IDENTITY_HEADER") != "":
+		options = p.appServiceRequest()
+	default:
+		options = p.imdsRequest()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := p.config.Client.Request(ctx, options)
+	if err != nil {
+		return "", fmt.Errorf("azure: error request token: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		oauthErr := &OAuth2Error{StatusCode: resp.StatusCode}
+		if json.Unmarshal(resp.Body, oauthErr) != nil || oauthErr.Code == "" {
+			oauthErr.Code = "managed_identity_failed"
+		}
+		return "", oauthErr
+	}
+
+	var token azureToken
+	if err := json.Unmarshal(resp.Body, &token); err != nil {
+		return "", fmt.Errorf("azure: failed to unmarshal token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", errors.New("azure: token response has no access_token")
+	}
+
+	p.accessToken = token.AccessToken
+	p.expiry = token.expiry()
+	return p.accessToken, nil
+}
+
+// InvalidateToken membuang access token di cache.
+func (p *AzureManagedIdentityProvider) InvalidateToken() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.accessToken = ""
+	p.expiry = time.Time{}
+}
+
+func (p *AzureManagedIdentityProvider) imdsRequest() RequestOptions {
+	query := url.Values{}
+	query.Set("api-version", "2018-02-01")
+	query.Set("resource", p.config.Resource)
+	p.identityQuery(query, "msi_res_id")
+
+	return RequestOptions{
+		Method:  "GET",
+		URL:     p.config.Endpoint + "?" + query.Encode(),
+		Headers: map[string]string{"Metadata": "true"},
+		// IMDS adalah alamat link-local dan tidak boleh lewat proxy
+		Proxy: ProxyDirect,
+	}
+}
+
+func (p *AzureManagedIdentityProvider) appServiceRequest() RequestOptions {
+	query := url.Values{}
+	query.Set("api-version", "2019-08-01")
+	query.Set("resource", p.config.Resource)
+	p.identityQuery(query, "mi_res_id")
+
+	return RequestOptions{
+		Method:  "GET",
+		URL:     os.Getenv("IDENTITY_ENDPOINT") + "?" + query.Encode(),
+		Headers: map[string]string{"X-IDENTITY-HEADER": os.Getenv("IDENTITY_HEADER")},
+		Proxy:   ProxyDirect,
+	}
+}
+
+func (p *AzureManagedIdentityProvider) identityQuery(query url.Values, resourceIDParam string) {
+	switch {
+	case p.config.ClientID != "":
+		query.Set("client_id", p.config.ClientID)
+	case p.config.ObjectID != "":
+		query.Set("object_id", p.config.ObjectID)
+	case p.config.ResourceID != "":
+		query.Set(resourceIDParam, p.config.ResourceID)
+	}
+}
+
+// workloadIdentityRequest menukar token service account Kubernetes dengan
+// access token Microsoft Entra (client_credentials + client_assertion).
+func (p *AzureManagedIdentityProvider) workloadIdentityRequest() (RequestOptions, error) {
+	assertion, err := os.ReadFile(os.Getenv("AZURE_FEDERATED_TOKEN_FILE"))
+	if err != nil {
+		return RequestOptions{}, fmt.Errorf("azure: error read federated token: %w", err)
+	}
+	clientID := p.config.ClientID
+	if clientID == "" {
+		clientID = os.Getenv("AZURE_CLIENT_ID")
+	}
+	tenantID := os.Getenv("AZURE_TENANT_ID")
+	if clientID == "" || tenantID == "" {
+		return RequestOptions{}, errors.New("azure: AZURE_CLIENT_ID and AZURE_TENANT_ID are required for workload identity")
+	}
+	authority := os.Getenv("AZURE_AUTHORITY_HOST")
+	if authority == "" {
+		authority = azureDefaultAuthority
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", clientID)
+	form.Set("client_assertion_type", azureAssertionTypeJWT)
+	form.Set("client_assertion", strings.TrimSpace(string(assertion)))
+	form.Set("scope", strings.TrimRight(p.config.Resource, "/")+"/.default")
+
+	return RequestOptions{
+		Method:      "POST",
+		URL:         strings.TrimRight(authority, "/") + "/" + tenantID + "/oauth2/v2.0/token",
+		ContentType: "application/x-www-form-urlencoded",
+		Headers:     map[string]string{"Accept": "application/json"},
+		RequestBody: form.Encode(),
+	}, nil
+}
+
+func (t azureToken) expiry() time.Time {
+	if on, err := strconv.ParseInt(t.ExpiresOn.String(), 10, 64); err == nil && on > 0 {
+		return time.Unix(on, 0)
+	}
+	if in, err := strconv.ParseInt(t.ExpiresIn.String(), 10, 64); err == nil && in > 0 {
+		return time.Now().Add(time.Duration(in) * time.Second)
+	}
+	return time.Now().Add(10 * time.Minute)
+}
@@ -0,0 +1,95 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAzureIMDSTokenCached(t *testing.T) {
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", "")
+	t.Setenv("IDENTITY_ENDPOINT", "")
+
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("Metadata") != "true" {
+			t.Errorf("missing Metadata header")
+		}
+		q := r.URL.Query()
+		if q.Get("resource") != "https://vault.azure.net" || q.Get("client_id") != "uami-1" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		// IMDS mengirim angka sebagai string
+		_, _ = w.Write([]byte(`{"access_token":"imds-token","expires_in":"3599","token_type":"Bearer"}`))
+	}))
+	defer ts.Close()
+
+	provider, err := NewAzureManagedIdentityProvider(AzureIdentityConfig{
+		Resource: "https://vault.azure.net",
+		ClientID: "uami-1",
+		Endpoint: ts.URL,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		token, err := provider.Token(context.TODO())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if token != "imds-token" {
+			t.Errorf("unexpected token: %s", token)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected cached token, got %d calls", calls)
+	}
+
+	provider.InvalidateToken()
+	if _, err := provider.Token(context.TODO()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected refetch after invalidate, got %d calls", calls)
+	}
+}
+
+func TestAzureWorkloadIdentity(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tenant-1/oauth2/v2.0/token" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		_ = r.ParseForm()
+		if r.PostForm.Get("client_assertion") != "k8s-sa-token" || r.PostForm.Get("scope") != "api://my-app/.default" {
+			t.Errorf("unexpected form: %v", r.PostForm)
+		}
+		_, _ = w.Write([]byte(`{"access_token":"entra-token","expires_in":3600}`))
+	}))
+	defer ts.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("k8s-sa-token\n"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", tokenFile)
+	t.Setenv("AZURE_CLIENT_ID", "app-1")
+	t.Setenv("AZURE_TENANT_ID", "tenant-1")
+	t.Setenv("AZURE_AUTHORITY_HOST", ts.URL)
+
+	provider, err := NewAzureManagedIdentityProvider(AzureIdentityConfig{Resource: "api://my-app"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	token, err := provider.Token(context.TODO())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token != "entra-token" {
+		t.Errorf("unexpected token: %s", token)
+	}
+}
@@ -0,0 +1,164 @@
+package http_request_instant
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// BandwidthLimit membatasi kecepatan transfer body dalam byte per detik.
+// Nilai 0 berarti arah tersebut tidak dibatasi.
+type BandwidthLimit struct {
+	Upload   int64 // Body request
+	Download int64 // Body response
+}
+
+// SetBandwidthLimit membatasi total kecepatan upload/download semua request
+// client ini, mis. untuk job sinkronisasi background. Batas per request bisa
+// diatur lewat RequestOptions.Bandwidth; keduanya berlaku bersamaan. Atur
+// sebelum client dipakai secara konkuren.
+func (c *HttpRequest) SetBandwidthLimit(limit BandwidthLimit) {
+	c.bandwidth = &bandwidthLimiters{
+		upload:   newBandwidthLimiter(limit.Upload),
+		download: newBandwidthLimiter(limit.Download),
+	}
+}
+
+type bandwidthLimiters struct {
+	upload   *bandwidthLimiter
+	download *bandwidthLimiter
+}
+
+// bandwidthLimiter adalah token bucket dalam satuan byte. Bucket boleh
+// berutang: pembaca menunggu sampai utangnya lunas, sehingga chunk besar
+// tetap jalan tanpa melampaui rata-rata rate.
+type bandwidthLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // byte per detik
+	burst  float64
+	tokens float64
+	last   time.Time
+	chunk  int
+}
+
+// newBandwidthLimiter mengembalikan nil jika rate tidak dibatasi.
+func newBandwidthLimiter(rate int64) *bandwidthLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	// Chunk sekitar 100ms transfer supaya aliran data rata
+	chunk := min(max(int(rate/10), 512), 32<<10)
+	return &bandwidthLimiter{
+		rate:   float64(rate),
+		burst:  float64(chunk),
+		tokens: float64(chunk),
+		last:   time.Now(),
+		chunk:  chunk,
+	}
+}
+
+// wait mengambil n byte dari bucket dan menunggu jika bucket berutang.
+func (l *bandwidthLimiter) wait(ctx context.Context, n int) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens -= float64(n)
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		timer.Stop()
+		return ctx.Err()
+	}
+}
+
+// bandwidthLimits mengumpulkan limiter client dan limiter baru untuk
+// batas per request.
+func (c *HttpRequest) bandwidthLimits(options RequestOptions) (upload, download []*bandwidthLimiter) {
+	if c.bandwidth != nil {
+		upload = appendLimiter(upload, c.bandwidth.upload)
+		download = appendLimiter(download, c.bandwidth.download)
+	}
+	if limit := options.Bandwidth; limit != nil {
+		upload = appendLimiter(upload, newBandwidthLimiter(limit.Upload))
+		download = appendLimiter(download, newBandwidthLimiter(limit.Download))
+	}
+	return upload, download
+}
+
+func appendLimiter(limiters []*bandwidthLimiter, l *bandwidthLimiter) []*bandwidthLimiter {
+	if l == nil {
+		return limiters
+	}
+	return append(limiters, l)
+}
+
+// throttleRequestBody mengembalikan salinan req yang body-nya dibatasi,
+// termasuk body dari GetBody yang dipakai transport saat mengirim ulang.
+// req sendiri tidak diubah, sehingga request turunannya (mis. fallback
+// HTTP/1.1) tidak dibatasi dua kali.
+func throttleRequestBody(ctx context.Context, req *http.Request, limiters []*bandwidthLimiter) *http.Request {
+	if len(limiters) == 0 || req.Body == nil || req.Body == http.NoBody {
+		return req
+	}
+	throttled := req.WithContext(req.Context())
+	throttled.Body = newThrottledBody(ctx, req.Body, limiters)
+	if getBody := req.GetBody; getBody != nil {
+		throttled.GetBody = func() (io.ReadCloser, error) {
+			body, err := getBody()
+			if err != nil {
+				return nil, err
+			}
+			return newThrottledBody(ctx, body, limiters), nil
+		}
+	}
+	return throttled
+}
+
+// throttledBody membaca body per chunk dan menunggu giliran dari setiap
+// limiter. Dengan membaca lebih lambat, TCP window ikut menahan pengirim.
+type throttledBody struct {
+	io.ReadCloser
+	ctx      context.Context
+	limiters []*bandwidthLimiter
+	chunk    int
+}
+
+func newThrottledBody(ctx context.Context, body io.ReadCloser, limiters []*bandwidthLimiter) io.ReadCloser {
+	if len(limiters) == 0 {
+		return body
+	}
+	chunk := limiters[0].chunk
+	for _, l := range limiters[1:] {
+		chunk = min(chunk, l.chunk)
+	}
+	return &throttledBody{ReadCloser: body, ctx: ctx, limiters: limiters, chunk: chunk}
+}
+
+func (b *throttledBody) Read(p []byte) (int, error) {
+	if len(p) > b.chunk {
+		p = p[:b.chunk]
+	}
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		for _, l := range b.limiters {
+			if werr := l.wait(b.ctx, n); werr != nil {
+				return n, werr
+			}
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,96 @@
+package http_request_instant
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBandwidthLimit(t *testing.T) {
+	payload := bytes.Repeat([]byte("x"), 8000)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := io.Copy(io.Discard, r.Body)
+		if r.Method == "GET" {
+			w.Write(payload)
+			return
+		}
+		w.Write([]byte(strconv.FormatInt(n, 10)))
+	}))
+	defer ts.Close()
+	client := NewHttpRequest()
+
+	// 8000 byte pada 16000 B/s: sekitar 0,45 detik setelah burst awal
+	start := time.Now()
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL, Bandwidth: &BandwidthLimit{Download: 16000}})
+	if err != nil || len(resp.Body) != len(payload) {
+		t.Fatalf("unexpected response: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 350*time.Millisecond || elapsed > 2*time.Second {
+		t.Errorf("expected throttled download around 450ms, took %s", elapsed)
+	}
+
+	start = time.Now()
+	resp, err = client.Request(context.TODO(), RequestOptions{Method: "POST", URL: ts.URL, RequestBody: payload, Bandwidth: &BandwidthLimit{Upload: 16000}})
+	if err != nil || string(resp.Body) != "8000" {
+		t.Fatalf("unexpected response: %v, %v", resp, err)
+	}
+	if elapsed := time.Since(start); elapsed < 350*time.Millisecond || elapsed > 2*time.Second {
+		t.Errorf("expected throttled upload around 450ms, took %s", elapsed)
+	}
+
+	// Batas client dibagi oleh semua request yang berjalan bersamaan
+	client.SetBandwidthLimit(BandwidthLimit{Upload: 16000})
+	start = time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client.Request(context.TODO(), RequestOptions{Method: "POST", URL: ts.URL, RequestBody: payload[:4000]})
+		}()
+	}
+	wg.Wait()
+	if elapsed := time.Since(start); elapsed < 350*time.Millisecond {
+		t.Errorf("expected shared client limit, took %s", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+	defer cancel()
+	_, err = client.Request(ctx, RequestOptions{Method: "GET", URL: ts.URL, Bandwidth: &BandwidthLimit{Download: 1000}})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded while throttled, got %v", err)
+	}
+}
+
+func TestThrottleRequestBodyFallbackNotDoubleWrapped(t *testing.T) {
+	req, _ := http.NewRequest("POST", "https://api.test/upload", strings.NewReader("payload"))
+	limiters := []*bandwidthLimiter{newBandwidthLimiter(1 << 20)}
+
+	throttled := throttleRequestBody(context.TODO(), req, limiters)
+	if _, ok := throttled.Body.(*throttledBody); !ok {
+		t.Fatalf("expected throttled body, got %T", throttled.Body)
+	}
+	if _, ok := req.Body.(*throttledBody); ok {
+		t.Errorf("expected original request body untouched")
+	}
+
+	retry, ok := http1FallbackRequest(req, errors.New("http2: stream error"), "h2")
+	if !ok {
+		t.Fatalf("expected fallback request")
+	}
+	body, ok := throttleRequestBody(context.TODO(), retry, limiters).Body.(*throttledBody)
+	if !ok {
+		t.Fatalf("expected throttled fallback body")
+	}
+	if _, double := body.ReadCloser.(*throttledBody); double {
+		t.Errorf("expected fallback body throttled once")
+	}
+}
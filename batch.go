@@ -0,0 +1,64 @@
+package http_request_instant
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// BatchResult adalah hasil satu request dalam Batch.
+type BatchResult struct {
+	Response *ApiResponse
+	Err      error // Error dari Request; response 4xx/5xx tidak dianggap error
+}
+
+// Batch menjalankan requests secara paralel dengan maksimal concurrency
+// request sekaligus (<= 0 berarti semuanya sekaligus) dan mengembalikan hasil
+// sesuai urutan requests. Seperti errgroup, error pertama membatalkan ctx
+// request lain; request yang belum berjalan mendapat Err context.Canceled.
+// Error pertama juga dikembalikan sebagai nilai kedua.
+func (c *HttpRequest) Batch(ctx context.Context, requests []RequestOptions, concurrency int) ([]BatchResult, error) {
+	if concurrency <= 0 || concurrency > len(requests) {
+		concurrency = len(requests)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]BatchResult, len(requests))
+	var (
+		next     atomic.Int64
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	fail := func(i int, err error) {
+		results[i].Err = err
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= len(requests) {
+					return
+				}
+				if err := ctx.Err(); err != nil {
+					fail(i, err)
+					continue
+				}
+				resp, err := c.Request(ctx, requests[i])
+				results[i].Response = resp
+				if err != nil {
+					fail(i, err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return results, firstErr
+}
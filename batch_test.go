@@ -0,0 +1,83 @@
+package http_request_instant
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBatch(t *testing.T) {
+	var active, peak atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := active.Add(1)
+		defer active.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	paths := []string{"/a", "/b", "/missing", "/c", "/d", "/e"}
+	var requests []RequestOptions
+	for _, path := range paths {
+		requests = append(requests, RequestOptions{Method: "GET", URL: ts.URL + path})
+	}
+
+	results, err := client.Batch(context.TODO(), requests, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, result := range results {
+		if result.Err != nil || string(result.Response.Body) != paths[i] {
+			t.Errorf("result %d: expected %s, got %+v", i, paths[i], result)
+		}
+	}
+	if results[2].Response.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 kept as response, got %d", results[2].Response.StatusCode)
+	}
+	if peak.Load() != 2 {
+		t.Errorf("expected concurrency 2, got peak %d", peak.Load())
+	}
+}
+
+func TestBatchCancelsOnFirstError(t *testing.T) {
+	var hits atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	requests := []RequestOptions{
+		{Method: "GET", URL: "http://%zz"},
+		{Method: "GET", URL: ts.URL},
+		{Method: "GET", URL: ts.URL},
+	}
+	results, err := client.Batch(context.TODO(), requests, 1)
+	if err == nil || !strings.Contains(err.Error(), "%zz") || !errors.Is(results[0].Err, err) {
+		t.Fatalf("expected first error returned, got %v", err)
+	}
+	for _, result := range results[1:] {
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("expected remaining requests canceled, got %v", result.Err)
+		}
+	}
+	if hits.Load() != 0 {
+		t.Errorf("expected no requests after failure, got %d", hits.Load())
+	}
+}
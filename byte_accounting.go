@@ -0,0 +1,103 @@
+package http_request_instant
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"sync/atomic"
+)
+
+// EnableByteAccounting mulai menghitung byte yang benar-benar ditulis dan
+// dibaca di socket untuk setiap request, termasuk request line, header, dan
+// overhead TLS. Hasilnya ada di ApiResponse.WireBytesSent/WireBytesReceived
+// dan RequestMetrics. Pada HTTP/2 satu koneksi dipakai beberapa request
+// sekaligus, sehingga hitungan per request bisa saling tumpang tindih.
+// Error jika transport client bukan *http.Transport.
+func (c *HttpRequest) EnableByteAccounting() error {
+	t, err := c.transport()
+	if err != nil {
+		return err
+	}
+	c.countBytes = true
+	t.DialContext = c.dialContext
+	return nil
+}
+
+// countingConn menghitung byte yang dibaca dan ditulis di koneksi.
+type countingConn struct {
+	net.Conn
+	read, written atomic.Int64
+}
+
+func (c *countingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.read.Add(int64(n))
+	return n, err
+}
+
+func (c *countingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	c.written.Add(int64(n))
+	return n, err
+}
+
+// NetConn mengembalikan koneksi yang dibungkus, seperti tls.Conn.NetConn.
+func (c *countingConn) NetConn() net.Conn {
+	return c.Conn
+}
+
+// byteCounter menghitung selisih counter koneksi selama satu request.
+type byteCounter struct {
+	mu                   sync.Mutex
+	conn                 *countingConn
+	startRead, startSent int64
+}
+
+// traceBytes memasang ClientTrace yang mencatat posisi counter koneksi saat
+// request mendapat koneksi.
+func traceBytes(req *http.Request) (*http.Request, *byteCounter) {
+	counter := &byteCounter{}
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			conn := unwrapCountingConn(info.Conn)
+			if conn == nil {
+				return
+			}
+			counter.mu.Lock()
+			defer counter.mu.Unlock()
+			counter.conn = conn
+			counter.startRead = conn.read.Load()
+			counter.startSent = conn.written.Load()
+		},
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace)), counter
+}
+
+// totals mengembalikan byte yang ditulis dan dibaca sejak request mendapat koneksi.
+func (b *byteCounter) totals() (sent, received int64) {
+	if b == nil {
+		return 0, 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.conn == nil {
+		return 0, 0
+	}
+	return b.conn.written.Load() - b.startSent, b.conn.read.Load() - b.startRead
+}
+
+// unwrapCountingConn mencari countingConn di balik koneksi, mis. di dalam *tls.Conn.
+func unwrapCountingConn(conn net.Conn) *countingConn {
+	for conn != nil {
+		switch v := conn.(type) {
+		case *countingConn:
+			return v
+		case interface{ NetConn() net.Conn }:
+			conn = v.NetConn()
+		default:
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestByteAccountingIncludesHeaders(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Padding", strings.Repeat("p", 200))
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer ts.Close()
+
+	metrics := NewPrometheusMetrics("test")
+	client := NewHttpRequest()
+	client.Metrics = metrics
+	if err := client.EnableByteAccounting(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	body := strings.Repeat("b", 100)
+	for i := 0; i < 2; i++ {
+		resp, err := client.Request(context.TODO(), RequestOptions{
+			Method:      "POST",
+			URL:         ts.URL,
+			RequestBody: body,
+			ContentType: "text/plain",
+			Headers:     map[string]string{"X-Team": "payments"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// body + request line + header; response body + header X-Padding
+		if resp.WireBytesSent <= int64(len(body)+len("X-Team: payments")) || resp.WireBytesReceived <= 205 {
+			t.Errorf("request %d: unexpected wire bytes sent=%d received=%d", i, resp.WireBytesSent, resp.WireBytesReceived)
+		}
+		if resp.WireBytesSent > 1000 || resp.WireBytesReceived > 1000 {
+			t.Errorf("request %d: wire bytes include other requests: sent=%d received=%d", i, resp.WireBytesSent, resp.WireBytesReceived)
+		}
+	}
+
+	var out strings.Builder
+	_, _ = metrics.WriteTo(&out)
+	if !strings.Contains(out.String(), "test_wire_sent_bytes_total{") || !strings.Contains(out.String(), "test_wire_received_bytes_total{") {
+		t.Errorf("expected wire byte counters in metrics output:\n%s", out.String())
+	}
+
+	plain := NewHttpRequest()
+	resp, err := plain.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.WireBytesSent != 0 || resp.WireBytesReceived != 0 {
+		t.Errorf("expected no wire accounting without EnableByteAccounting")
+	}
+}
@@ -0,0 +1,485 @@
+package http_request_instant
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheEntry adalah satu response yang disimpan HTTP cache.
+type CacheEntry struct {
+	StatusCode   int               `json:"status_code"`
+	Headers      map[string]string `json:"headers"`        // Header response
+	Body         []byte            `json:"body,omitempty"` // Body setelah ResponseTransformer
+	Vary         map[string]string `json:"vary,omitempty"` // Nilai header request yang disebut header Vary
+	RequestTime  time.Time         `json:"request_time"`   // Waktu request dikirim
+	ResponseTime time.Time         `json:"response_time"`  // Waktu response diterima
+}
+
+// CacheStore adalah penyimpanan HTTP cache. Implementasi harus aman dipakai
+// konkuren dan tidak boleh mengubah entry setelah disimpan.
+type CacheStore interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, entry *CacheEntry)
+	Delete(key string)
+}
+
+// NewMemoryCacheStore membuat CacheStore di memori tanpa batas ukuran.
+func NewMemoryCacheStore() CacheStore {
+	return &memoryCacheStore{entries: make(map[string]*CacheEntry)}
+}
+
+type memoryCacheStore struct {
+	mu      sync.RWMutex
+	entries map[string]*CacheEntry
+}
+
+func (s *memoryCacheStore) Get(key string) (*CacheEntry, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entry, ok := s.entries[key]
+	return entry, ok
+}
+
+func (s *memoryCacheStore) Set(key string, entry *CacheEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = entry
+}
+
+func (s *memoryCacheStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+}
+
+// CacheOptions menyimpan konfigurasi CacheMiddleware.
+type CacheOptions struct {
+	// Optional: default NewMemoryCacheStore().
+	Store CacheStore
+
+	// Optional: default DefaultCacheKey. Pakai NewCacheKey untuk memisahkan
+	// entry per tenant atau mengabaikan query param yang tidak relevan.
+	KeyFunc CacheKeyFunc
+
+	// Berperilaku sebagai cache bersama (mis. di gateway): response
+	// "private" tidak disimpan, s-maxage dihormati, dan response untuk
+	// request ber-Authorization hanya disimpan jika diizinkan eksplisit.
+	Shared bool
+
+	// Optional: lama entry basi boleh dipakai saat server membalas 5xx atau
+	// gagal dihubungi, untuk response tanpa directive stale-if-error
+	// (RFC 5861). 0 berarti hanya directive dari server atau request.
+	StaleIfError time.Duration
+}
+
+// CacheMiddleware membuat middleware HTTP cache (RFC 7234) untuk GET dan
+// HEAD. Cache-Control, Pragma, Expires, Age, dan Vary dihormati; entry
+// yang basi divalidasi ulang dengan If-None-Match/If-Modified-Since, dan
+// response 304 melayani body dari cache. Jika server membalas 5xx atau
+// gagal dihubungi dalam jendela stale-if-error, entry basi dipakai dengan
+// ApiResponse.Stale bernilai true. Request dengan method lain yang
+// berhasil menghapus entry untuk URL yang sama.
+func CacheMiddleware(options CacheOptions) Middleware {
+	cache := newHTTPCache(options)
+	return func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, options RequestOptions) (*ApiResponse, error) {
+			return cache.do(ctx, next, options)
+		})
+	}
+}
+
+type httpCache struct {
+	store        CacheStore
+	keyFunc      CacheKeyFunc
+	shared       bool
+	staleIfError time.Duration
+}
+
+func newHTTPCache(options CacheOptions) *httpCache {
+	cache := &httpCache{store: options.Store, keyFunc: options.KeyFunc, shared: options.Shared, staleIfError: options.StaleIfError}
+	if cache.store == nil {
+		cache.store = NewMemoryCacheStore()
+	}
+	if cache.keyFunc == nil {
+		cache.keyFunc = DefaultCacheKey
+	}
+	return cache
+}
+
+// key menghitung cache key untuk options dengan method tertentu.
+func (h *httpCache) key(ctx context.Context, method string, options RequestOptions) string {
+	options.Method = method
+	return h.keyFunc(ctx, options)
+}
+
+func (h *httpCache) do(ctx context.Context, next Doer, options RequestOptions) (*ApiResponse, error) {
+	method := strings.ToUpper(options.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	if method != http.MethodGet && method != http.MethodHead {
+		resp, err := next.Do(ctx, options)
+		if err == nil && resp.StatusCode < 400 {
+			h.store.Delete(h.key(ctx, http.MethodGet, options))
+			h.store.Delete(h.key(ctx, http.MethodHead, options))
+		}
+		return resp, err
+	}
+
+	reqCC := parseCacheControl(headerValue(options.Headers, "Cache-Control"))
+	if _, ok := reqCC["no-store"]; ok {
+		return next.Do(ctx, options)
+	}
+	if len(reqCC) == 0 && strings.Contains(strings.ToLower(headerValue(options.Headers, "Pragma")), "no-cache") {
+		reqCC["no-cache"] = ""
+	}
+
+	key := h.key(ctx, method, options)
+	entry, cached := h.store.Get(key)
+	if cached && !entry.matchesVary(options.Headers) {
+		cached = false
+	}
+	if cached && h.fresh(entry, reqCC, time.Now()) {
+		return respondFromCache(entry, options)
+	}
+	if _, ok := reqCC["only-if-cached"]; ok {
+		return &ApiResponse{StatusCode: http.StatusGatewayTimeout, Headers: map[string]string{}}, nil
+	}
+
+	// Kirim ke server, dengan validator jika ada entry basi
+	target := options.ResponseTarget
+	options.ResponseTarget = nil
+	conditional := cached && entry.addValidators(&options)
+	requestTime := time.Now()
+	resp, err := next.Do(ctx, options)
+	if (err != nil || resp.StatusCode >= 500) && cached && ctx.Err() == nil && h.staleIfErrorAllowed(entry, reqCC, time.Now()) {
+		options.ResponseTarget = target
+		return respondStale(entry, options)
+	}
+	if err != nil {
+		return nil, err
+	}
+	options.ResponseTarget = target
+
+	if conditional && resp.StatusCode == http.StatusNotModified {
+		entry = entry.revalidated(resp, requestTime, time.Now())
+		h.store.Set(key, entry)
+		return respondRevalidated(entry, options)
+	}
+
+	if h.storable(options, resp) {
+		h.store.Set(key, newCacheEntry(options, resp, requestTime, time.Now()))
+	} else if cached {
+		h.store.Delete(key)
+	}
+	if err := decodeResponseTarget(options, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// respondFromCache membuat ApiResponse dari entry lalu men-decode ResponseTarget.
+func respondFromCache(entry *CacheEntry, options RequestOptions) (*ApiResponse, error) {
+	resp := entry.response(time.Now())
+	if err := decodeResponseTarget(options, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// respondRevalidated seperti respondFromCache untuk entry yang baru divalidasi ulang.
+func respondRevalidated(entry *CacheEntry, options RequestOptions) (*ApiResponse, error) {
+	resp, err := respondFromCache(entry, options)
+	if resp != nil {
+		resp.Revalidated = true
+	}
+	return resp, err
+}
+
+// respondStale seperti respondFromCache untuk entry basi yang dipakai
+// karena server gagal.
+func respondStale(entry *CacheEntry, options RequestOptions) (*ApiResponse, error) {
+	resp, err := respondFromCache(entry, options)
+	if resp != nil {
+		resp.Stale = true
+	}
+	return resp, err
+}
+
+// cacheableStatus adalah status yang boleh disimpan dengan freshness heuristik.
+var cacheableStatus = map[int]bool{
+	200: true, 203: true, 204: true, 300: true, 301: true, 308: true,
+	404: true, 405: true, 410: true, 414: true, 501: true,
+}
+
+// storable mengecek apakah response boleh disimpan (RFC 7234 bagian 3).
+func (h *httpCache) storable(options RequestOptions, resp *ApiResponse) bool {
+	respCC := parseCacheControl(resp.Headers["Cache-Control"])
+	if _, ok := respCC["no-store"]; ok {
+		return false
+	}
+	if _, ok := respCC["private"]; ok && h.shared {
+		return false
+	}
+	if strings.TrimSpace(resp.Headers["Vary"]) == "*" {
+		return false
+	}
+	if h.shared && hasAuthorization(options) {
+		_, public := respCC["public"]
+		_, mustRevalidate := respCC["must-revalidate"]
+		_, sMaxAge := respCC["s-maxage"]
+		if !public && !mustRevalidate && !sMaxAge {
+			return false
+		}
+	}
+
+	explicit := false
+	for _, directive := range []string{"max-age", "s-maxage", "public"} {
+		if _, ok := respCC[directive]; ok {
+			explicit = true
+		}
+	}
+	if resp.Headers["Expires"] != "" {
+		explicit = true
+	}
+	if !explicit && !cacheableStatus[resp.StatusCode] {
+		return false
+	}
+	// Tanpa freshness maupun validator, entry tidak pernah bisa dipakai
+	return explicit || resp.Headers["Etag"] != "" || resp.Headers["Last-Modified"] != ""
+}
+
+// fresh mengecek apakah entry boleh dipakai tanpa menghubungi server,
+// dengan memperhitungkan max-age, min-fresh, dan max-stale dari request.
+func (h *httpCache) fresh(entry *CacheEntry, reqCC map[string]string, now time.Time) bool {
+	respCC := parseCacheControl(entry.Headers["Cache-Control"])
+	if _, ok := respCC["no-cache"]; ok {
+		return false
+	}
+	if _, ok := reqCC["no-cache"]; ok {
+		return false
+	}
+
+	lifetime := entry.freshnessLifetime(h.shared)
+	age := entry.currentAge(now)
+	if maxAge, ok := cacheControlSeconds(reqCC, "max-age"); ok && age > maxAge {
+		return false
+	}
+	if minFresh, ok := cacheControlSeconds(reqCC, "min-fresh"); ok {
+		lifetime -= minFresh
+	}
+	if age < lifetime {
+		return true
+	}
+
+	_, mustRevalidate := respCC["must-revalidate"]
+	_, proxyRevalidate := respCC["proxy-revalidate"]
+	if mustRevalidate || (h.shared && proxyRevalidate) {
+		return false
+	}
+	if v, ok := reqCC["max-stale"]; ok {
+		if v == "" {
+			return true
+		}
+		maxStale, ok := cacheControlSeconds(reqCC, "max-stale")
+		return ok && age < lifetime+maxStale
+	}
+	return false
+}
+
+// staleIfErrorAllowed mengecek apakah entry basi masih dalam jendela
+// stale-if-error dari response, request, atau CacheOptions.StaleIfError.
+// must-revalidate tetap melarang entry basi dipakai.
+func (h *httpCache) staleIfErrorAllowed(entry *CacheEntry, reqCC map[string]string, now time.Time) bool {
+	respCC := parseCacheControl(entry.Headers["Cache-Control"])
+	_, mustRevalidate := respCC["must-revalidate"]
+	_, proxyRevalidate := respCC["proxy-revalidate"]
+	if mustRevalidate || (h.shared && proxyRevalidate) {
+		return false
+	}
+
+	window := h.staleIfError
+	for _, directives := range []map[string]string{respCC, reqCC} {
+		if v, ok := cacheControlSeconds(directives, "stale-if-error"); ok && v > window {
+			window = v
+		}
+	}
+	if window <= 0 {
+		return false
+	}
+	return entry.currentAge(now) < entry.freshnessLifetime(h.shared)+window
+}
+
+func newCacheEntry(options RequestOptions, resp *ApiResponse, requestTime, responseTime time.Time) *CacheEntry {
+	entry := &CacheEntry{
+		StatusCode:   resp.StatusCode,
+		Headers:      cloneHeaders(resp.Headers),
+		Body:         bytes.Clone(resp.Body),
+		RequestTime:  requestTime,
+		ResponseTime: responseTime,
+	}
+	if vary := resp.Headers["Vary"]; vary != "" {
+		entry.Vary = make(map[string]string)
+		for _, name := range strings.Split(vary, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			entry.Vary[name] = headerValue(options.Headers, name)
+		}
+	}
+	return entry
+}
+
+// matchesVary mengecek apakah header request sama dengan request asal entry.
+func (e *CacheEntry) matchesVary(headers map[string]string) bool {
+	for name, value := range e.Vary {
+		if headerValue(headers, name) != value {
+			return false
+		}
+	}
+	return true
+}
+
+// freshnessLifetime menghitung lama entry segar sejak dibuat server.
+func (e *CacheEntry) freshnessLifetime(shared bool) time.Duration {
+	respCC := parseCacheControl(e.Headers["Cache-Control"])
+	if shared {
+		if sMaxAge, ok := cacheControlSeconds(respCC, "s-maxage"); ok {
+			return sMaxAge
+		}
+	}
+	if maxAge, ok := cacheControlSeconds(respCC, "max-age"); ok {
+		return maxAge
+	}
+
+	date := e.date()
+	if v, ok := e.Headers["Expires"]; ok {
+		expires, err := http.ParseTime(v)
+		if err != nil {
+			// Expires tidak valid, mis. "0", berarti sudah basi
+			return 0
+		}
+		return expires.Sub(date)
+	}
+
+	// Heuristik: 10% dari umur dokumen sejak Last-Modified
+	if lastModified, err := http.ParseTime(e.Headers["Last-Modified"]); err == nil && cacheableStatus[e.StatusCode] {
+		if d := date.Sub(lastModified); d > 0 {
+			return d / 10
+		}
+	}
+	return 0
+}
+
+// currentAge menghitung umur entry (RFC 7234 bagian 4.2.3).
+func (e *CacheEntry) currentAge(now time.Time) time.Duration {
+	apparentAge := max(0, e.ResponseTime.Sub(e.date()))
+	responseDelay := e.ResponseTime.Sub(e.RequestTime)
+	ageValue := time.Duration(0)
+	if seconds, err := strconv.Atoi(strings.TrimSpace(e.Headers["Age"])); err == nil && seconds > 0 {
+		ageValue = time.Duration(seconds) * time.Second
+	}
+	correctedInitialAge := max(apparentAge, ageValue+responseDelay)
+	return correctedInitialAge + now.Sub(e.ResponseTime)
+}
+
+// date mengembalikan header Date, atau waktu response diterima.
+func (e *CacheEntry) date() time.Time {
+	if date, err := http.ParseTime(e.Headers["Date"]); err == nil {
+		return date
+	}
+	return e.ResponseTime
+}
+
+// addValidators memasang If-None-Match/If-Modified-Since dari entry,
+// kecuali pemanggil sudah mengirim header kondisional sendiri.
+func (e *CacheEntry) addValidators(options *RequestOptions) bool {
+	if headerValue(options.Headers, "If-None-Match") != "" || headerValue(options.Headers, "If-Modified-Since") != "" {
+		return false
+	}
+	etag, lastModified := e.Headers["Etag"], e.Headers["Last-Modified"]
+	if etag == "" && lastModified == "" {
+		return false
+	}
+	options.Headers = cloneHeaders(options.Headers)
+	if etag != "" {
+		options.Headers["If-None-Match"] = etag
+	}
+	if lastModified != "" {
+		options.Headers["If-Modified-Since"] = lastModified
+	}
+	return true
+}
+
+// revalidated membuat entry baru dari entry lama dan header response 304.
+func (e *CacheEntry) revalidated(resp *ApiResponse, requestTime, responseTime time.Time) *CacheEntry {
+	updated := *e
+	updated.Headers = cloneHeaders(e.Headers)
+	for k, v := range resp.Headers {
+		if k != "Content-Length" {
+			updated.Headers[k] = v
+		}
+	}
+	updated.RequestTime, updated.ResponseTime = requestTime, responseTime
+	return &updated
+}
+
+// response membuat ApiResponse dari entry, dengan header Age.
+func (e *CacheEntry) response(now time.Time) *ApiResponse {
+	headers := cloneHeaders(e.Headers)
+	headers["Age"] = strconv.Itoa(int(e.currentAge(now) / time.Second))
+	return &ApiResponse{
+		StatusCode: e.StatusCode,
+		Body:       bytes.Clone(e.Body),
+		Headers:    headers,
+		FromCache:  true,
+	}
+}
+
+// parseCacheControl mengurai header Cache-Control menjadi directive
+// (huruf kecil) dan nilainya.
+func parseCacheControl(v string) map[string]string {
+	directives := make(map[string]string)
+	for _, part := range strings.Split(v, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if name == "" {
+			continue
+		}
+		directives[strings.ToLower(name)] = strings.Trim(value, `"`)
+	}
+	return directives
+}
+
+func cacheControlSeconds(directives map[string]string, name string) (time.Duration, bool) {
+	v, ok := directives[name]
+	if !ok {
+		return 0, false
+	}
+	seconds, err := strconv.Atoi(v)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// headerValue membaca header dari map tanpa membedakan huruf besar kecil.
+func headerValue(headers map[string]string, name string) string {
+	if v, ok := headers[name]; ok {
+		return v
+	}
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
+
+// hasAuthorization mengecek apakah request membawa kredensial.
+func hasAuthorization(options RequestOptions) bool {
+	return headerValue(options.Headers, "Authorization") != "" || options.BasicAuth != nil ||
+		options.BearerToken != "" || options.ApiKey != nil || options.Auth != nil
+}
@@ -0,0 +1,110 @@
+package http_request_instant
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// CacheKeyFunc menurunkan cache key dari request. options.Method sudah
+// huruf besar (GET atau HEAD). Request yang key-nya sama berbagi entry.
+type CacheKeyFunc func(ctx context.Context, options RequestOptions) string
+
+// DefaultCacheKey memakai method dan URL lengkap apa adanya.
+func DefaultCacheKey(ctx context.Context, options RequestOptions) string {
+	return options.Method + " " + options.URL
+}
+
+// CacheKeyOptions menyimpan konfigurasi NewCacheKey.
+type CacheKeyOptions struct {
+	// Header request yang nilainya ikut membentuk key, mis. "X-Tenant-Id"
+	// atau "Authorization", supaya response per tenant tidak tertukar.
+	Headers []string
+
+	// Sertakan principal dari ContextWithPrincipal.
+	Principal bool
+
+	// Query param yang diabaikan, mis. "_" (cache buster) atau "utm_source".
+	IgnoreQuery []string
+}
+
+// NewCacheKey membuat CacheKeyFunc dari options. Query param diurutkan
+// sehingga urutan berbeda tetap menghasilkan key yang sama. Kredensial per
+// request (BearerToken, BasicAuth, ApiKey, Auth) selalu ikut membentuk key
+// karena tidak terlihat di Headers. Nilai header, kredensial, dan principal
+// di-hash, jadi rahasia tidak tersimpan di key.
+func NewCacheKey(options CacheKeyOptions) CacheKeyFunc {
+	ignore := make(map[string]bool, len(options.IgnoreQuery))
+	for _, name := range options.IgnoreQuery {
+		ignore[name] = true
+	}
+
+	return func(ctx context.Context, req RequestOptions) string {
+		key := req.Method + " " + normalizeCacheURL(req.URL, ignore)
+		credentials := requestCredentials(req)
+		if len(options.Headers) == 0 && !options.Principal && credentials == "" {
+			return key
+		}
+
+		var vary strings.Builder
+		vary.WriteString(credentials)
+		for _, name := range options.Headers {
+			vary.WriteString(http.CanonicalHeaderKey(name) + ": " + headerValue(req.Headers, name) + "\n")
+		}
+		if options.Principal {
+			vary.WriteString("principal: " + PrincipalFromContext(ctx) + "\n")
+		}
+		sum := sha256.Sum256([]byte(vary.String()))
+		return key + " " + hex.EncodeToString(sum[:16])
+	}
+}
+
+// normalizeCacheURL membuang query param di ignore lalu mengurutkan sisanya.
+func normalizeCacheURL(rawURL string, ignore map[string]bool) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.RawQuery == "" {
+		return rawURL
+	}
+	query := u.Query()
+	for name := range ignore {
+		query.Del(name)
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// requestCredentials merangkum kredensial per request yang dipasang
+// buildRequest di luar options.Headers. Hasilnya kosong jika request hanya
+// memakai kredensial client, yang sama untuk semua request.
+func requestCredentials(options RequestOptions) string {
+	var b strings.Builder
+	if options.BearerToken != "" {
+		b.WriteString("bearer: " + options.BearerToken + "\n")
+	}
+	if options.BasicAuth != nil {
+		b.WriteString("basic: " + options.BasicAuth.Username + ":" + options.BasicAuth.Password + "\n")
+	}
+	if options.ApiKey != nil {
+		fmt.Fprintf(&b, "api key: %d %s %s\n", options.ApiKey.In, options.ApiKey.Name, options.ApiKey.Value)
+	}
+	if options.Auth != nil {
+		b.WriteString("auth: " + providerIdentity(options.Auth) + "\n")
+	}
+	return b.String()
+}
+
+// providerIdentity membedakan AuthProvider berdasarkan tipe dan alamatnya.
+// Isi provider tidak dibaca karena bisa berubah, mis. token yang di-refresh.
+func providerIdentity(auth AuthProvider) string {
+	v := reflect.ValueOf(auth)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Func, reflect.Map, reflect.Chan:
+		return fmt.Sprintf("%T %#x", auth, v.Pointer())
+	}
+	return fmt.Sprintf("%T %v", auth, auth)
+}
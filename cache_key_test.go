@@ -0,0 +1,113 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewCacheKey(t *testing.T) {
+	key := NewCacheKey(CacheKeyOptions{Headers: []string{"X-Tenant-Id"}, IgnoreQuery: []string{"_", "utm_source"}})
+	get := func(rawURL, tenant string) string {
+		return key(context.TODO(), RequestOptions{Method: "GET", URL: rawURL, Headers: map[string]string{"x-tenant-id": tenant}})
+	}
+
+	if get("https://api.test/items?b=2&a=1&_=123", "t1") != get("https://api.test/items?a=1&b=2&utm_source=mail", "t1") {
+		t.Errorf("expected ignored and reordered params to share a key")
+	}
+	if get("https://api.test/items?a=1", "t1") == get("https://api.test/items?a=2", "t1") {
+		t.Errorf("expected different params to differ")
+	}
+	if get("https://api.test/items", "t1") == get("https://api.test/items", "t2") {
+		t.Errorf("expected different tenants to differ")
+	}
+	if strings.Contains(get("https://api.test/items", "secret-tenant"), "secret-tenant") {
+		t.Errorf("expected header values hashed")
+	}
+
+	byPrincipal := NewCacheKey(CacheKeyOptions{Principal: true})
+	alice := byPrincipal(ContextWithPrincipal(context.TODO(), "alice"), RequestOptions{Method: "GET", URL: "https://api.test/me"})
+	bob := byPrincipal(ContextWithPrincipal(context.TODO(), "bob"), RequestOptions{Method: "GET", URL: "https://api.test/me"})
+	if alice == bob {
+		t.Errorf("expected different principals to differ")
+	}
+}
+
+func TestCacheMiddlewareKeyFunc(t *testing.T) {
+	var hits int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Write([]byte(r.Header.Get("X-Tenant-Id")))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.Use(CacheMiddleware(CacheOptions{KeyFunc: NewCacheKey(CacheKeyOptions{Headers: []string{"X-Tenant-Id"}, IgnoreQuery: []string{"_"}})}))
+	get := func(query, tenant string) *ApiResponse {
+		resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL + "/items" + query, Headers: map[string]string{"X-Tenant-Id": tenant}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return resp
+	}
+
+	get("?_=1", "t1")
+	if resp := get("?_=2", "t1"); !resp.FromCache || string(resp.Body) != "t1" {
+		t.Errorf("expected cache hit ignoring _, got %q FromCache %v", resp.Body, resp.FromCache)
+	}
+	if resp := get("?_=3", "t2"); resp.FromCache || string(resp.Body) != "t2" {
+		t.Errorf("expected tenant t2 not served t1 response, got %q", resp.Body)
+	}
+
+	// Invalidasi memakai KeyFunc yang sama
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "PUT", URL: ts.URL + "/items", Headers: map[string]string{"X-Tenant-Id": "t1"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp := get("", "t1"); resp.FromCache {
+		t.Errorf("expected t1 entry invalidated by PUT")
+	}
+	if hits != 4 {
+		t.Errorf("expected 4 origin hits, got %d", hits)
+	}
+}
+
+func TestCacheMiddlewareKeyFuncBearerToken(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Write([]byte(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.Use(CacheMiddleware(CacheOptions{KeyFunc: NewCacheKey(CacheKeyOptions{Headers: []string{"Authorization"}})}))
+	get := func(token string) *ApiResponse {
+		resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL + "/me", BearerToken: token})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return resp
+	}
+
+	get("alice")
+	if resp := get("bob"); resp.FromCache || string(resp.Body) != "bob" {
+		t.Errorf("expected bob not served alice response, got %q FromCache %v", resp.Body, resp.FromCache)
+	}
+	if resp := get("alice"); !resp.FromCache || string(resp.Body) != "alice" {
+		t.Errorf("expected cache hit for alice, got %q FromCache %v", resp.Body, resp.FromCache)
+	}
+
+	key := NewCacheKey(CacheKeyOptions{})
+	basic := func(password string) string {
+		return key(context.TODO(), RequestOptions{Method: "GET", URL: "https://api.test/me", BasicAuth: &BasicAuth{Username: "u", Password: password}})
+	}
+	if basic("p1") == basic("p2") || strings.Contains(basic("p1"), "p1") {
+		t.Errorf("expected hashed BasicAuth in key, got %q", basic("p1"))
+	}
+	apiKey := key(context.TODO(), RequestOptions{Method: "GET", URL: "https://api.test/me", ApiKey: &ApiKeyAuth{Value: "k1"}})
+	if apiKey == key(context.TODO(), RequestOptions{Method: "GET", URL: "https://api.test/me", ApiKey: &ApiKeyAuth{Value: "k2"}}) {
+		t.Errorf("expected different API keys to differ")
+	}
+}
@@ -0,0 +1,231 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheMiddlewareServesFreshResponses(t *testing.T) {
+	var hits int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Write([]byte(`{"version":3}`))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.Use(CacheMiddleware(CacheOptions{}))
+
+	for i := 0; i < 3; i++ {
+		var config struct {
+			Version int `json:"version"`
+		}
+		resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL, ResponseTarget: &config})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if config.Version != 3 || resp.FromCache != (i > 0) {
+			t.Errorf("request %d: unexpected config %+v, FromCache %v", i, config, resp.FromCache)
+		}
+	}
+	if hits != 1 {
+		t.Errorf("expected 1 origin hit, got %d", hits)
+	}
+
+	// Request no-cache dan POST ke URL yang sama melewati/menghapus cache
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL, Headers: map[string]string{"Cache-Control": "no-cache"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "POST", URL: ts.URL}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hits != 4 || resp.FromCache {
+		t.Errorf("expected cache bypass and invalidation, got %d hits, FromCache %v", hits, resp.FromCache)
+	}
+}
+
+func TestCacheMiddlewareRevalidatesStaleEntries(t *testing.T) {
+	var hits, notModified int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte(`{"flag":true}`))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.Use(CacheMiddleware(CacheOptions{}))
+
+	for i := 0; i < 2; i++ {
+		var target struct {
+			Flag bool `json:"flag"`
+		}
+		resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL, ResponseTarget: &target})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK || !target.Flag || string(resp.Body) != `{"flag":true}` || resp.Revalidated != (i > 0) {
+			t.Errorf("request %d: unexpected response %d %q", i, resp.StatusCode, resp.Body)
+		}
+	}
+	if hits != 2 || notModified != 1 {
+		t.Errorf("expected revalidation with 304, got %d hits, %d not modified", hits, notModified)
+	}
+}
+
+func TestCacheMiddlewareVaryAndNoStore(t *testing.T) {
+	var hits int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if r.URL.Path == "/secret" {
+			w.Header().Set("Cache-Control", "no-store")
+		} else {
+			w.Header().Set("Cache-Control", "public, max-age=60")
+			w.Header().Set("Vary", "Accept-Language")
+		}
+		w.Write([]byte(r.Header.Get("Accept-Language")))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.Use(CacheMiddleware(CacheOptions{}))
+	get := func(path, lang string) *ApiResponse {
+		resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL + path, Headers: map[string]string{"Accept-Language": lang}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return resp
+	}
+
+	get("/", "id")
+	if resp := get("/", "en"); resp.FromCache || string(resp.Body) != "en" {
+		t.Errorf("expected Vary mismatch to miss, got %q FromCache %v", resp.Body, resp.FromCache)
+	}
+	if resp := get("/", "en"); !resp.FromCache {
+		t.Errorf("expected cached response for same Accept-Language")
+	}
+	get("/secret", "id")
+	if resp := get("/secret", "id"); resp.FromCache {
+		t.Errorf("expected no-store response not cached")
+	}
+	if hits != 4 {
+		t.Errorf("expected 4 origin hits, got %d", hits)
+	}
+}
+
+func TestCacheMiddlewareStaleIfError(t *testing.T) {
+	var down atomic.Bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Cache-Control", "max-age=0, stale-if-error=60")
+		w.Write([]byte(`{"version":3}`))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.Use(CacheMiddleware(CacheOptions{}))
+	get := func() (*ApiResponse, error) {
+		return client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+	}
+	if resp, err := get(); err != nil || resp.Stale {
+		t.Fatalf("unexpected response %v %v", resp, err)
+	}
+
+	// 5xx dari server
+	down.Store(true)
+	resp, err := get()
+	if err != nil || resp.StatusCode != http.StatusOK || !resp.Stale || !resp.FromCache || string(resp.Body) != `{"version":3}` {
+		t.Errorf("expected stale entry on 503, got %v %v", resp, err)
+	}
+
+	// Server tidak bisa dihubungi
+	ts.Close()
+	resp, err = get()
+	if err != nil || !resp.Stale || string(resp.Body) != `{"version":3}` {
+		t.Errorf("expected stale entry on network error, got %v %v", resp, err)
+	}
+}
+
+func TestCacheMiddlewareStaleIfErrorOption(t *testing.T) {
+	var down atomic.Bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Cache-Control", "max-age=0")
+		w.Write([]byte("v1"))
+	}))
+	defer ts.Close()
+
+	for _, tt := range []struct {
+		staleIfError time.Duration
+		wantStale    bool
+	}{
+		{0, false},
+		{time.Minute, true},
+	} {
+		down.Store(false)
+		client := NewHttpRequest()
+		client.Use(CacheMiddleware(CacheOptions{StaleIfError: tt.staleIfError}))
+		client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+
+		down.Store(true)
+		resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+		if err != nil || resp.Stale != tt.wantStale || (resp.StatusCode == http.StatusOK) != tt.wantStale {
+			t.Errorf("StaleIfError %v: unexpected response %d stale %v, err %v", tt.staleIfError, resp.StatusCode, resp.Stale, err)
+		}
+	}
+}
+
+func TestCacheEntryFreshness(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache := &httpCache{}
+	shared := &httpCache{shared: true}
+
+	tests := []struct {
+		name    string
+		cache   *httpCache
+		headers map[string]string
+		reqCC   string
+		age     time.Duration
+		want    bool
+	}{
+		{"max-age fresh", cache, map[string]string{"Cache-Control": "max-age=60"}, "", 30 * time.Second, true},
+		{"max-age stale", cache, map[string]string{"Cache-Control": "max-age=60"}, "", 90 * time.Second, false},
+		{"age header counts", cache, map[string]string{"Cache-Control": "max-age=60", "Age": "50"}, "", 20 * time.Second, false},
+		{"s-maxage for shared", shared, map[string]string{"Cache-Control": "max-age=60, s-maxage=10"}, "", 30 * time.Second, false},
+		{"expires", cache, map[string]string{"Date": now.Format(http.TimeFormat), "Expires": now.Add(time.Minute).Format(http.TimeFormat)}, "", 30 * time.Second, true},
+		{"invalid expires", cache, map[string]string{"Expires": "0"}, "", 0, false},
+		{"heuristic", cache, map[string]string{"Date": now.Format(http.TimeFormat), "Last-Modified": now.Add(-10 * time.Hour).Format(http.TimeFormat)}, "", 30 * time.Minute, true},
+		{"request max-age", cache, map[string]string{"Cache-Control": "max-age=60"}, "max-age=10", 30 * time.Second, false},
+		{"request min-fresh", cache, map[string]string{"Cache-Control": "max-age=60"}, "min-fresh=40", 30 * time.Second, false},
+		{"request max-stale", cache, map[string]string{"Cache-Control": "max-age=60"}, "max-stale=60", 90 * time.Second, true},
+		{"must-revalidate ignores max-stale", cache, map[string]string{"Cache-Control": "max-age=60, must-revalidate"}, "max-stale", 90 * time.Second, false},
+	}
+	for _, tt := range tests {
+		entry := &CacheEntry{StatusCode: 200, Headers: tt.headers, RequestTime: now, ResponseTime: now}
+		if got := tt.cache.fresh(entry, parseCacheControl(tt.reqCC), now.Add(tt.age)); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,335 @@
+package http_request_instant
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ErrCassetteMiss dikembalikan saat replay jika tidak ada interaksi di
+// cassette yang cocok dengan request.
+var ErrCassetteMiss = errors.New("cassette: no recorded interaction matches request")
+
+// CassetteMode menentukan apakah Cassette merekam, memutar ulang, atau keduanya.
+type CassetteMode int
+
+const (
+	// CassetteReplay hanya melayani request dari cassette; request yang
+	// tidak cocok gagal dengan ErrCassetteMiss. Ini mode default.
+	CassetteReplay CassetteMode = iota
+	// CassetteRecord selalu meneruskan request ke server dan merekam
+	// hasilnya, menimpa isi cassette lama.
+	CassetteRecord
+	// CassetteReplayOrRecord memutar ulang interaksi yang cocok dan
+	// merekam request yang belum ada di cassette.
+	CassetteReplayOrRecord
+)
+
+// CassetteMatcher mengecek apakah request cocok dengan interaksi yang direkam.
+type CassetteMatcher func(req *http.Request, body []byte, recorded CassetteRequest) bool
+
+// MatchMethod mencocokkan HTTP method.
+func MatchMethod(req *http.Request, body []byte, recorded CassetteRequest) bool {
+	return req.Method == recorded.Method
+}
+
+// MatchURL mencocokkan URL lengkap, termasuk query.
+func MatchURL(req *http.Request, body []byte, recorded CassetteRequest) bool {
+	return req.URL.String() == recorded.URL
+}
+
+// MatchBody mencocokkan body request byte per byte.
+func MatchBody(req *http.Request, body []byte, recorded CassetteRequest) bool {
+	return bytes.Equal(body, recorded.body())
+}
+
+// MatchAll menggabungkan beberapa matcher; request cocok jika semua matcher cocok.
+func MatchAll(matchers ...CassetteMatcher) CassetteMatcher {
+	return func(req *http.Request, body []byte, recorded CassetteRequest) bool {
+		for _, match := range matchers {
+			if !match(req, body, recorded) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// CassetteOptions menyimpan konfigurasi Cassette.
+type CassetteOptions struct {
+	Mode CassetteMode
+
+	// Optional: default MatchAll(MatchMethod, MatchURL).
+	Matcher CassetteMatcher
+
+	// Header request yang nilainya disamarkan sebelum disimpan ke file,
+	// default Authorization, Proxy-Authorization, dan Cookie.
+	FilterHeaders []string
+}
+
+// CassetteRequest adalah request yang tersimpan di cassette.
+type CassetteRequest struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	BodyBase64 bool        `json:"body_base64,omitempty"` // Body biner disimpan dalam base64
+}
+
+// CassetteResponse adalah response yang tersimpan di cassette.
+type CassetteResponse struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	BodyBase64 bool        `json:"body_base64,omitempty"`
+}
+
+// CassetteInteraction adalah satu pasangan request/response.
+type CassetteInteraction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+type cassetteFile struct {
+	Interactions []CassetteInteraction `json:"interactions"`
+}
+
+// Cassette adalah http.RoundTripper yang merekam pasangan request/response
+// ke file JSON dan memutarnya ulang, sehingga test integrasi bisa berjalan
+// tanpa memanggil API sungguhan.
+type Cassette struct {
+	path    string
+	next    http.RoundTripper
+	options CassetteOptions
+
+	mu           sync.Mutex
+	interactions []CassetteInteraction
+	used         []bool
+}
+
+// NewCassette membuka cassette di path dan membungkus next (default
+// http.DefaultTransport). Pada mode replay file harus sudah ada.
+func NewCassette(path string, next http.RoundTripper, options CassetteOptions) (*Cassette, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if options.Matcher == nil {
+		options.Matcher = MatchAll(MatchMethod, MatchURL)
+	}
+	if options.FilterHeaders == nil {
+		options.FilterHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+	}
+
+	c := &Cassette{path: path, next: next, options: options}
+	if options.Mode == CassetteRecord {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && options.Mode == CassetteReplayOrRecord {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cassette: %w", err)
+	}
+	var file cassetteFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("cassette: invalid file %s: %w", path, err)
+	}
+	c.interactions = file.Interactions
+	c.used = make([]bool, len(file.Interactions))
+	return c, nil
+}
+
+// UseCassette membungkus transport client dengan Cassette.
+func (c *HttpRequest) UseCassette(path string, options CassetteOptions) (*Cassette, error) {
+	if c.Client == nil || c.Client.Transport == nil {
+		// pasang transport milik client supaya setter transport lain tidak mengubah global
+		_, _ = c.transport()
+	}
+	cassette, err := NewCassette(path, c.Client.Transport, options)
+	if err != nil {
+		return nil, err
+	}
+	c.Client.Transport = cassette
+	return cassette, nil
+}
+
+// Unwrap mengembalikan RoundTripper yang dibungkus.
+func (c *Cassette) Unwrap() http.RoundTripper {
+	return c.next
+}
+
+// Interactions mengembalikan salinan semua interaksi di cassette.
+func (c *Cassette) Interactions() []CassetteInteraction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]CassetteInteraction{}, c.interactions...)
+}
+
+// RoundTrip mengimplementasikan http.RoundTripper.
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	return c.roundTrip(c.next, req)
+}
+
+// rewrap memasang cassette yang sama di atas next; interaksi tetap dicatat
+// dan diputar dari file yang sama.
+func (c *Cassette) rewrap(next http.RoundTripper) http.RoundTripper {
+	return &rewrappedTransport{next: next, roundTrip: c.roundTrip}
+}
+
+func (c *Cassette) roundTrip(next http.RoundTripper, req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if c.options.Mode != CassetteRecord {
+		if recorded, ok := c.match(req, body); ok {
+			return recorded.Response.httpResponse(req), nil
+		}
+		if c.options.Mode == CassetteReplay {
+			return nil, fmt.Errorf("%w: %s %s", ErrCassetteMiss, req.Method, req.URL)
+		}
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	if err := c.record(req, body, resp, respBody); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// match mencari interaksi cocok yang belum dipakai. Jika semua sudah dipakai,
+// interaksi cocok terakhir diputar ulang, mis. untuk polling.
+func (c *Cassette) match(req *http.Request, body []byte) (CassetteInteraction, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	last := -1
+	for i, interaction := range c.interactions {
+		if !c.options.Matcher(req, body, interaction.Request) {
+			continue
+		}
+		if !c.used[i] {
+			c.used[i] = true
+			return interaction, true
+		}
+		last = i
+	}
+	if last < 0 {
+		return CassetteInteraction{}, false
+	}
+	return c.interactions[last], true
+}
+
+func (c *Cassette) record(req *http.Request, body []byte, resp *http.Response, respBody []byte) error {
+	headers := req.Header.Clone()
+	for _, name := range c.options.FilterHeaders {
+		if headers.Get(name) != "" {
+			headers.Set(name, redactedValue)
+		}
+	}
+	interaction := CassetteInteraction{
+		Request:  CassetteRequest{Method: req.Method, URL: req.URL.String(), Headers: headers},
+		Response: CassetteResponse{StatusCode: resp.StatusCode, Headers: resp.Header.Clone()},
+	}
+	interaction.Request.Body, interaction.Request.BodyBase64 = encodeCassetteBody(body)
+	interaction.Response.Body, interaction.Response.BodyBase64 = encodeCassetteBody(respBody)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.interactions = append(c.interactions, interaction)
+	c.used = append(c.used, true)
+	return c.save()
+}
+
+// Save menulis semua interaksi ke file cassette. Dipanggil otomatis setiap
+// kali interaksi baru direkam.
+func (c *Cassette) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.save()
+}
+
+func (c *Cassette) save() error {
+	data, err := json.MarshalIndent(cassetteFile{Interactions: c.interactions}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(c.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("cassette: %w", err)
+		}
+	}
+	if err := os.WriteFile(c.path, data, 0o644); err != nil {
+		return fmt.Errorf("cassette: %w", err)
+	}
+	return nil
+}
+
+func (r CassetteRequest) body() []byte {
+	return decodeCassetteBody(r.Body, r.BodyBase64)
+}
+
+func (r CassetteResponse) httpResponse(req *http.Request) *http.Response {
+	body := decodeCassetteBody(r.Body, r.BodyBase64)
+	header := r.Headers.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+func encodeCassetteBody(body []byte) (string, bool) {
+	if utf8.Valid(body) {
+		return string(body), false
+	}
+	return base64.StdEncoding.EncodeToString(body), true
+}
+
+func decodeCassetteBody(body string, isBase64 bool) []byte {
+	if !isBase64 {
+		return []byte(body)
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(body))
+	if err != nil {
+		return []byte(body)
+	}
+	return data
+}
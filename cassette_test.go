@@ -0,0 +1,100 @@
+package http_request_instant
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	var hits atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"echo":` + string(body) + `}`))
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "cassettes", "orders.json")
+	recorder := NewHttpRequest()
+	if _, err := recorder.UseCassette(path, CassetteOptions{Mode: CassetteRecord}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	options := RequestOptions{
+		Method:      "POST",
+		URL:         ts.URL + "/orders",
+		Headers:     map[string]string{"Authorization": "Bearer secret"},
+		RequestBody: map[string]int{"qty": 2},
+	}
+	if _, err := recorder.Request(context.TODO(), options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(data), "Bearer secret") {
+		t.Errorf("cassette leaks Authorization header:\n%s", data)
+	}
+
+	player := NewHttpRequest()
+	cassette, err := player.UseCassette(path, CassetteOptions{Matcher: MatchAll(MatchMethod, MatchURL, MatchBody)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out struct {
+		Echo struct{ Qty int } `json:"echo"`
+	}
+	options.ResponseTarget = &out
+	if _, err := player.Request(context.TODO(), options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Echo.Qty != 2 || hits.Load() != 1 || len(cassette.Interactions()) != 1 {
+		t.Errorf("expected replay from cassette, got %+v (hits %d)", out, hits.Load())
+	}
+
+	options.RequestBody = map[string]int{"qty": 3}
+	options.ResponseTarget = nil
+	if _, err := player.Request(context.TODO(), options); !errors.Is(err, ErrCassetteMiss) {
+		t.Errorf("expected ErrCassetteMiss, got %v", err)
+	}
+}
+
+func TestCassetteReplayOrRecord(t *testing.T) {
+	var hits atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "mixed.json")
+	client := NewHttpRequest()
+	if _, err := client.UseCassette(path, CassetteOptions{Mode: CassetteReplayOrRecord}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, p := range []string{"/a", "/b", "/a"} {
+		resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL + p})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(resp.Body) != p {
+			t.Errorf("expected body %s, got %s", p, resp.Body)
+		}
+	}
+	if hits.Load() != 2 {
+		t.Errorf("expected only new requests to hit the server, got %d", hits.Load())
+	}
+
+	if _, err := NewCassette(filepath.Join(t.TempDir(), "missing.json"), nil, CassetteOptions{}); err == nil {
+		t.Errorf("expected error replaying missing cassette")
+	}
+}
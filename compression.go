@@ -0,0 +1,57 @@
+package http_request_instant
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// CompressionMode menentukan apakah transport mengompresi response secara
+// transparan.
+type CompressionMode int
+
+const (
+	// CompressionAuto membiarkan transport meminta gzip dan mendekompresi
+	// body otomatis; header Content-Encoding dan Content-Length dibuang.
+	// Ini perilaku default. Pada RequestOptions berarti ikut pengaturan client.
+	CompressionAuto CompressionMode = iota
+	// CompressionDisabled mematikan gzip otomatis: body dan Content-Length
+	// diterima apa adanya dari server. Jika server tetap mengompresi, body
+	// yang diterima masih terkompresi.
+	CompressionDisabled
+	// CompressionIdentity mengirim Accept-Encoding: identity supaya server
+	// tidak mengompresi sama sekali, untuk download yang checksum-nya
+	// dicocokkan dengan byte asli.
+	CompressionIdentity
+)
+
+// SetCompression mengatur kompresi transparan untuk semua request client.
+// Error jika transport client bukan *http.Transport.
+func (c *HttpRequest) SetCompression(mode CompressionMode) error {
+	if mode < CompressionAuto || mode > CompressionIdentity {
+		return fmt.Errorf("unknown CompressionMode %d", mode)
+	}
+	t, err := c.transport()
+	if err != nil {
+		return err
+	}
+	t.DisableCompression = mode != CompressionAuto
+	c.compression = mode
+	return nil
+}
+
+// applyCompression memasang Accept-Encoding sesuai mode request atau client.
+// Header Accept-Encoding yang diisi sendiri tidak diubah. Transport dipakai
+// bersama, jadi CompressionDisabled per request dikirim sebagai identity:
+// header eksplisit juga membuat transport tidak mendekompresi.
+func (c *HttpRequest) applyCompression(req *http.Request, options RequestOptions) {
+	mode := options.Compression
+	if mode == CompressionAuto {
+		mode = c.compression
+	}
+	if req.Header.Get("Accept-Encoding") != "" {
+		return
+	}
+	if mode == CompressionIdentity || (mode == CompressionDisabled && c.compression == CompressionAuto) {
+		req.Header.Set("Accept-Encoding", "identity")
+	}
+}
@@ -0,0 +1,70 @@
+package http_request_instant
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestCompression(t *testing.T) {
+	plain := bytes.Repeat([]byte("checksum-sensitive "), 100)
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	zw.Write(plain)
+	zw.Close()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Accept-Encoding", r.Header.Get("Accept-Encoding"))
+		if r.Header.Get("Accept-Encoding") == "gzip" || r.URL.Path == "/always-gzip" {
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Header().Set("Content-Length", strconv.Itoa(compressed.Len()))
+			w.Write(compressed.Bytes())
+			return
+		}
+		w.Write(plain)
+	}))
+	defer ts.Close()
+
+	get := func(client *HttpRequest, path string, mode CompressionMode) *ApiResponse {
+		t.Helper()
+		resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL + path, Compression: mode})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return resp
+	}
+
+	client := NewHttpRequest()
+	resp := get(client, "/", CompressionAuto)
+	if !bytes.Equal(resp.Body, plain) || resp.Headers["X-Accept-Encoding"] != "gzip" || resp.Headers["Content-Length"] != "" {
+		t.Errorf("expected transparent gzip, got %d bytes, %v", len(resp.Body), resp.Headers)
+	}
+	resp = get(client, "/", CompressionIdentity)
+	if !bytes.Equal(resp.Body, plain) || resp.Headers["X-Accept-Encoding"] != "identity" {
+		t.Errorf("expected identity per request, got %v", resp.Headers)
+	}
+	resp = get(client, "/", CompressionDisabled)
+	if resp.Headers["X-Accept-Encoding"] != "identity" {
+		t.Errorf("expected per-request disabled to send identity, got %v", resp.Headers)
+	}
+
+	if err := client.SetCompression(CompressionDisabled); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp = get(client, "/always-gzip", CompressionAuto)
+	if !bytes.Equal(resp.Body, compressed.Bytes()) || resp.Headers["Content-Length"] != strconv.Itoa(compressed.Len()) || resp.Headers["X-Accept-Encoding"] != "" {
+		t.Errorf("expected raw gzip bytes with Content-Length, got %d bytes, %v", len(resp.Body), resp.Headers)
+	}
+
+	client.SetCompression(CompressionIdentity)
+	if resp = get(client, "/", CompressionAuto); resp.Headers["X-Accept-Encoding"] != "identity" {
+		t.Errorf("expected client identity, got %v", resp.Headers)
+	}
+	if err := client.SetCompression(CompressionMode(9)); err == nil {
+		t.Errorf("expected error for unknown mode")
+	}
+}
@@ -0,0 +1,65 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ConditionalMiddleware membuat middleware yang menyimpan validator (ETag
+// dan Last-Modified) setiap response GET 200, lalu mengirim If-None-Match
+// dan If-Modified-Since pada request berikutnya ke URL yang sama, apa pun
+// Cache-Control-nya. Jika server membalas 304, body tersimpan dikembalikan
+// dengan ApiResponse.Revalidated bernilai true. Cocok untuk endpoint polling
+// yang jarang berubah. options.Shared diabaikan.
+func ConditionalMiddleware(options CacheOptions) Middleware {
+	cache := newHTTPCache(options)
+	return func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, options RequestOptions) (*ApiResponse, error) {
+			return cache.conditional(ctx, next, options)
+		})
+	}
+}
+
+func (h *httpCache) conditional(ctx context.Context, next Doer, options RequestOptions) (*ApiResponse, error) {
+	if method := strings.ToUpper(options.Method); method != "" && method != http.MethodGet {
+		return next.Do(ctx, options)
+	}
+
+	key := h.key(ctx, http.MethodGet, options)
+	entry, cached := h.store.Get(key)
+	if cached && !entry.matchesVary(options.Headers) {
+		cached = false
+	}
+
+	target := options.ResponseTarget
+	options.ResponseTarget = nil
+	conditional := cached && entry.addValidators(&options)
+	requestTime := time.Now()
+	resp, err := next.Do(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	options.ResponseTarget = target
+
+	if conditional && resp.StatusCode == http.StatusNotModified {
+		entry = entry.revalidated(resp, requestTime, time.Now())
+		h.store.Set(key, entry)
+		return respondRevalidated(entry, options)
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		_, noStore := parseCacheControl(resp.Headers["Cache-Control"])["no-store"]
+		hasValidator := resp.Headers["Etag"] != "" || resp.Headers["Last-Modified"] != ""
+		if hasValidator && !noStore {
+			h.store.Set(key, newCacheEntry(options, resp, requestTime, time.Now()))
+		} else if cached {
+			h.store.Delete(key)
+		}
+	}
+	if err := decodeResponseTarget(options, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
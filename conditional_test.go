@@ -0,0 +1,84 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestConditionalMiddlewareRevalidates(t *testing.T) {
+	lastModified := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Format(http.TimeFormat)
+	var ifNoneMatch, ifModifiedSince []string
+	version := "1"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifNoneMatch = append(ifNoneMatch, r.Header.Get("If-None-Match"))
+		ifModifiedSince = append(ifModifiedSince, r.Header.Get("If-Modified-Since"))
+		// Tanpa Cache-Control: CacheMiddleware tidak akan menyimpan ini
+		w.Header().Set("ETag", `"v`+version+`"`)
+		w.Header().Set("Last-Modified", lastModified)
+		if r.Header.Get("If-None-Match") == `"v`+version+`"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"version":` + version + `}`))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.Use(ConditionalMiddleware(CacheOptions{}))
+	poll := func() (*ApiResponse, int) {
+		var target struct {
+			Version int `json:"version"`
+		}
+		resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL, ResponseTarget: &target})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return resp, target.Version
+	}
+
+	if resp, v := poll(); resp.Revalidated || v != 1 {
+		t.Errorf("first poll: Revalidated %v, version %d", resp.Revalidated, v)
+	}
+	if resp, v := poll(); !resp.Revalidated || !resp.FromCache || resp.StatusCode != http.StatusOK || v != 1 {
+		t.Errorf("second poll: Revalidated %v, status %d, version %d", resp.Revalidated, resp.StatusCode, v)
+	}
+	version = "2"
+	if resp, v := poll(); resp.Revalidated || v != 2 {
+		t.Errorf("third poll: Revalidated %v, version %d", resp.Revalidated, v)
+	}
+
+	if ifNoneMatch[0] != "" || ifNoneMatch[1] != `"v1"` || ifNoneMatch[2] != `"v1"` {
+		t.Errorf("unexpected If-None-Match: %q", ifNoneMatch)
+	}
+	if ifModifiedSince[1] != lastModified {
+		t.Errorf("unexpected If-Modified-Since: %q", ifModifiedSince)
+	}
+}
+
+func TestConditionalMiddlewareKeepsCallerValidators(t *testing.T) {
+	var got string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("If-None-Match")
+		w.Header().Set("ETag", `"server"`)
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.Use(ConditionalMiddleware(CacheOptions{}))
+	for _, options := range []RequestOptions{
+		{Method: "GET", URL: ts.URL},
+		{Method: "GET", URL: ts.URL, Headers: map[string]string{"If-None-Match": `"caller"`}},
+	} {
+		if _, err := client.Request(context.TODO(), options); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got != `"caller"` {
+		t.Errorf("expected caller validator kept, got %q", got)
+	}
+}
@@ -0,0 +1,158 @@
+package http_request_instant
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// CloseIdleConnections menutup koneksi idle di transport client. Koneksi
+// yang sedang dipakai request tidak terganggu.
+func (c *HttpRequest) CloseIdleConnections() {
+	if c.Client != nil {
+		c.Client.CloseIdleConnections()
+	}
+}
+
+// ConnectionRecycling menentukan kapan koneksi keep-alive ditutup supaya
+// request berikutnya membuka koneksi baru. Berguna ketika load balancer
+// upstream butuh rotasi koneksi untuk menyeimbangkan beban. Nilai 0 berarti
+// batas tersebut tidak dipakai.
+type ConnectionRecycling struct {
+	MaxRequests int           // Tutup koneksi setelah melayani sekian request
+	MaxAge      time.Duration // Tutup koneksi setelah berumur sekian
+}
+
+// SetConnectionRecycling menutup koneksi yang melewati MaxRequests atau
+// MaxAge begitu tidak ada request yang memakainya. Request yang sedang
+// berjalan selalu selesai di koneksinya. Berlaku untuk koneksi yang dibuka
+// setelah dipanggil. Error jika transport client bukan *http.Transport.
+func (c *HttpRequest) SetConnectionRecycling(options ConnectionRecycling) error {
+	t, err := c.transport()
+	if err != nil {
+		return err
+	}
+	if options.MaxRequests <= 0 && options.MaxAge <= 0 {
+		c.recycle = nil
+		return nil
+	}
+	c.recycle = &options
+	t.DialContext = c.dialContext
+	return nil
+}
+
+// recycledConn mencatat umur dan jumlah pemakaian koneksi.
+type recycledConn struct {
+	net.Conn
+	options ConnectionRecycling
+	timer   *time.Timer
+
+	mu      sync.Mutex
+	uses    int
+	active  int
+	expired bool
+	closed  bool
+}
+
+func newRecycledConn(conn net.Conn, options ConnectionRecycling) *recycledConn {
+	rc := &recycledConn{Conn: conn, options: options}
+	if options.MaxAge > 0 {
+		rc.timer = time.AfterFunc(options.MaxAge, rc.expire)
+	}
+	return rc
+}
+
+// acquire dipanggil saat request mendapat koneksi ini.
+func (rc *recycledConn) acquire() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.active++
+	rc.uses++
+	if rc.options.MaxRequests > 0 && rc.uses >= rc.options.MaxRequests {
+		rc.expired = true
+	}
+}
+
+// release dipanggil setelah request selesai membaca response.
+func (rc *recycledConn) release() {
+	rc.mu.Lock()
+	rc.active--
+	closeNow := rc.active == 0 && rc.expired && !rc.closed
+	rc.mu.Unlock()
+	if closeNow {
+		rc.Close()
+	}
+}
+
+// expire dipanggil timer MaxAge.
+func (rc *recycledConn) expire() {
+	rc.mu.Lock()
+	rc.expired = true
+	closeNow := rc.active == 0 && !rc.closed
+	rc.mu.Unlock()
+	if closeNow {
+		rc.Close()
+	}
+}
+
+// Close menutup koneksi; transport melihatnya sebagai koneksi yang putus
+// dan membuangnya dari pool.
+func (rc *recycledConn) Close() error {
+	rc.mu.Lock()
+	rc.closed = true
+	rc.mu.Unlock()
+	if rc.timer != nil {
+		rc.timer.Stop()
+	}
+	return rc.Conn.Close()
+}
+
+// NetConn mengembalikan koneksi yang dibungkus, seperti tls.Conn.NetConn.
+func (rc *recycledConn) NetConn() net.Conn {
+	return rc.Conn
+}
+
+// traceRecycle memasang ClientTrace yang mencatat pemakaian koneksi. Fungsi
+// done wajib dipanggil setelah body response selesai dibaca.
+func traceRecycle(req *http.Request) (*http.Request, func()) {
+	var mu sync.Mutex
+	var conn *recycledConn
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			rc := unwrapRecycledConn(info.Conn)
+			if rc == nil {
+				return
+			}
+			rc.acquire()
+			mu.Lock()
+			conn = rc
+			mu.Unlock()
+		},
+	}
+	done := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if conn != nil {
+			conn.release()
+			conn = nil
+		}
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace)), done
+}
+
+// unwrapRecycledConn mencari recycledConn di balik koneksi, mis. di dalam *tls.Conn.
+func unwrapRecycledConn(conn net.Conn) *recycledConn {
+	for conn != nil {
+		switch v := conn.(type) {
+		case *recycledConn:
+			return v
+		case interface{ NetConn() net.Conn }:
+			conn = v.NetConn()
+		default:
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// remoteAddrServer mencatat alamat klien yang berbeda, satu per koneksi.
+func remoteAddrServer() (*httptest.Server, func() int) {
+	var mu sync.Mutex
+	addrs := make(map[string]bool)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		addrs[r.RemoteAddr] = true
+		mu.Unlock()
+	}))
+	return ts, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(addrs)
+	}
+}
+
+func TestConnectionRecyclingMaxRequests(t *testing.T) {
+	ts, conns := remoteAddrServer()
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.EnablePoolStats()
+	if err := client.SetConnectionRecycling(ConnectionRecycling{MaxRequests: 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 6; i++ {
+		if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if conns() != 3 {
+		t.Errorf("expected 3 connections for 6 requests, got %d", conns())
+	}
+	if stats := client.PoolStats(); stats.Total.Closed != 3 || stats.Total.Reused != 3 {
+		t.Errorf("expected 3 closed and 3 reused, got %+v", stats.Total)
+	}
+}
+
+func TestConnectionRecyclingMaxAge(t *testing.T) {
+	ts, conns := remoteAddrServer()
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.SetConnectionRecycling(ConnectionRecycling{MaxAge: 100 * time.Millisecond})
+	client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+	client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+	time.Sleep(200 * time.Millisecond)
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conns() != 2 {
+		t.Errorf("expected a new connection after MaxAge, got %d connections", conns())
+	}
+}
+
+func TestCloseIdleConnections(t *testing.T) {
+	ts, conns := remoteAddrServer()
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+	client.CloseIdleConnections()
+	client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+	if conns() != 2 {
+		t.Errorf("expected idle connection to be closed, got %d connections", conns())
+	}
+}
@@ -0,0 +1,61 @@
+package http_request_instant
+
+import (
+	"context"
+	"time"
+)
+
+// ContextOptions adalah override RequestOptions yang dibawa context.Context,
+// supaya framework atau middleware di lapisan atas bisa memengaruhi request
+// tanpa mengubah setiap pemanggilan Request.
+type ContextOptions struct {
+	Headers map[string]string // Ditambahkan jika header yang sama belum diisi RequestOptions.Headers
+	Timeout time.Duration     // Batas waktu seluruh Request, 0 berarti tidak diubah
+	Debug   *bool             // Dipakai jika RequestOptions.Debug kosong
+}
+
+type contextOptionsKey struct{}
+
+// ContextWithOptions menyimpan override di ctx. Jika ctx sudah membawa
+// ContextOptions, keduanya digabung dan nilai dari opts yang menang.
+func ContextWithOptions(ctx context.Context, opts ContextOptions) context.Context {
+	if parent, ok := OptionsFromContext(ctx); ok {
+		merged := parent
+		merged.Headers = mergeHeaders(parent.Headers, opts.Headers)
+		if opts.Timeout > 0 {
+			merged.Timeout = opts.Timeout
+		}
+		if opts.Debug != nil {
+			merged.Debug = opts.Debug
+		}
+		opts = merged
+	}
+	return context.WithValue(ctx, contextOptionsKey{}, opts)
+}
+
+// OptionsFromContext mengambil ContextOptions dari ctx.
+func OptionsFromContext(ctx context.Context) (ContextOptions, bool) {
+	opts, ok := ctx.Value(contextOptionsKey{}).(ContextOptions)
+	return opts, ok
+}
+
+// applyContextOptions menggabungkan ContextOptions dari ctx ke options.
+// Fungsi cancel wajib dipanggil setelah Request selesai.
+func applyContextOptions(ctx context.Context, options RequestOptions) (context.Context, RequestOptions, context.CancelFunc) {
+	opts, ok := OptionsFromContext(ctx)
+	if !ok {
+		return ctx, options, func() {}
+	}
+
+	if len(opts.Headers) > 0 {
+		options.Headers = mergeHeaders(opts.Headers, options.Headers)
+	}
+	if options.Debug == nil {
+		options.Debug = opts.Debug
+	}
+	if opts.Timeout > 0 {
+		ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+		return ctx, options, cancel
+	}
+	return ctx, options, func() {}
+}
@@ -0,0 +1,64 @@
+package http_request_instant
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestContextOptionsOverrideRequests(t *testing.T) {
+	var tenant, trace string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant, trace = r.Header.Get("X-Tenant"), r.Header.Get("X-Trace")
+		if r.URL.Path == "/slow" {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}))
+	defer ts.Close()
+
+	logger := &recordLogger{}
+	client := NewHttpRequest()
+	client.Logger = logger
+
+	debug := true
+	ctx := ContextWithOptions(context.Background(), ContextOptions{
+		Headers: map[string]string{"X-Tenant": "acme", "X-Trace": "outer"},
+		Debug:   &debug,
+	})
+	ctx = ContextWithOptions(ctx, ContextOptions{Headers: map[string]string{"X-Trace": "inner"}, Timeout: 30 * time.Millisecond})
+
+	_, err := client.Request(ctx, RequestOptions{
+		Method:  "GET",
+		URL:     ts.URL,
+		Headers: map[string]string{"X-Tenant": "explicit"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tenant != "explicit" || trace != "inner" {
+		t.Errorf("unexpected headers: tenant %q, trace %q", tenant, trace)
+	}
+	if len(logger.entries) != 2 || !strings.Contains(logger.entries[0], "X-Trace: inner") {
+		t.Errorf("expected debug enabled from context, got %q", logger.entries)
+	}
+
+	_, err = client.Request(ctx, RequestOptions{Method: "GET", URL: ts.URL + "/slow"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context timeout, got %v", err)
+	}
+}
+
+func TestContextOptionsHeadersCaseInsensitive(t *testing.T) {
+	ctx := ContextWithOptions(context.Background(), ContextOptions{Headers: map[string]string{"X-Foo": "outer", "X-Bar": "outer"}})
+	ctx = ContextWithOptions(ctx, ContextOptions{Headers: map[string]string{"x-bar": "inner"}})
+	_, options, cancel := applyContextOptions(ctx, RequestOptions{Headers: map[string]string{"x-foo": "request"}})
+	defer cancel()
+
+	if len(options.Headers) != 2 || options.Headers["X-Foo"] != "request" || options.Headers["X-Bar"] != "inner" {
+		t.Errorf("expected one value per header, got %v", options.Headers)
+	}
+}
@@ -0,0 +1,181 @@
+package http_request_instant
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// CSRFConfig menyimpan konfigurasi pengambilan dan injeksi token CSRF.
+type CSRFConfig struct {
+	// Ambil token dari cookie ini di cookie jar, mis. "XSRF-TOKEN" atau "csrftoken".
+	CookieName string
+
+	// URL yang di-GET untuk memancing token jika belum ada.
+	PrimeURL string
+	// Optional: ambil token dari header response priming, mis. "X-CSRF-Token".
+	ResponseHeader string
+	// Optional: ambil token dari response priming secara kustom, mis. CSRFFromMetaTag.
+	Extract func(resp *ApiResponse) (string, error)
+
+	// Header untuk mengirim token, default "X-CSRF-Token".
+	HeaderName string
+	// Optional: juga kirim token sebagai field form untuk body
+	// application/x-www-form-urlencoded, mis. "csrf_token" atau "_token".
+	FormField string
+}
+
+// CSRFSession adalah RequestSigner yang menyisipkan token CSRF ke setiap
+// request yang mengubah data (POST, PUT, PATCH, DELETE) dalam satu sesi cookie.
+type CSRFSession struct {
+	client *HttpRequest
+	config CSRFConfig
+
+	mu    sync.Mutex
+	token string
+}
+
+// NewCSRFSession membuat CSRFSession untuk client. Cookie jar dipasang
+// jika client belum punya, karena token CSRF terikat ke cookie sesi.
+// Pasang hasilnya ke client.Signer.
+func NewCSRFSession(client *HttpRequest, config CSRFConfig) (*CSRFSession, error) {
+	if config.CookieName == "" && config.PrimeURL == "" {
+		return nil, fmt.Errorf("csrf: CookieName or PrimeURL is required")
+	}
+	if config.HeaderName == "" {
+		config.HeaderName = "X-CSRF-Token"
+	}
+	if client.Client == nil {
+		client.Client = &http.Client{}
+	}
+	if client.Client.Jar == nil {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return nil, err
+		}
+		client.Client.Jar = jar
+	}
+	return &CSRFSession{client: client, config: config}, nil
+}
+
+// CSRFFromMetaTag mengambil token dari tag HTML <meta name="..." content="...">.
+func CSRFFromMetaTag(name string) func(resp *ApiResponse) (string, error) {
+	pattern := regexp.MustCompile(`<meta[^>]+name=["']` + regexp.QuoteMeta(name) + `["'][^>]*content=["']([^"']+)["']`)
+	return func(resp *ApiResponse) (string, error) {
+		match := pattern.FindSubmatch(resp.Body)
+		if match == nil {
+			return "", fmt.Errorf("csrf: meta tag %q not found", name)
+		}
+		return html.UnescapeString(string(match[1])), nil
+	}
+}
+
+// Invalidate membuang token yang tersimpan, mis. setelah server menolak token (403).
+func (s *CSRFSession) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = ""
+}
+
+// SignRequest mengimplementasikan RequestSigner.
+func (s *CSRFSession) SignRequest(ctx context.Context, req *http.Request) error {
+	switch req.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return nil
+	}
+
+	token, err := s.Token(ctx, req.URL)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(s.config.HeaderName, token)
+
+	if s.config.FormField != "" && strings.HasPrefix(req.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		return appendFormField(req, s.config.FormField, token)
+	}
+	return nil
+}
+
+// Token mengembalikan token CSRF untuk target, memancing lewat PrimeURL jika belum ada.
+func (s *CSRFSession) Token(ctx context.Context, target *url.URL) (string, error) {
+	if token := s.cookieToken(target); token != "" {
+		return token, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" {
+		return s.token, nil
+	}
+	if s.config.PrimeURL == "" {
+		return "", fmt.Errorf("csrf: cookie %q not found", s.config.CookieName)
+	}
+
+	resp, err := s.client.Request(ctx, RequestOptions{Method: "GET", URL: s.config.PrimeURL})
+	if err != nil {
+		return "", fmt.Errorf("csrf: error prime token: %w", err)
+	}
+
+	token := ""
+	switch {
+	case s.config.Extract != nil:
+		if token, err = s.config.Extract(resp); err != nil {
+			return "", err
+		}
+	case s.config.ResponseHeader != "":
+		token = resp.Headers[http.CanonicalHeaderKey(s.config.ResponseHeader)]
+	}
+	if token == "" {
+		token = s.cookieToken(target)
+	}
+	if token == "" {
+		return "", fmt.Errorf("csrf: no token found after priming %s", s.config.PrimeURL)
+	}
+	s.token = token
+	return token, nil
+}
+
+func (s *CSRFSession) cookieToken(target *url.URL) string {
+	if s.config.CookieName == "" || s.client.Client.Jar == nil {
+		return ""
+	}
+	for _, cookie := range s.client.Client.Jar.Cookies(target) {
+		if cookie.Name == s.config.CookieName {
+			if value, err := url.QueryUnescape(cookie.Value); err == nil {
+				return value
+			}
+			return cookie.Value
+		}
+	}
+	return ""
+}
+
+// appendFormField menambahkan field ke body form urlencoded.
+func appendFormField(req *http.Request, name, value string) error {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return fmt.Errorf("csrf: error read form body: %w", err)
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return fmt.Errorf("csrf: error parse form body: %w", err)
+	}
+	form.Set(name, value)
+
+	encoded := []byte(form.Encode())
+	req.Body = io.NopCloser(bytes.NewReader(encoded))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(encoded)), nil
+	}
+	req.ContentLength = int64(len(encoded))
+	return nil
+}
@@ -0,0 +1,81 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCSRFFromCookie(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			http.SetCookie(w, &http.Cookie{Name: "XSRF-TOKEN", Value: "cookie-token", Path: "/"})
+			return
+		}
+		_, _ = w.Write([]byte(r.Header.Get("X-XSRF-TOKEN")))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	session, err := NewCSRFSession(client, CSRFConfig{
+		CookieName: "XSRF-TOKEN",
+		PrimeURL:   ts.URL + "/login",
+		HeaderName: "X-XSRF-TOKEN",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.Signer = session
+
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "POST", URL: ts.URL + "/items", RequestBody: "{}"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(resp.Body) != "cookie-token" {
+		t.Errorf("expected csrf header from cookie, got %q", string(resp.Body))
+	}
+}
+
+func TestCSRFFromMetaTagAndFormField(t *testing.T) {
+	primes := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			primes++
+			_, _ = w.Write([]byte(`<html><head><meta name="csrf-token" content="meta-token"></head></html>`))
+			return
+		}
+		_ = r.ParseForm()
+		_, _ = w.Write([]byte(r.Header.Get("X-CSRF-Token") + "|" + r.PostForm.Get("_token") + "|" + r.PostForm.Get("name")))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	session, err := NewCSRFSession(client, CSRFConfig{
+		PrimeURL:  ts.URL + "/form",
+		Extract:   CSRFFromMetaTag("csrf-token"),
+		FormField: "_token",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.Signer = session
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Request(context.TODO(), RequestOptions{
+			Method:      "POST",
+			URL:         ts.URL + "/submit",
+			ContentType: "application/x-www-form-urlencoded",
+			RequestBody: "name=alice",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(resp.Body) != "meta-token|meta-token|alice" {
+			t.Errorf("unexpected response: %q", string(resp.Body))
+		}
+	}
+	if primes != 1 {
+		t.Errorf("expected token to be primed once, got %d", primes)
+	}
+}
@@ -0,0 +1,96 @@
+package http_request_instant
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Curl membangun request dari options (termasuk header, auth, dan signer)
+// tanpa mengirimnya, lalu mengembalikan perintah curl yang setara. Jika
+// redact true, nilai rahasia disamarkan memakai aturan output debug.
+func (c *HttpRequest) Curl(ctx context.Context, options RequestOptions, redact bool) (string, error) {
+	req, _, err := c.buildRequest(ctx, options)
+	if err != nil {
+		return "", err
+	}
+	signer := options.Signer
+	if signer == nil {
+		signer = c.Signer
+	}
+	if signer != nil {
+		if err := signer.SignRequest(req.Context(), req); err != nil {
+			return "", fmt.Errorf("error sign request: %w", err)
+		}
+	}
+	return c.curlCommand(req, redact), nil
+}
+
+// Curl mengembalikan perintah curl untuk request yang menghasilkan response
+// ini, persis seperti yang dikirim client (setelah hook dan signer).
+func (r *ApiResponse) Curl(redact bool) string {
+	if r.request == nil || r.client == nil {
+		return ""
+	}
+	return r.client.curlCommand(r.request, redact)
+}
+
+func (c *HttpRequest) curlCommand(req *http.Request, redact bool) string {
+	var body []byte
+	if req.GetBody != nil {
+		if rc, err := req.GetBody(); err == nil {
+			body, _ = io.ReadAll(rc)
+			rc.Close()
+		}
+	}
+
+	target := req.URL.String()
+	if redact {
+		target = c.redactURL(req.URL, c.requestApiKey(req))
+	}
+	parts := []string{"curl"}
+	if req.Method != http.MethodGet || len(body) > 0 {
+		parts = append(parts, "-X "+req.Method)
+	}
+	parts = append(parts, shellQuote(target))
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if req.Host != "" && req.Host != req.URL.Host {
+		parts = append(parts, "-H "+shellQuote("Host: "+req.Host))
+	}
+	for _, name := range names {
+		values := req.Header[name]
+		if redact {
+			values = c.redactHeader(name, values, c.requestApiKey(req))
+		}
+		for _, value := range values {
+			parts = append(parts, "-H "+shellQuote(name+": "+value))
+		}
+	}
+
+	if len(body) > 0 {
+		text := string(body)
+		if redact {
+			text = c.redactBody(body)
+		}
+		if utf8.ValidString(text) {
+			parts = append(parts, "--data-binary "+shellQuote(text))
+		} else {
+			parts = append(parts, fmt.Sprintf("--data-binary @body.bin # %d byte body biner tidak disertakan", len(body)))
+		}
+	}
+	return strings.Join(parts, " \\\n  ")
+}
+
+// shellQuote membungkus s dengan kutip tunggal yang aman untuk sh/bash.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
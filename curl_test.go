@@ -0,0 +1,63 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCurl(t *testing.T) {
+	client := NewHttpRequest()
+	client.RedactBodyFields = []string{"password"}
+
+	cmd, err := client.Curl(context.TODO(), RequestOptions{
+		Method:      "POST",
+		URL:         "https://api.example.com/login?page=1",
+		Headers:     map[string]string{"Authorization": "Bearer abc", "X-Note": "it's"},
+		RequestBody: map[string]string{"user": "budi", "password": "rahasia"},
+	}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"curl \\\n  -X POST \\\n  'https://api.example.com/login?page=1'",
+		`-H 'Authorization: Bearer [REDACTED]'`,
+		`-H 'X-Note: it'\''s'`,
+		`--data-binary '{"password":"[REDACTED]","user":"budi"}'`,
+	} {
+		if !strings.Contains(cmd, want) {
+			t.Errorf("curl command missing %q:\n%s", want, cmd)
+		}
+	}
+
+	raw, _ := client.Curl(context.TODO(), RequestOptions{
+		Method:  "GET",
+		URL:     "https://api.example.com/items",
+		Headers: map[string]string{"Authorization": "Bearer abc"},
+	}, false)
+	if strings.Contains(raw, "-X") || !strings.Contains(raw, "Bearer abc") {
+		t.Errorf("unexpected unredacted GET command:\n%s", raw)
+	}
+}
+
+func TestApiResponseCurl(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.OnBeforeRequest(func(ctx context.Context, req *http.Request, info AttemptInfo) error {
+		req.Header.Set("X-Stamp", "stamped")
+		return nil
+	})
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL + "/ping"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cmd := resp.Curl(true); !strings.Contains(cmd, ts.URL+"/ping") || !strings.Contains(cmd, "X-Stamp: stamped") {
+		t.Errorf("unexpected curl command:\n%s", cmd)
+	}
+}
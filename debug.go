@@ -0,0 +1,303 @@
+package http_request_instant
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// redactedValue menggantikan nilai rahasia di output debug.
+const redactedValue = "[REDACTED]"
+
+// defaultRedactHeaders selalu disamarkan di output debug.
+var defaultRedactHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+	"Api-Key",
+	"X-Auth-Token",
+	"X-Amz-Security-Token",
+}
+
+// defaultRedactQueryParams selalu disamarkan di URL output debug.
+var defaultRedactQueryParams = []string{"api_key", "apikey", "access_token", "token"}
+
+// debugRequest mencatat detail request dengan nilai rahasia disamarkan.
+func (c *HttpRequest) debugRequest(ctx context.Context, req *http.Request, body []byte, attempt int) {
+	if events, ok := c.logger().(EventLogger); ok {
+		event := LogEvent{
+			Kind:     LogEventRequest,
+			Time:     time.Now(),
+			Method:   req.Method,
+			URL:      c.redactURL(req.URL, c.requestApiKey(req)),
+			Attempt:  attempt,
+			Headers:  c.redactHeaders(req.Header, c.requestApiKey(req)),
+			BodySize: len(body),
+		}
+		if body != nil {
+			event.Body = c.debugBody(body)
+		}
+		events.LogEvent(ctx, event)
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("=== [HTTP REQUEST] ===\n")
+	fmt.Fprintf(&b, "URL: %s\n", c.redactURL(req.URL, c.requestApiKey(req)))
+	fmt.Fprintf(&b, "Method: %s\n", req.Method)
+	b.WriteString("Headers:\n")
+	c.writeHeaders(&b, req.Header, c.requestApiKey(req))
+	if body != nil {
+		fmt.Fprintf(&b, "Body: %s\n", c.debugBody(body))
+	}
+	b.WriteString("======================")
+	c.logger().Debugf("%s", b.String())
+}
+
+// debugResponse mencatat detail response dengan nilai rahasia disamarkan.
+// req adalah request yang dikirim; resp.Request bisa nil dari RoundTripper
+// custom.
+func (c *HttpRequest) debugResponse(ctx context.Context, req *http.Request, resp *http.Response, body []byte, attempt int, duration time.Duration) {
+	if events, ok := c.logger().(EventLogger); ok {
+		events.LogEvent(ctx, LogEvent{
+			Kind:       LogEventResponse,
+			Time:       time.Now(),
+			Method:     req.Method,
+			URL:        c.redactURL(req.URL, c.requestApiKey(req)),
+			Attempt:    attempt,
+			StatusCode: resp.StatusCode,
+			Duration:   duration,
+			Headers:    c.redactHeaders(resp.Header, c.requestApiKey(req)),
+			Body:       c.debugBody(body),
+			BodySize:   len(body),
+		})
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("=== [HTTP RESPONSE] ===\n")
+	fmt.Fprintf(&b, "Status Code: %d\n", resp.StatusCode)
+	b.WriteString("Headers:\n")
+	c.writeHeaders(&b, resp.Header, c.requestApiKey(req))
+	fmt.Fprintf(&b, "Body: %s\n", c.debugBody(body))
+	b.WriteString("=======================")
+	c.logger().Debugf("%s", b.String())
+}
+
+// logError mencatat request yang gagal tanpa response. EventLogger selalu
+// menerima event error, Logger biasa hanya saat Debug aktif.
+func (c *HttpRequest) logError(ctx context.Context, req *http.Request, attempt int, duration time.Duration, err error) {
+	if events, ok := c.logger().(EventLogger); ok {
+		events.LogEvent(ctx, LogEvent{
+			Kind:     LogEventError,
+			Time:     time.Now(),
+			Method:   req.Method,
+			URL:      c.redactURL(req.URL, c.requestApiKey(req)),
+			Attempt:  attempt,
+			Duration: duration,
+			Err:      err,
+		})
+		return
+	}
+	if c.debugEnabled(ctx, req) {
+		c.logger().Errorf("=== [HTTP ERROR] === %s %s: %v", req.Method, c.redactURL(req.URL, c.requestApiKey(req)), err)
+	}
+}
+
+// logSlowRequest mencatat peringatan untuk percobaan yang melewati
+// SlowRequestThreshold, terlepas dari Debug.
+func (c *HttpRequest) logSlowRequest(ctx context.Context, req *http.Request, statusCode, attempt int, duration time.Duration, timings *Timings) {
+	if events, ok := c.logger().(EventLogger); ok {
+		events.LogEvent(ctx, LogEvent{
+			Kind:       LogEventSlow,
+			Time:       time.Now(),
+			Method:     req.Method,
+			URL:        c.redactURL(req.URL, c.requestApiKey(req)),
+			Attempt:    attempt,
+			StatusCode: statusCode,
+			Duration:   duration,
+			Timings:    timings,
+		})
+		return
+	}
+
+	msg := fmt.Sprintf("=== [HTTP SLOW] === %s %s: %d in %s (threshold %s)",
+		req.Method, c.redactURL(req.URL, c.requestApiKey(req)), statusCode, duration, c.SlowRequestThreshold)
+	if timings != nil {
+		msg += fmt.Sprintf(" dns=%s connect=%s tls=%s wait_conn=%s ttfb=%s download=%s reused=%t",
+			timings.DNS, timings.Connect, timings.TLSHandshake, timings.WaitForConn,
+			timings.FirstByte, timings.Download, timings.ConnReused)
+	}
+	c.logger().Infof("%s", msg)
+}
+
+// redactHeaders menyalin header dengan nilai rahasia disamarkan.
+func (c *HttpRequest) redactHeaders(header http.Header, apiKey *ApiKeyAuth) http.Header {
+	redacted := make(http.Header, len(header))
+	for k, v := range header {
+		redacted[k] = c.redactHeader(k, v, apiKey)
+	}
+	return redacted
+}
+
+func (c *HttpRequest) writeHeaders(b *strings.Builder, header http.Header, apiKey *ApiKeyAuth) {
+	for k, v := range header {
+		fmt.Fprintf(b, "  %s: %s\n", k, strings.Join(c.redactHeader(k, v, apiKey), ", "))
+	}
+}
+
+type apiKeyContextKey struct{}
+
+// requestApiKey mengembalikan ApiKey yang dipasang buildRequest ke req (per
+// request, HostConfig, atau client), supaya nama header/query custom-nya
+// ikut disamarkan.
+func (c *HttpRequest) requestApiKey(req *http.Request) *ApiKeyAuth {
+	if key, ok := req.Context().Value(apiKeyContextKey{}).(*ApiKeyAuth); ok {
+		return key
+	}
+	return c.ApiKey
+}
+
+// isSensitiveHeader mengecek header bawaan, RedactHeaders, dan nama header
+// apiKey yang dipasang ke request.
+func (c *HttpRequest) isSensitiveHeader(name string, apiKey *ApiKeyAuth) bool {
+	for _, h := range defaultRedactHeaders {
+		if strings.EqualFold(h, name) {
+			return true
+		}
+	}
+	for _, h := range c.RedactHeaders {
+		if strings.EqualFold(h, name) {
+			return true
+		}
+	}
+	return apiKey != nil && apiKey.In == ApiKeyInHeader && strings.EqualFold(apiKey.Name, name)
+}
+
+// redactHeader menyamarkan nilai header rahasia. Untuk Authorization,
+// scheme (Basic/Bearer) tetap ditampilkan supaya masih berguna untuk debug.
+func (c *HttpRequest) redactHeader(name string, values []string, apiKey *ApiKeyAuth) []string {
+	if !c.isSensitiveHeader(name, apiKey) {
+		return values
+	}
+	redacted := make([]string, len(values))
+	for i, v := range values {
+		if scheme, _, ok := strings.Cut(v, " "); ok && strings.HasSuffix(strings.ToLower(name), "authorization") {
+			redacted[i] = scheme + " " + redactedValue
+		} else {
+			redacted[i] = redactedValue
+		}
+	}
+	return redacted
+}
+
+// redactURL menyamarkan password di userinfo dan query param rahasia.
+func (c *HttpRequest) redactURL(u *url.URL, apiKey *ApiKeyAuth) string {
+	redacted := *u
+	if _, hasPassword := u.User.Password(); hasPassword {
+		redacted.User = url.UserPassword(u.User.Username(), redactedValue)
+	}
+
+	names := defaultRedactQueryParams
+	if apiKey != nil && apiKey.In == ApiKeyInQuery {
+		names = append([]string{apiKey.Name}, names...)
+	}
+	query := u.Query()
+	changed := false
+	for key := range query {
+		for _, name := range names {
+			if strings.EqualFold(key, name) {
+				query[key] = []string{redactedValue}
+				changed = true
+			}
+		}
+	}
+	if changed {
+		redacted.RawQuery = query.Encode()
+	}
+	return redacted.String()
+}
+
+// debugBody menyiapkan body untuk output debug. Body biner diganti ringkasan
+// ukuran dan hash, body teks disamarkan lalu dipotong sesuai MaxDebugBodyBytes.
+func (c *HttpRequest) debugBody(body []byte) string {
+	if isBinaryBody(body) {
+		return fmt.Sprintf("[binary body: %d bytes, sha256=%x]", len(body), sha256.Sum256(body))
+	}
+	text := c.redactBody(body)
+	if c.MaxDebugBodyBytes <= 0 || len(text) <= c.MaxDebugBodyBytes {
+		return text
+	}
+
+	// Potong di awal rune supaya output tetap UTF-8 valid
+	cut := c.MaxDebugBodyBytes
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s... [truncated: %d bytes, sha256=%x]", text[:cut], len(body), sha256.Sum256(body))
+}
+
+// isBinaryBody menganggap body biner jika bukan UTF-8 valid atau berisi byte NUL.
+func isBinaryBody(body []byte) bool {
+	return !utf8.Valid(body) || bytes.IndexByte(body, 0) >= 0
+}
+
+// redactBody menyamarkan field JSON sesuai RedactBodyFields. Body non-JSON
+// dikembalikan apa adanya.
+func (c *HttpRequest) redactBody(body []byte) string {
+	if len(c.RedactBodyFields) == 0 {
+		return string(body)
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return string(body)
+	}
+	for _, path := range c.RedactBodyFields {
+		doc = redactJSONPath(doc, strings.Split(path, "."))
+	}
+	redacted, err := json.Marshal(doc)
+	if err != nil {
+		return string(body)
+	}
+	return string(redacted)
+}
+
+// redactJSONPath menyamarkan nilai pada path bertitik, mis. "user.password".
+// Segmen "*" cocok dengan semua key object atau semua elemen array.
+// Segmen yang tidak ketemu di object juga dicari di setiap elemen array.
+func redactJSONPath(node interface{}, path []string) interface{} {
+	if len(path) == 0 {
+		return redactedValue
+	}
+
+	switch v := node.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if path[0] == "*" || key == path[0] {
+				v[key] = redactJSONPath(child, path[1:])
+			}
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			if path[0] == "*" {
+				v[i] = redactJSONPath(child, path[1:])
+			} else {
+				v[i] = redactJSONPath(child, path)
+			}
+		}
+		return v
+	default:
+		return node
+	}
+}
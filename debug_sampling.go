@@ -0,0 +1,121 @@
+package http_request_instant
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// DebugSampler memilih request yang dicatat saat Debug aktif. Keputusan
+// diambil sekali per Request dan berlaku untuk semua percobaannya.
+type DebugSampler func(req *http.Request) bool
+
+// SampleRate memilih sekitar rate (0..1) dari semua request secara acak.
+func SampleRate(rate float64) DebugSampler {
+	return func(req *http.Request) bool {
+		return rand.Float64() < rate
+	}
+}
+
+// SampleHosts memilih request ke salah satu host (tanpa port).
+func SampleHosts(hosts ...string) DebugSampler {
+	return func(req *http.Request) bool {
+		for _, host := range hosts {
+			if strings.EqualFold(req.URL.Hostname(), host) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// SamplePathPrefix memilih request yang path-nya diawali salah satu prefix.
+func SamplePathPrefix(prefixes ...string) DebugSampler {
+	return func(req *http.Request) bool {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(req.URL.Path, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// SampleHeader memilih request yang membawa header name. Jika value tidak
+// kosong, nilai header juga harus sama.
+func SampleHeader(name, value string) DebugSampler {
+	return func(req *http.Request) bool {
+		v := req.Header.Get(name)
+		if value == "" {
+			return v != ""
+		}
+		return v == value
+	}
+}
+
+// SampleAny memilih request jika salah satu sampler memilihnya.
+func SampleAny(samplers ...DebugSampler) DebugSampler {
+	return func(req *http.Request) bool {
+		for _, sample := range samplers {
+			if sample(req) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// SampleAll memilih request jika semua sampler memilihnya.
+func SampleAll(samplers ...DebugSampler) DebugSampler {
+	return func(req *http.Request) bool {
+		for _, sample := range samplers {
+			if !sample(req) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// debugDecision menyimpan hasil DebugSampler atau RequestOptions.Debug
+// untuk satu panggilan Request.
+type debugDecision struct {
+	once     sync.Once
+	sampled  bool
+	override bool // sampled berasal dari RequestOptions.Debug
+}
+
+type debugDecisionKey struct{}
+
+// withDebugDecision menyiapkan keputusan debug untuk satu Request.
+func (c *HttpRequest) withDebugDecision(ctx context.Context, options RequestOptions) context.Context {
+	if options.Debug != nil {
+		return context.WithValue(ctx, debugDecisionKey{}, &debugDecision{sampled: *options.Debug, override: true})
+	}
+	if c.DebugSampler == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, debugDecisionKey{}, &debugDecision{})
+}
+
+// debugEnabled mengecek apakah output debug dicatat untuk req.
+// RequestOptions.Debug menimpa Debug dan DebugSampler milik client.
+func (c *HttpRequest) debugEnabled(ctx context.Context, req *http.Request) bool {
+	decision, ok := ctx.Value(debugDecisionKey{}).(*debugDecision)
+	if ok && decision.override {
+		return decision.sampled
+	}
+	if !c.Debug {
+		return false
+	}
+	if c.DebugSampler == nil {
+		return true
+	}
+	if !ok {
+		return c.DebugSampler(req)
+	}
+	decision.once.Do(func() { decision.sampled = c.DebugSampler(req) })
+	return decision.sampled
+}
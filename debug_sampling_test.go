@@ -0,0 +1,105 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDebugSamplerSelectsRequests(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	logger := &recordLogger{}
+	client := NewHttpRequest()
+	client.SetDebug(true)
+	client.Logger = logger
+	client.DebugSampler = SampleAny(SamplePathPrefix("/orders"), SampleHeader("X-Debug", "1"))
+
+	for _, options := range []RequestOptions{
+		{Method: "GET", URL: ts.URL + "/orders/1"},
+		{Method: "GET", URL: ts.URL + "/users/1"},
+		{Method: "GET", URL: ts.URL + "/users/2", Headers: map[string]string{"X-Debug": "1"}},
+	} {
+		if _, err := client.Request(context.TODO(), options); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	joined := strings.Join(logger.entries, "\n")
+	if len(logger.entries) != 4 || !strings.Contains(joined, "/orders/1") || !strings.Contains(joined, "/users/2") ||
+		strings.Contains(joined, "/users/1") {
+		t.Errorf("unexpected sampled debug output: %q", logger.entries)
+	}
+}
+
+func TestDebugSamplerDecidesOncePerRequest(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	calls := 0
+	logger := &recordLogger{}
+	client := NewHttpRequest()
+	client.SetDebug(true)
+	client.Logger = logger
+	client.DebugSampler = func(req *http.Request) bool {
+		calls++
+		return true
+	}
+	client.OnUnauthorized = func(ctx context.Context, options *RequestOptions) error {
+		options.Headers["Authorization"] = "Bearer refreshed"
+		return nil
+	}
+
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 || len(logger.entries) != 4 {
+		t.Errorf("expected one sampling decision covering both attempts, got %d calls and %d entries", calls, len(logger.entries))
+	}
+
+	if SampleRate(0)(&http.Request{}) || !SampleRate(1)(&http.Request{}) {
+		t.Errorf("unexpected SampleRate bounds")
+	}
+}
+
+func TestPerRequestDebugOverride(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	logger := &recordLogger{}
+	client := NewHttpRequest()
+	client.Logger = logger
+
+	on, off := true, false
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL + "/traced", Debug: &on}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL + "/plain"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(logger.entries) != 2 || !strings.Contains(logger.entries[0], "/traced") {
+		t.Errorf("expected debug output only for overridden request, got %q", logger.entries)
+	}
+
+	logger.entries = nil
+	client.SetDebug(true)
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL + "/noisy", Debug: &off}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(logger.entries) != 0 {
+		t.Errorf("expected per-request override to silence debug, got %q", logger.entries)
+	}
+}
@@ -0,0 +1,187 @@
+package http_request_instant
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// captureStdout menangkap output fmt.Print* selama fn berjalan.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+		done <- buf.String()
+	}()
+
+	fn()
+	w.Close()
+	return <-done
+}
+
+func TestDebugRedaction(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=abc123")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"token":"resp-secret","items":[{"secret":"s1"},{"secret":"s2"}]}`))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.SetDebug(true)
+	client.RedactHeaders = []string{"X-Session"}
+	client.RedactBodyFields = []string{"password", "token", "items.*.secret"}
+
+	output := captureStdout(t, func() {
+		_, err := client.Request(context.TODO(), RequestOptions{
+			Method:      "POST",
+			URL:         ts.URL + "?api_key=query-secret&page=1",
+			BearerToken: "bearer-secret",
+			Headers:     map[string]string{"X-Session": "header-secret", "Cookie": "a=cookie-secret"},
+			RequestBody: map[string]string{"user": "alice", "password": "body-secret"},
+		})
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	for _, secret := range []string{"query-secret", "bearer-secret", "header-secret", "cookie-secret", "body-secret", "abc123", "resp-secret", "s1", "s2"} {
+		if strings.Contains(output, secret) {
+			t.Errorf("debug output leaks %q:\n%s", secret, output)
+		}
+	}
+	for _, expected := range []string{"Bearer [REDACTED]", `"user":"alice"`, "page=1"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected debug output to contain %q:\n%s", expected, output)
+		}
+	}
+}
+
+func TestDebugRedactsAppliedApiKey(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.SetDebug(true)
+	client.SetHostConfig("127.0.0.1", HostConfig{ApiKey: &ApiKeyAuth{Name: "sig", Value: "host-secret", In: ApiKeyInQuery}})
+
+	output := captureStdout(t, func() {
+		// ApiKey per request dengan nama header custom
+		_, err := client.Request(context.TODO(), RequestOptions{
+			Method: "GET",
+			URL:    "http://localhost:" + ts.URL[strings.LastIndex(ts.URL, ":")+1:],
+			ApiKey: &ApiKeyAuth{Name: "X-Partner-Token", Value: "request-secret"},
+		})
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		// ApiKey HostConfig dengan nama query param custom
+		if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	for _, secret := range []string{"request-secret", "host-secret"} {
+		if strings.Contains(output, secret) {
+			t.Errorf("debug output leaks %q:\n%s", secret, output)
+		}
+	}
+	if !strings.Contains(output, "X-Partner-Token: [REDACTED]") {
+		t.Errorf("expected custom API key header to be redacted:\n%s", output)
+	}
+}
+
+func TestSlowRequestWarningWithoutDebug(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(30 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	logger := &recordLogger{}
+	client := NewHttpRequest()
+	client.Logger = logger
+	client.SlowRequestThreshold = 20 * time.Millisecond
+
+	for _, path := range []string{"/fast", "/slow"} {
+		resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL + path + "?token=abc"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Timings != nil {
+			t.Errorf("expected no Timings on response without CaptureTimings")
+		}
+	}
+
+	if len(logger.entries) != 1 {
+		t.Fatalf("expected exactly one slow warning, got %q", logger.entries)
+	}
+	entry := logger.entries[0]
+	if !strings.HasPrefix(entry, "INFO === [HTTP SLOW] ===") || !strings.Contains(entry, "/slow") ||
+		!strings.Contains(entry, "ttfb=") || strings.Contains(entry, "abc") {
+		t.Errorf("unexpected slow warning: %q", entry)
+	}
+
+	var buf bytes.Buffer
+	client.Logger = NewJSONLogger(&buf)
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL + "/slow"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, `"event":"slow_request"`) || !strings.Contains(out, `"level":"warn"`) ||
+		!strings.Contains(out, `"first_byte"`) {
+		t.Errorf("unexpected JSON slow event: %s", out)
+	}
+}
+
+func TestDebugBodyTruncationAndBinarySummary(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write([]byte{0x89, 'P', 'N', 'G', 0x00, 0xff})
+	}))
+	defer ts.Close()
+
+	logger := &recordLogger{}
+	client := NewHttpRequest()
+	client.SetDebug(true)
+	client.Logger = logger
+	client.MaxDebugBodyBytes = 16
+
+	_, err := client.Request(context.TODO(), RequestOptions{
+		Method:      "POST",
+		URL:         ts.URL,
+		RequestBody: strings.Repeat("a", 1000),
+		ContentType: "text/plain",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(logger.entries) != 2 {
+		t.Fatalf("expected request and response output, got %q", logger.entries)
+	}
+	if req := logger.entries[0]; !strings.Contains(req, "Body: "+strings.Repeat("a", 16)+"... [truncated: 1000 bytes, sha256=") ||
+		strings.Contains(req, strings.Repeat("a", 17)) {
+		t.Errorf("expected truncated request body, got %q", req)
+	}
+	if resp := logger.entries[1]; !strings.Contains(resp, "Body: [binary body: 6 bytes, sha256=") {
+		t.Errorf("expected binary summary, got %q", resp)
+	}
+}
@@ -0,0 +1,215 @@
+package http_request_instant
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// diskCacheExt adalah ekstensi file entry di direktori DiskCacheStore.
+const diskCacheExt = ".cache.json"
+
+// DiskCacheStore adalah CacheStore persisten di satu direktori, satu file
+// per entry, sehingga cache tetap terpakai antar eksekusi CLI. Jika total
+// ukuran file melewati batas, entry yang paling lama tidak dipakai dihapus.
+// Beberapa proses boleh memakai direktori yang sama; file ditulis atomik.
+type DiskCacheStore struct {
+	dir      string
+	maxBytes int64
+
+	mu    sync.Mutex
+	files map[string]diskCacheFile // nama file -> info
+	size  int64
+}
+
+type diskCacheFile struct {
+	size    int64
+	lastUse time.Time
+}
+
+// diskCacheRecord adalah isi satu file entry.
+type diskCacheRecord struct {
+	Key   string      `json:"key"`
+	Entry *CacheEntry `json:"entry"`
+}
+
+// NewDiskCacheStore membuka (atau membuat) direktori cache dir. maxBytes
+// adalah batas total ukuran file; 0 berarti tidak dibatasi.
+func NewDiskCacheStore(dir string, maxBytes int64) (*DiskCacheStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("disk cache: %w", err)
+	}
+	s := &DiskCacheStore{dir: dir, maxBytes: maxBytes}
+	if err := s.scan(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Get mengimplementasikan CacheStore. Entry yang rusak dihapus dan
+// dianggap tidak ada.
+func (s *DiskCacheStore) Get(key string) (*CacheEntry, bool) {
+	name := diskCacheName(key)
+	path := filepath.Join(s.dir, name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var record diskCacheRecord
+	if err := json.Unmarshal(data, &record); err != nil || record.Key != key || record.Entry == nil {
+		s.Delete(key)
+		return nil, false
+	}
+
+	// mtime dipakai sebagai waktu terakhir dipakai untuk eviction antar proses
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	s.mu.Lock()
+	s.track(name, int64(len(data)), now)
+	s.mu.Unlock()
+	return record.Entry, true
+}
+
+// Set mengimplementasikan CacheStore. Kegagalan menulis diabaikan karena
+// cache hanya optimasi.
+func (s *DiskCacheStore) Set(key string, entry *CacheEntry) {
+	data, err := json.Marshal(diskCacheRecord{Key: key, Entry: entry})
+	if err != nil {
+		return
+	}
+	if s.maxBytes > 0 && int64(len(data)) > s.maxBytes {
+		return
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	name := diskCacheName(key)
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(s.dir, name))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.track(name, int64(len(data)), time.Now())
+	if s.maxBytes > 0 && s.size > s.maxBytes {
+		s.evict()
+	}
+}
+
+// Delete mengimplementasikan CacheStore.
+func (s *DiskCacheStore) Delete(key string) {
+	name := diskCacheName(key)
+	os.Remove(filepath.Join(s.dir, name))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.untrack(name)
+}
+
+// Size mengembalikan total ukuran file entry yang diketahui proses ini.
+func (s *DiskCacheStore) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size
+}
+
+// Clear menghapus semua entry.
+func (s *DiskCacheStore) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.scanLocked(); err != nil {
+		return err
+	}
+	for name := range s.files {
+		if err := os.Remove(filepath.Join(s.dir, name)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("disk cache: %w", err)
+		}
+	}
+	s.files, s.size = make(map[string]diskCacheFile), 0
+	return nil
+}
+
+func (s *DiskCacheStore) scan() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.scanLocked()
+}
+
+// scanLocked membaca ulang isi direktori, termasuk file dari proses lain.
+func (s *DiskCacheStore) scanLocked() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("disk cache: %w", err)
+	}
+	s.files, s.size = make(map[string]diskCacheFile), 0
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), diskCacheExt) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		s.track(entry.Name(), info.Size(), info.ModTime())
+	}
+	return nil
+}
+
+// evict menghapus file yang paling lama tidak dipakai sampai total ukuran
+// di bawah batas. Dipanggil dengan mu terkunci.
+func (s *DiskCacheStore) evict() {
+	if err := s.scanLocked(); err != nil {
+		return
+	}
+	names := make([]string, 0, len(s.files))
+	for name := range s.files {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return s.files[names[i]].lastUse.Before(s.files[names[j]].lastUse)
+	})
+	for _, name := range names {
+		if s.size <= s.maxBytes {
+			return
+		}
+		if err := os.Remove(filepath.Join(s.dir, name)); err != nil && !os.IsNotExist(err) {
+			continue
+		}
+		s.untrack(name)
+	}
+}
+
+func (s *DiskCacheStore) track(name string, size int64, lastUse time.Time) {
+	s.untrack(name)
+	s.files[name] = diskCacheFile{size: size, lastUse: lastUse}
+	s.size += size
+}
+
+func (s *DiskCacheStore) untrack(name string) {
+	if file, ok := s.files[name]; ok {
+		s.size -= file.size
+		delete(s.files, name)
+	}
+}
+
+// diskCacheName mengubah cache key menjadi nama file yang aman.
+func diskCacheName(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:]) + diskCacheExt
+}
@@ -0,0 +1,103 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiskCacheStorePersistsAcrossInstances(t *testing.T) {
+	var hits int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("Cache-Control", "max-age=300")
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	for i := 0; i < 2; i++ {
+		// Store dan client baru setiap iterasi, seperti eksekusi CLI terpisah
+		store, err := NewDiskCacheStore(dir, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		client := NewHttpRequest()
+		client.Use(CacheMiddleware(CacheOptions{Store: store}))
+		resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.FromCache != (i == 1) || string(resp.Body) != `{"ok":true}` {
+			t.Errorf("run %d: FromCache %v, body %q", i, resp.FromCache, resp.Body)
+		}
+	}
+	if hits != 1 {
+		t.Errorf("expected 1 origin hit, got %d", hits)
+	}
+}
+
+func TestDiskCacheStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewDiskCacheStore(dir, 1000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entry := func() *CacheEntry {
+		return &CacheEntry{StatusCode: 200, Body: []byte(strings.Repeat("x", 200)), ResponseTime: time.Now()}
+	}
+
+	store.Set("a", entry())
+	store.Set("b", entry())
+	// Mundurkan mtime supaya urutan pemakaian jelas walau resolusi waktu kasar
+	past := time.Now().Add(-time.Hour)
+	os.Chtimes(filepath.Join(dir, diskCacheName("a")), past, past)
+	os.Chtimes(filepath.Join(dir, diskCacheName("b")), past.Add(time.Minute), past.Add(time.Minute))
+	if _, ok := store.Get("a"); !ok {
+		t.Fatalf("expected a cached")
+	}
+	store.Set("c", entry())
+
+	if _, ok := store.Get("b"); ok {
+		t.Errorf("expected least recently used entry b evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := store.Get(key); !ok {
+			t.Errorf("expected %s kept", key)
+		}
+	}
+	if size := store.Size(); size == 0 || size > 1000 {
+		t.Errorf("unexpected size %d", size)
+	}
+}
+
+func TestDiskCacheStoreIgnoresCorruptFiles(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewDiskCacheStore(dir, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	path := filepath.Join(dir, diskCacheName("key"))
+	if err := os.WriteFile(path, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.Get("key"); ok {
+		t.Errorf("expected corrupt entry treated as miss")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected corrupt file removed")
+	}
+
+	store.Set("key", &CacheEntry{StatusCode: 200})
+	if err := store.Clear(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := store.Get("key"); ok || store.Size() != 0 {
+		t.Errorf("expected empty store after Clear")
+	}
+}
@@ -0,0 +1,173 @@
+package http_request_instant
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DNSCacheOptions menyimpan konfigurasi cache DNS in-process.
+type DNSCacheOptions struct {
+	TTL         time.Duration // Lama hasil lookup disimpan, default 1 menit
+	NegativeTTL time.Duration // Lama host tidak ditemukan (NXDOMAIN) disimpan, default 10 detik; < 0 menonaktifkan
+	Resolver    *net.Resolver // Optional: default net.DefaultResolver
+}
+
+// EnableDNSCache menyimpan hasil lookup DNS di memori selama TTL, sehingga
+// koneksi baru ke host yang sama tidak menunggu resolver. Lookup konkuren
+// untuk host yang sama digabung menjadi satu. Alamat dicoba berurutan sampai
+// salah satu berhasil di-dial. Error jika transport client bukan *http.Transport.
+func (c *HttpRequest) EnableDNSCache(options DNSCacheOptions) error {
+	t, err := c.transport()
+	if err != nil {
+		return err
+	}
+	if options.TTL <= 0 {
+		options.TTL = time.Minute
+	}
+	if options.NegativeTTL == 0 {
+		options.NegativeTTL = 10 * time.Second
+	}
+	if options.Resolver == nil {
+		options.Resolver = net.DefaultResolver
+	}
+	c.dnsCache = &dnsCache{
+		options:    options,
+		lookupHost: options.Resolver.LookupHost,
+		now:        time.Now,
+		entries:    make(map[string]*dnsCacheEntry),
+	}
+	t.DialContext = c.dialContext
+	return nil
+}
+
+// FlushDNSCache menghapus semua hasil lookup yang tersimpan.
+func (c *HttpRequest) FlushDNSCache() {
+	if c.dnsCache != nil {
+		c.dnsCache.flush()
+	}
+}
+
+type dnsCache struct {
+	options    DNSCacheOptions
+	lookupHost func(ctx context.Context, host string) ([]string, error)
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*dnsCacheEntry
+}
+
+type dnsCacheEntry struct {
+	ready   chan struct{} // Ditutup setelah lookup selesai
+	addrs   []string
+	err     error
+	expires time.Time
+}
+
+// lookup mengembalikan alamat host dari cache, atau menjalankan satu lookup
+// bersama untuk semua pemanggil jika belum ada atau sudah kedaluwarsa.
+func (d *dnsCache) lookup(ctx context.Context, host string) ([]string, error) {
+	d.mu.Lock()
+	entry, ok := d.entries[host]
+	if ok {
+		select {
+		case <-entry.ready:
+			if d.now().After(entry.expires) {
+				ok = false
+			}
+		default:
+		}
+	}
+	if !ok {
+		entry = &dnsCacheEntry{ready: make(chan struct{})}
+		d.entries[host] = entry
+		go d.resolve(host, entry)
+	}
+	d.mu.Unlock()
+
+	select {
+	case <-entry.ready:
+		return entry.addrs, entry.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (d *dnsCache) resolve(host string, entry *dnsCacheEntry) {
+	// Lookup tidak ikut dibatalkan ctx pemanggil pertama karena hasilnya
+	// dipakai bersama; resolver tetap punya timeout sendiri
+	entry.addrs, entry.err = d.lookupHost(context.Background(), host)
+	ttl := d.options.TTL
+	var dnsErr *net.DNSError
+	if entry.err != nil {
+		ttl = 0
+		if errors.As(entry.err, &dnsErr) && dnsErr.IsNotFound && d.options.NegativeTTL > 0 {
+			ttl = d.options.NegativeTTL
+		}
+	}
+	entry.expires = d.now().Add(ttl)
+	close(entry.ready)
+
+	if ttl == 0 {
+		d.mu.Lock()
+		if d.entries[host] == entry {
+			delete(d.entries, host)
+		}
+		d.mu.Unlock()
+	}
+}
+
+func (d *dnsCache) flush() {
+	d.mu.Lock()
+	d.entries = make(map[string]*dnsCacheEntry)
+	d.mu.Unlock()
+}
+
+// dial me-resolve host lewat cache lalu mencoba setiap alamat berurutan.
+// Alamat IP dan network selain TCP/UDP di-dial langsung.
+func (d *dnsCache) dial(ctx context.Context, dialer *net.Dialer, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || net.ParseIP(host) != nil || !strings.HasPrefix(network, "tcp") && !strings.HasPrefix(network, "udp") {
+		return dialer.DialContext(ctx, network, addr)
+	}
+	addrs, err := d.lookup(ctx, host)
+	if err != nil {
+		return nil, &net.OpError{Op: "dial", Net: network, Err: err}
+	}
+
+	var firstErr error
+	for _, ip := range addrs {
+		if !ipMatchesNetwork(ip, network) {
+			continue
+		}
+		conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+		if err == nil {
+			return conn, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if firstErr == nil {
+		firstErr = &net.OpError{Op: "dial", Net: network, Err: &net.AddrError{Err: "no suitable address found", Addr: host}}
+	}
+	return nil, firstErr
+}
+
+// ipMatchesNetwork mengecek keluarga alamat untuk network "tcp4"/"tcp6".
+func ipMatchesNetwork(ip, network string) bool {
+	switch {
+	case strings.HasSuffix(network, "4"):
+		return !strings.Contains(ip, ":")
+	case strings.HasSuffix(network, "6"):
+		return strings.Contains(ip, ":")
+	default:
+		return true
+	}
+}
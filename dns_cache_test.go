@@ -0,0 +1,74 @@
+package http_request_instant
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDNSCache(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Host))
+	}))
+	defer ts.Close()
+	_, port, _ := net.SplitHostPort(strings.TrimPrefix(ts.URL, "http://"))
+
+	client := NewHttpRequest()
+	if err := client.EnableDNSCache(DNSCacheOptions{TTL: time.Minute}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Now()
+	var lookups atomic.Int32
+	client.dnsCache.now = func() time.Time { return now }
+	client.dnsCache.lookupHost = func(ctx context.Context, host string) ([]string, error) {
+		lookups.Add(1)
+		if host == "missing.test" {
+			return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+		}
+		// Alamat pertama tidak bisa di-dial, dial lanjut ke alamat berikutnya
+		return []string{"127.0.0.2", "127.0.0.1"}, nil
+	}
+	// Setiap request membuka koneksi baru supaya dial selalu terjadi
+	transport, _ := client.transport()
+	transport.DisableKeepAlives = true
+	client.netDialer().Timeout = 200 * time.Millisecond
+
+	get := func(host string) error {
+		_, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: "http://" + host + ":" + port + "/"})
+		return err
+	}
+	for i := 0; i < 3; i++ {
+		if err := get("api.test"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if lookups.Load() != 1 {
+		t.Errorf("expected 1 lookup within TTL, got %d", lookups.Load())
+	}
+
+	now = now.Add(2 * time.Minute)
+	get("api.test")
+	if lookups.Load() != 2 {
+		t.Errorf("expected expired entry looked up again, got %d", lookups.Load())
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := get("missing.test"); Category(err) != CategoryDNS {
+			t.Errorf("expected dns error, got %v", err)
+		}
+	}
+	if lookups.Load() != 3 {
+		t.Errorf("expected NXDOMAIN cached, got %d lookups", lookups.Load())
+	}
+
+	client.FlushDNSCache()
+	get("api.test")
+	if lookups.Load() != 4 {
+		t.Errorf("expected lookup after flush, got %d", lookups.Load())
+	}
+}
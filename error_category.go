@@ -0,0 +1,171 @@
+package http_request_instant
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// ErrorCategory adalah kelompok penyebab kegagalan request, untuk dashboard
+// dan logika retry.
+type ErrorCategory string
+
+const (
+	CategoryNone     ErrorCategory = ""         // Tidak ada error
+	CategoryDNS      ErrorCategory = "dns"      // Resolusi nama gagal
+	CategoryConnect  ErrorCategory = "connect"  // TCP connect ditolak atau gagal
+	CategoryTLS      ErrorCategory = "tls"      // Handshake atau verifikasi certificate gagal
+	CategoryTimeout  ErrorCategory = "timeout"  // Deadline ctx atau timeout client/transport
+	CategoryCanceled ErrorCategory = "canceled" // ctx dibatalkan pemanggil
+	CategoryHTTP4xx  ErrorCategory = "http_4xx" // Server membalas 4xx
+	CategoryHTTP5xx  ErrorCategory = "http_5xx" // Server membalas 5xx
+	CategoryDecode   ErrorCategory = "decode"   // Body response tidak bisa di-decode
+	CategoryPolicy   ErrorCategory = "policy"   // Request ditolak OutboundPolicy
+	CategoryOther    ErrorCategory = "other"    // Penyebab lain
+)
+
+// Retryable mengecek apakah kategori umumnya aman untuk dicoba ulang.
+func (c ErrorCategory) Retryable() bool {
+	switch c {
+	case CategoryDNS, CategoryConnect, CategoryTimeout, CategoryHTTP5xx:
+		return true
+	default:
+		return false
+	}
+}
+
+// CategorizedError diimplementasikan error yang tahu kategorinya sendiri.
+type CategorizedError interface {
+	error
+	Category() ErrorCategory
+}
+
+// Category mengklasifikasikan err dari Request atau ApiResponse.Err.
+// Error yang dibungkus (mis. *RequestIDError) tetap diklasifikasikan
+// berdasarkan penyebab aslinya.
+func Category(err error) ErrorCategory {
+	if err == nil {
+		return CategoryNone
+	}
+	var categorized CategorizedError
+	if errors.As(err, &categorized) {
+		return categorized.Category()
+	}
+	return classifyError(err)
+}
+
+// classifyError menentukan kategori error mentah dari http.Client.
+func classifyError(err error) ErrorCategory {
+	var netErr net.Error
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	var recordErr tls.RecordHeaderError
+	var alertErr tls.AlertError
+	var verifyErr *tls.CertificateVerificationError
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidCert x509.CertificateInvalidError
+	var pinErr *PinMismatchError
+	var policyErr *PolicyViolation
+	var ssrfErr *SSRFError
+
+	switch {
+	case errors.As(err, &policyErr), errors.As(err, &ssrfErr):
+		return CategoryPolicy
+	case errors.Is(err, context.Canceled):
+		return CategoryCanceled
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return CategoryTimeout
+	case errors.As(err, &dnsErr):
+		return CategoryDNS
+	case errors.As(err, &recordErr), errors.As(err, &alertErr), errors.As(err, &verifyErr),
+		errors.As(err, &unknownAuthority), errors.As(err, &hostnameErr), errors.As(err, &invalidCert),
+		errors.As(err, &pinErr):
+		return CategoryTLS
+	case errors.As(err, &opErr) && opErr.Op == "dial":
+		return CategoryConnect
+	default:
+		return CategoryOther
+	}
+}
+
+// TransportError membungkus error dari http.Client beserta kategorinya.
+// Error() sama dengan error aslinya, dan errors.Is/As tetap menjangkau
+// error asli (mis. *url.Error, context.DeadlineExceeded).
+type TransportError struct {
+	Kind ErrorCategory
+	Err  error
+}
+
+func (e *TransportError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// Category mengimplementasikan CategorizedError.
+func (e *TransportError) Category() ErrorCategory {
+	return e.Kind
+}
+
+// StatusError merepresentasikan response dengan status 4xx atau 5xx.
+type StatusError struct {
+	StatusCode int
+	Body       []byte
+	Headers    map[string]string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("http status %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Category mengimplementasikan CategorizedError.
+func (e *StatusError) Category() ErrorCategory {
+	if e.StatusCode >= 500 {
+		return CategoryHTTP5xx
+	}
+	return CategoryHTTP4xx
+}
+
+// DecodeError dikembalikan jika body response gagal di-decode ke
+// ResponseTarget atau oleh PayloadCodec.
+type DecodeError struct {
+	ContentType string
+	Err         error
+}
+
+func (e *DecodeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Category mengimplementasikan CategorizedError.
+func (e *DecodeError) Category() ErrorCategory {
+	return CategoryDecode
+}
+
+// Err mengembalikan *StatusError jika status response 4xx atau 5xx, selain itu nil.
+func (r *ApiResponse) Err() error {
+	if r.StatusCode < 400 {
+		return nil
+	}
+	return &StatusError{StatusCode: r.StatusCode, Body: r.Body, Headers: r.Headers}
+}
+
+// wrapTransportError membungkus error http.Client dengan kategorinya.
+func wrapTransportError(err error) error {
+	var transportErr *TransportError
+	if errors.As(err, &transportErr) {
+		return err
+	}
+	return &TransportError{Kind: classifyError(err), Err: err}
+}
@@ -0,0 +1,85 @@
+package http_request_instant
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestErrorCategories(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow":
+			time.Sleep(100 * time.Millisecond)
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/broken":
+			w.WriteHeader(http.StatusBadGateway)
+		case "/invalid":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte("{not json"))
+		}
+	}))
+	defer ts.Close()
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer tlsServer.Close()
+
+	client := NewHttpRequest()
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	timeout, cancelTimeout := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelTimeout()
+
+	tests := []struct {
+		ctx  context.Context
+		url  string
+		want ErrorCategory
+	}{
+		{context.TODO(), "http://127.0.0.1:1", CategoryConnect},
+		{context.TODO(), "http://nonexistent.invalid", CategoryDNS},
+		{context.TODO(), tlsServer.URL, CategoryTLS},
+		{canceled, ts.URL, CategoryCanceled},
+		{timeout, ts.URL + "/slow", CategoryTimeout},
+		{context.TODO(), ts.URL + "/invalid", CategoryDecode},
+	}
+	for _, tt := range tests {
+		var out map[string]any
+		_, err := client.Request(tt.ctx, RequestOptions{Method: "GET", URL: tt.url, ResponseTarget: &out})
+		if got := Category(err); got != tt.want {
+			t.Errorf("%s: expected category %q, got %q (%v)", tt.url, tt.want, got, err)
+		}
+	}
+
+	for path, want := range map[string]ErrorCategory{"/missing": CategoryHTTP4xx, "/broken": CategoryHTTP5xx, "/": CategoryNone} {
+		resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL + path})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := Category(resp.Err()); got != want {
+			t.Errorf("%s: expected category %q, got %q", path, want, got)
+		}
+	}
+
+	var statusErr *StatusError
+	resp, _ := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL + "/broken"})
+	if err := resp.Err(); !errors.As(err, &statusErr) || statusErr.StatusCode != 502 || !Category(err).Retryable() {
+		t.Errorf("expected retryable *StatusError, got %v", err)
+	}
+}
+
+func TestCategoryThroughWrappers(t *testing.T) {
+	client := NewHttpRequest()
+	client.SetRequestID(RequestIDOptions{WrapErrors: true})
+
+	_, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: "http://127.0.0.1:1"})
+	var transportErr *TransportError
+	if !errors.As(err, &transportErr) || Category(err) != CategoryConnect {
+		t.Errorf("expected connect *TransportError through RequestIDError, got %v", err)
+	}
+	if Category(errors.New("boom")) != CategoryOther {
+		t.Errorf("expected plain errors to be CategoryOther")
+	}
+}
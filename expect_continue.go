@@ -0,0 +1,47 @@
+package http_request_instant
+
+import (
+	"net/http"
+	"time"
+)
+
+// ExpectContinueOptions menyimpan konfigurasi Expect: 100-continue.
+type ExpectContinueOptions struct {
+	// Body sebesar ini atau lebih (atau yang panjangnya tidak diketahui)
+	// dikirim dengan Expect: 100-continue. Default 1 MiB.
+	MinBodyBytes int64
+
+	// Lama menunggu 100 Continue sebelum body tetap dikirim, untuk server
+	// yang tidak mendukung Expect. Default 1 detik.
+	Timeout time.Duration
+}
+
+// SetExpectContinue mengirim Expect: 100-continue untuk upload besar,
+// sehingga server bisa menolak (mis. 401 atau 413) berdasarkan header
+// sebelum body dikirim. Error jika transport client bukan *http.Transport.
+func (c *HttpRequest) SetExpectContinue(options ExpectContinueOptions) error {
+	t, err := c.transport()
+	if err != nil {
+		return err
+	}
+	if options.MinBodyBytes <= 0 {
+		options.MinBodyBytes = 1 << 20
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = time.Second
+	}
+	t.ExpectContinueTimeout = options.Timeout
+	c.expect100 = &options
+	return nil
+}
+
+// applyExpectContinue memasang header Expect jika body cukup besar.
+func (c *HttpRequest) applyExpectContinue(req *http.Request) {
+	if c.expect100 == nil || req.Body == nil || req.Body == http.NoBody {
+		return
+	}
+	// ContentLength 0 dengan body berarti panjang tidak diketahui (streaming)
+	if req.ContentLength <= 0 || req.ContentLength >= c.expect100.MinBodyBytes {
+		req.Header.Set("Expect", "100-continue")
+	}
+}
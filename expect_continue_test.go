@@ -0,0 +1,56 @@
+package http_request_instant
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSetExpectContinue(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer ok" {
+			// Tolak tanpa membaca body: server tidak mengirim 100 Continue
+			w.Header().Set("Connection", "close")
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(r.Header.Get("Expect")))
+			return
+		}
+		n, _ := io.Copy(io.Discard, r.Body)
+		fmt.Fprintf(w, "%s %d", r.Header.Get("Expect"), n)
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	if err := client.EnableByteAccounting(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.SetExpectContinue(ExpectContinueOptions{MinBodyBytes: 1024}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	upload := bytes.Repeat([]byte("x"), 8<<20)
+
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "PUT", URL: ts.URL, RequestBody: upload})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusUnauthorized || string(resp.Body) != "100-continue" {
+		t.Fatalf("expected 401 with Expect header, got %d %q", resp.StatusCode, resp.Body)
+	}
+	if resp.WireBytesSent >= 1<<20 {
+		t.Errorf("expected body not sent after rejection, sent %d bytes", resp.WireBytesSent)
+	}
+
+	resp, err = client.Request(context.TODO(), RequestOptions{Method: "PUT", URL: ts.URL, RequestBody: upload, BearerToken: "ok"})
+	if err != nil || string(resp.Body) != "100-continue 8388608" {
+		t.Errorf("expected accepted upload of 8 MiB, got %q, %v", resp.Body, err)
+	}
+
+	resp, err = client.Request(context.TODO(), RequestOptions{Method: "PUT", URL: ts.URL, RequestBody: "small", BearerToken: "ok"})
+	if err != nil || string(resp.Body) != " 5" {
+		t.Errorf("expected no Expect for small body, got %q, %v", resp.Body, err)
+	}
+}
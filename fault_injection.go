@@ -0,0 +1,185 @@
+package http_request_instant
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// ErrFaultInjected menandai error yang sengaja dibuat oleh FaultInjector.
+var ErrFaultInjected = errors.New("fault injected")
+
+// FaultConfig menyimpan konfigurasi fault injection (chaos mode).
+// Probabilitas bernilai 0.0 - 1.0.
+type FaultConfig struct {
+	LatencyProbability float64       // Peluang request ditambah latency
+	Latency            time.Duration // Latency tambahan
+	LatencyJitter      time.Duration // Optional: jitter acak di atas Latency
+
+	ErrorProbability float64 // Peluang response diganti 5xx
+	ErrorStatusCode  int     // Status code yang dikembalikan, default 503
+
+	ResetProbability float64 // Peluang request gagal dengan connection reset
+
+	// Optional: hanya request yang match yang bisa kena fault.
+	Match func(req *http.Request) bool
+
+	// Optional: sumber angka acak [0.0, 1.0), default math/rand.
+	Rand func() float64
+}
+
+// FaultStats menyimpan jumlah fault yang sudah diinjeksi.
+type FaultStats struct {
+	Latencies int64
+	Errors    int64
+	Resets    int64
+}
+
+// FaultInjector adalah http.RoundTripper yang menyuntikkan latency, 5xx,
+// dan connection reset secara acak sebelum meneruskan request ke transport
+// asli. Karena bekerja di level transport, retry atau circuit breaker
+// di atasnya akan melihat fault ini seperti kegagalan jaringan sungguhan.
+type FaultInjector struct {
+	next    http.RoundTripper
+	config  FaultConfig
+	enabled atomic.Bool
+
+	latencies atomic.Int64
+	errors    atomic.Int64
+	resets    atomic.Int64
+}
+
+// NewFaultInjector membungkus next (default http.DefaultTransport) dengan fault injection.
+// Injector langsung aktif.
+func NewFaultInjector(next http.RoundTripper, config FaultConfig) *FaultInjector {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if config.ErrorStatusCode == 0 {
+		config.ErrorStatusCode = http.StatusServiceUnavailable
+	}
+	if config.Rand == nil {
+		config.Rand = rand.Float64
+	}
+
+	f := &FaultInjector{next: next, config: config}
+	f.enabled.Store(true)
+	return f
+}
+
+// EnableFaultInjection membungkus transport client dengan FaultInjector.
+func (c *HttpRequest) EnableFaultInjection(config FaultConfig) *FaultInjector {
+	if c.Client == nil || c.Client.Transport == nil {
+		// pasang transport milik client supaya setter transport lain tidak mengubah global
+		_, _ = c.transport()
+	}
+	injector := NewFaultInjector(c.Client.Transport, config)
+	c.Client.Transport = injector
+	return injector
+}
+
+// SetEnabled mengaktifkan atau menonaktifkan fault injection saat runtime.
+func (f *FaultInjector) SetEnabled(enabled bool) {
+	f.enabled.Store(enabled)
+}
+
+// Stats mengembalikan jumlah fault yang sudah diinjeksi.
+func (f *FaultInjector) Stats() FaultStats {
+	return FaultStats{
+		Latencies: f.latencies.Load(),
+		Errors:    f.errors.Load(),
+		Resets:    f.resets.Load(),
+	}
+}
+
+// Unwrap mengembalikan RoundTripper yang dibungkus.
+func (f *FaultInjector) Unwrap() http.RoundTripper {
+	return f.next
+}
+
+// RoundTrip mengimplementasikan http.RoundTripper.
+func (f *FaultInjector) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f.roundTrip(f.next, req)
+}
+
+// rewrap memasang injector yang sama (config, status aktif, dan statistik)
+// di atas next.
+func (f *FaultInjector) rewrap(next http.RoundTripper) http.RoundTripper {
+	return &rewrappedTransport{next: next, roundTrip: f.roundTrip}
+}
+
+func (f *FaultInjector) roundTrip(next http.RoundTripper, req *http.Request) (*http.Response, error) {
+	if !f.enabled.Load() || (f.config.Match != nil && !f.config.Match(req)) {
+		return next.RoundTrip(req)
+	}
+
+	if f.hit(f.config.LatencyProbability) {
+		f.latencies.Add(1)
+		delay := f.config.Latency
+		if f.config.LatencyJitter > 0 {
+			delay += time.Duration(f.config.Rand() * float64(f.config.LatencyJitter))
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			if req.Body != nil {
+				_ = req.Body.Close()
+			}
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+
+	if f.hit(f.config.ResetProbability) {
+		f.resets.Add(1)
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, &injectedResetError{}
+	}
+
+	if f.hit(f.config.ErrorProbability) {
+		f.errors.Add(1)
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		body := fmt.Sprintf("fault injected: %d", f.config.ErrorStatusCode)
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", f.config.ErrorStatusCode, http.StatusText(f.config.ErrorStatusCode)),
+			StatusCode:    f.config.ErrorStatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": {"text/plain"}, "X-Fault-Injected": {"true"}},
+			Body:          io.NopCloser(strings.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	return next.RoundTrip(req)
+}
+
+func (f *FaultInjector) hit(probability float64) bool {
+	return probability > 0 && f.config.Rand() < probability
+}
+
+// injectedResetError berperilaku seperti connection reset asli:
+// errors.Is(err, syscall.ECONNRESET) dan errors.Is(err, ErrFaultInjected) sama-sama true.
+type injectedResetError struct{}
+
+func (e *injectedResetError) Error() string {
+	return "fault injected: connection reset by peer"
+}
+
+func (e *injectedResetError) Is(target error) bool {
+	return target == ErrFaultInjected || target == syscall.ECONNRESET
+}
@@ -0,0 +1,102 @@
+package http_request_instant
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestFaultInjectionError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not reach server")
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	injector := client.EnableFaultInjection(FaultConfig{ErrorProbability: 1})
+
+	resp, err := client.Request(context.Background(), RequestOptions{Method: "GET", URL: ts.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected status=503, got %d", resp.StatusCode)
+	}
+	if injector.Stats().Errors != 1 {
+		t.Errorf("expected 1 injected error, got %+v", injector.Stats())
+	}
+
+	// transport asli tetap bisa dikonfigurasi lewat wrapper
+	if err := client.SetTimeouts(Timeouts{Dial: time.Second}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestFaultInjectionReset(t *testing.T) {
+	client := NewHttpRequest()
+	client.EnableFaultInjection(FaultConfig{ResetProbability: 1})
+
+	_, err := client.Request(context.Background(), RequestOptions{Method: "GET", URL: "http://example.invalid"})
+	if !errors.Is(err, syscall.ECONNRESET) || !errors.Is(err, ErrFaultInjected) {
+		t.Fatalf("expected injected reset error, got %v", err)
+	}
+}
+
+func TestFaultInjectionLatencyAndDisable(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	injector := client.EnableFaultInjection(FaultConfig{LatencyProbability: 1, Latency: 50 * time.Millisecond})
+
+	start := time.Now()
+	if _, err := client.Request(context.Background(), RequestOptions{Method: "GET", URL: ts.URL}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if time.Since(start) < 50*time.Millisecond {
+		t.Errorf("expected injected latency, took %v", time.Since(start))
+	}
+
+	injector.SetEnabled(false)
+	if _, err := client.Request(context.Background(), RequestOptions{Method: "GET", URL: ts.URL}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if injector.Stats().Latencies != 1 {
+		t.Errorf("expected 1 injected latency, got %+v", injector.Stats())
+	}
+}
+
+// closeRecorder mencatat apakah body request sudah ditutup.
+type closeRecorder struct {
+	io.Reader
+	closed atomic.Bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed.Store(true)
+	return nil
+}
+
+func TestFaultInjectionLatencyCancelClosesBody(t *testing.T) {
+	injector := NewFaultInjector(http.DefaultTransport, FaultConfig{LatencyProbability: 1, Latency: time.Minute})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	body := &closeRecorder{Reader: strings.NewReader("payload")}
+	req, _ := http.NewRequestWithContext(ctx, "POST", "http://example.invalid", body)
+	if _, err := injector.RoundTrip(req); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled, got %v", err)
+	}
+	if !body.closed.Load() {
+		t.Errorf("expected request body to be closed")
+	}
+}
@@ -0,0 +1,271 @@
+package http_request_instant
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	googleCloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+	googleDefaultTokenURL    = "https://oauth2.googleapis.com/token"
+	googleMetadataHost       = "metadata.google.internal"
+)
+
+// GoogleCredentialsConfig menyimpan konfigurasi Application Default Credentials.
+type GoogleCredentialsConfig struct {
+	// Scope untuk access token, default cloud-platform.
+	Scopes []string
+	// Optional: jika diisi, provider mengembalikan ID token untuk audience ini
+	// (mis. URL Cloud Run atau client ID OAuth milik IAP), bukan access token.
+	Audience string
+
+	// Optional: isi file kredensial JSON. Jika kosong, dibaca dari CredentialsFile,
+	// env GOOGLE_APPLICATION_CREDENTIALS, lalu file ADC milik gcloud.
+	// Jika semuanya tidak ada, metadata server dipakai.
+	CredentialsJSON []byte
+	CredentialsFile string
+
+	// Optional: base URL metadata server, default dari env GCE_METADATA_HOST
+	// atau http://metadata.google.internal.
+	MetadataURL string
+
+	// Optional: client untuk token endpoint dan metadata server, default NewHttpRequest().
+	Client *HttpRequest
+}
+
+// GoogleTokenProvider adalah TokenProvider untuk layanan Google Cloud dan
+// layanan di belakang IAP, berbasis service account, authorized user, atau
+// metadata server GCE/GKE/Cloud Run.
+type GoogleTokenProvider struct {
+	source TokenProvider
+}
+
+// googleCredentialsFile adalah format file kredensial JSON Google.
+type googleCredentialsFile struct {
+	Type         string `json:"type"`
+	ClientEmail  string `json:"client_email"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	TokenURI     string `json:"token_uri"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// NewGoogleTokenProvider mencari kredensial sesuai urutan Application Default
+// Credentials lalu membuat GoogleTokenProvider.
+func NewGoogleTokenProvider(config GoogleCredentialsConfig) (*GoogleTokenProvider, error) {
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{googleCloudPlatformScope}
+	}
+	if config.Client == nil {
+		config.Client = NewHttpRequest()
+	}
+
+	data := config.CredentialsJSON
+	if data == nil {
+		path, err := googleCredentialsPath(config.CredentialsFile)
+		if err != nil {
+			return nil, err
+		}
+		if path != "" {
+			if data, err = os.ReadFile(path); err != nil {
+				return nil, fmt.Errorf("gcp: error read credentials file: %w", err)
+			}
+		}
+	}
+	if data == nil {
+		return &GoogleTokenProvider{source: newGoogleMetadataSource(config)}, nil
+	}
+
+	var creds googleCredentialsFile
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("gcp: error parse credentials: %w", err)
+	}
+
+	switch creds.Type {
+	case "service_account":
+		key, err := ParsePrivateKeyPEM([]byte(creds.PrivateKey))
+		if err != nil {
+			return nil, fmt.Errorf("gcp: %w", err)
+		}
+		tokenURL := creds.TokenURI
+		if tokenURL == "" {
+			tokenURL = googleDefaultTokenURL
+		}
+		claims := map[string]any{"scope": strings.Join(config.Scopes, " ")}
+		if config.Audience != "" {
+			claims = map[string]any{"target_audience": config.Audience}
+		}
+		source, err := NewJWTAssertionProvider(JWTAssertionConfig{
+			PrivateKey: key,
+			KeyID:      creds.PrivateKeyID,
+			Issuer:     creds.ClientEmail,
+			Audience:   tokenURL,
+			Claims:     claims,
+			TTL:        time.Hour,
+			TokenURL:   tokenURL,
+			Client:     config.Client,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("gcp: %w", err)
+		}
+		return &GoogleTokenProvider{source: source}, nil
+	case "authorized_user":
+		if config.Audience != "" {
+			return nil, errors.New("gcp: ID tokens require service account or metadata server credentials")
+		}
+		return &GoogleTokenProvider{source: NewOAuth2TokenProvider(OAuth2Config{
+			TokenURL:                googleDefaultTokenURL,
+			ClientID:                creds.ClientID,
+			ClientSecret:            creds.ClientSecret,
+			RefreshToken:            creds.RefreshToken,
+			ClientCredentialsInBody: true,
+			Client:                  config.Client,
+		})}, nil
+	default:
+		return nil, fmt.Errorf("gcp: unsupported credentials type %q", creds.Type)
+	}
+}
+
+// Token mengimplementasikan TokenProvider.
+func (p *GoogleTokenProvider) Token(ctx context.Context) (string, error) {
+	return p.source.Token(ctx)
+}
+
+// InvalidateToken mengimplementasikan TokenInvalidator.
+func (p *GoogleTokenProvider) InvalidateToken() {
+	if invalidator, ok := p.source.(TokenInvalidator); ok {
+		invalidator.InvalidateToken()
+	}
+}
+
+// googleCredentialsPath mengembalikan path file kredensial, atau string
+// kosong jika tidak ada sehingga metadata server dipakai.
+func googleCredentialsPath(explicit string) (string, error) {
+	if explicit != "" {
+		return explicit, nil
+	}
+	if path := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"); path != "" {
+		return path, nil
+	}
+
+	var dir string
+	if runtime.GOOS == "windows" {
+		dir = filepath.Join(os.Getenv("APPDATA"), "gcloud")
+	} else if home, err := os.UserHomeDir(); err == nil {
+		dir = filepath.Join(home, ".config", "gcloud")
+	}
+	if dir == "" {
+		return "", nil
+	}
+	path := filepath.Join(dir, "application_default_credentials.json")
+	if _, err := os.Stat(path); err != nil {
+		return "", nil
+	}
+	return path, nil
+}
+
+// googleMetadataSource mengambil token dari metadata server dan menyimpannya di cache.
+type googleMetadataSource struct {
+	config  GoogleCredentialsConfig
+	baseURL string
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+func newGoogleMetadataSource(config GoogleCredentialsConfig) *googleMetadataSource {
+	baseURL := config.MetadataURL
+	if baseURL == "" {
+		host := os.Getenv("GCE_METADATA_HOST")
+		if host == "" {
+			host = googleMetadataHost
+		}
+		baseURL = "http://" + host
+	}
+	return &googleMetadataSource{config: config, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+func (s *googleMetadataSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Now().Add(30*time.Second).Before(s.expiry) {
+		return s.token, nil
+	}
+
+	endpoint := s.baseURL + "/computeMetadata/v1/instance/service-accounts/default/"
+	if s.config.Audience != "" {
+		endpoint += "identity?format=full&audience=" + url.QueryEscape(s.config.Audience)
+	} else {
+		endpoint += "token?scopes=" + url.QueryEscape(strings.Join(s.config.Scopes, ","))
+	}
+
+	resp, err := s.config.Client.Request(ctx, RequestOptions{
+		Method:  "GET",
+		URL:     endpoint,
+		Headers: map[string]string{"Metadata-Flavor": "Google"},
+	})
+	if err != nil {
+		return "", fmt.Errorf("gcp: error request metadata server: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("gcp: metadata server returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(resp.Body)))
+	}
+
+	if s.config.Audience != "" {
+		// Endpoint identity mengembalikan JWT mentah
+		s.token = strings.TrimSpace(string(resp.Body))
+		s.expiry = jwtExpiry(s.token)
+		return s.token, nil
+	}
+
+	var token oauth2Token
+	if err := json.Unmarshal(resp.Body, &token); err != nil {
+		return "", fmt.Errorf("gcp: failed to unmarshal metadata token: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", errors.New("gcp: metadata token response has no access_token")
+	}
+	s.token = token.AccessToken
+	s.expiry = token.expiry()
+	if s.expiry.IsZero() {
+		s.expiry = time.Now().Add(5 * time.Minute)
+	}
+	return s.token, nil
+}
+
+func (s *googleMetadataSource) InvalidateToken() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = ""
+	s.expiry = time.Time{}
+}
+
+// jwtExpiry membaca claim "exp" tanpa verifikasi; default 5 menit jika gagal.
+func jwtExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) == 3 {
+		if payload, err := base64.RawURLEncoding.DecodeString(parts[1]); err == nil {
+			var claims struct {
+				Exp int64 `json:"exp"`
+			}
+			if json.Unmarshal(payload, &claims) == nil && claims.Exp > 0 {
+				return time.Unix(claims.Exp, 0)
+			}
+		}
+	}
+	return time.Now().Add(5 * time.Minute)
+}
@@ -0,0 +1,121 @@
+package http_request_instant
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGoogleMetadataAccessToken(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			t.Errorf("missing Metadata-Flavor header")
+		}
+		if r.URL.Path != "/computeMetadata/v1/instance/service-accounts/default/token" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"access_token":"gce-token","expires_in":3600,"token_type":"Bearer"}`))
+	}))
+	defer ts.Close()
+
+	// langsung pakai jalur metadata walaupun mesin test punya file ADC
+	provider := &GoogleTokenProvider{source: newGoogleMetadataSource(GoogleCredentialsConfig{
+		Scopes:      []string{googleCloudPlatformScope},
+		MetadataURL: ts.URL,
+		Client:      NewHttpRequest(),
+	})}
+
+	for i := 0; i < 2; i++ {
+		token, err := provider.Token(context.TODO())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if token != "gce-token" {
+			t.Errorf("unexpected token: %s", token)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected cached token, got %d calls", calls)
+	}
+}
+
+func TestGoogleMetadataIDToken(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/identity") || r.URL.Query().Get("audience") != "https://svc.run.app" {
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+		// header.{"exp":4102444800}.sig
+		_, _ = w.Write([]byte("eyJhbGciOiJSUzI1NiJ9.eyJleHAiOjQxMDI0NDQ4MDB9.c2ln"))
+	}))
+	defer ts.Close()
+
+	source := newGoogleMetadataSource(GoogleCredentialsConfig{
+		Audience:    "https://svc.run.app",
+		MetadataURL: ts.URL,
+		Client:      NewHttpRequest(),
+	})
+	token, err := source.Token(context.TODO())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(token, "eyJ") {
+		t.Errorf("unexpected token: %s", token)
+	}
+	if source.expiry.Unix() != 4102444800 {
+		t.Errorf("expected expiry from exp claim, got %v", source.expiry)
+	}
+}
+
+func TestGoogleServiceAccountIDToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		var claims map[string]any
+		decodeJWTPart(t, strings.Split(r.PostForm.Get("assertion"), ".")[1], &claims)
+		if claims["target_audience"] != "client-id.apps.googleusercontent.com" {
+			t.Errorf("unexpected target_audience: %v", claims["target_audience"])
+		}
+		if claims["iss"] != "svc@project.iam.gserviceaccount.com" {
+			t.Errorf("unexpected iss: %v", claims["iss"])
+		}
+		_, _ = w.Write([]byte(`{"id_token":"iap-id-token"}`))
+	}))
+	defer ts.Close()
+
+	creds, _ := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"client_email":   "svc@project.iam.gserviceaccount.com",
+		"private_key_id": "kid-1",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":      ts.URL,
+	})
+	provider, err := NewGoogleTokenProvider(GoogleCredentialsConfig{
+		CredentialsJSON: creds,
+		Audience:        "client-id.apps.googleusercontent.com",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	token, err := provider.Token(context.TODO())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token != "iap-id-token" {
+		t.Errorf("unexpected token: %s", token)
+	}
+}
@@ -0,0 +1,313 @@
+package http_request_instant
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HAROptions menyimpan konfigurasi HARRecorder.
+type HAROptions struct {
+	// Body lebih panjang dipotong, default 64 KiB; -1 untuk tidak menyimpan body.
+	MaxBodySize int
+	// Simpan header, URL, dan body apa adanya tanpa penyamaran. Default false:
+	// nilai rahasia disamarkan memakai aturan debug client (RedactHeaders, RedactBodyFields).
+	DisableRedaction bool
+}
+
+// HARRecorder merekam traffic client ke format HTTP Archive (HAR 1.2)
+// untuk dibagikan ke vendor saat debugging integrasi.
+type HARRecorder struct {
+	client  *HttpRequest
+	options HAROptions
+
+	mu      sync.Mutex
+	entries []harEntry
+	last    *http.Request // Request terakhir, supaya error hook after-response tidak tercatat dua kali
+}
+
+// RecordHAR mulai merekam semua percobaan request client ke HARRecorder baru.
+func (c *HttpRequest) RecordHAR(options HAROptions) *HARRecorder {
+	if options.MaxBodySize == 0 {
+		options.MaxBodySize = 64 << 10
+	}
+	r := &HARRecorder{client: c, options: options}
+	c.OnAfterResponse(func(ctx context.Context, req *http.Request, resp *ApiResponse, info AttemptInfo) error {
+		r.record(req, resp, info, nil)
+		return nil
+	})
+	c.OnError(func(ctx context.Context, req *http.Request, err error, info AttemptInfo) {
+		r.record(req, nil, info, err)
+	})
+	return r
+}
+
+type harLog struct {
+	Log harLogBody `json:"log"`
+}
+
+type harLogBody struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Error           string      `json:"_error,omitempty"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	Cookies     []harNameValue `json:"cookies"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	Cookies     []harNameValue `json:"cookies"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+func (r *HARRecorder) record(req *http.Request, resp *ApiResponse, info AttemptInfo, err error) {
+	r.mu.Lock()
+	duplicate := resp == nil && r.last == req
+	r.last = req
+	r.mu.Unlock()
+	if duplicate {
+		return
+	}
+
+	entry := harEntry{
+		StartedDateTime: info.Start.UTC().Format(time.RFC3339Nano),
+		Time:            millis(info.Duration),
+		Request:         r.harRequest(req),
+		Response: harResponse{
+			HTTPVersion: "HTTP/1.1",
+			Headers:     []harNameValue{},
+			Cookies:     []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Timings: harTimings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1, Wait: millis(info.Duration)},
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if resp != nil {
+		entry.Response = r.harResponse(req, resp)
+		if t := resp.Timings; t != nil {
+			entry.Timings = harTimings{
+				Blocked: -1,
+				DNS:     millis(t.DNS),
+				Connect: millis(t.Connect),
+				SSL:     millis(t.TLSHandshake),
+				Wait:    millis(t.FirstByte),
+				Receive: millis(t.Download),
+			}
+			if t.ConnReused {
+				entry.Timings.DNS, entry.Timings.Connect, entry.Timings.SSL = -1, -1, -1
+			}
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry)
+}
+
+func (r *HARRecorder) harRequest(req *http.Request) harRequest {
+	out := harRequest{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		HTTPVersion: "HTTP/1.1",
+		Headers:     r.headers(req.Header, r.client.requestApiKey(req)),
+		QueryString: []harNameValue{},
+		Cookies:     []harNameValue{},
+		HeadersSize: -1,
+		BodySize:    0,
+	}
+	if !r.options.DisableRedaction {
+		out.URL = r.client.redactURL(req.URL, r.client.requestApiKey(req))
+	}
+	if u, err := url.Parse(out.URL); err == nil {
+		for name, values := range u.Query() {
+			for _, value := range values {
+				out.QueryString = append(out.QueryString, harNameValue{Name: name, Value: value})
+			}
+		}
+	}
+	sort.Slice(out.QueryString, func(i, j int) bool { return out.QueryString[i].Name < out.QueryString[j].Name })
+
+	if req.GetBody != nil {
+		if rc, err := req.GetBody(); err == nil {
+			body, _ := io.ReadAll(rc)
+			rc.Close()
+			out.BodySize = len(body)
+			if r.options.MaxBodySize >= 0 {
+				out.PostData = &harPostData{MimeType: req.Header.Get("Content-Type"), Text: r.body(body)}
+			}
+		}
+	}
+	return out
+}
+
+func (r *HARRecorder) harResponse(req *http.Request, resp *ApiResponse) harResponse {
+	header := make(http.Header, len(resp.Headers))
+	for k, v := range resp.Headers {
+		header.Set(k, v)
+	}
+	out := harResponse{
+		Status:      resp.StatusCode,
+		StatusText:  http.StatusText(resp.StatusCode),
+		HTTPVersion: "HTTP/1.1",
+		Headers:     r.headers(header, r.client.requestApiKey(req)),
+		Cookies:     []harNameValue{},
+		Content:     harContent{Size: len(resp.Body), MimeType: resp.Headers["Content-Type"]},
+		RedirectURL: resp.Headers["Location"],
+		HeadersSize: -1,
+		BodySize:    len(resp.Body),
+	}
+	if r.options.MaxBodySize >= 0 {
+		out.Content.Text = r.body(resp.Body)
+		if len(resp.Body) > r.options.MaxBodySize {
+			out.Content.Comment = "body truncated"
+		}
+	}
+	return out
+}
+
+func (r *HARRecorder) headers(header http.Header, apiKey *ApiKeyAuth) []harNameValue {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := []harNameValue{}
+	for _, name := range names {
+		values := header[name]
+		if !r.options.DisableRedaction {
+			values = r.client.redactHeader(name, values, apiKey)
+		}
+		for _, value := range values {
+			out = append(out, harNameValue{Name: name, Value: value})
+		}
+	}
+	return out
+}
+
+func (r *HARRecorder) body(body []byte) string {
+	text := string(body)
+	if !r.options.DisableRedaction {
+		text = r.client.redactBody(body)
+	}
+	if len(text) > r.options.MaxBodySize {
+		text = text[:r.options.MaxBodySize]
+	}
+	return text
+}
+
+// Len mengembalikan jumlah entry yang sudah direkam.
+func (r *HARRecorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.entries)
+}
+
+// Reset menghapus semua entry yang sudah direkam.
+func (r *HARRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = nil
+}
+
+// WriteTo menulis HAR dalam format JSON ke w.
+func (r *HARRecorder) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	entries := append([]harEntry{}, r.entries...)
+	r.mu.Unlock()
+
+	data, err := json.MarshalIndent(harLog{Log: harLogBody{
+		Version: "1.2",
+		Creator: harCreator{Name: "http_request_instant", Version: "1"},
+		Entries: entries,
+	}}, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// SaveFile menyimpan HAR ke file path.
+func (r *HARRecorder) SaveFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := r.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,95 @@
+package http_request_instant
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHARRecorder(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"token":"secret-token","data":"` + strings.Repeat("x", 100) + `"}`))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.RedactBodyFields = []string{"token", "password"}
+	har := client.RecordHAR(HAROptions{MaxBodySize: 64})
+
+	_, err := client.Request(context.TODO(), RequestOptions{
+		Method:      "POST",
+		URL:         ts.URL + "/login?access_token=abc&page=1",
+		Headers:     map[string]string{"Authorization": "Bearer abc"},
+		RequestBody: map[string]string{"user": "budi", "password": "rahasia"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = client.Request(context.TODO(), RequestOptions{Method: "GET", URL: "http://127.0.0.1:1"})
+
+	var buf bytes.Buffer
+	if _, err := har.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, secret := range []string{"rahasia", "secret-token", "Bearer abc", "access_token=abc"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("HAR leaks %q:\n%s", secret, out)
+		}
+	}
+
+	var doc harLog
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if doc.Log.Version != "1.2" || len(doc.Log.Entries) != 2 {
+		t.Fatalf("unexpected HAR log: %+v", doc.Log)
+	}
+	entry := doc.Log.Entries[0]
+	if entry.Request.Method != "POST" || entry.Request.PostData == nil || entry.Response.Status != 200 {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+	if len(entry.Response.Content.Text) != 64 || entry.Response.Content.Comment != "body truncated" {
+		t.Errorf("expected truncated body, got %q", entry.Response.Content.Text)
+	}
+	if failed := doc.Log.Entries[1]; failed.Response.Status != 0 || failed.Error == "" {
+		t.Errorf("expected failed entry with error, got %+v", failed)
+	}
+
+	path := filepath.Join(t.TempDir(), "traffic.har")
+	if err := har.SaveFile(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || !bytes.Equal(data, buf.Bytes()) {
+		t.Errorf("saved HAR differs from WriteTo output: %v", err)
+	}
+}
+
+func TestHARRecorderWithoutBody(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	har := client.RecordHAR(HAROptions{MaxBodySize: -1})
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var buf bytes.Buffer
+	_, _ = har.WriteTo(&buf)
+	if strings.Contains(buf.String(), "hello") || har.Len() != 1 {
+		t.Errorf("expected body to be omitted:\n%s", buf.String())
+	}
+	har.Reset()
+	if har.Len() != 0 {
+		t.Errorf("expected empty recorder after Reset")
+	}
+}
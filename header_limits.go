@@ -0,0 +1,58 @@
+package http_request_instant
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrResponseHeaderLimit dikembalikan ketika header response melewati
+// ResponseHeaderLimits.
+var ErrResponseHeaderLimit = errors.New("response header limit exceeded")
+
+// ResponseHeaderLimits membatasi header response untuk melindungi worker
+// yang berjalan lama dari upstream yang mengirim header sangat besar.
+// Nilai 0 berarti default.
+type ResponseHeaderLimits struct {
+	MaxBytes int64 // Total ukuran header, default transport Go (1 MiB)
+	MaxCount int   // Jumlah baris header, 0 berarti tidak dibatasi
+}
+
+// SetResponseHeaderLimits mengatur batas header response. MaxBytes dicek
+// transport saat membaca header; MaxCount dicek setelah header diterima,
+// sebelum body dibaca. Error jika transport client bukan *http.Transport.
+func (c *HttpRequest) SetResponseHeaderLimits(limits ResponseHeaderLimits) error {
+	t, err := c.transport()
+	if err != nil {
+		return err
+	}
+	t.MaxResponseHeaderBytes = limits.MaxBytes
+	c.headerLimits = limits
+	return nil
+}
+
+// checkResponseHeaders menolak response yang jumlah header-nya melewati MaxCount.
+func (c *HttpRequest) checkResponseHeaders(resp *http.Response) error {
+	if c.headerLimits.MaxCount <= 0 {
+		return nil
+	}
+	count := 0
+	for _, values := range resp.Header {
+		count += len(values)
+	}
+	if count > c.headerLimits.MaxCount {
+		return fmt.Errorf("%w: %d headers, max %d", ErrResponseHeaderLimit, count, c.headerLimits.MaxCount)
+	}
+	return nil
+}
+
+// wrapHeaderLimitError menandai error transport karena header melewati
+// MaxResponseHeaderBytes. net/http tidak punya tipe error untuk kasus ini.
+func wrapHeaderLimitError(err error) error {
+	msg := err.Error()
+	if strings.Contains(msg, "server response headers exceeded") || strings.Contains(msg, "header list too large") {
+		return fmt.Errorf("%w: %w", ErrResponseHeaderLimit, err)
+	}
+	return err
+}
@@ -0,0 +1,44 @@
+package http_request_instant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestResponseHeaderLimits(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/many":
+			for i := 0; i < 50; i++ {
+				w.Header().Add(fmt.Sprintf("X-Junk-%d", i), "x")
+			}
+		case "/large":
+			w.Header().Set("X-Large", strings.Repeat("a", 64<<10))
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	if err := client.SetResponseHeaderLimits(ResponseHeaderLimits{MaxBytes: 16 << 10, MaxCount: 20}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err != nil || string(resp.Body) != "ok" {
+		t.Fatalf("expected normal response, got %v", err)
+	}
+	for _, path := range []string{"/many", "/large"} {
+		_, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL + path})
+		if !errors.Is(err, ErrResponseHeaderLimit) {
+			t.Errorf("%s: expected ErrResponseHeaderLimit, got %v", path, err)
+		}
+	}
+	if stats := client.Stats(); stats.Active != 0 {
+		t.Errorf("expected no active requests, got %d", stats.Active)
+	}
+}
@@ -0,0 +1,235 @@
+package http_request_instant
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HealthCheckOptions menyimpan konfigurasi health check aktif.
+type HealthCheckOptions struct {
+	Endpoints          []string       // Base URL endpoint yang dicek (mis. https://api-1.example.com)
+	Path               string         // Path health check, default "/health"
+	Method             string         // HEAD atau GET, default GET
+	Interval           time.Duration  // Jeda antar pengecekan, default 10 detik
+	Timeout            time.Duration  // Timeout per pengecekan, default 5 detik
+	HealthyThreshold   int            // Jumlah sukses berturut-turut sebelum ditandai up, default 1
+	UnhealthyThreshold int            // Jumlah gagal berturut-turut sebelum ditandai down, default 1
+	ExpectStatus       func(int) bool // Optional: validasi status code, default 2xx
+
+	// Optional: dipanggil setiap kali status sebuah endpoint berubah.
+	OnStatusChange func(status EndpointStatus)
+}
+
+// EndpointStatus merepresentasikan status terakhir sebuah endpoint.
+type EndpointStatus struct {
+	Endpoint             string
+	Healthy              bool
+	StatusCode           int
+	LastError            error
+	LastChecked          time.Time
+	ConsecutiveSuccesses int
+	ConsecutiveFailures  int
+}
+
+// HealthChecker melakukan health check berkala ke sekumpulan endpoint
+// dan menyimpan status up/down masing-masing.
+type HealthChecker struct {
+	client  *HttpRequest
+	options HealthCheckOptions
+
+	mu       sync.RWMutex
+	statuses map[string]*EndpointStatus
+
+	started  bool
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewHealthChecker membuat HealthChecker baru. Semua endpoint dianggap
+// healthy sampai pengecekan pertama membuktikan sebaliknya.
+func NewHealthChecker(client *HttpRequest, options HealthCheckOptions) *HealthChecker {
+	if client == nil {
+		client = NewHttpRequest()
+	}
+	if options.Path == "" {
+		options.Path = "/health"
+	}
+	if options.Method == "" {
+		options.Method = "GET"
+	}
+	if options.Interval <= 0 {
+		options.Interval = 10 * time.Second
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = 5 * time.Second
+	}
+	if options.HealthyThreshold <= 0 {
+		options.HealthyThreshold = 1
+	}
+	if options.UnhealthyThreshold <= 0 {
+		options.UnhealthyThreshold = 1
+	}
+	if options.ExpectStatus == nil {
+		options.ExpectStatus = func(code int) bool { return code >= 200 && code < 300 }
+	}
+
+	statuses := make(map[string]*EndpointStatus, len(options.Endpoints))
+	for _, endpoint := range options.Endpoints {
+		statuses[endpoint] = &EndpointStatus{Endpoint: endpoint, Healthy: true}
+	}
+
+	return &HealthChecker{
+		client:   client,
+		options:  options,
+		statuses: statuses,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start menjalankan health check berkala di background sampai ctx selesai
+// atau Stop dipanggil. Pengecekan pertama langsung dijalankan.
+func (h *HealthChecker) Start(ctx context.Context) {
+	h.mu.Lock()
+	if h.started {
+		h.mu.Unlock()
+		return
+	}
+	h.started = true
+	h.mu.Unlock()
+
+	go func() {
+		defer close(h.done)
+
+		ticker := time.NewTicker(h.options.Interval)
+		defer ticker.Stop()
+
+		h.CheckNow(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-h.stop:
+				return
+			case <-ticker.C:
+				h.CheckNow(ctx)
+			}
+		}
+	}()
+}
+
+// Stop menghentikan health check background dan menunggu loop selesai.
+func (h *HealthChecker) Stop() {
+	h.stopOnce.Do(func() {
+		close(h.stop)
+	})
+
+	h.mu.RLock()
+	started := h.started
+	h.mu.RUnlock()
+	if started {
+		<-h.done
+	}
+}
+
+// CheckNow mengecek semua endpoint secara paralel satu kali.
+func (h *HealthChecker) CheckNow(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, endpoint := range h.options.Endpoints {
+		wg.Add(1)
+		go func(endpoint string) {
+			defer wg.Done()
+			h.check(ctx, endpoint)
+		}(endpoint)
+	}
+	wg.Wait()
+}
+
+func (h *HealthChecker) check(ctx context.Context, endpoint string) {
+	ctx, cancel := context.WithTimeout(ctx, h.options.Timeout)
+	defer cancel()
+
+	resp, err := h.client.Request(ctx, RequestOptions{
+		Method: h.options.Method,
+		URL:    strings.TrimRight(endpoint, "/") + "/" + strings.TrimLeft(h.options.Path, "/"),
+	})
+
+	statusCode := 0
+	if err == nil {
+		statusCode = resp.StatusCode
+		if !h.options.ExpectStatus(resp.StatusCode) {
+			err = fmt.Errorf("unexpected health check status: %d", resp.StatusCode)
+		}
+	}
+
+	h.mu.Lock()
+	status, ok := h.statuses[endpoint]
+	if !ok {
+		h.mu.Unlock()
+		return
+	}
+	wasHealthy := status.Healthy
+	status.StatusCode = statusCode
+	status.LastError = err
+	status.LastChecked = time.Now()
+	if err != nil {
+		status.ConsecutiveFailures++
+		status.ConsecutiveSuccesses = 0
+		if status.ConsecutiveFailures >= h.options.UnhealthyThreshold {
+			status.Healthy = false
+		}
+	} else {
+		status.ConsecutiveSuccesses++
+		status.ConsecutiveFailures = 0
+		if status.ConsecutiveSuccesses >= h.options.HealthyThreshold {
+			status.Healthy = true
+		}
+	}
+	changed := status.Healthy != wasHealthy
+	snapshot := *status
+	h.mu.Unlock()
+
+	if changed && h.options.OnStatusChange != nil {
+		h.options.OnStatusChange(snapshot)
+	}
+}
+
+// Status mengembalikan snapshot status semua endpoint sesuai urutan konfigurasi.
+func (h *HealthChecker) Status() []EndpointStatus {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	result := make([]EndpointStatus, 0, len(h.options.Endpoints))
+	for _, endpoint := range h.options.Endpoints {
+		result = append(result, *h.statuses[endpoint])
+	}
+	return result
+}
+
+// IsHealthy mengembalikan true jika endpoint terakhir tercatat up.
+// Endpoint yang tidak terdaftar dianggap tidak healthy.
+func (h *HealthChecker) IsHealthy(endpoint string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	status, ok := h.statuses[endpoint]
+	return ok && status.Healthy
+}
+
+// HealthyEndpoints mengembalikan daftar endpoint yang sedang up.
+func (h *HealthChecker) HealthyEndpoints() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var result []string
+	for _, endpoint := range h.options.Endpoints {
+		if h.statuses[endpoint].Healthy {
+			result = append(result, endpoint)
+		}
+	}
+	return result
+}
@@ -0,0 +1,77 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHealthCheckerMarksEndpointDown(t *testing.T) {
+	var healthy atomic.Bool
+	healthy.Store(true)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			t.Errorf("expected path=/health, got %s", r.URL.Path)
+		}
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	var changes atomic.Int32
+	checker := NewHealthChecker(NewHttpRequest(), HealthCheckOptions{
+		Endpoints:      []string{ts.URL},
+		OnStatusChange: func(EndpointStatus) { changes.Add(1) },
+	})
+
+	checker.CheckNow(context.Background())
+	if !checker.IsHealthy(ts.URL) {
+		t.Fatal("expected endpoint to be healthy")
+	}
+
+	healthy.Store(false)
+	checker.CheckNow(context.Background())
+	if checker.IsHealthy(ts.URL) {
+		t.Fatal("expected endpoint to be unhealthy")
+	}
+
+	status := checker.Status()[0]
+	if status.StatusCode != http.StatusServiceUnavailable || status.LastError == nil {
+		t.Errorf("unexpected status: %+v", status)
+	}
+	if len(checker.HealthyEndpoints()) != 0 {
+		t.Errorf("expected no healthy endpoints, got %v", checker.HealthyEndpoints())
+	}
+	if changes.Load() != 1 {
+		t.Errorf("expected 1 status change, got %d", changes.Load())
+	}
+}
+
+func TestHealthCheckerBackground(t *testing.T) {
+	var hits atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	checker := NewHealthChecker(nil, HealthCheckOptions{
+		Endpoints: []string{ts.URL},
+		Method:    "HEAD",
+		Interval:  20 * time.Millisecond,
+	})
+	checker.Start(context.Background())
+	time.Sleep(70 * time.Millisecond)
+	checker.Stop()
+
+	if hits.Load() < 2 {
+		t.Errorf("expected at least 2 health checks, got %d", hits.Load())
+	}
+}
@@ -0,0 +1,150 @@
+package http_request_instant
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HMACCanonicalInput berisi bagian-bagian request yang tersedia untuk
+// dirangkai menjadi string yang ditandatangani.
+type HMACCanonicalInput struct {
+	Method     string
+	Path       string      // Path ter-escape, mis. /v1/orders
+	Query      string      // Raw query tanpa "?"
+	Timestamp  string      // Nilai header timestamp, kosong jika TimestampHeader tidak dipakai
+	Headers    [][2]string // Pasangan nama (lowercase) dan nilai sesuai urutan SignedHeaders
+	Body       []byte      // Body asli (nil jika tidak ada atau IncludeBody false)
+	BodyDigest string      // Digest body (encoding sama dengan signature), kosong jika IncludeBody false
+}
+
+// HMACSigner adalah RequestSigner HMAC yang bisa dikonfigurasi untuk
+// skema signature vendor yang berbeda-beda.
+type HMACSigner struct {
+	Key   []byte           // Secret key
+	Hash  func() hash.Hash // Algoritma digest, default sha256.New
+	KeyID string           // Optional: dikirim lewat KeyIDHeader
+
+	SignedHeaders []string // Header yang ikut ditandatangani, sesuai urutan
+	IncludeBody   bool     // Sertakan digest body dalam string yang ditandatangani
+
+	SignatureHeader string // Nama header signature, default X-Signature
+	SignaturePrefix string // Optional: prefix nilai signature, mis. "HMAC-SHA256 "
+	TimestampHeader string // Optional: header timestamp yang ikut ditandatangani, mis. X-Timestamp
+	KeyIDHeader     string // Optional: header key id, default X-Key-Id jika KeyID diisi
+
+	// Optional: format timestamp, default unix detik.
+	FormatTimestamp func(t time.Time) string
+	// Optional: encoding signature dan body digest, default hex.
+	Encode func(sum []byte) string
+	// Optional: canonicalization kustom. Default: method, path, query,
+	// timestamp, "name:value" per header, lalu body digest, dipisah "\n".
+	Canonicalize func(input HMACCanonicalInput) string
+
+	// Optional: sumber waktu, default time.Now.
+	Clock func() time.Time
+}
+
+// SignRequest mengimplementasikan RequestSigner.
+func (s *HMACSigner) SignRequest(ctx context.Context, req *http.Request) error {
+	if len(s.Key) == 0 {
+		return fmt.Errorf("hmac signer: key is empty")
+	}
+
+	newHash := s.Hash
+	if newHash == nil {
+		newHash = sha256.New
+	}
+	encode := s.Encode
+	if encode == nil {
+		encode = hex.EncodeToString
+	}
+
+	input := HMACCanonicalInput{
+		Method: req.Method,
+		Path:   req.URL.EscapedPath(),
+		Query:  req.URL.RawQuery,
+	}
+	if input.Path == "" {
+		input.Path = "/"
+	}
+
+	if s.TimestampHeader != "" {
+		clock := time.Now
+		if s.Clock != nil {
+			clock = s.Clock
+		}
+		formatTimestamp := s.FormatTimestamp
+		if formatTimestamp == nil {
+			formatTimestamp = func(t time.Time) string { return strconv.FormatInt(t.Unix(), 10) }
+		}
+		input.Timestamp = formatTimestamp(clock())
+		req.Header.Set(s.TimestampHeader, input.Timestamp)
+	}
+
+	if s.KeyID != "" {
+		keyIDHeader := s.KeyIDHeader
+		if keyIDHeader == "" {
+			keyIDHeader = "X-Key-Id"
+		}
+		req.Header.Set(keyIDHeader, s.KeyID)
+	}
+
+	for _, name := range s.SignedHeaders {
+		value := req.Header.Get(name)
+		if strings.EqualFold(name, "host") {
+			value = req.Host
+			if value == "" {
+				value = req.URL.Host
+			}
+		}
+		input.Headers = append(input.Headers, [2]string{strings.ToLower(name), value})
+	}
+
+	if s.IncludeBody {
+		body, err := readRequestBody(req)
+		if err != nil {
+			return fmt.Errorf("hmac signer: error read body: %w", err)
+		}
+		digest := newHash()
+		digest.Write(body)
+		input.Body = body
+		input.BodyDigest = encode(digest.Sum(nil))
+	}
+
+	canonicalize := s.Canonicalize
+	if canonicalize == nil {
+		canonicalize = defaultHMACCanonicalize
+	}
+
+	mac := hmac.New(newHash, s.Key)
+	mac.Write([]byte(canonicalize(input)))
+
+	signatureHeader := s.SignatureHeader
+	if signatureHeader == "" {
+		signatureHeader = "X-Signature"
+	}
+	req.Header.Set(signatureHeader, s.SignaturePrefix+encode(mac.Sum(nil)))
+	return nil
+}
+
+func defaultHMACCanonicalize(input HMACCanonicalInput) string {
+	lines := []string{input.Method, input.Path, input.Query}
+	if input.Timestamp != "" {
+		lines = append(lines, input.Timestamp)
+	}
+	for _, header := range input.Headers {
+		lines = append(lines, header[0]+":"+header[1])
+	}
+	if input.BodyDigest != "" {
+		lines = append(lines, input.BodyDigest)
+	}
+	return strings.Join(lines, "\n")
+}
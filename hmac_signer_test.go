@@ -0,0 +1,81 @@
+package http_request_instant
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHMACSignerDefault(t *testing.T) {
+	req, _ := http.NewRequest("POST", "https://api.vendor.com/v1/orders?id=7", nil)
+	req.Header.Set("Content-Type", "application/json")
+
+	signer := &HMACSigner{
+		Key:             []byte("secret"),
+		SignedHeaders:   []string{"Content-Type"},
+		TimestampHeader: "X-Timestamp",
+		Clock:           func() time.Time { return time.Unix(1700000000, 0) },
+	}
+	if err := signer.SignRequest(context.TODO(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	canonical := "POST\n/v1/orders\nid=7\n1700000000\ncontent-type:application/json"
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(canonical))
+	if got, want := req.Header.Get("X-Signature"), hex.EncodeToString(mac.Sum(nil)); got != want {
+		t.Errorf("unexpected signature: got %s, want %s", got, want)
+	}
+	if req.Header.Get("X-Timestamp") != "1700000000" {
+		t.Errorf("unexpected timestamp header: %s", req.Header.Get("X-Timestamp"))
+	}
+}
+
+func TestHMACSignerCustomScheme(t *testing.T) {
+	var signature, keyID string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get("Authorization")
+		keyID = r.Header.Get("X-Key-Id")
+		w.WriteHeader(200)
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.Signer = &HMACSigner{
+		Key:             []byte("secret"),
+		KeyID:           "key-1",
+		Hash:            sha512.New,
+		IncludeBody:     true,
+		SignatureHeader: "Authorization",
+		SignaturePrefix: "HMAC ",
+		Encode:          base64.StdEncoding.EncodeToString,
+		Canonicalize: func(input HMACCanonicalInput) string {
+			return input.Method + " " + input.Path + " " + string(input.Body)
+		},
+	}
+
+	_, err := client.Request(context.TODO(), RequestOptions{
+		Method:      "POST",
+		URL:         ts.URL + "/pay",
+		RequestBody: `{"amount":10}`,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mac := hmac.New(sha512.New, []byte("secret"))
+	mac.Write([]byte(`POST /pay {"amount":10}`))
+	if want := "HMAC " + base64.StdEncoding.EncodeToString(mac.Sum(nil)); signature != want {
+		t.Errorf("unexpected signature: got %s, want %s", signature, want)
+	}
+	if keyID != "key-1" {
+		t.Errorf("expected key id header, got %q", keyID)
+	}
+}
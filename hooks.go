@@ -0,0 +1,61 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// AttemptInfo berisi metadata satu percobaan request untuk lifecycle hook.
+type AttemptInfo struct {
+	Attempt  int           // Percobaan ke-n, mulai dari 1
+	Start    time.Time     // Waktu percobaan dimulai
+	Duration time.Duration // Lama percobaan, nol di OnBeforeRequest
+}
+
+// BeforeRequestHook dipanggil sebelum request ditandatangani dan dikirim.
+// Hook boleh mengubah req, mis. menambah header. Error membatalkan request.
+type BeforeRequestHook func(ctx context.Context, req *http.Request, info AttemptInfo) error
+
+// AfterResponseHook dipanggil setelah response diterima dan body dibaca.
+// Error membuat Request gagal dengan error tersebut.
+type AfterResponseHook func(ctx context.Context, req *http.Request, resp *ApiResponse, info AttemptInfo) error
+
+// ErrorHook dipanggil ketika percobaan request gagal setelah request dibangun.
+type ErrorHook func(ctx context.Context, req *http.Request, err error, info AttemptInfo)
+
+type lifecycleHooks struct {
+	beforeRequest []BeforeRequestHook
+	afterResponse []AfterResponseHook
+	onError       []ErrorHook
+}
+
+// OnBeforeRequest mendaftarkan hook sebelum setiap percobaan request.
+// Daftarkan hook sebelum client dipakai secara konkuren.
+func (c *HttpRequest) OnBeforeRequest(hook BeforeRequestHook) {
+	c.hooks.beforeRequest = append(c.hooks.beforeRequest, hook)
+}
+
+// OnAfterResponse mendaftarkan hook setelah setiap response diterima.
+func (c *HttpRequest) OnAfterResponse(hook AfterResponseHook) {
+	c.hooks.afterResponse = append(c.hooks.afterResponse, hook)
+}
+
+// OnError mendaftarkan hook untuk setiap percobaan request yang gagal.
+func (c *HttpRequest) OnError(hook ErrorHook) {
+	c.hooks.onError = append(c.hooks.onError, hook)
+}
+
+// runErrorHooks memanggil semua ErrorHook lalu mengembalikan err apa adanya.
+func (c *HttpRequest) runErrorHooks(ctx context.Context, req *http.Request, info AttemptInfo, err error) error {
+	if info.Duration == 0 {
+		info.Duration = time.Since(info.Start)
+	}
+	for _, hook := range c.hooks.onError {
+		if hookErr := safeCall("error hook", func() error { hook(ctx, req, err, info); return nil }); hookErr != nil {
+			panicErr := hookErr.(*PanicError)
+			c.logger().Errorf("error hook panic: %v\n%s", panicErr.Value, panicErr.Stack)
+		}
+	}
+	return err
+}
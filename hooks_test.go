@@ -0,0 +1,62 @@
+package http_request_instant
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLifecycleHooks(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("X-Stamp")))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	var events []string
+	client.OnBeforeRequest(func(ctx context.Context, req *http.Request, info AttemptInfo) error {
+		req.Header.Set("X-Stamp", "stamped")
+		events = append(events, "before")
+		return nil
+	})
+	client.OnAfterResponse(func(ctx context.Context, req *http.Request, resp *ApiResponse, info AttemptInfo) error {
+		if info.Attempt != 1 || info.Duration <= 0 {
+			t.Errorf("unexpected attempt info: %+v", info)
+		}
+		events = append(events, "after:"+string(resp.Body))
+		return nil
+	})
+	client.OnError(func(ctx context.Context, req *http.Request, err error, info AttemptInfo) {
+		events = append(events, "error")
+	})
+
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 || events[0] != "before" || events[1] != "after:stamped" {
+		t.Errorf("unexpected events: %v", events)
+	}
+}
+
+func TestLifecycleHookErrors(t *testing.T) {
+	client := NewHttpRequest()
+	var hookErr error
+	client.OnError(func(ctx context.Context, req *http.Request, err error, info AttemptInfo) {
+		hookErr = err
+	})
+
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: "http://127.0.0.1:1"}); err == nil || hookErr != err {
+		t.Fatalf("expected OnError to receive transport error, got %v / %v", err, hookErr)
+	}
+
+	abort := errors.New("blocked")
+	client.OnBeforeRequest(func(ctx context.Context, req *http.Request, info AttemptInfo) error {
+		return abort
+	})
+	_, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: "http://127.0.0.1:1"})
+	if !errors.Is(err, abort) || !errors.Is(hookErr, abort) {
+		t.Errorf("expected before hook to abort request, got %v", err)
+	}
+}
@@ -0,0 +1,257 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HostConfig adalah pengaturan khusus satu host yang dipilih otomatis dari
+// URL request, supaya satu client bisa melayani banyak vendor. Field kosong
+// berarti pengaturan client yang berlaku. Nilai di RequestOptions tetap menang.
+type HostConfig struct {
+	Timeout   time.Duration     // Batas waktu tiap percobaan ke host ini
+	Headers   map[string]string // Header default untuk host ini
+	Auth      AuthProvider      // Menimpa Auth milik client
+	ApiKey    *ApiKeyAuth       // Menimpa ApiKey milik client
+	Signer    RequestSigner     // Menimpa Signer milik client
+	Debug     *bool             // Menimpa Debug dan DebugSampler milik client
+	Retry     *RetryPolicy      // Optional: ulangi request yang gagal sementara
+	RateLimit *RateLimitOptions // Optional: pacing rate limit khusus host ini
+}
+
+// RetryPolicy menentukan kapan dan berapa kali request diulang.
+type RetryPolicy struct {
+	MaxAttempts int           // Total percobaan termasuk yang pertama, default 3
+	Backoff     time.Duration // Jeda sebelum percobaan kedua, dikali dua setiap percobaan; default 100ms
+	MaxBackoff  time.Duration // Optional: batas atas jeda
+
+	// Optional: default mengulang error yang Category-nya Retryable,
+	// status 5xx, dan 429, hanya untuk method idempoten (GET, HEAD,
+	// OPTIONS, PUT, DELETE) atau request dengan header Idempotency-Key di
+	// RequestOptions.Headers. Jika diisi, RetryOn berlaku untuk semua method
+	// sehingga POST/PATCH ikut diulang sesuai keputusannya.
+	RetryOn func(resp *ApiResponse, err error) bool
+
+	// Optional: dipanggil sebelum menunggu percobaan berikutnya, dengan nomor
+	// percobaan yang baru gagal, jeda yang akan ditunggu, dan hasilnya.
+	OnRetry func(attempt int, wait time.Duration, resp *ApiResponse, err error)
+
+	// Optional: dipanggil saat request berhenti diulang padahal hasil
+	// terakhir masih layak diulang, yaitu MaxAttempts habis atau context
+	// selesai saat menunggu.
+	OnGiveUp func(attempts int, resp *ApiResponse, err error)
+}
+
+type hostConfigEntry struct {
+	pattern string
+	config  HostConfig
+}
+
+// SetHostConfig memasang pengaturan untuk host. pattern berupa nama host
+// tanpa port ("api.stripe.com") atau wildcard subdomain ("*.amazonaws.com");
+// pattern persis lebih diutamakan daripada wildcard. Memanggil ulang dengan
+// pattern yang sama menimpa pengaturan sebelumnya. Atur host sebelum client
+// dipakai secara konkuren.
+func (c *HttpRequest) SetHostConfig(pattern string, config HostConfig) {
+	pattern = strings.ToLower(pattern)
+	if config.RateLimit != nil && c.rateLimit == nil {
+		c.rateLimit = newRateLimiter()
+	}
+	for i, entry := range c.hostConfigs {
+		if entry.pattern == pattern {
+			c.hostConfigs[i].config = config
+			return
+		}
+	}
+	c.hostConfigs = append(c.hostConfigs, hostConfigEntry{pattern: pattern, config: config})
+}
+
+// hostConfig mencari HostConfig untuk hostname.
+func (c *HttpRequest) hostConfig(hostname string) (HostConfig, bool) {
+	hostname = strings.ToLower(hostname)
+	var wildcard *HostConfig
+	for i, entry := range c.hostConfigs {
+		if entry.pattern == hostname {
+			return entry.config, true
+		}
+		if wildcard == nil && strings.HasPrefix(entry.pattern, "*") && matchHostPattern(entry.pattern, hostname) {
+			wildcard = &c.hostConfigs[i].config
+		}
+	}
+	if wildcard != nil {
+		return *wildcard, true
+	}
+	return HostConfig{}, false
+}
+
+// matchHostPattern mencocokkan hostname (tanpa port) dengan nama host persis
+// atau wildcard subdomain "*.example.com", tanpa membedakan huruf besar kecil.
+func matchHostPattern(pattern, hostname string) bool {
+	pattern, hostname = strings.ToLower(pattern), strings.ToLower(hostname)
+	if suffix, ok := strings.CutPrefix(pattern, "*"); ok {
+		return strings.HasSuffix(hostname, suffix)
+	}
+	return hostname == pattern
+}
+
+// matchAnyHost mencocokkan hostname dengan salah satu pattern.
+func matchAnyHost(patterns []string, hostname string) bool {
+	for _, pattern := range patterns {
+		if matchHostPattern(pattern, hostname) {
+			return true
+		}
+	}
+	return false
+}
+
+// rateLimitOptions mengembalikan opsi pacing untuk hostname, dari
+// HostConfig atau SetRateLimitPacing.
+func (c *HttpRequest) rateLimitOptions(hostname string) (RateLimitOptions, bool) {
+	if config, ok := c.hostConfig(hostname); ok && config.RateLimit != nil {
+		return *config.RateLimit, true
+	}
+	if c.rateLimit.defaults != nil {
+		return *c.rateLimit.defaults, true
+	}
+	return RateLimitOptions{}, false
+}
+
+// apply mengisi header dan kredensial options yang kosong dari config.
+// Header options menang jika namanya sama, tanpa membedakan huruf besar kecil.
+func (config HostConfig) apply(options RequestOptions) RequestOptions {
+	if len(config.Headers) > 0 {
+		options.Headers = mergeHeaders(config.Headers, options.Headers)
+	}
+	if options.Auth == nil {
+		options.Auth = config.Auth
+	}
+	if options.ApiKey == nil {
+		options.ApiKey = config.ApiKey
+	}
+	if options.Signer == nil {
+		options.Signer = config.Signer
+	}
+	if options.Debug == nil {
+		options.Debug = config.Debug
+	}
+	return options
+}
+
+// doWithHostConfig menerapkan HostConfig yang cocok dengan options.URL lalu
+// menjalankan request, diulang sesuai RetryPolicy di bawah satu span induk.
+func (c *HttpRequest) doWithHostConfig(ctx context.Context, options RequestOptions) (*ApiResponse, error) {
+	single := func(ctx context.Context) (*ApiResponse, error) {
+		return c.request(ctx, options, 1)
+	}
+	u, err := url.Parse(options.URL)
+	if err != nil || len(c.hostConfigs) == 0 {
+		return c.traceRequest(ctx, options, single)
+	}
+	config, ok := c.hostConfig(u.Hostname())
+	if !ok {
+		return c.traceRequest(ctx, options, single)
+	}
+
+	options = config.apply(options)
+
+	attempt := func(ctx context.Context, options RequestOptions, n int) (*ApiResponse, error) {
+		if config.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, config.Timeout)
+			defer cancel()
+		}
+		return c.request(ctx, options, n)
+	}
+	return c.traceRequest(ctx, options, func(ctx context.Context) (*ApiResponse, error) {
+		if config.Retry == nil {
+			return attempt(ctx, options, 1)
+		}
+		return config.Retry.do(ctx, &c.stats, options, attempt)
+	})
+}
+
+// do menjalankan attempt sampai berhasil, tidak layak diulang, atau
+// MaxAttempts habis. ResponseTarget baru di-decode setelah percobaan terakhir
+// supaya body error 5xx tidak membuat retry gagal sebagai error decode.
+// Setiap percobaan ulang dihitung di stats.
+func (p *RetryPolicy) do(ctx context.Context, stats *clientStats, options RequestOptions,
+	attempt func(ctx context.Context, options RequestOptions, n int) (*ApiResponse, error)) (*ApiResponse, error) {
+	maxAttempts := p.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+	if !replayableBody(options) {
+		maxAttempts = 1
+	}
+	backoff := p.Backoff
+	if backoff <= 0 {
+		backoff = 100 * time.Millisecond
+	}
+	retryOn := p.RetryOn
+	if retryOn == nil {
+		retryOn = defaultRetryOn
+		// POST atau PATCH yang diulang bisa menggandakan efeknya, mis.
+		// pembayaran dua kali
+		if !idempotentRequest(options) {
+			maxAttempts = 1
+		}
+	}
+
+	target := options.ResponseTarget
+	options.ResponseTarget = nil
+	for n := 1; ; n++ {
+		resp, err := attempt(ctx, options, n)
+		retry := retryOn(resp, err)
+		if n >= maxAttempts || !retry || ctx.Err() != nil {
+			if retry && n > 1 && p.OnGiveUp != nil {
+				p.OnGiveUp(n, resp, err)
+			}
+			if err == nil {
+				options.ResponseTarget = target
+				if err := decodeResponseTarget(options, resp); err != nil {
+					return nil, err
+				}
+			}
+			return resp, err
+		}
+
+		if p.OnRetry != nil {
+			p.OnRetry(n, backoff, resp, err)
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			if p.OnGiveUp != nil {
+				p.OnGiveUp(n, resp, ctx.Err())
+			}
+			return nil, ctx.Err()
+		}
+		backoff *= 2
+		if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+			backoff = p.MaxBackoff
+		}
+		stats.retry()
+	}
+}
+
+// idempotentRequest melaporkan apakah request aman diulang tanpa RetryOn
+// eksplisit.
+func idempotentRequest(options RequestOptions) bool {
+	switch strings.ToUpper(options.Method) {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return headerValue(options.Headers, "Idempotency-Key") != ""
+}
+
+func defaultRetryOn(resp *ApiResponse, err error) bool {
+	if err != nil {
+		return Category(err).Retryable()
+	}
+	return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+}
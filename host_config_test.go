@@ -0,0 +1,230 @@
+package http_request_instant
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHostConfigHeadersAndRetry(t *testing.T) {
+	var calls int32
+	var vendor, trace string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vendor, trace = r.Header.Get("X-Vendor"), r.Header.Get("X-Trace")
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("upstream down"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"ch_1"}`))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.SetHostConfig("127.0.0.1", HostConfig{
+		Headers: map[string]string{"X-Vendor": "stripe", "X-Trace": "host"},
+		Retry:   &RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond},
+	})
+
+	var target struct {
+		ID string `json:"id"`
+	}
+	resp, err := client.Request(context.TODO(), RequestOptions{
+		Method:         "GET",
+		URL:            ts.URL,
+		Headers:        map[string]string{"X-Trace": "request"},
+		ResponseTarget: &target,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || target.ID != "ch_1" {
+		t.Errorf("unexpected response: status %d, target %+v", resp.StatusCode, target)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+	if vendor != "stripe" || trace != "request" {
+		t.Errorf("unexpected headers: vendor %q, trace %q", vendor, trace)
+	}
+}
+
+func TestHostConfigHeadersCaseInsensitive(t *testing.T) {
+	config := HostConfig{Headers: map[string]string{"X-Foo": "config", "x-vendor": "stripe"}}
+	options := config.apply(RequestOptions{Headers: map[string]string{"x-foo": "request"}})
+	want := map[string]string{"X-Foo": "request", "X-Vendor": "stripe"}
+	if len(options.Headers) != len(want) || options.Headers["X-Foo"] != want["X-Foo"] || options.Headers["X-Vendor"] != want["X-Vendor"] {
+		t.Errorf("expected %v, got %v", want, options.Headers)
+	}
+}
+
+func TestHostConfigRetryGivesUp(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.SetHostConfig("127.0.0.1", HostConfig{Retry: &RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond}})
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusBadGateway || calls != 2 {
+		t.Errorf("expected last 502 after 2 attempts, got %d after %d", resp.StatusCode, calls)
+	}
+}
+
+func TestHostConfigRetryCallbacks(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	var retries []string
+	var gaveUp string
+	client := NewHttpRequest()
+	client.SetHostConfig("127.0.0.1", HostConfig{Retry: &RetryPolicy{
+		MaxAttempts: 3,
+		Backoff:     time.Millisecond,
+		OnRetry: func(attempt int, wait time.Duration, resp *ApiResponse, err error) {
+			retries = append(retries, fmt.Sprintf("%d %v %d", attempt, wait, resp.StatusCode))
+		},
+		OnGiveUp: func(attempts int, resp *ApiResponse, err error) {
+			gaveUp = fmt.Sprintf("%d %d %v", attempts, resp.StatusCode, err)
+		},
+	}})
+
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(retries) != 2 || retries[0] != "1 1ms 503" || retries[1] != "2 2ms 503" {
+		t.Errorf("unexpected OnRetry calls: %q", retries)
+	}
+	if gaveUp != "3 503 <nil>" {
+		t.Errorf("unexpected OnGiveUp call: %q", gaveUp)
+	}
+
+	// Hasil yang tidak layak diulang bukan give up
+	gaveUp, retries = "", nil
+	client.SetHostConfig("127.0.0.1", HostConfig{Retry: &RetryPolicy{
+		MaxAttempts: 3,
+		Backoff:     time.Millisecond,
+		RetryOn:     func(resp *ApiResponse, err error) bool { return false },
+		OnGiveUp: func(attempts int, resp *ApiResponse, err error) {
+			gaveUp = "called"
+		},
+	}})
+	client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+	if gaveUp != "" || retries != nil {
+		t.Errorf("expected no callbacks for non-retryable result, got %q %q", gaveUp, retries)
+	}
+}
+
+func TestHostConfigRetryNonIdempotent(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.SetHostConfig("127.0.0.1", HostConfig{Retry: &RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}})
+
+	// POST tanpa Idempotency-Key tidak diulang secara default
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "POST", URL: ts.URL, RequestBody: "{}"})
+	if err != nil || resp.StatusCode != http.StatusServiceUnavailable || atomic.LoadInt32(&calls) != 1 {
+		t.Errorf("expected single POST attempt, got %v after %d", err, calls)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	client.Request(context.TODO(), RequestOptions{Method: "POST", URL: ts.URL, RequestBody: "{}", Headers: map[string]string{"idempotency-key": "k1"}})
+	if atomic.LoadInt32(&calls) != 3 {
+		t.Errorf("expected POST with Idempotency-Key to be retried, got %d attempts", calls)
+	}
+
+	// RetryOn eksplisit berlaku untuk semua method
+	atomic.StoreInt32(&calls, 0)
+	client.SetHostConfig("127.0.0.1", HostConfig{Retry: &RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond, RetryOn: func(resp *ApiResponse, err error) bool {
+		return resp != nil && resp.StatusCode == http.StatusServiceUnavailable
+	}}})
+	client.Request(context.TODO(), RequestOptions{Method: "PATCH", URL: ts.URL, RequestBody: "{}"})
+	if atomic.LoadInt32(&calls) != 2 {
+		t.Errorf("expected opted-in PATCH to be retried, got %d attempts", calls)
+	}
+}
+
+func TestHostConfigTimeoutPerAttempt(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.SetHostConfig("127.0.0.1", HostConfig{
+		Timeout: 50 * time.Millisecond,
+		Retry:   &RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond},
+	})
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || calls != 2 {
+		t.Errorf("expected success on second attempt, got %d after %d", resp.StatusCode, calls)
+	}
+}
+
+func TestHostConfigMatching(t *testing.T) {
+	client := NewHttpRequest()
+	client.SetHostConfig("*.amazonaws.com", HostConfig{Timeout: time.Second})
+	client.SetHostConfig("s3.amazonaws.com", HostConfig{Timeout: 2 * time.Second})
+	client.SetHostConfig("API.stripe.com", HostConfig{Timeout: 3 * time.Second})
+	client.SetHostConfig("api.stripe.com", HostConfig{Timeout: 4 * time.Second})
+
+	tests := []struct {
+		host    string
+		timeout time.Duration
+		ok      bool
+	}{
+		{"s3.amazonaws.com", 2 * time.Second, true},
+		{"sqs.us-east-1.amazonaws.com", time.Second, true},
+		{"amazonaws.com", 0, false},
+		{"api.stripe.com", 4 * time.Second, true},
+		{"example.com", 0, false},
+	}
+	for _, tt := range tests {
+		config, ok := client.hostConfig(tt.host)
+		if ok != tt.ok || config.Timeout != tt.timeout {
+			t.Errorf("%s: got %s, %v; want %s, %v", tt.host, config.Timeout, ok, tt.timeout, tt.ok)
+		}
+	}
+}
+
+func TestHostConfigRateLimit(t *testing.T) {
+	client := NewHttpRequest()
+	client.SetHostConfig("api.github.com", HostConfig{RateLimit: &RateLimitOptions{Reserve: 5}})
+
+	if _, ok := client.rateLimitOptions("example.com"); ok {
+		t.Errorf("expected no pacing for unconfigured host")
+	}
+	if options, ok := client.rateLimitOptions("api.github.com"); !ok || options.Reserve != 5 {
+		t.Errorf("unexpected options: %+v, %v", options, ok)
+	}
+
+	client.SetRateLimitPacing(RateLimitOptions{Reserve: 1})
+	if options, ok := client.rateLimitOptions("example.com"); !ok || options.Reserve != 1 {
+		t.Errorf("expected client default, got %+v, %v", options, ok)
+	}
+}
@@ -0,0 +1,156 @@
+package http_request_instant
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// HTTP2Mode menentukan versi HTTP yang dipakai transport client.
+type HTTP2Mode int
+
+const (
+	// HTTP2Attempt menawarkan HTTP/2 lewat ALPN untuk https:// dengan fallback
+	// ke HTTP/1.1, juga saat dialer atau TLS config diganti. http:// tetap
+	// HTTP/1.1. Ini perilaku default NewHttpRequest.
+	HTTP2Attempt HTTP2Mode = iota
+	// HTTP2Disabled hanya memakai HTTP/1.1.
+	HTTP2Disabled
+	// HTTP2PriorKnowledge memakai h2c (HTTP/2 tanpa TLS, RFC 9113 bagian
+	// 3.3) untuk http:// dan HTTP/2 untuk https://, tanpa fallback HTTP/1.1.
+	// Untuk service internal seperti service mesh; WebSocket tidak bisa dipakai.
+	HTTP2PriorKnowledge
+)
+
+// SetHTTP2 mengatur pemakaian HTTP/2 pada transport client. Panggil sebelum
+// request pertama, karena transport membaca pengaturan ini saat pertama dipakai.
+func (c *HttpRequest) SetHTTP2(mode HTTP2Mode) error {
+	t, err := c.transport()
+	if err != nil {
+		return err
+	}
+
+	protocols := new(http.Protocols)
+	switch mode {
+	case HTTP2Attempt:
+		protocols.SetHTTP1(true)
+		protocols.SetHTTP2(true)
+	case HTTP2Disabled:
+		protocols.SetHTTP1(true)
+	case HTTP2PriorKnowledge:
+		protocols.SetHTTP2(true)
+		protocols.SetUnencryptedHTTP2(true)
+	default:
+		return fmt.Errorf("unknown HTTP2Mode %d", mode)
+	}
+	t.ForceAttemptHTTP2 = mode != HTTP2Disabled
+	t.Protocols = protocols
+	if mode == HTTP2Disabled {
+		stripH2(t)
+	}
+	return nil
+}
+
+// stripH2 membuang h2 dari ALPN. TLS config yang di-clone dari transport
+// yang sudah dipakai bisa masih menawarkan h2.
+func stripH2(t *http.Transport) {
+	if t.TLSClientConfig != nil {
+		t.TLSClientConfig.NextProtos = slices.DeleteFunc(slices.Clone(t.TLSClientConfig.NextProtos), func(proto string) bool {
+			return proto == "h2"
+		})
+	}
+}
+
+// SetHTTP1Fallback mengulang request satu kali lewat HTTP/1.1 ketika
+// percobaan HTTP/2 gagal, mis. karena middlebox yang merusak frame h2 atau
+// menolak ALPN h2. Protokol yang akhirnya dipakai ada di ApiResponse.Proto
+// dan error HTTP/2 aslinya di ApiResponse.HTTP2Error. Request dengan body
+// streaming atau lewat TransportProfile tidak diulang. Error jika transport
+// client bukan *http.Transport.
+func (c *HttpRequest) SetHTTP1Fallback(enabled bool) error {
+	if _, err := c.transport(); err != nil {
+		return err
+	}
+	if !enabled {
+		c.h1Fallback = nil
+		return nil
+	}
+	c.h1Fallback = &http1Fallback{}
+	return nil
+}
+
+// http1Fallback menyimpan client HTTP/1.1 yang dibuat saat fallback pertama,
+// dari clone transport client supaya pengaturan terakhir ikut terbawa.
+type http1Fallback struct {
+	once   sync.Once
+	client *http.Client
+}
+
+func (f *http1Fallback) httpClient(c *HttpRequest) *http.Client {
+	f.once.Do(func() {
+		t, _ := c.transport()
+		clone := t.Clone()
+		protocols := new(http.Protocols)
+		protocols.SetHTTP1(true)
+		clone.Protocols = protocols
+		clone.ForceAttemptHTTP2 = false
+		stripH2(clone)
+
+		client := *c.Client
+		client.Transport = clone
+		f.client = &client
+	})
+	return f.client
+}
+
+// traceProtocol mencatat protokol ALPN koneksi yang dipakai req.
+func traceProtocol(req *http.Request) (*http.Request, func() string) {
+	var mu sync.Mutex
+	var proto string
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if conn, ok := info.Conn.(interface{ ConnectionState() tls.ConnectionState }); ok {
+				mu.Lock()
+				proto = conn.ConnectionState().NegotiatedProtocol
+				mu.Unlock()
+			}
+		},
+	}
+	negotiated := func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		return proto
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace)), negotiated
+}
+
+// http1FallbackRequest menyalin req untuk dikirim ulang lewat HTTP/1.1 jika
+// err berasal dari HTTP/2 dan body-nya bisa dibaca ulang.
+func http1FallbackRequest(req *http.Request, err error, proto string) (*http.Request, bool) {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return nil, false
+	}
+	msg := err.Error()
+	if proto != "h2" && !strings.Contains(msg, "http2") && !strings.Contains(msg, "no application protocol") {
+		return nil, false
+	}
+
+	retry := req.Clone(req.Context())
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return nil, false
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, false
+		}
+		retry.Body = body
+	}
+	return retry, true
+}
@@ -0,0 +1,103 @@
+package http_request_instant
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSetHTTP2(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	})
+
+	// Server cleartext yang menerima h2c prior knowledge dan HTTP/1.1
+	h2c := httptest.NewUnstartedServer(handler)
+	h2c.Config.Protocols = new(http.Protocols)
+	h2c.Config.Protocols.SetHTTP1(true)
+	h2c.Config.Protocols.SetUnencryptedHTTP2(true)
+	h2c.Start()
+	defer h2c.Close()
+
+	tlsServer := httptest.NewUnstartedServer(handler)
+	tlsServer.EnableHTTP2 = true
+	tlsServer.StartTLS()
+	defer tlsServer.Close()
+
+	tests := []struct {
+		mode      HTTP2Mode
+		cleartext string
+		tls       string
+	}{
+		{HTTP2Attempt, "HTTP/1.1", "HTTP/2.0"},
+		{HTTP2Disabled, "HTTP/1.1", "HTTP/1.1"},
+		{HTTP2PriorKnowledge, "HTTP/2.0", "HTTP/2.0"},
+	}
+	for _, tt := range tests {
+		client := NewHttpRequest()
+		if err := client.SetRootCAs(tlsServer.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := client.SetHTTP2(tt.mode); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for url, want := range map[string]string{h2c.URL: tt.cleartext, tlsServer.URL: tt.tls} {
+			resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: url})
+			if err != nil {
+				t.Fatalf("mode %d %s: unexpected error: %v", tt.mode, url, err)
+			}
+			if string(resp.Body) != want || resp.Proto != want {
+				t.Errorf("mode %d %s: expected %s, got server %s client %s", tt.mode, url, want, resp.Body, resp.Proto)
+			}
+		}
+	}
+
+	if err := NewHttpRequest().SetHTTP2(HTTP2Mode(99)); err == nil {
+		t.Errorf("expected unknown mode error")
+	}
+}
+
+func TestHTTP1Fallback(t *testing.T) {
+	// Stream HTTP/2 di-reset server, seperti frame yang dirusak middlebox
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 {
+			panic(http.ErrAbortHandler)
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Write(append([]byte(r.Proto+" "), body...))
+	}))
+	ts.EnableHTTP2 = true
+	ts.Config.ErrorLog = log.New(io.Discard, "", 0)
+	ts.StartTLS()
+	defer ts.Close()
+	rootCAs := ts.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+
+	client := NewHttpRequest()
+	client.SetRootCAs(rootCAs)
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err == nil {
+		t.Fatalf("expected HTTP/2 error without fallback")
+	}
+
+	client = NewHttpRequest()
+	client.SetRootCAs(rootCAs)
+	if err := client.SetHTTP1Fallback(true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "POST", URL: ts.URL, RequestBody: "payload"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Proto != "HTTP/1.1" || string(resp.Body) != "HTTP/1.1 payload" || resp.HTTP2Error == nil {
+		t.Errorf("expected fallback to HTTP/1.1 with body replayed, got %s %q, %v", resp.Proto, resp.Body, resp.HTTP2Error)
+	}
+
+	// Body streaming tidak bisa diulang
+	_, err = client.Request(context.TODO(), RequestOptions{Method: "POST", URL: ts.URL, RequestBody: strings.NewReader("stream")})
+	if err == nil {
+		t.Errorf("expected streaming body not to fall back")
+	}
+}
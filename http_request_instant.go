@@ -5,21 +5,43 @@ import (
 	"context"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // RequestOptions menyimpan konfigurasi request HTTP.
 type RequestOptions struct {
-	Method         string            // HTTP method (GET, POST, PUT, DELETE, dll.)
-	URL            string            // Target URL
-	Headers        map[string]string // Custom headers
-	RequestBody    interface{}       // Body request (bisa map, struct, string, []byte)
-	ContentType    string            // Content-Type request (application/json, application/xml, dll.)
-	ResponseTarget interface{}       // Optional: jika diisi, response akan di-unmarshal ke struct
+	Method         string                // HTTP method (GET, POST, PUT, DELETE, dll.)
+	URL            string                // Target URL
+	Headers        map[string]string     // Custom headers
+	RequestBody    interface{}           // Body request (bisa map, struct, string, []byte, atau io.Reader untuk streaming)
+	ContentType    string                // Content-Type request (application/json, application/xml, dll.)
+	ResponseTarget interface{}           // Optional: jika diisi, response akan di-unmarshal ke struct
+	Priority       Priority              // Optional: kelas prioritas jika antrian request aktif
+	BearerToken    string                // Optional: token untuk header Authorization: Bearer
+	ApiKey         *ApiKeyAuth           // Optional: API key, menimpa ApiKey milik client
+	Signer         RequestSigner         // Optional: signer per request, menimpa Signer milik client
+	Auth           AuthProvider          // Optional: AuthProvider per request, menimpa Auth milik client
+	Proxy          string                // Optional: URL proxy khusus request ini, atau ProxyDirect
+	PayloadCodec   PayloadCodec          // Optional: codec body per request, menimpa PayloadCodec milik client
+	Verifier       ResponseVerifier      // Optional: verifikasi signature response, menimpa Verifier milik client
+	Debug          *bool                 // Optional: aktif/nonaktifkan debug khusus request ini, menimpa Debug dan DebugSampler milik client
+	Mutators       []RequestMutator      // Optional: mutator khusus request ini, dijalankan setelah mutator milik client
+	Transformers   []ResponseTransformer // Optional: transformer body response khusus request ini, dijalankan setelah transformer milik client
+	Trailers       map[string]string     // Optional: trailer request, body dikirim chunked; nilai dibaca setelah body terkirim
+	Bandwidth      *BandwidthLimit       // Optional: batas kecepatan upload/download khusus request ini, berlaku bersama batas client
+	Compression    CompressionMode       // Optional: kompresi transparan khusus request ini, default ikut client
+	Host           string                // Optional: header Host yang berbeda dari host di URL, mis. virtual host di balik gateway
+
+	TransportProfile string // Optional: nama profile dari SetTransportProfile, mis. "no-proxy"
 	*BasicAuth
 }
 
@@ -31,9 +53,27 @@ type BasicAuth struct {
 
 // ApiResponse merepresentasikan response dari server.
 type ApiResponse struct {
-	StatusCode int               // HTTP status code
-	Body       []byte            // Response body dalam bentuk raw
-	Headers    map[string]string // Response headers
+	StatusCode  int               // HTTP status code
+	Proto       string            // Protokol response, mis. "HTTP/1.1" atau "HTTP/2.0"
+	Body        []byte            // Response body dalam bentuk raw
+	Headers     map[string]string // Response headers
+	Trailers    map[string]string // Response trailers, mis. grpc-status dari gRPC-gateway
+	RequestID   string            // Request ID dari server atau yang dikirim, jika SetRequestID aktif
+	Timings     *Timings          // Durasi per fase, jika CaptureTimings aktif
+	FromCache   bool              // Body berasal dari cache, bukan dari server
+	Revalidated bool              // Server membalas 304 dan body cache yang dipakai
+	Stale       bool              // Body dari cache basi karena server gagal (stale-if-error)
+
+	// Byte di socket termasuk header dan overhead TLS, jika EnableByteAccounting aktif
+	WireBytesSent     int64
+	WireBytesReceived int64
+
+	// Error HTTP/2 yang membuat request diulang lewat HTTP/1.1, jika
+	// SetHTTP1Fallback aktif; nil jika tidak terjadi fallback
+	HTTP2Error error
+
+	request *http.Request // Request yang dikirim, untuk Curl
+	client  *HttpRequest
 }
 
 // HttpRequestInf mendefinisikan interface untuk request HTTP.
@@ -48,16 +88,119 @@ type HttpRequest struct {
 
 	// debug request and response
 	Debug bool
+
+	// Optional: jika Debug aktif, hanya request yang dipilih sampler yang
+	// dicatat, mis. SampleRate(0.01) atau SampleHosts("api.vendor.com").
+	DebugSampler DebugSampler
+
+	// Optional: tujuan output debug dan peringatan, default stdout.
+	Logger Logger
+
+	// Catat durasi DNS, connect, TLS, TTFB, dan download ke ApiResponse.Timings.
+	CaptureTimings bool
+
+	// Optional: percobaan yang lebih lama dari ini dicatat sebagai peringatan
+	// lengkap dengan durasi per fase, walaupun Debug tidak aktif.
+	SlowRequestThreshold time.Duration
+
+	// Optional: tracer untuk membuat span per request dan per percobaan.
+	Tracer Tracer
+
+	// Optional: penerima metrik per percobaan request, mis. PrometheusMetrics.
+	Metrics MetricsObserver
+
+	// Format header trace yang dipasang jika ctx membawa TraceContext,
+	// default PropagateW3C.
+	TracePropagation TracePropagation
+
+	// Optional: body lebih panjang dari ini dipotong di output debug dan
+	// diberi ukuran asli beserta hash SHA-256. 0 berarti tidak dipotong.
+	// Body biner selalu diringkas menjadi ukuran dan hash.
+	MaxDebugBodyBytes int
+
+	// Header tambahan yang disamarkan di output debug. Authorization, Cookie,
+	// Set-Cookie, dan header API key umum selalu disamarkan.
+	RedactHeaders []string
+	// Path field JSON yang disamarkan di body output debug, mis. "password"
+	// atau "user.credentials.token". Segmen "*" cocok dengan semua key/elemen.
+	RedactBodyFields []string
+
+	// Optional: sumber token Bearer untuk semua request yang belum
+	// membawa header Authorization, BasicAuth, atau BearerToken sendiri.
+	TokenProvider TokenProvider
+
+	// Optional: API key yang dipasang ke setiap request.
+	ApiKey *ApiKeyAuth
+
+	// Optional: AuthProvider untuk semua request, dijalankan setelah BasicAuth,
+	// ApiKey, dan BearerToken. Jika diisi, TokenProvider tidak dipakai.
+	Auth AuthProvider
+
+	// Optional: signer (mis. SigV4Signer) yang dijalankan paling akhir sebelum request dikirim.
+	Signer RequestSigner
+
+	// Optional: codec (mis. JWEPayload atau JWSPayload) untuk body request
+	// setelah marshal dan body response sebelum unmarshal.
+	PayloadCodec PayloadCodec
+
+	// Optional: verifikasi signature response (mis. HMACResponseVerifier).
+	// Response yang gagal diverifikasi dikembalikan sebagai *ResponseSignatureError.
+	Verifier ResponseVerifier
+
+	// Optional: dipanggil sekali ketika server membalas 401. Callback bisa
+	// me-refresh token lalu mengubah options (mis. header Authorization),
+	// kemudian request diulang otomatis dengan options tersebut.
+	OnUnauthorized func(ctx context.Context, options *RequestOptions) error
+
+	dialer       *net.Dialer
+	dialerOnce   sync.Once
+	unixSocket   string
+	dnsCache     *dnsCache
+	ipFamily     IPFamilyOptions
+	expect100    *ExpectContinueOptions
+	bandwidth    *bandwidthLimiters
+	compression  CompressionMode
+	ssrf         *ssrfGuard
+	headerLimits ResponseHeaderLimits
+	recycle      *ConnectionRecycling
+	h1Fallback   *http1Fallback
+	proxyChains  []proxyChainEntry
+	queue        *requestQueue
+	rateLimit    *rateLimiter
+	pool         *poolStats
+	warm         warmConns
+	policy       *OutboundPolicy
+	memoOnce     sync.Once
+	memo         *memoizer
+	countBytes   bool
+	requestID    *RequestIDOptions
+	stats        clientStats
+	hooks        lifecycleHooks
+	mutators     []RequestMutator
+	transformers []ResponseTransformer
+	middlewares  []Middleware
+	hostConfigs  []hostConfigEntry
+	handler      Doer // Rantai middleware, nil jika Use belum dipanggil
+	proxy        func(req *http.Request) (*url.URL, error)
+	inFlight     int64 // Request yang sedang berjalan, untuk Shutdown
+	closed       int32 // 1 setelah Shutdown dipanggil
+
+	transportProfiles map[string]*http.Client // Dari SetTransportProfile
+
+	proxyAuth           *ProxyAuth
+	proxyConnectHeaders map[string]string
 }
 
 // NewHttpRequest membuat instance baru HttpRequest dengan default timeout 30 detik.
 func NewHttpRequest() *HttpRequest {
-	return &HttpRequest{
+	c := &HttpRequest{
 		Client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 		Debug: false,
 	}
+	c.Client.Transport = c.newTransport()
+	return c
 }
 
 // SetDebug mengaktifkan atau menonaktifkan mode debug.
@@ -65,38 +208,264 @@ func (h *HttpRequest) SetDebug(debug bool) {
 	h.Debug = debug
 }
 
-// Request mengeksekusi HTTP request berdasarkan RequestOptions.
+// Request mengeksekusi HTTP request berdasarkan RequestOptions, melewati
+// middleware yang didaftarkan dengan Use. Override dari ContextWithOptions
+// digabung lebih dulu, sehingga middleware juga melihatnya.
 func (c *HttpRequest) Request(ctx context.Context, options RequestOptions) (*ApiResponse, error) {
+	atomic.AddInt64(&c.inFlight, 1)
+	defer atomic.AddInt64(&c.inFlight, -1)
+	if atomic.LoadInt32(&c.closed) != 0 {
+		return nil, ErrClientClosed
+	}
+
+	ctx, options, cancel := applyContextOptions(ctx, options)
+	defer cancel()
+
+	if c.handler != nil {
+		return c.handler.Do(ctx, options)
+	}
+	return c.safeDo(ctx, options)
+}
+
+// do mengeksekusi request tanpa middleware.
+func (c *HttpRequest) do(ctx context.Context, options RequestOptions) (*ApiResponse, error) {
+	if c.requestID == nil {
+		return c.doWithHostConfig(ctx, options)
+	}
+
+	ctx, state := c.withRequestID(ctx)
+	apiResp, err := c.doWithHostConfig(ctx, options)
+	if err != nil && c.requestID.WrapErrors {
+		err = state.wrap(err)
+	}
+	return apiResp, err
+}
+
+// traceRequest menjalankan run, dibungkus span induk jika Tracer diisi.
+// Setiap percobaan di dalam run, termasuk retry RetryPolicy, menjadi span anak.
+func (c *HttpRequest) traceRequest(ctx context.Context, options RequestOptions,
+	run func(ctx context.Context) (*ApiResponse, error)) (*ApiResponse, error) {
+	if c.Tracer == nil {
+		return run(ctx)
+	}
+
+	ctx, span := c.Tracer.Start(ctx, "HTTP "+options.Method)
+	defer span.End()
+	setRequestSpanAttributes(span, options)
+
+	apiResp, err := run(ctx)
+	endSpan(span, apiResp, err)
+	return apiResp, err
+}
+
+// request menjalankan satu percobaan bernomor attempt, diulang sekali
+// setelah 401 jika kredensial bisa di-refresh.
+func (c *HttpRequest) request(ctx context.Context, options RequestOptions, attempt int) (*ApiResponse, error) {
+	ctx = c.withDebugDecision(ctx, options)
+	apiResp, err := c.execute(ctx, options, attempt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Refresh kredensial lalu ulangi request satu kali jika 401
+	if apiResp.StatusCode == http.StatusUnauthorized {
+		replay := false
+		if invalidator, ok := c.TokenProvider.(TokenInvalidator); ok && c.usesTokenProvider(options) &&
+			isInvalidTokenChallenge(apiResp.Headers["Www-Authenticate"]) {
+			invalidator.InvalidateToken()
+			replay = true
+		}
+		if handler, ok := c.authProvider(options).(UnauthorizedHandler); ok {
+			retry, err := handler.OnUnauthorized(ctx, apiResp)
+			if err != nil {
+				return nil, fmt.Errorf("error refresh credentials: %w", err)
+			}
+			replay = replay || retry
+		}
+		if c.OnUnauthorized != nil {
+			options.Headers = cloneHeaders(options.Headers)
+			if err := c.OnUnauthorized(ctx, &options); err != nil {
+				return nil, fmt.Errorf("error refresh credentials: %w", err)
+			}
+			replay = true
+		}
+		if replay && replayableBody(options) {
+			apiResp, err = c.execute(ctx, options, attempt+1)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := c.transformResponse(ctx, apiResp, options); err != nil {
+		return nil, err
+	}
+
+	// Jika ada ResponseTarget, unmarshal otomatis
+	if err := decodeResponseTarget(options, apiResp); err != nil {
+		return nil, err
+	}
+
+	return apiResp, nil
+}
+
+// execute mengirim satu percobaan request, dibungkus span jika Tracer diisi.
+// attempt adalah percobaan ke-n (mulai dari 1) untuk keperluan log dan trace.
+func (c *HttpRequest) execute(ctx context.Context, options RequestOptions, attempt int) (*ApiResponse, error) {
+	if c.Tracer == nil {
+		return c.send(ctx, options, attempt)
+	}
+
+	ctx, span := c.Tracer.Start(ctx, "HTTP "+options.Method)
+	defer span.End()
+	setRequestSpanAttributes(span, options)
+	if carrier, ok := span.(TraceContextSpan); ok {
+		ctx = ContextWithTraceContext(ctx, carrier.TraceContext())
+	}
+	if attempt > 1 {
+		span.SetAttribute("http.request.resend_count", attempt-1)
+	}
+
+	apiResp, err := c.send(ctx, options, attempt)
+	endSpan(span, apiResp, err)
+	return apiResp, err
+}
+
+// send membangun dan mengirim satu HTTP request tanpa decode ResponseTarget.
+func (c *HttpRequest) send(ctx context.Context, options RequestOptions, attempt int) (*ApiResponse, error) {
+	req, body, err := c.buildRequest(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	ctx = req.Context()
+	info := AttemptInfo{Attempt: attempt, Start: time.Now()}
+	if err := c.prepareRequest(ctx, req, options, info); err != nil {
+		return nil, err
+	}
+
+	if c.debugEnabled(ctx, req) {
+		c.debugRequest(ctx, req, body, attempt)
+	}
+
+	apiResp, err := c.roundTrip(ctx, req, options, attempt)
+	info.Duration = time.Since(info.Start)
+	if err != nil {
+		return nil, c.runErrorHooks(ctx, req, info, err)
+	}
+	apiResp.request, apiResp.client = req, c
+
+	for _, hook := range c.hooks.afterResponse {
+		if err := safeCall("after response hook", func() error { return hook(ctx, req, apiResp, info) }); err != nil {
+			return nil, c.runErrorHooks(ctx, req, info, fmt.Errorf("error after response hook: %w", err))
+		}
+	}
+	return apiResp, nil
+}
+
+// prepareRequest menjalankan hook sebelum request, signer, dan OutboundPolicy
+// atas request yang sudah dibangun. Error sudah melewati error hook.
+func (c *HttpRequest) prepareRequest(ctx context.Context, req *http.Request, options RequestOptions, info AttemptInfo) error {
+	// Hook sebelum request, mis. stamping header, dijalankan sebelum signer
+	for _, hook := range c.hooks.beforeRequest {
+		if err := safeCall("before request hook", func() error { return hook(ctx, req, info) }); err != nil {
+			return c.runErrorHooks(ctx, req, info, fmt.Errorf("error before request hook: %w", err))
+		}
+	}
+
+	// Tanda tangani request setelah semua header terpasang
+	signer := options.Signer
+	if signer == nil {
+		signer = c.Signer
+	}
+	if signer != nil {
+		if err := safeCall("signer", func() error { return signer.SignRequest(ctx, req) }); err != nil {
+			return c.runErrorHooks(ctx, req, info, fmt.Errorf("error sign request: %w", err))
+		}
+	}
+
+	// Bearer proxy dipasang setelah signer karena dilepas oleh proxy
+	c.setProxyBearer(req)
+
+	// Cek guardrail egress terhadap request final
+	if err := c.enforcePolicy(req); err != nil {
+		return c.runErrorHooks(ctx, req, info, err)
+	}
+
+	// Tolak tujuan di alamat internal jika proteksi SSRF aktif
+	if err := c.checkSSRF(req); err != nil {
+		return c.runErrorHooks(ctx, req, info, err)
+	}
+	return nil
+}
+
+// buildRequest membangun http.Request lengkap dengan header, body, dan kredensial.
+// Body yang dikembalikan adalah body sebelum PayloadCodec, untuk output debug.
+func (c *HttpRequest) buildRequest(ctx context.Context, options RequestOptions) (*http.Request, []byte, error) {
 	var req *http.Request
 	var err error
 
+	// Proxy per request dibaca Transport.Proxy dari context
+	ctx, err = withRequestProxy(ctx, options.Proxy)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// URL uds:// diarahkan ke Unix socket oleh dialContext
+	ctx, options.URL, err = withUnixSocketURL(ctx, options.URL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// ApiKey yang dipakai request ini, untuk disamarkan di debug dan log
+	apiKey := options.ApiKey
+	if apiKey == nil {
+		apiKey = c.ApiKey
+	}
+	if apiKey != nil {
+		ctx = context.WithValue(ctx, apiKeyContextKey{}, apiKey)
+	}
+
 	var body []byte
 	if options.RequestBody != nil {
+		var stream io.Reader
 		switch v := options.RequestBody.(type) {
 		case string:
 			body = []byte(v)
 		case []byte:
 			body = v
+		case io.Reader:
+			stream = v
 		default:
+			var marshal func(v any) ([]byte, error)
 			switch options.ContentType {
 			case "application/json", "":
-				body, err = json.Marshal(v)
+				marshal = json.Marshal
 			case "application/xml":
-				body, err = xml.Marshal(v)
+				marshal = xml.Marshal
 			default:
-				return nil, fmt.Errorf("unsupported Content-Type: %s", options.ContentType)
+				return nil, nil, fmt.Errorf("unsupported Content-Type: %s", options.ContentType)
 			}
+			err = safeCall("marshal", func() (err error) {
+				body, err = marshal(v)
+				return err
+			})
 			if err != nil {
-				return nil, fmt.Errorf("error marshal request body: %w", err)
+				return nil, nil, fmt.Errorf("error marshal request body: %w", err)
 			}
 		}
-		req, err = http.NewRequestWithContext(ctx, options.Method, options.URL, bytes.NewBuffer(body))
+		if stream == nil {
+			stream = bytes.NewBuffer(body)
+		}
+		req, err = http.NewRequestWithContext(ctx, options.Method, options.URL, stream)
 	} else {
 		req, err = http.NewRequestWithContext(ctx, options.Method, options.URL, nil)
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("error create request: %w", err)
+		return nil, nil, fmt.Errorf("error create request: %w", err)
+	}
+	if _, ok := ctx.Value(unixSocketContextKey{}).(string); ok {
+		req.Host = "localhost"
 	}
 
 	// Set Content-Type untuk request jika ada
@@ -109,37 +478,193 @@ func (c *HttpRequest) Request(ctx context.Context, options RequestOptions) (*Api
 		req.Header.Set(key, value)
 	}
 
+	// net/http mengabaikan header Host di req.Header, jadi pindahkan ke req.Host
+	if host := req.Header.Get("Host"); host != "" {
+		req.Host = host
+		req.Header.Del("Host")
+	}
+	if options.Host != "" {
+		req.Host = options.Host
+	}
+
+	// Accept-Encoding untuk kompresi transparan
+	c.applyCompression(req, options)
+
+	// Set request ID untuk korelasi log
+	c.applyRequestID(ctx, req)
+
+	// Propagasi header trace (traceparent/B3) dari ctx
+	c.injectTraceHeaders(ctx, req.Header)
+
 	// Set Basic Auth jika diisi
 	if options.BasicAuth != nil {
 		req.SetBasicAuth(options.BasicAuth.Username, options.BasicAuth.Password)
 	}
 
-	if c.Debug {
-		fmt.Println("=== [HTTP REQUEST] ===")
-		fmt.Printf("URL: %s\n", req.URL.String())
-		fmt.Printf("Method: %s\n", req.Method)
-		fmt.Println("Headers:")
-		for k, v := range req.Header {
-			fmt.Printf("  %s: %s\n", k, strings.Join(v, ", "))
+	// Enkripsi/tanda tangani body sebelum auth dan signer dipasang
+	if codec := c.payloadCodec(options); codec != nil && options.RequestBody != nil {
+		if !replayableBody(options) {
+			return nil, nil, errors.New("error encode payload: streaming body is not supported by PayloadCodec")
 		}
-		if body != nil {
-			fmt.Printf("Body: %s\n", string(body))
+		var encoded []byte
+		err := safeCall("payload codec", func() (err error) {
+			encoded, err = codec.EncodePayload(ctx, body, req.Header)
+			return err
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("error encode payload: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(encoded))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(encoded)), nil
+		}
+		req.ContentLength = int64(len(encoded))
+	}
+
+	// Trailer request memaksa body dikirim chunked
+	if err := applyRequestTrailers(req, options.Trailers); err != nil {
+		return nil, nil, err
+	}
+
+	// Minta 100 Continue sebelum mengirim body besar
+	c.applyExpectContinue(req)
+
+	// Jalankan mutator sebelum kredensial supaya auth melihat URL dan header akhir
+	if err := c.mutateRequest(ctx, req, options); err != nil {
+		return nil, nil, err
+	}
+
+	// Set API key per request atau milik client
+	if apiKey != nil {
+		apiKey.apply(req)
+	}
+
+	// Set Bearer token jika diisi atau tersedia dari TokenProvider
+	if err := c.applyBearerToken(ctx, req, options); err != nil {
+		return nil, nil, err
+	}
+
+	// Pasang kredensial dari AuthProvider
+	if auth := c.authProvider(options); auth != nil {
+		if err := auth.Apply(ctx, req); err != nil {
+			return nil, nil, fmt.Errorf("error apply auth: %w", err)
 		}
-		fmt.Println("======================")
+	}
+
+	return req, body, nil
+}
+
+// roundTrip mengirim request lalu membaca, memverifikasi, dan men-decode body response.
+func (c *HttpRequest) roundTrip(ctx context.Context, req *http.Request, options RequestOptions, attempt int) (*ApiResponse, error) {
+	client, err := c.httpClient(options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tunda request jika kuota rate limit host sudah habis
+	if c.rateLimit != nil {
+		if limit, ok := c.rateLimitOptions(req.URL.Hostname()); ok {
+			waited, err := c.rateLimit.wait(ctx, req.URL.Host, limit)
+			c.stats.waitedRateLimit(waited)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Tunggu slot antrian jika antrian request aktif
+	if c.queue != nil {
+		release, err := c.queue.acquire(ctx, options.Priority)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
 	}
 
 	// Eksekusi request
-	resp, err := c.Client.Do(req)
+	untraced := req
+	c.stats.start(req.ContentLength)
+	if c.Metrics != nil {
+		c.Metrics.RequestStarted(req.Method, req.URL.Host)
+	}
+	var timings *timingsRecorder
+	if c.CaptureTimings || c.SlowRequestThreshold > 0 {
+		timings = newTimingsRecorder()
+		req = timings.trace(req)
+	}
+	var wire *byteCounter
+	if c.countBytes {
+		req, wire = traceBytes(req)
+	}
+	if c.pool != nil {
+		var done func()
+		req, done = c.pool.trace(req)
+		defer done()
+	}
+	if c.recycle != nil {
+		var done func()
+		req, done = traceRecycle(req)
+		defer done()
+	}
+	var negotiated func() string
+	if c.h1Fallback != nil {
+		req, negotiated = traceProtocol(req)
+	}
+	upload, download := c.bandwidthLimits(options)
+	req = throttleRequestBody(ctx, req, upload)
+	start := time.Now()
+	resp, err := client.Do(req)
+	var http2Err error
+	if err != nil && c.h1Fallback != nil && client == c.Client && replayableBody(options) {
+		// Ulangi lewat HTTP/1.1 jika HTTP/2 gagal di tengah jalan
+		if retry, ok := http1FallbackRequest(untraced, err, negotiated()); ok {
+			c.logger().Infof("[HTTP2 FALLBACK] %s %s: retrying over HTTP/1.1 after: %v", req.Method, c.redactURL(req.URL, c.requestApiKey(req)), err)
+			http2Err = err
+			resp, err = c.h1Fallback.httpClient(c).Do(throttleRequestBody(ctx, retry, upload))
+		}
+	}
 	if err != nil {
+		err = wrapTransportError(wrapHeaderLimitError(err))
+		c.logError(ctx, req, attempt, time.Since(start), err)
+		c.observeRequest(req, nil, nil, wire, time.Since(start), err)
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	// Tolak response dengan header terlalu banyak sebelum body dibaca
+	if err := c.checkResponseHeaders(resp); err != nil {
+		c.logError(ctx, req, attempt, time.Since(start), err)
+		c.observeRequest(req, resp, nil, wire, time.Since(start), err)
+		return nil, err
+	}
+
+	if c.rateLimit != nil {
+		c.rateLimit.update(req.URL.Host, resp, time.Now())
+	}
+
+	requestID := c.responseRequestID(ctx, req, resp)
+
 	// Baca response body
-	respByte, err := io.ReadAll(resp.Body)
+	respByte, err := io.ReadAll(newThrottledBody(ctx, resp.Body, download))
+	var phaseTimings *Timings
+	if timings != nil {
+		phaseTimings = timings.finish()
+	}
+	duration := time.Since(start)
 	if err != nil {
+		err = wrapTransportError(err)
+	}
+	c.observeRequest(req, resp, respByte, wire, duration, err)
+	if err != nil {
+		c.logError(ctx, req, attempt, duration, err)
 		return nil, err
 	}
+	if c.SlowRequestThreshold > 0 && duration >= c.SlowRequestThreshold {
+		c.logSlowRequest(ctx, req, resp.StatusCode, attempt, duration, phaseTimings)
+	}
+	if !c.CaptureTimings {
+		phaseTimings = nil
+	}
 
 	// Simpan response headers ke map
 	headers := make(map[string]string)
@@ -149,45 +674,149 @@ func (c *HttpRequest) Request(ctx context.Context, options RequestOptions) (*Api
 		}
 	}
 
-	// Debug: print response details
-	if c.Debug {
-		fmt.Println("=== [HTTP RESPONSE] ===")
-		fmt.Printf("Status Code: %d\n", resp.StatusCode)
-		fmt.Println("Headers:")
-		for k, v := range resp.Header {
-			fmt.Printf("  %s: %s\n", k, strings.Join(v, ", "))
-		}
-		fmt.Printf("Body: %s\n", string(respByte))
-		fmt.Println("=======================")
+	// Verifikasi signature atas body asli dari server
+	verifier := options.Verifier
+	if verifier == nil {
+		verifier = c.Verifier
 	}
-
-	// Jika ada ResponseTarget, unmarshal otomatis
-	if options.ResponseTarget != nil {
-		contentType := options.ContentType
-		if contentType == "" {
-			contentType = resp.Header.Get("Content-Type")
+	if verifier != nil {
+		if err := safeCall("verifier", func() error { return verifier.VerifyResponse(ctx, resp, respByte) }); err != nil {
+			return nil, err
 		}
+	}
 
-		switch {
-		case strings.Contains(contentType, "application/json"), contentType == "":
-			if err := json.Unmarshal(respByte, options.ResponseTarget); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal JSON response: %w", err)
-			}
-		case strings.Contains(contentType, "application/xml"):
-			if err := xml.Unmarshal(respByte, options.ResponseTarget); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal XML response: %w", err)
-			}
-		default:
-			// fallback JSON
-			if err := json.Unmarshal(respByte, options.ResponseTarget); err != nil {
-				return nil, fmt.Errorf("unsupported Content-Type (%s) and failed JSON fallback: %w", contentType, err)
+	// Dekripsi/verifikasi body sebelum di-decode ke ResponseTarget
+	if codec := c.payloadCodec(options); codec != nil {
+		err = safeCall("payload codec", func() (err error) {
+			respByte, err = codec.DecodePayload(ctx, respByte, resp.Header)
+			return err
+		})
+		if err != nil {
+			return nil, &DecodeError{
+				ContentType: resp.Header.Get("Content-Type"),
+				Err:         fmt.Errorf("error decode payload: %w", err),
 			}
 		}
 	}
 
+	// Debug: print response details
+	if c.debugEnabled(ctx, req) {
+		c.debugResponse(ctx, req, resp, respByte, attempt, time.Since(start))
+	}
+
+	wireSent, wireReceived := wire.totals()
 	return &ApiResponse{
-		StatusCode: resp.StatusCode,
-		Body:       respByte,
-		Headers:    headers,
+		StatusCode:        resp.StatusCode,
+		Proto:             resp.Proto,
+		Body:              respByte,
+		Headers:           headers,
+		Trailers:          responseTrailers(resp),
+		RequestID:         requestID,
+		Timings:           phaseTimings,
+		WireBytesSent:     wireSent,
+		WireBytesReceived: wireReceived,
+		HTTP2Error:        http2Err,
 	}, nil
 }
+
+// decodeResponseTarget meng-unmarshal body ke options.ResponseTarget jika
+// diisi. Error dibungkus *DecodeError.
+func decodeResponseTarget(options RequestOptions, resp *ApiResponse) error {
+	if options.ResponseTarget == nil {
+		return nil
+	}
+	if err := decodeResponse(options, resp); err != nil {
+		return &DecodeError{ContentType: resp.Headers["Content-Type"], Err: err}
+	}
+	return nil
+}
+
+// decodeResponse meng-unmarshal body response ke options.ResponseTarget
+// berdasarkan Content-Type request atau response.
+func decodeResponse(options RequestOptions, resp *ApiResponse) (err error) {
+	defer recoverPanic("unmarshal", &err)
+
+	contentType := options.ContentType
+	if contentType == "" {
+		contentType = resp.Headers["Content-Type"]
+	}
+
+	switch {
+	case strings.Contains(contentType, "application/json"), contentType == "":
+		if err := json.Unmarshal(resp.Body, options.ResponseTarget); err != nil {
+			return fmt.Errorf("failed to unmarshal JSON response: %w", err)
+		}
+	case strings.Contains(contentType, "application/xml"):
+		if err := xml.Unmarshal(resp.Body, options.ResponseTarget); err != nil {
+			return fmt.Errorf("failed to unmarshal XML response: %w", err)
+		}
+	default:
+		// fallback JSON
+		if err := json.Unmarshal(resp.Body, options.ResponseTarget); err != nil {
+			return fmt.Errorf("unsupported Content-Type (%s) and failed JSON fallback: %w", contentType, err)
+		}
+	}
+	return nil
+}
+
+// observeRequest melaporkan hasil satu percobaan ke Stats dan Metrics.
+func (c *HttpRequest) observeRequest(req *http.Request, resp *http.Response, body []byte, wire *byteCounter, duration time.Duration, err error) {
+	c.stats.finish(int64(len(body)), err)
+	if c.Metrics == nil {
+		return
+	}
+	metrics := RequestMetrics{
+		Method:       req.Method,
+		Host:         req.URL.Host,
+		Err:          err,
+		Duration:     duration,
+		ResponseSize: int64(len(body)),
+	}
+	if req.ContentLength > 0 {
+		metrics.RequestSize = req.ContentLength
+	}
+	if resp != nil {
+		metrics.StatusCode = resp.StatusCode
+	}
+	metrics.WireBytesSent, metrics.WireBytesReceived = wire.totals()
+	c.Metrics.RequestFinished(metrics)
+}
+
+// payloadCodec mengembalikan PayloadCodec per request atau milik client.
+func (c *HttpRequest) payloadCodec(options RequestOptions) PayloadCodec {
+	if options.PayloadCodec != nil {
+		return options.PayloadCodec
+	}
+	return c.PayloadCodec
+}
+
+// authProvider mengembalikan AuthProvider per request atau milik client.
+func (c *HttpRequest) authProvider(options RequestOptions) AuthProvider {
+	if options.Auth != nil {
+		return options.Auth
+	}
+	return c.Auth
+}
+
+// cloneHeaders menyalin map header supaya map milik pemanggil tidak ikut berubah.
+func cloneHeaders(headers map[string]string) map[string]string {
+	cloned := make(map[string]string, len(headers))
+	for k, v := range headers {
+		cloned[k] = v
+	}
+	return cloned
+}
+
+// mergeHeaders menggabungkan header default dengan header yang menimpanya.
+// Nama header dikanonikalkan, jadi "x-foo" di override menimpa "X-Foo" di
+// base alih-alih keduanya terkirim dalam urutan acak.
+func mergeHeaders(base, override map[string]string) map[string]string {
+	merged := make(map[string]string, len(base)+len(override))
+	for k, v := range base {
+		merged[http.CanonicalHeaderKey(k)] = v
+	}
+	for k, v := range override {
+		merged[http.CanonicalHeaderKey(k)] = v
+	}
+	return merged
+}
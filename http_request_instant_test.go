@@ -137,3 +137,64 @@ func TestContextCancel(t *testing.T) {
 		t.Fatal("expected context deadline exceeded error, got nil")
 	}
 }
+
+func TestUnauthorizedRefreshAndReplay(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Post{ID: 1, Title: "Hello JSON"})
+	}))
+	defer ts.Close()
+
+	refreshed := 0
+	client := NewHttpRequest()
+	client.OnUnauthorized = func(ctx context.Context, options *RequestOptions) error {
+		refreshed++
+		options.Headers["Authorization"] = "Bearer fresh"
+		return nil
+	}
+
+	headers := map[string]string{"Authorization": "Bearer stale"}
+	var post Post
+	resp, err := client.Request(context.TODO(), RequestOptions{
+		Method:         "GET",
+		URL:            ts.URL,
+		Headers:        headers,
+		ResponseTarget: &post,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 200 || post.Title != "Hello JSON" {
+		t.Errorf("unexpected result: status=%d post=%+v", resp.StatusCode, post)
+	}
+	if refreshed != 1 {
+		t.Errorf("expected 1 refresh, got %d", refreshed)
+	}
+	if headers["Authorization"] != "Bearer stale" {
+		t.Errorf("caller headers should not be modified, got %v", headers)
+	}
+}
+
+func TestUnauthorizedReplayOnlyOnce(t *testing.T) {
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.OnUnauthorized = func(ctx context.Context, options *RequestOptions) error { return nil }
+
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusUnauthorized || hits != 2 {
+		t.Errorf("expected 2 hits ending in 401, got status=%d hits=%d", resp.StatusCode, hits)
+	}
+}
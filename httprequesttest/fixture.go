@@ -0,0 +1,193 @@
+package httprequesttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ojipoji/http_request_instant"
+)
+
+// Fixture adalah response yang dimuat dari file fixture, mis.
+//
+//	{"status": 200, "headers": {"ETag": "\"v1\""}, "body_file": "order.json"}
+//
+// Isi body diambil dari salah satu: body (teks apa adanya), json (nilai
+// yang di-marshal dengan Content-Type application/json), atau body_file
+// (path relatif terhadap file fixture).
+type Fixture struct {
+	Status   int               `json:"status" yaml:"status"`
+	Headers  map[string]string `json:"headers" yaml:"headers"`
+	Body     string            `json:"body" yaml:"body"`
+	JSON     interface{}       `json:"json" yaml:"json"`
+	BodyFile string            `json:"body_file" yaml:"body_file"`
+	Delay    string            `json:"delay" yaml:"delay"` // Mis. "200ms", hanya untuk Route
+
+	body  []byte
+	delay time.Duration
+}
+
+var (
+	fixtureFormatsMu sync.RWMutex
+	fixtureFormats   = map[string]func(data []byte, v interface{}) error{
+		".json": json.Unmarshal,
+	}
+)
+
+// RegisterFixtureFormat mendaftarkan decoder untuk ekstensi file fixture.
+// JSON sudah didukung; YAML bisa ditambahkan tanpa menjadikannya dependency
+// package ini:
+//
+//	httprequesttest.RegisterFixtureFormat(".yaml", yaml.Unmarshal)
+//	httprequesttest.RegisterFixtureFormat(".yml", yaml.Unmarshal)
+func RegisterFixtureFormat(ext string, unmarshal func(data []byte, v interface{}) error) {
+	fixtureFormatsMu.Lock()
+	defer fixtureFormatsMu.Unlock()
+	fixtureFormats[strings.ToLower(ext)] = unmarshal
+}
+
+// LoadFixture memuat satu response dari file fixture. Test gagal jika file
+// tidak bisa dibaca atau formatnya tidak dikenal.
+func LoadFixture(t testing.TB, path string) *Fixture {
+	t.Helper()
+	var f Fixture
+	if err := decodeFixtureFile(path, &f); err != nil {
+		t.Fatalf("httprequesttest: %v", err)
+	}
+	if err := f.resolve(filepath.Dir(path)); err != nil {
+		t.Fatalf("httprequesttest: fixture %s: %v", path, err)
+	}
+	return &f
+}
+
+// LoadRoutes memuat tabel route untuk NewFixtureServer dari file yang
+// memetakan pattern ke fixture, mis. {"GET /orders/{id}": {"body_file": "order.json"}}.
+func LoadRoutes(t testing.TB, path string) Routes {
+	t.Helper()
+	var fixtures map[string]*Fixture
+	if err := decodeFixtureFile(path, &fixtures); err != nil {
+		t.Fatalf("httprequesttest: %v", err)
+	}
+	routes := make(Routes, len(fixtures))
+	for pattern, f := range fixtures {
+		if f == nil {
+			f = &Fixture{}
+		}
+		if err := f.resolve(filepath.Dir(path)); err != nil {
+			t.Fatalf("httprequesttest: fixture %s route %q: %v", path, pattern, err)
+		}
+		routes[pattern] = f.Route()
+	}
+	return routes
+}
+
+func decodeFixtureFile(path string, v interface{}) error {
+	ext := strings.ToLower(filepath.Ext(path))
+	fixtureFormatsMu.RLock()
+	unmarshal := fixtureFormats[ext]
+	fixtureFormatsMu.RUnlock()
+	if unmarshal == nil {
+		return fmt.Errorf("no fixture format registered for %q (see RegisterFixtureFormat)", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := unmarshal(data, v); err != nil {
+		return fmt.Errorf("decode fixture %s: %w", path, err)
+	}
+	return nil
+}
+
+// resolve mengisi body dan delay; body_file dibaca relatif terhadap dir.
+func (f *Fixture) resolve(dir string) error {
+	if f.Status == 0 {
+		f.Status = http.StatusOK
+	}
+	if f.Headers == nil {
+		f.Headers = map[string]string{}
+	}
+	set := 0
+	for _, present := range []bool{f.Body != "", f.JSON != nil, f.BodyFile != ""} {
+		if present {
+			set++
+		}
+	}
+	if set > 1 {
+		return fmt.Errorf("only one of body, json, and body_file may be set")
+	}
+
+	switch {
+	case f.BodyFile != "":
+		path := f.BodyFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		body, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		f.body = body
+	case f.JSON != nil:
+		body, err := json.Marshal(f.JSON)
+		if err != nil {
+			return fmt.Errorf("marshal json: %w", err)
+		}
+		f.body = body
+		if _, ok := f.Headers["Content-Type"]; !ok {
+			f.Headers["Content-Type"] = "application/json"
+		}
+	default:
+		f.body = []byte(f.Body)
+	}
+
+	if f.Delay != "" {
+		delay, err := time.ParseDuration(f.Delay)
+		if err != nil {
+			return fmt.Errorf("invalid delay: %w", err)
+		}
+		f.delay = delay
+	}
+	return nil
+}
+
+// Response mengubah fixture menjadi ApiResponse untuk MockHttpRequest.Return.
+func (f *Fixture) Response() *http_request_instant.ApiResponse {
+	return &http_request_instant.ApiResponse{
+		StatusCode: f.Status,
+		Body:       append([]byte(nil), f.body...),
+		Headers:    maps.Clone(f.Headers),
+	}
+}
+
+// Route mengubah fixture menjadi Route untuk NewFixtureServer.
+func (f *Fixture) Route() Route {
+	return Route{
+		Status:  f.Status,
+		Headers: maps.Clone(f.Headers),
+		Body:    string(f.body),
+		Delay:   f.delay,
+	}
+}
+
+// ReturnFixture menambahkan response dari fixture ke antrian.
+func (m *MockHttpRequest) ReturnFixture(f *Fixture) *MockHttpRequest {
+	return m.Return(f.Response(), nil)
+}
+
+// RespondFixture mengatur status, header, dan body response dari fixture.
+func (e *Expectation) RespondFixture(f *Fixture) *Expectation {
+	e.status = f.Status
+	e.body = append([]byte(nil), f.body...)
+	for k, v := range f.Headers {
+		e.header.Set(k, v)
+	}
+	return e
+}
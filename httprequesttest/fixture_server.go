@@ -0,0 +1,78 @@
+package httprequesttest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Route adalah response tetap untuk satu route di FixtureHandler.
+type Route struct {
+	Status  int               // Default 200
+	Headers map[string]string // Header response
+	Body    string            // Body response apa adanya
+	JSON    interface{}       // Jika diisi, di-marshal menjadi body dengan Content-Type application/json
+	Delay   time.Duration     // Jeda sebelum menjawab, mis. untuk menguji timeout
+}
+
+// Routes memetakan pattern http.ServeMux ke response, mis.
+// "GET /orders/{id}" atau "/health". Route yang tidak terdaftar dijawab 404.
+type Routes map[string]Route
+
+// FixtureHandler membuat http.Handler dari tabel routes. Panic jika ada
+// pattern yang tidak valid atau saling bentrok, seperti http.ServeMux.
+func FixtureHandler(routes Routes) http.Handler {
+	mux := http.NewServeMux()
+	for pattern, route := range routes {
+		mux.Handle(pattern, route.handler())
+	}
+	return mux
+}
+
+// NewFixtureServer menjalankan server httptest dari tabel routes yang
+// ditutup saat test selesai.
+func NewFixtureServer(t testing.TB, routes Routes) *httptest.Server {
+	server := httptest.NewServer(FixtureHandler(routes))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// NewFixtureTLSServer sama dengan NewFixtureServer dengan HTTPS. Pakai
+// server.Certificate() untuk AddRootCAPEM atau SetRootCAs client.
+func NewFixtureTLSServer(t testing.TB, routes Routes) *httptest.Server {
+	server := httptest.NewTLSServer(FixtureHandler(routes))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (route Route) handler() http.Handler {
+	body := []byte(route.Body)
+	if route.JSON != nil {
+		body = mustMarshal(route.JSON)
+	}
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if route.Delay > 0 {
+			timer := time.NewTimer(route.Delay)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if route.JSON != nil {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		for k, v := range route.Headers {
+			w.Header().Set(k, v)
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write(body)
+	})
+}
@@ -0,0 +1,62 @@
+package httprequesttest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ojipoji/http_request_instant"
+)
+
+func TestNewFixtureServer(t *testing.T) {
+	server := NewFixtureServer(t, Routes{
+		"GET /orders/{id}": {JSON: map[string]int{"id": 7}, Headers: map[string]string{"ETag": `"v1"`}},
+		"POST /orders":     {Status: 201, Body: "created", Headers: map[string]string{"Location": "/orders/7"}},
+		"/slow":            {Body: "late", Delay: 500 * time.Millisecond},
+	})
+	client := http_request_instant.NewHttpRequest()
+
+	var order struct{ ID int }
+	resp, err := client.Request(context.TODO(), http_request_instant.RequestOptions{Method: "GET", URL: server.URL + "/orders/7", ResponseTarget: &order})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if order.ID != 7 || resp.Headers["Etag"] != `"v1"` || resp.Headers["Content-Type"] != "application/json" {
+		t.Errorf("unexpected response %+v %v", order, resp.Headers)
+	}
+
+	resp, err = client.Request(context.TODO(), http_request_instant.RequestOptions{Method: "POST", URL: server.URL + "/orders", RequestBody: "{}"})
+	if err != nil || resp.StatusCode != 201 || string(resp.Body) != "created" || resp.Headers["Location"] != "/orders/7" {
+		t.Errorf("unexpected response %v %v", resp, err)
+	}
+
+	resp, err = client.Request(context.TODO(), http_request_instant.RequestOptions{Method: "DELETE", URL: server.URL + "/orders/7"})
+	if err != nil || resp.StatusCode != 405 {
+		t.Errorf("expected 405 for unregistered method, got %v %v", resp, err)
+	}
+	resp, err = client.Request(context.TODO(), http_request_instant.RequestOptions{Method: "GET", URL: server.URL + "/missing"})
+	if err != nil || resp.StatusCode != 404 {
+		t.Errorf("expected 404 for unknown route, got %v %v", resp, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = client.Request(ctx, http_request_instant.RequestOptions{Method: "GET", URL: server.URL + "/slow"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded from delayed route, got %v", err)
+	}
+}
+
+func TestNewFixtureTLSServer(t *testing.T) {
+	server := NewFixtureTLSServer(t, Routes{"/": {Body: "secure"}})
+	client := http_request_instant.NewHttpRequest()
+	if err := client.SetRootCAs(server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := client.Request(context.TODO(), http_request_instant.RequestOptions{Method: "GET", URL: server.URL})
+	if err != nil || string(resp.Body) != "secure" {
+		t.Errorf("unexpected response %v %v", resp, err)
+	}
+}
@@ -0,0 +1,79 @@
+package httprequesttest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ojipoji/http_request_instant"
+)
+
+type fixtureOrder struct {
+	ID    int
+	Items []struct {
+		SKU string
+		Qty int
+	}
+}
+
+func TestLoadFixture(t *testing.T) {
+	fixture := LoadFixture(t, "testdata/order.json")
+
+	var order fixtureOrder
+	mock := NewMockHttpRequest().ReturnFixture(fixture)
+	resp, err := mock.Request(context.TODO(), http_request_instant.RequestOptions{Method: "GET", URL: "/orders/7", ResponseTarget: &order})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if order.ID != 7 || len(order.Items) != 1 || order.Items[0].SKU != "A1" || resp.Headers["ETag"] != `"v1"` {
+		t.Errorf("unexpected response %+v %v", order, resp.Headers)
+	}
+
+	stub := NewStub(t)
+	stub.Expect("GET", "/orders/7").RespondFixture(fixture)
+	order = fixtureOrder{}
+	if _, err := stub.Client().Request(context.TODO(), http_request_instant.RequestOptions{Method: "GET", URL: "http://svc/orders/7", ResponseTarget: &order}); err != nil || order.ID != 7 {
+		t.Errorf("unexpected stub response %+v %v", order, err)
+	}
+
+	// Format lain didaftarkan lewat RegisterFixtureFormat, mis. yaml.Unmarshal
+	RegisterFixtureFormat(".fixture", json.Unmarshal)
+	if f := LoadFixture(t, "testdata/not_found.fixture"); f.Status != 404 || string(f.Response().Body) != "no such order" {
+		t.Errorf("unexpected fixture %+v", f)
+	}
+
+	var invalid Fixture
+	if err := decodeFixtureFile("testdata/invalid.json", &invalid); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := invalid.resolve("testdata"); err == nil || !strings.Contains(err.Error(), "only one of") {
+		t.Errorf("expected conflicting body error, got %v", err)
+	}
+	if err := decodeFixtureFile("testdata/order.yaml", &invalid); err == nil || !strings.Contains(err.Error(), "RegisterFixtureFormat") {
+		t.Errorf("expected unregistered format error, got %v", err)
+	}
+}
+
+func TestLoadRoutes(t *testing.T) {
+	server := NewFixtureServer(t, LoadRoutes(t, "testdata/routes.json"))
+	client := http_request_instant.NewHttpRequest()
+
+	var order fixtureOrder
+	if _, err := client.Request(context.TODO(), http_request_instant.RequestOptions{Method: "GET", URL: server.URL + "/orders/7", ResponseTarget: &order}); err != nil || order.ID != 7 {
+		t.Errorf("unexpected response %+v %v", order, err)
+	}
+
+	resp, err := client.Request(context.TODO(), http_request_instant.RequestOptions{Method: "POST", URL: server.URL + "/orders", RequestBody: "{}"})
+	if err != nil || resp.StatusCode != 201 || string(resp.Body) != `{"id":8}` || resp.Headers["Content-Type"] != "application/json" {
+		t.Errorf("unexpected response %v %v", resp, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.Request(ctx, http_request_instant.RequestOptions{Method: "GET", URL: server.URL + "/slow"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected delay from fixture, got %v", err)
+	}
+}
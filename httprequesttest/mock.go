@@ -0,0 +1,183 @@
+// Package httprequesttest menyediakan utilitas untuk menguji kode yang
+// memakai http_request_instant tanpa server sungguhan.
+package httprequesttest
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+
+	"github.com/ojipoji/http_request_instant"
+)
+
+// ErrNoResponse dikembalikan MockHttpRequest yang dipanggil tanpa response
+// dari Return maupun RequestFunc.
+var ErrNoResponse = errors.New("httprequesttest: no response programmed")
+
+// Call adalah satu pemanggilan Request yang direkam MockHttpRequest.
+type Call struct {
+	Ctx     context.Context
+	Options http_request_instant.RequestOptions
+}
+
+type mockResult struct {
+	resp *http_request_instant.ApiResponse
+	err  error
+}
+
+// MockHttpRequest adalah implementasi HttpRequestInf untuk unit test. Setiap
+// pemanggilan Request direkam dan dijawab dengan response yang diprogram
+// lewat Return. Aman dipakai secara konkuren.
+type MockHttpRequest struct {
+	// Optional: menjawab request jika tidak ada response dari Return,
+	// mis. untuk response yang bergantung pada options.
+	RequestFunc func(ctx context.Context, options http_request_instant.RequestOptions) (*http_request_instant.ApiResponse, error)
+
+	mu      sync.Mutex
+	calls   []Call
+	results []mockResult
+}
+
+var _ http_request_instant.HttpRequestInf = (*MockHttpRequest)(nil)
+
+// NewMockHttpRequest membuat MockHttpRequest kosong.
+func NewMockHttpRequest() *MockHttpRequest {
+	return &MockHttpRequest{}
+}
+
+// Return menambahkan hasil ke antrian. Hasil dipakai berurutan per
+// pemanggilan; hasil terakhir dipakai terus setelah antrian habis.
+func (m *MockHttpRequest) Return(resp *http_request_instant.ApiResponse, err error) *MockHttpRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.results = append(m.results, mockResult{resp: resp, err: err})
+	return m
+}
+
+// ReturnStatus menambahkan response dengan status dan body apa adanya.
+func (m *MockHttpRequest) ReturnStatus(statusCode int, body string) *MockHttpRequest {
+	return m.Return(&http_request_instant.ApiResponse{StatusCode: statusCode, Body: []byte(body), Headers: map[string]string{}}, nil)
+}
+
+// ReturnJSON menambahkan response dengan body v yang di-marshal ke JSON.
+// Panic jika v tidak bisa di-marshal.
+func (m *MockHttpRequest) ReturnJSON(statusCode int, v interface{}) *MockHttpRequest {
+	return m.Return(&http_request_instant.ApiResponse{
+		StatusCode: statusCode,
+		Body:       mustMarshal(v),
+		Headers:    map[string]string{"Content-Type": "application/json"},
+	}, nil)
+}
+
+// ReturnError menambahkan error transport, mis. context.DeadlineExceeded.
+func (m *MockHttpRequest) ReturnError(err error) *MockHttpRequest {
+	return m.Return(nil, err)
+}
+
+// Request merekam pemanggilan lalu mengembalikan hasil berikutnya. Seperti
+// client sungguhan, body di-decode ke options.ResponseTarget jika diisi.
+func (m *MockHttpRequest) Request(ctx context.Context, options http_request_instant.RequestOptions) (*http_request_instant.ApiResponse, error) {
+	m.mu.Lock()
+	m.calls = append(m.calls, Call{Ctx: ctx, Options: options})
+	var result mockResult
+	programmed := len(m.results) > 0
+	if programmed {
+		result = m.results[0]
+		if len(m.results) > 1 {
+			m.results = m.results[1:]
+		}
+	}
+	requestFunc := m.RequestFunc
+	m.mu.Unlock()
+
+	switch {
+	case programmed:
+	case requestFunc != nil:
+		result.resp, result.err = requestFunc(ctx, options)
+	default:
+		return nil, fmt.Errorf("%w: %s %s", ErrNoResponse, options.Method, options.URL)
+	}
+	if result.err != nil {
+		return nil, result.err
+	}
+	resp := cloneResponse(result.resp)
+	if err := decodeResponseTarget(options, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Calls mengembalikan salinan semua pemanggilan yang terekam.
+func (m *MockHttpRequest) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// CallCount mengembalikan jumlah pemanggilan Request.
+func (m *MockHttpRequest) CallCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.calls)
+}
+
+// LastCall mengembalikan pemanggilan terakhir; false jika belum ada.
+func (m *MockHttpRequest) LastCall() (Call, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.calls) == 0 {
+		return Call{}, false
+	}
+	return m.calls[len(m.calls)-1], true
+}
+
+// Reset menghapus pemanggilan yang terekam dan antrian hasil.
+func (m *MockHttpRequest) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = nil
+	m.results = nil
+}
+
+// cloneResponse menyalin response supaya pemanggil yang mengubah Body atau
+// Headers tidak mengubah hasil untuk pemanggilan berikutnya.
+func cloneResponse(resp *http_request_instant.ApiResponse) *http_request_instant.ApiResponse {
+	if resp == nil {
+		return &http_request_instant.ApiResponse{StatusCode: 200, Headers: map[string]string{}}
+	}
+	clone := *resp
+	clone.Body = append([]byte(nil), resp.Body...)
+	clone.Headers = maps.Clone(resp.Headers)
+	clone.Trailers = maps.Clone(resp.Trailers)
+	if clone.Headers == nil {
+		clone.Headers = map[string]string{}
+	}
+	return &clone
+}
+
+// decodeResponseTarget mengikuti aturan decode client: JSON atau XML
+// berdasarkan Content-Type request atau response, dengan fallback JSON.
+func decodeResponseTarget(options http_request_instant.RequestOptions, resp *http_request_instant.ApiResponse) error {
+	if options.ResponseTarget == nil {
+		return nil
+	}
+	contentType := options.ContentType
+	if contentType == "" {
+		contentType = resp.Headers["Content-Type"]
+	}
+	var err error
+	if strings.Contains(contentType, "application/xml") {
+		err = xml.Unmarshal(resp.Body, options.ResponseTarget)
+	} else {
+		err = json.Unmarshal(resp.Body, options.ResponseTarget)
+	}
+	if err != nil {
+		return &http_request_instant.DecodeError{ContentType: resp.Headers["Content-Type"], Err: err}
+	}
+	return nil
+}
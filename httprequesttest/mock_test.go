@@ -0,0 +1,84 @@
+package httprequesttest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ojipoji/http_request_instant"
+)
+
+func TestMockHttpRequest(t *testing.T) {
+	mock := NewMockHttpRequest().
+		ReturnJSON(201, map[string]int{"id": 7}).
+		ReturnStatus(404, "not found")
+
+	var created struct{ ID int }
+	resp, err := mock.Request(context.TODO(), http_request_instant.RequestOptions{
+		Method:         "POST",
+		URL:            "https://api.example.com/orders",
+		RequestBody:    map[string]string{"sku": "A1"},
+		ResponseTarget: &created,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 201 || created.ID != 7 {
+		t.Errorf("unexpected response %d %+v", resp.StatusCode, created)
+	}
+	resp.Headers["Content-Type"] = "changed"
+
+	// Hasil terakhir dipakai terus setelah antrian habis
+	for i := 0; i < 2; i++ {
+		resp, err = mock.Request(context.TODO(), http_request_instant.RequestOptions{Method: "GET", URL: "https://api.example.com/orders/8"})
+		if err != nil || resp.StatusCode != 404 || string(resp.Body) != "not found" {
+			t.Errorf("unexpected response %v %v", resp, err)
+		}
+	}
+
+	if mock.CallCount() != 3 {
+		t.Errorf("expected 3 calls, got %d", mock.CallCount())
+	}
+	if calls := mock.Calls(); calls[0].Options.Method != "POST" || calls[0].Options.RequestBody.(map[string]string)["sku"] != "A1" {
+		t.Errorf("unexpected first call %+v", calls[0].Options)
+	}
+	if last, ok := mock.LastCall(); !ok || last.Options.URL != "https://api.example.com/orders/8" {
+		t.Errorf("unexpected last call %+v", last.Options)
+	}
+
+	// Body yang tidak bisa di-decode menjadi *DecodeError seperti client asli
+	var decodeErr *http_request_instant.DecodeError
+	_, err = mock.Request(context.TODO(), http_request_instant.RequestOptions{URL: "x", ResponseTarget: &created})
+	if !errors.As(err, &decodeErr) {
+		t.Errorf("expected DecodeError, got %v", err)
+	}
+}
+
+func TestMockHttpRequestErrorsAndFunc(t *testing.T) {
+	mock := NewMockHttpRequest()
+	if _, err := mock.Request(context.TODO(), http_request_instant.RequestOptions{Method: "GET", URL: "x"}); !errors.Is(err, ErrNoResponse) {
+		t.Errorf("expected ErrNoResponse, got %v", err)
+	}
+
+	mock.RequestFunc = func(ctx context.Context, options http_request_instant.RequestOptions) (*http_request_instant.ApiResponse, error) {
+		return &http_request_instant.ApiResponse{StatusCode: 200, Body: []byte(options.URL)}, nil
+	}
+	resp, err := mock.Request(context.TODO(), http_request_instant.RequestOptions{Method: "GET", URL: "/ping"})
+	if err != nil || string(resp.Body) != "/ping" {
+		t.Errorf("unexpected response %v %v", resp, err)
+	}
+
+	// Return mengalahkan RequestFunc
+	mock.ReturnError(context.DeadlineExceeded)
+	if _, err := mock.Request(context.TODO(), http_request_instant.RequestOptions{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline error, got %v", err)
+	}
+
+	mock.Reset()
+	if mock.CallCount() != 0 {
+		t.Errorf("expected calls cleared")
+	}
+	if _, ok := mock.LastCall(); ok {
+		t.Errorf("expected no last call")
+	}
+}
@@ -0,0 +1,357 @@
+package httprequesttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ojipoji/http_request_instant"
+)
+
+// ErrUnexpectedRequest dikembalikan transport Stub untuk request yang tidak
+// cocok dengan expectation mana pun.
+var ErrUnexpectedRequest = errors.New("httprequesttest: unexpected request")
+
+// Stub menjawab request berdasarkan expectation yang dideklarasikan test.
+// Stub bisa dipakai sebagai transport (Client) atau di belakang server
+// httptest (Server). Request yang tidak diharapkan dan expectation yang
+// kurang dipanggil dilaporkan sebagai kegagalan test.
+type Stub struct {
+	t            testing.TB
+	mu           sync.Mutex
+	expectations []*Expectation
+}
+
+var (
+	_ http.RoundTripper = (*Stub)(nil)
+	_ http.Handler      = (*Stub)(nil)
+)
+
+// NewStub membuat Stub yang mengecek expectation yang belum terpenuhi saat
+// test selesai.
+func NewStub(t testing.TB) *Stub {
+	s := &Stub{t: t}
+	t.Cleanup(s.AssertExpectations)
+	return s
+}
+
+// Expect mendeklarasikan request yang diharapkan. method kosong cocok
+// dengan method apa pun. pattern dicocokkan dengan path request, atau
+// path beserta query jika pattern mengandung "?", atau URL lengkap jika
+// pattern mengandung "://". "*" cocok dengan karakter apa pun selain "/".
+// Secara default expectation harus dipanggil tepat sekali dan menjawab 200.
+// Jika beberapa expectation cocok, yang dideklarasikan lebih dulu dan
+// belum habis dipakai.
+func (s *Stub) Expect(method, pattern string) *Expectation {
+	e := &Expectation{
+		stub:    s,
+		method:  strings.ToUpper(method),
+		pattern: pattern,
+		re:      compilePattern(pattern),
+		times:   1,
+		status:  http.StatusOK,
+		header:  make(http.Header),
+	}
+	s.mu.Lock()
+	s.expectations = append(s.expectations, e)
+	s.mu.Unlock()
+	return e
+}
+
+// Client membuat HttpRequest yang memakai Stub sebagai transport, jadi
+// request tetap melewati pipeline client (header, auth, decode) tanpa
+// jaringan.
+func (s *Stub) Client() *http_request_instant.HttpRequest {
+	return http_request_instant.NewHttpRequestWithTransport(s)
+}
+
+// Server menjalankan server httptest yang dijawab Stub, untuk kode yang
+// membutuhkan URL sungguhan. Server ditutup saat test selesai.
+func (s *Stub) Server() *httptest.Server {
+	server := httptest.NewServer(s)
+	s.t.Cleanup(server.Close)
+	return server
+}
+
+// AssertExpectations melaporkan expectation yang dipanggil lebih sedikit
+// dari yang diharapkan. Dipanggil otomatis saat test selesai.
+func (s *Stub) AssertExpectations() {
+	s.t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.expectations {
+		if e.times >= 0 && e.calls < e.times {
+			s.t.Errorf("httprequesttest: expected %s to be called %d times, got %d", e, e.times, e.calls)
+		}
+	}
+}
+
+// RoundTrip mengimplementasikan http.RoundTripper.
+func (s *Stub) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	e, err := s.match(req, body)
+	if err != nil {
+		return nil, err
+	}
+	if e.err != nil {
+		return nil, e.err
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.status, http.StatusText(e.status)),
+		StatusCode:    e.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}, nil
+}
+
+// ServeHTTP mengimplementasikan http.Handler. Request yang tidak diharapkan
+// dijawab 501; RespondError memutus koneksi.
+func (s *Stub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := readBody(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	e, err := s.match(r, body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+		return
+	}
+	if e.err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	for k, v := range e.header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(e.status)
+	_, _ = w.Write(e.body)
+}
+
+// match mencari expectation untuk req dan mencatat pemanggilannya.
+func (s *Stub) match(req *http.Request, body []byte) (*Expectation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var exhausted *Expectation
+	for _, e := range s.expectations {
+		if !e.matches(req, body) {
+			continue
+		}
+		if e.times >= 0 && e.calls >= e.times {
+			exhausted = e
+			continue
+		}
+		e.calls++
+		return e, nil
+	}
+
+	err := fmt.Errorf("%w: %s %s", ErrUnexpectedRequest, req.Method, req.URL)
+	if exhausted != nil {
+		err = fmt.Errorf("%w: %s already called %d times", err, exhausted, exhausted.calls)
+	}
+	s.t.Errorf("%v", err)
+	return nil, err
+}
+
+func readBody(body io.ReadCloser) ([]byte, error) {
+	if body == nil {
+		return nil, nil
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+// compilePattern mengubah pattern dengan wildcard "*" menjadi regexp.
+func compilePattern(pattern string) *regexp.Regexp {
+	quoted := strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, `[^/]*`)
+	return regexp.MustCompile("^" + quoted + "$")
+}
+
+type requestMatcher struct {
+	desc  string
+	match func(req *http.Request, body []byte) bool
+}
+
+// Expectation adalah satu request yang diharapkan Stub beserta response-nya.
+// Method-nya mengembalikan Expectation yang sama supaya bisa dirangkai.
+// Konfigurasikan sebelum request pertama dikirim.
+type Expectation struct {
+	stub     *Stub
+	method   string
+	pattern  string
+	re       *regexp.Regexp
+	matchers []requestMatcher
+	times    int // -1 berarti berapa kali pun
+	calls    int
+
+	status int
+	header http.Header
+	body   []byte
+	err    error
+}
+
+func (e *Expectation) String() string {
+	method := e.method
+	if method == "" {
+		method = "*"
+	}
+	desc := method + " " + e.pattern
+	for _, m := range e.matchers {
+		desc += " " + m.desc
+	}
+	return desc
+}
+
+func (e *Expectation) matches(req *http.Request, body []byte) bool {
+	if e.method != "" && e.method != req.Method {
+		return false
+	}
+	target := req.URL.EscapedPath()
+	switch {
+	case strings.Contains(e.pattern, "://") && req.URL.Host == "":
+		// Request di sisi server tidak membawa skema dan host di URL
+		target = "http://" + req.Host + req.URL.RequestURI()
+	case strings.Contains(e.pattern, "://"):
+		target = req.URL.String()
+	case strings.Contains(e.pattern, "?"):
+		target = req.URL.RequestURI()
+	}
+	if !e.re.MatchString(target) {
+		return false
+	}
+	for _, m := range e.matchers {
+		if !m.match(req, body) {
+			return false
+		}
+	}
+	return true
+}
+
+// WithHeader mensyaratkan header request bernilai value.
+func (e *Expectation) WithHeader(key, value string) *Expectation {
+	return e.Match(fmt.Sprintf("header %s=%q", key, value), func(req *http.Request, body []byte) bool {
+		return req.Header.Get(key) == value
+	})
+}
+
+// WithQuery mensyaratkan query parameter bernilai value.
+func (e *Expectation) WithQuery(key, value string) *Expectation {
+	return e.Match(fmt.Sprintf("query %s=%q", key, value), func(req *http.Request, body []byte) bool {
+		return req.URL.Query().Get(key) == value
+	})
+}
+
+// WithBody mensyaratkan body request sama persis dengan body.
+func (e *Expectation) WithBody(body string) *Expectation {
+	return e.Match(fmt.Sprintf("body %q", body), func(req *http.Request, got []byte) bool {
+		return string(got) == body
+	})
+}
+
+// WithBodyContains mensyaratkan body request mengandung substr.
+func (e *Expectation) WithBodyContains(substr string) *Expectation {
+	return e.Match(fmt.Sprintf("body containing %q", substr), func(req *http.Request, body []byte) bool {
+		return bytes.Contains(body, []byte(substr))
+	})
+}
+
+// WithJSONBody mensyaratkan body request berupa JSON yang setara dengan v,
+// tanpa memedulikan urutan field dan spasi.
+func (e *Expectation) WithJSONBody(v interface{}) *Expectation {
+	want, err := normalizeJSON(v)
+	if err != nil {
+		panic(fmt.Sprintf("httprequesttest: marshal expected body: %v", err))
+	}
+	return e.Match(fmt.Sprintf("JSON body %s", mustMarshal(want)), func(req *http.Request, body []byte) bool {
+		var got interface{}
+		return json.Unmarshal(body, &got) == nil && reflect.DeepEqual(got, want)
+	})
+}
+
+// Match menambahkan matcher sendiri; desc dipakai di pesan kegagalan.
+func (e *Expectation) Match(desc string, match func(req *http.Request, body []byte) bool) *Expectation {
+	e.matchers = append(e.matchers, requestMatcher{desc: desc, match: match})
+	return e
+}
+
+// Times mengatur berapa kali expectation harus dipanggil.
+func (e *Expectation) Times(n int) *Expectation {
+	e.times = n
+	return e
+}
+
+// AnyTimes membuat expectation boleh dipanggil berapa kali pun, termasuk
+// tidak sama sekali.
+func (e *Expectation) AnyTimes() *Expectation {
+	e.times = -1
+	return e
+}
+
+// Respond mengatur status dan body response.
+func (e *Expectation) Respond(status int, body string) *Expectation {
+	e.status = status
+	e.body = []byte(body)
+	return e
+}
+
+// RespondJSON mengatur response dengan body v yang di-marshal ke JSON.
+func (e *Expectation) RespondJSON(status int, v interface{}) *Expectation {
+	e.status = status
+	e.body = mustMarshal(v)
+	e.header.Set("Content-Type", "application/json")
+	return e
+}
+
+// RespondHeader menambahkan header response.
+func (e *Expectation) RespondHeader(key, value string) *Expectation {
+	e.header.Add(key, value)
+	return e
+}
+
+// RespondError membuat request gagal di level transport dengan err. Di
+// belakang Server, koneksi diputus tanpa response.
+func (e *Expectation) RespondError(err error) *Expectation {
+	e.err = err
+	return e
+}
+
+// Calls mengembalikan berapa kali expectation sudah dipanggil.
+func (e *Expectation) Calls() int {
+	e.stub.mu.Lock()
+	defer e.stub.mu.Unlock()
+	return e.calls
+}
+
+func normalizeJSON(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	err = json.Unmarshal(data, &normalized)
+	return normalized, err
+}
+
+func mustMarshal(v interface{}) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("httprequesttest: marshal: %v", err))
+	}
+	return data
+}
@@ -0,0 +1,136 @@
+package httprequesttest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ojipoji/http_request_instant"
+)
+
+// recordingTB menampung kegagalan dan cleanup supaya test bisa memeriksa
+// laporan Stub tanpa menggagalkan dirinya sendiri.
+type recordingTB struct {
+	testing.TB
+	mu       sync.Mutex
+	errors   []string
+	cleanups []func()
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingTB) Cleanup(f func()) {
+	r.cleanups = append(r.cleanups, f)
+}
+
+// finish menjalankan cleanup seperti testing dan mengembalikan kegagalan.
+func (r *recordingTB) finish() []string {
+	for i := len(r.cleanups) - 1; i >= 0; i-- {
+		r.cleanups[i]()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.errors
+}
+
+func TestStubTransport(t *testing.T) {
+	stub := NewStub(t)
+	stub.Expect("POST", "/orders").
+		WithHeader("Authorization", "Bearer tok").
+		WithJSONBody(map[string]interface{}{"sku": "A1", "qty": 2}).
+		RespondJSON(201, map[string]int{"id": 7}).
+		RespondHeader("Location", "/orders/7")
+	get := stub.Expect("GET", "/orders/*").WithQuery("expand", "items").Respond(200, "order").Times(2)
+	stub.Expect("", "https://api.example.com/health").AnyTimes()
+
+	client := stub.Client()
+	var created struct{ ID int }
+	resp, err := client.Request(context.TODO(), http_request_instant.RequestOptions{
+		Method:         "POST",
+		URL:            "https://api.example.com/orders",
+		BearerToken:    "tok",
+		RequestBody:    map[string]interface{}{"qty": 2, "sku": "A1"},
+		ResponseTarget: &created,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 201 || created.ID != 7 || resp.Headers["Location"] != "/orders/7" {
+		t.Errorf("unexpected response %d %+v %v", resp.StatusCode, created, resp.Headers)
+	}
+
+	for _, id := range []string{"1", "2"} {
+		resp, err := client.Request(context.TODO(), http_request_instant.RequestOptions{Method: "GET", URL: "https://api.example.com/orders/" + id + "?expand=items"})
+		if err != nil || string(resp.Body) != "order" {
+			t.Errorf("unexpected response %v %v", resp, err)
+		}
+	}
+	if get.Calls() != 2 {
+		t.Errorf("expected 2 calls, got %d", get.Calls())
+	}
+}
+
+func TestStubFailures(t *testing.T) {
+	tb := &recordingTB{}
+	stub := NewStub(tb)
+	stub.Expect("GET", "/once").Respond(200, "ok")
+	stub.Expect("DELETE", "/never-called")
+	stub.Expect("GET", "/down").RespondError(errors.New("connection reset"))
+
+	client := stub.Client()
+	get := func(url string) error {
+		_, err := client.Request(context.TODO(), http_request_instant.RequestOptions{Method: "GET", URL: url})
+		return err
+	}
+	if err := get("http://svc/once"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := get("http://svc/once"); !errors.Is(err, ErrUnexpectedRequest) || !strings.Contains(err.Error(), "already called 1 times") {
+		t.Errorf("expected exhausted expectation error, got %v", err)
+	}
+	if err := get("http://svc/other"); !errors.Is(err, ErrUnexpectedRequest) {
+		t.Errorf("expected unexpected request error, got %v", err)
+	}
+	if err := get("http://svc/down"); err == nil || !strings.Contains(err.Error(), "connection reset") {
+		t.Errorf("expected transport error, got %v", err)
+	}
+
+	failures := tb.finish()
+	if len(failures) != 3 || !strings.Contains(failures[2], "DELETE /never-called to be called 1 times, got 0") {
+		t.Errorf("unexpected failures: %q", failures)
+	}
+}
+
+func TestStubServer(t *testing.T) {
+	tb := &recordingTB{}
+	stub := NewStub(tb)
+	server := stub.Server()
+	stub.Expect("PUT", server.URL+"/items/*").WithBodyContains(`"name"`).Respond(204, "")
+
+	client := http_request_instant.NewHttpRequest()
+	resp, err := client.Request(context.TODO(), http_request_instant.RequestOptions{
+		Method:      "PUT",
+		URL:         server.URL + "/items/9",
+		RequestBody: map[string]string{"name": "x"},
+	})
+	if err != nil || resp.StatusCode != 204 {
+		t.Errorf("unexpected response %v %v", resp, err)
+	}
+
+	resp, err = client.Request(context.TODO(), http_request_instant.RequestOptions{Method: "GET", URL: server.URL + "/items/9"})
+	if err != nil || resp.StatusCode != 501 {
+		t.Errorf("expected 501 for unexpected request, got %v %v", resp, err)
+	}
+	if failures := tb.finish(); len(failures) != 1 || !strings.Contains(failures[0], "GET /items/9") {
+		t.Errorf("unexpected failures: %q", failures)
+	}
+}
@@ -0,0 +1,133 @@
+package http_request_instant
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// IPFamily menentukan keluarga alamat IP yang dipakai saat connect.
+type IPFamily int
+
+const (
+	IPFamilyAny IPFamily = iota // Default Go: urutan dari resolver dengan happy eyeballs
+	IPv4Only                    // Hanya IPv4
+	IPv6Only                    // Hanya IPv6
+	PreferIPv4                  // IPv4 dulu, IPv6 menyusul setelah FallbackDelay
+	PreferIPv6                  // IPv6 dulu, IPv4 menyusul setelah FallbackDelay
+)
+
+// IPFamilyOptions menyimpan preferensi IPv4/IPv6 client.
+type IPFamilyOptions struct {
+	Family IPFamily
+
+	// Jeda sebelum keluarga alamat kedua ikut dicoba secara paralel
+	// (happy eyeballs, RFC 8305). 0 berarti default Go 300ms; negatif berarti
+	// keluarga kedua baru dicoba setelah yang pertama gagal.
+	FallbackDelay time.Duration
+}
+
+// SetIPFamily mengatur keluarga alamat IP untuk koneksi TCP baru, mis.
+// PreferIPv4 untuk host dengan record AAAA yang tidak bisa dijangkau.
+// Host berupa alamat IP literal di-dial apa adanya.
+func (c *HttpRequest) SetIPFamily(options IPFamilyOptions) error {
+	t, err := c.transport()
+	if err != nil {
+		return err
+	}
+	if options.Family < IPFamilyAny || options.Family > PreferIPv6 {
+		return fmt.Errorf("unknown IPFamily %d", options.Family)
+	}
+	c.ipFamily = options
+	c.netDialer().FallbackDelay = options.FallbackDelay
+	t.DialContext = c.dialContext
+	return nil
+}
+
+// dialFamily menerapkan IPFamilyOptions pada dial TCP.
+func (c *HttpRequest) dialFamily(ctx context.Context, network, addr string) (net.Conn, error) {
+	if network != "tcp" || c.ipFamily.Family == IPFamilyAny {
+		return c.dialAddr(ctx, network, addr)
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil && net.ParseIP(host) != nil {
+		return c.dialAddr(ctx, network, addr)
+	}
+
+	switch c.ipFamily.Family {
+	case IPv4Only:
+		return c.dialAddr(ctx, "tcp4", addr)
+	case IPv6Only:
+		return c.dialAddr(ctx, "tcp6", addr)
+	case PreferIPv6:
+		return c.dialRace(ctx, "tcp6", "tcp4", addr)
+	default:
+		return c.dialRace(ctx, "tcp4", "tcp6", addr)
+	}
+}
+
+// dialRace men-dial lewat primary, lalu ikut mencoba fallback setelah
+// FallbackDelay atau segera setelah primary gagal. Koneksi pertama yang
+// berhasil dipakai; yang lain ditutup.
+func (c *HttpRequest) dialRace(ctx context.Context, primary, fallback, addr string) (net.Conn, error) {
+	delay := c.ipFamily.FallbackDelay
+	if delay == 0 {
+		delay = 300 * time.Millisecond
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	results := make(chan result, 2)
+	start := func(network string) {
+		go func() {
+			conn, err := c.dialAddr(ctx, network, addr)
+			results <- result{conn, err}
+		}()
+	}
+
+	start(primary)
+	pending, fallbackStarted := 1, false
+	var timer <-chan time.Time
+	if delay > 0 {
+		t := time.NewTimer(delay)
+		defer t.Stop()
+		timer = t.C
+	}
+	var firstErr error
+	for {
+		select {
+		case <-timer:
+			if !fallbackStarted {
+				start(fallback)
+				pending, fallbackStarted = pending+1, true
+			}
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				// Dial lain yang masih berjalan dibatalkan dan koneksinya ditutup
+				go func(n int) {
+					for ; n > 0; n-- {
+						if late := <-results; late.conn != nil {
+							late.conn.Close()
+						}
+					}
+				}(pending)
+				return r.conn, nil
+			}
+			if firstErr == nil {
+				firstErr = r.err
+			}
+			if !fallbackStarted && ctx.Err() == nil {
+				start(fallback)
+				pending, fallbackStarted = pending+1, true
+			}
+			if pending == 0 {
+				return nil, firstErr
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package http_request_instant
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// dualStackServer melayani port yang sama di ::1 dan 127.0.0.1, membalas
+// keluarga alamat yang dipakai.
+func dualStackServer(t *testing.T) int {
+	v6, err := net.Listen("tcp6", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 loopback not available: %v", err)
+	}
+	port := v6.Addr().(*net.TCPAddr).Port
+	v4, err := net.Listen("tcp4", "127.0.0.1:"+strconv.Itoa(port))
+	if err != nil {
+		v6.Close()
+		t.Skipf("port %d not free on IPv4: %v", port, err)
+	}
+	for family, listener := range map[string]net.Listener{"v4": v4, "v6": v6} {
+		server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(family))
+		})}
+		go server.Serve(listener)
+		t.Cleanup(func() { server.Close() })
+	}
+	return port
+}
+
+func TestSetIPFamily(t *testing.T) {
+	port := dualStackServer(t)
+
+	tests := []struct {
+		family IPFamily
+		addrs  []string
+		want   string
+	}{
+		{IPv4Only, []string{"::1", "127.0.0.1"}, "v4"},
+		{IPv6Only, []string{"127.0.0.1", "::1"}, "v6"},
+		{PreferIPv4, []string{"::1", "127.0.0.1"}, "v4"},
+		{PreferIPv6, []string{"127.0.0.1", "::1"}, "v6"},
+		// IPv6 yang tidak bisa dijangkau jatuh ke IPv4 setelah FallbackDelay
+		{PreferIPv6, []string{"::2", "127.0.0.1"}, "v4"},
+	}
+	for _, tt := range tests {
+		client := NewHttpRequest()
+		if err := client.EnableDNSCache(DNSCacheOptions{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		client.dnsCache.lookupHost = func(ctx context.Context, host string) ([]string, error) {
+			return tt.addrs, nil
+		}
+		if err := client.SetIPFamily(IPFamilyOptions{Family: tt.family, FallbackDelay: 50 * time.Millisecond}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		start := time.Now()
+		resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: "http://dual.test:" + strconv.Itoa(port)})
+		if err != nil {
+			t.Errorf("family %d %v: unexpected error: %v", tt.family, tt.addrs, err)
+			continue
+		}
+		if string(resp.Body) != tt.want || time.Since(start) > 500*time.Millisecond {
+			t.Errorf("family %d %v: expected %s quickly, got %s in %v", tt.family, tt.addrs, tt.want, resp.Body, time.Since(start))
+		}
+	}
+
+	if err := NewHttpRequest().SetIPFamily(IPFamilyOptions{Family: IPFamily(42)}); err == nil {
+		t.Errorf("expected unknown family error")
+	}
+}
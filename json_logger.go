@@ -0,0 +1,147 @@
+package http_request_instant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// JSONLogger menulis satu event JSON per baris, mudah di-query di Loki/Elastic.
+// Pesan Debugf/Infof/Errorf juga ditulis sebagai JSON dengan field "msg".
+type JSONLogger struct {
+	Writer      io.Writer // Tujuan output, default os.Stdout
+	MaxBodySize int       // Body lebih panjang dipotong, default 1024 byte; -1 untuk tanpa body
+
+	mu sync.Mutex
+}
+
+// NewJSONLogger membuat JSONLogger yang menulis ke w.
+func NewJSONLogger(w io.Writer) *JSONLogger {
+	return &JSONLogger{Writer: w}
+}
+
+// jsonLogEvent adalah bentuk JSON dari LogEvent.
+type jsonLogEvent struct {
+	Time          string             `json:"time"`
+	Level         string             `json:"level"`
+	Event         string             `json:"event,omitempty"`
+	Msg           string             `json:"msg,omitempty"`
+	Method        string             `json:"method,omitempty"`
+	URL           string             `json:"url,omitempty"`
+	Attempt       int                `json:"attempt,omitempty"`
+	Status        int                `json:"status,omitempty"`
+	DurationMs    *float64           `json:"duration_ms,omitempty"`
+	Headers       map[string]string  `json:"headers,omitempty"`
+	BodySize      *int               `json:"body_size,omitempty"`
+	Body          string             `json:"body,omitempty"`
+	BodyTruncated bool               `json:"body_truncated,omitempty"`
+	Error         string             `json:"error,omitempty"`
+	TimingsMs     map[string]float64 `json:"timings_ms,omitempty"`
+	ConnReused    *bool              `json:"conn_reused,omitempty"`
+}
+
+// Debugf mengimplementasikan Logger.
+func (l *JSONLogger) Debugf(format string, args ...any) { l.message("debug", format, args...) }
+
+// Infof mengimplementasikan Logger.
+func (l *JSONLogger) Infof(format string, args ...any) { l.message("info", format, args...) }
+
+// Errorf mengimplementasikan Logger.
+func (l *JSONLogger) Errorf(format string, args ...any) { l.message("error", format, args...) }
+
+func (l *JSONLogger) message(level, format string, args ...any) {
+	l.write(jsonLogEvent{
+		Time:  time.Now().UTC().Format(time.RFC3339Nano),
+		Level: level,
+		Msg:   fmt.Sprintf(format, args...),
+	})
+}
+
+// LogEvent mengimplementasikan EventLogger.
+func (l *JSONLogger) LogEvent(ctx context.Context, event LogEvent) {
+	out := jsonLogEvent{
+		Time:    event.Time.UTC().Format(time.RFC3339Nano),
+		Level:   "debug",
+		Event:   event.Kind,
+		Method:  event.Method,
+		URL:     event.URL,
+		Attempt: event.Attempt,
+		Status:  event.StatusCode,
+	}
+	switch event.Kind {
+	case LogEventError:
+		out.Level = "error"
+	case LogEventSlow:
+		out.Level = "warn"
+	default:
+		size := event.BodySize
+		out.BodySize = &size
+	}
+	if event.Duration > 0 {
+		ms := durationMs(event.Duration)
+		out.DurationMs = &ms
+	}
+	if len(event.Headers) > 0 {
+		out.Headers = make(map[string]string, len(event.Headers))
+		for name, values := range event.Headers {
+			out.Headers[name] = strings.Join(values, ", ")
+		}
+	}
+	if l.MaxBodySize >= 0 {
+		limit := l.MaxBodySize
+		if limit == 0 {
+			limit = 1024
+		}
+		out.Body = event.Body
+		if len(out.Body) > limit {
+			// Jangan memotong di tengah karakter UTF-8
+			for limit > 0 && !utf8.RuneStart(out.Body[limit]) {
+				limit--
+			}
+			out.Body = out.Body[:limit]
+			out.BodyTruncated = true
+		}
+	}
+	if event.Err != nil {
+		out.Error = event.Err.Error()
+	}
+	if t := event.Timings; t != nil {
+		out.TimingsMs = map[string]float64{
+			"dns":           durationMs(t.DNS),
+			"connect":       durationMs(t.Connect),
+			"tls_handshake": durationMs(t.TLSHandshake),
+			"wait_for_conn": durationMs(t.WaitForConn),
+			"first_byte":    durationMs(t.FirstByte),
+			"download":      durationMs(t.Download),
+		}
+		reused := t.ConnReused
+		out.ConnReused = &reused
+	}
+	l.write(out)
+}
+
+func (l *JSONLogger) write(event jsonLogEvent) {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	line = append(line, '\n')
+
+	w := l.Writer
+	if w == nil {
+		w = os.Stdout
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = w.Write(line)
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
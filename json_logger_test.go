@@ -0,0 +1,102 @@
+package http_request_instant
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestJSONLoggerOneEventPerLine(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("x", 100)))
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	client := NewHttpRequest()
+	client.SetDebug(true)
+	client.Logger = &JSONLogger{Writer: &buf, MaxBodySize: 10}
+
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL + "?token=abc"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 JSON lines, got %q", buf.String())
+	}
+	var req, resp map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &req); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &resp); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if req["event"] != "request" || strings.Contains(req["url"].(string), "abc") {
+		t.Errorf("unexpected request event: %v", req)
+	}
+	if resp["event"] != "response" || resp["status"] != float64(200) || resp["duration_ms"] == nil {
+		t.Errorf("unexpected response event: %v", resp)
+	}
+	if resp["body"] != "xxxxxxxxxx" || resp["body_truncated"] != true || resp["body_size"] != float64(100) {
+		t.Errorf("expected truncated body with full size, got %v", resp)
+	}
+}
+
+func TestJSONLoggerResponseWithoutRequest(t *testing.T) {
+	// RoundTripper custom boleh mengembalikan response tanpa Request
+	rt := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader("ok")),
+		}, nil
+	})
+
+	var buf bytes.Buffer
+	client := NewHttpRequestWithTransport(rt)
+	client.SetDebug(true)
+	client.Logger = &JSONLogger{Writer: &buf}
+
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: "http://svc/status"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var resp map[string]any
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &resp); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if resp["event"] != "response" || resp["method"] != "GET" || resp["url"] != "http://svc/status" {
+		t.Errorf("unexpected response event: %v", resp)
+	}
+}
+
+func TestJSONLoggerTruncatesAtRuneBoundary(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("é", 10)))
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	client := NewHttpRequest()
+	client.SetDebug(true)
+	client.Logger = &JSONLogger{Writer: &buf, MaxBodySize: 5}
+
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var resp map[string]any
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &resp); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if resp["body"] != "éé" || resp["body_truncated"] != true {
+		t.Errorf("expected body cut at rune boundary, got %v", resp)
+	}
+}
@@ -0,0 +1,241 @@
+package http_request_instant
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// JWTAssertionConfig menyimpan konfigurasi untuk membuat JWT yang
+// ditandatangani private key (private_key_jwt / service account).
+type JWTAssertionConfig struct {
+	PrivateKey crypto.Signer // *rsa.PrivateKey (RS256) atau *ecdsa.PrivateKey P-256 (ES256)
+	KeyID      string        // Optional: header "kid"
+
+	Issuer   string         // Claim "iss"
+	Subject  string         // Claim "sub"
+	Audience string         // Claim "aud"
+	Claims   map[string]any // Optional: claim tambahan, mis. "scope"
+	TTL      time.Duration  // Masa berlaku JWT, default 5 menit
+
+	// Optional: jika diisi, JWT ditukar menjadi access token lewat grant
+	// urn:ietf:params:oauth:grant-type:jwt-bearer (RFC 7523) dan di-cache.
+	// Jika kosong, JWT baru dipakai langsung sebagai Bearer token.
+	TokenURL string
+
+	// Optional: client untuk memanggil TokenURL, default NewHttpRequest().
+	Client *HttpRequest
+	// Optional: sumber waktu, default time.Now.
+	Clock func() time.Time
+}
+
+// JWTAssertionProvider adalah TokenProvider berbasis JWT assertion.
+type JWTAssertionProvider struct {
+	config JWTAssertionConfig
+	alg    string
+
+	mu          sync.Mutex
+	accessToken string
+	expiry      time.Time
+}
+
+// NewJWTAssertionProvider membuat JWTAssertionProvider baru.
+func NewJWTAssertionProvider(config JWTAssertionConfig) (*JWTAssertionProvider, error) {
+	alg, err := jwtAlgorithm(config.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	if config.TTL <= 0 {
+		config.TTL = 5 * time.Minute
+	}
+	if config.Clock == nil {
+		config.Clock = time.Now
+	}
+	if config.Client == nil && config.TokenURL != "" {
+		config.Client = NewHttpRequest()
+	}
+	return &JWTAssertionProvider{config: config, alg: alg}, nil
+}
+
+// Token mengembalikan JWT baru, atau access token hasil pertukaran jika TokenURL diisi.
+func (p *JWTAssertionProvider) Token(ctx context.Context) (string, error) {
+	if p.config.TokenURL == "" {
+		return p.Assertion()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.accessToken != "" && (p.expiry.IsZero() || p.config.Clock().Add(30*time.Second).Before(p.expiry)) {
+		return p.accessToken, nil
+	}
+
+	assertion, err := p.Assertion()
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	form.Set("assertion", assertion)
+
+	token, err := requestOAuth2Token(ctx, p.config.Client, RequestOptions{
+		Method:      "POST",
+		URL:         p.config.TokenURL,
+		ContentType: "application/x-www-form-urlencoded",
+		Headers:     map[string]string{"Accept": "application/json"},
+		RequestBody: form.Encode(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	p.accessToken = token.AccessToken
+	p.expiry = token.expiry()
+	if p.accessToken == "" {
+		// Beberapa server (mis. Google untuk target_audience) mengembalikan id_token
+		p.accessToken = token.IDToken
+		if p.expiry.IsZero() {
+			p.expiry = jwtExpiry(token.IDToken)
+		}
+	}
+	return p.accessToken, nil
+}
+
+// InvalidateToken membuang access token hasil pertukaran di cache.
+func (p *JWTAssertionProvider) InvalidateToken() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.accessToken = ""
+	p.expiry = time.Time{}
+}
+
+// Assertion membuat JWT baru yang sudah ditandatangani.
+func (p *JWTAssertionProvider) Assertion() (string, error) {
+	now := p.config.Clock()
+	claims := make(map[string]any, len(p.config.Claims)+6)
+	for k, v := range p.config.Claims {
+		claims[k] = v
+	}
+	if p.config.Issuer != "" {
+		claims["iss"] = p.config.Issuer
+	}
+	if p.config.Subject != "" {
+		claims["sub"] = p.config.Subject
+	}
+	if p.config.Audience != "" {
+		claims["aud"] = p.config.Audience
+	}
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(p.config.TTL).Unix()
+	if _, ok := claims["jti"]; !ok {
+		jti := make([]byte, 16)
+		if _, err := rand.Read(jti); err != nil {
+			return "", err
+		}
+		claims["jti"] = base64.RawURLEncoding.EncodeToString(jti)
+	}
+
+	header := map[string]string{"alg": p.alg, "typ": "JWT"}
+	if p.config.KeyID != "" {
+		header["kid"] = p.config.KeyID
+	}
+	return signJWT(p.config.PrivateKey, header, claims)
+}
+
+// ParsePrivateKeyPEM mem-parsing private key RSA atau EC dalam format PEM
+// (PKCS#1, PKCS#8, atau SEC 1).
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T", key)
+		}
+		return signer, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, errors.New("unsupported private key format")
+}
+
+func jwtAlgorithm(key crypto.Signer) (string, error) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return "RS256", nil
+	case *ecdsa.PrivateKey:
+		if k.Curve.Params().BitSize != 256 {
+			return "", fmt.Errorf("unsupported ECDSA curve %s, only P-256 is supported", k.Curve.Params().Name)
+		}
+		return "ES256", nil
+	case nil:
+		return "", errors.New("jwt: private key is nil")
+	default:
+		return "", fmt.Errorf("jwt: unsupported private key type %T", key)
+	}
+}
+
+func signJWT(key crypto.Signer, header map[string]string, claims map[string]any) (string, error) {
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	return signCompactJWS(key, header["alg"], headerJSON, claimsJSON)
+}
+
+// signCompactJWS menandatangani payload menjadi JWS compact serialization.
+// alg yang didukung: RS256, PS256, dan ES256.
+func signCompactJWS(key crypto.Signer, alg string, headerJSON, payload []byte) (string, error) {
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	var opts crypto.SignerOpts = crypto.SHA256
+	if alg == "PS256" {
+		opts = &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: crypto.SHA256}
+	}
+	signature, err := key.Sign(rand.Reader, digest[:], opts)
+	if err != nil {
+		return "", fmt.Errorf("jwt: error sign: %w", err)
+	}
+
+	// ES256 memakai format r||s, bukan ASN.1
+	if _, ok := key.(*ecdsa.PrivateKey); ok {
+		var sig struct{ R, S *big.Int }
+		if _, err := asn1.Unmarshal(signature, &sig); err != nil {
+			return "", fmt.Errorf("jwt: error parse ECDSA signature: %w", err)
+		}
+		raw := make([]byte, 64)
+		sig.R.FillBytes(raw[:32])
+		sig.S.FillBytes(raw[32:])
+		signature = raw
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
@@ -0,0 +1,136 @@
+package http_request_instant
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func decodeJWTPart(t *testing.T, part string, v any) {
+	t.Helper()
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestJWTAssertionRS256(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	provider, err := NewJWTAssertionProvider(JWTAssertionConfig{
+		PrivateKey: key,
+		KeyID:      "kid-1",
+		Issuer:     "svc@example.com",
+		Audience:   "https://api.example.com",
+		Claims:     map[string]any{"scope": "read"},
+		Clock:      func() time.Time { return time.Unix(1700000000, 0) },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	token, err := provider.Token(context.TODO())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("expected 3 JWT parts, got %d", len(parts))
+	}
+
+	var header map[string]string
+	var claims map[string]any
+	decodeJWTPart(t, parts[0], &header)
+	decodeJWTPart(t, parts[1], &claims)
+	if header["alg"] != "RS256" || header["kid"] != "kid-1" {
+		t.Errorf("unexpected header: %v", header)
+	}
+	if claims["iss"] != "svc@example.com" || claims["scope"] != "read" || claims["exp"].(float64) != 1700000300 {
+		t.Errorf("unexpected claims: %v", claims)
+	}
+
+	sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], sig); err != nil {
+		t.Errorf("invalid signature: %v", err)
+	}
+}
+
+func TestJWTAssertionES256FromPEM(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	der, _ := x509.MarshalPKCS8PrivateKey(ecKey)
+	key, err := ParsePrivateKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	provider, err := NewJWTAssertionProvider(JWTAssertionConfig{PrivateKey: key, Issuer: "svc"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	token, err := provider.Assertion()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	parts := strings.Split(token, ".")
+	sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	if len(sig) != 64 {
+		t.Fatalf("expected 64 byte ES256 signature, got %d", len(sig))
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+	if !ecdsa.Verify(&ecKey.PublicKey, digest[:], r, s) {
+		t.Error("invalid ES256 signature")
+	}
+}
+
+func TestJWTAssertionExchange(t *testing.T) {
+	exchanges := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.Form.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" || r.Form.Get("assertion") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		exchanges++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"exchanged","expires_in":3600}`))
+	}))
+	defer ts.Close()
+
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	provider, err := NewJWTAssertionProvider(JWTAssertionConfig{PrivateKey: key, Issuer: "svc", TokenURL: ts.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		token, err := provider.Token(context.TODO())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if token != "exchanged" {
+			t.Errorf("expected exchanged token, got %s", token)
+		}
+	}
+	if exchanges != 1 {
+		t.Errorf("expected token to be cached, got %d exchanges", exchanges)
+	}
+}
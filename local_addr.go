@@ -0,0 +1,67 @@
+package http_request_instant
+
+import (
+	"fmt"
+	"net"
+)
+
+// SetLocalAddr mengikat koneksi keluar ke IP lokal tertentu ("203.0.113.7")
+// atau ke IP milik interface ("eth1"), untuk host dengan beberapa IP egress
+// yang hanya salah satunya masuk allowlist vendor. Interface dengan IPv4
+// dan IPv6 memakai alamat IPv4-nya. String kosong mengembalikan pemilihan
+// alamat ke OS. Error jika transport client bukan *http.Transport.
+func (c *HttpRequest) SetLocalAddr(addr string) error {
+	t, err := c.transport()
+	if err != nil {
+		return err
+	}
+
+	dialer := c.netDialer()
+	if addr == "" {
+		dialer.LocalAddr = nil
+	} else {
+		ip, err := localIP(addr)
+		if err != nil {
+			return fmt.Errorf("error set local address: %w", err)
+		}
+		// Dialer hanya mencoba IP tujuan yang family-nya sama dengan LocalAddr
+		dialer.LocalAddr = &net.TCPAddr{IP: ip}
+	}
+	t.DialContext = c.dialContext
+	return nil
+}
+
+// localIP mengurai addr sebagai IP, atau mencari IP unicast milik interface
+// bernama addr.
+func localIP(addr string) (net.IP, error) {
+	if ip := net.ParseIP(addr); ip != nil {
+		return ip, nil
+	}
+	iface, err := net.InterfaceByName(addr)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, err
+	}
+
+	var fallback net.IP
+	for _, a := range addrs {
+		ipNet, ok := a.(*net.IPNet)
+		// IPv6 link-local butuh zone, tidak bisa dipakai untuk tujuan umum
+		if !ok || ipNet.IP.IsLinkLocalUnicast() {
+			continue
+		}
+		if ipNet.IP.To4() != nil {
+			return ipNet.IP, nil
+		}
+		if fallback == nil {
+			fallback = ipNet.IP
+		}
+	}
+	if fallback == nil {
+		return nil, fmt.Errorf("interface %s has no usable address", addr)
+	}
+	return fallback, nil
+}
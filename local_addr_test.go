@@ -0,0 +1,58 @@
+package http_request_instant
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSetLocalAddr(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, _ := net.SplitHostPort(r.RemoteAddr)
+		w.Write([]byte(host))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	// Seluruh 127.0.0.0/8 adalah loopback di Linux
+	if err := client.SetLocalAddr("127.0.0.2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+	if err != nil {
+		t.Skipf("binding 127.0.0.2 not supported: %v", err)
+	}
+	if string(resp.Body) != "127.0.0.2" {
+		t.Errorf("expected source 127.0.0.2, got %s", resp.Body)
+	}
+
+	// Unix socket tetap bisa dipakai walaupun LocalAddr TCP terpasang
+	socket := serveUnixSocket(t, "local.sock")
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: "uds://" + socket}); err != nil {
+		t.Errorf("unexpected unix socket error: %v", err)
+	}
+
+	if err := client.SetLocalAddr("no-such-interface0"); err == nil {
+		t.Errorf("expected error for unknown interface")
+	}
+	if err := client.SetLocalAddr(""); err != nil || client.netDialer().LocalAddr != nil {
+		t.Errorf("expected local address reset, got %v", err)
+	}
+}
+
+func TestLocalIPFromInterface(t *testing.T) {
+	ifaces, _ := net.Interfaces()
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback == 0 {
+			continue
+		}
+		ip, err := localIP(iface.Name)
+		if err != nil || !ip.IsLoopback() {
+			t.Errorf("expected loopback address for %s, got %v, %v", iface.Name, ip, err)
+		}
+		return
+	}
+	t.Skip("no loopback interface")
+}
@@ -0,0 +1,93 @@
+package http_request_instant
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Logger adalah tujuan semua output log library: debug request/response,
+// peringatan keamanan, dan error.
+type Logger interface {
+	Debugf(format string, args ...any)
+	Infof(format string, args ...any)
+	Errorf(format string, args ...any)
+}
+
+// Jenis LogEvent.
+const (
+	LogEventRequest  = "request"
+	LogEventResponse = "response"
+	LogEventError    = "error"
+	LogEventSlow     = "slow_request"
+)
+
+// LogEvent adalah satu event log terstruktur untuk request, response, atau error.
+// Semua nilai rahasia sudah disamarkan.
+type LogEvent struct {
+	Kind       string        // LogEventRequest, LogEventResponse, LogEventError, atau LogEventSlow
+	Time       time.Time     // Waktu event dicatat
+	Method     string        // HTTP method
+	URL        string        // URL dengan query rahasia disamarkan
+	Attempt    int           // Percobaan ke-n, mulai dari 1
+	StatusCode int           // Status code, hanya untuk response
+	Duration   time.Duration // Lama request, untuk response dan error
+	Headers    http.Header   // Header request atau response
+	Body       string        // Body request atau response
+	BodySize   int           // Ukuran body dalam byte
+	Err        error         // Error, hanya untuk LogEventError
+	Timings    *Timings      // Durasi per fase, hanya untuk LogEventSlow
+}
+
+// EventLogger opsional diimplementasikan Logger untuk menerima event
+// terstruktur, bukan banner teks. Event request/response dikirim saat Debug
+// aktif, event error dan slow request selalu dikirim.
+type EventLogger interface {
+	LogEvent(ctx context.Context, event LogEvent)
+}
+
+// NewWriterLogger membuat Logger yang menulis satu baris per pesan ke w,
+// dengan format yang sama seperti output debug bawaan.
+func NewWriterLogger(w io.Writer) Logger {
+	return &writerLogger{w: w}
+}
+
+// NopLogger membuang semua pesan log.
+var NopLogger Logger = nopLogger{}
+
+type writerLogger struct {
+	w io.Writer
+}
+
+func (l *writerLogger) Debugf(format string, args ...any) { l.printf(format, args...) }
+func (l *writerLogger) Infof(format string, args ...any)  { l.printf(format, args...) }
+func (l *writerLogger) Errorf(format string, args ...any) { l.printf(format, args...) }
+
+func (l *writerLogger) printf(format string, args ...any) {
+	w := l.w
+	if w == nil {
+		// Baca os.Stdout saat menulis supaya redirect stdout tetap berlaku
+		w = os.Stdout
+	}
+	fmt.Fprintf(w, format+"\n", args...)
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debugf(format string, args ...any) {}
+func (nopLogger) Infof(format string, args ...any)  {}
+func (nopLogger) Errorf(format string, args ...any) {}
+
+// stdoutLogger adalah Logger default jika HttpRequest.Logger kosong.
+var stdoutLogger Logger = &writerLogger{}
+
+// logger mengembalikan Logger client atau logger stdout default.
+func (c *HttpRequest) logger() Logger {
+	if c.Logger != nil {
+		return c.Logger
+	}
+	return stdoutLogger
+}
@@ -0,0 +1,77 @@
+package http_request_instant
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordLogger menyimpan pesan beserta level-nya.
+type recordLogger struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+func (l *recordLogger) add(level, format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, level+" "+fmt.Sprintf(format, args...))
+}
+
+func (l *recordLogger) Debugf(format string, args ...any) { l.add("DEBUG", format, args...) }
+func (l *recordLogger) Infof(format string, args ...any)  { l.add("INFO", format, args...) }
+func (l *recordLogger) Errorf(format string, args ...any) { l.add("ERROR", format, args...) }
+
+func TestCustomLoggerReceivesDebugOutput(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("pong"))
+	}))
+	defer ts.Close()
+
+	logger := &recordLogger{}
+	client := NewHttpRequest()
+	client.SetDebug(true)
+	client.Logger = logger
+
+	out := captureStdout(t, func() {
+		if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if out != "" {
+		t.Errorf("expected nothing on stdout, got %q", out)
+	}
+	if len(logger.entries) != 2 {
+		t.Fatalf("expected request and response entries, got %v", logger.entries)
+	}
+	if !strings.HasPrefix(logger.entries[0], "DEBUG === [HTTP REQUEST] ===") ||
+		!strings.Contains(logger.entries[1], "Body: pong") {
+		t.Errorf("unexpected entries: %v", logger.entries)
+	}
+}
+
+func TestInsecureTLSWarningUsesLogger(t *testing.T) {
+	logger := &recordLogger{}
+	client := NewHttpRequest()
+	client.Logger = logger
+
+	if err := client.EnableInsecureSkipVerify(true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(logger.entries) != 1 || !strings.HasPrefix(logger.entries[0], "ERROR !!! [INSECURE TLS]") {
+		t.Errorf("unexpected entries: %v", logger.entries)
+	}
+}
+
+func TestWriterLogger(t *testing.T) {
+	var buf bytes.Buffer
+	NewWriterLogger(&buf).Infof("hello %s", "world")
+	if buf.String() != "hello world\n" {
+		t.Errorf("unexpected output: %q", buf.String())
+	}
+}
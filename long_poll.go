@@ -0,0 +1,138 @@
+package http_request_instant
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// LongPollOptions menyimpan konfigurasi LongPoll.
+type LongPollOptions struct {
+	// Optional: batas tunggu per request. Request yang timeout langsung
+	// dikirim ulang tanpa jeda. 0 berarti hanya timeout client.
+	Timeout time.Duration
+
+	// Optional: path bertitik ke resume token di body, mis. "last_event_id".
+	// Token dikirim di query param TokenParam (default "cursor") pada request
+	// berikutnya. Response tanpa token mempertahankan token sebelumnya.
+	TokenPath  string
+	TokenParam string
+
+	// Optional: membentuk request berikutnya dari response terakhir, untuk
+	// token di header atau body. Jika diisi, TokenPath diabaikan.
+	Next func(options RequestOptions, resp *ApiResponse) (RequestOptions, error)
+
+	// Jeda reconnect setelah error, digandakan dari MinBackoff (default
+	// 500ms) sampai MaxBackoff (default 30s) dengan jitter.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// LongPoll mengirim ulang request segera setelah setiap response, dan
+// mengiterasi response 2xx sampai loop dihentikan atau ctx selesai.
+// Response 204 dan 304 dianggap "tidak ada perubahan" dan tidak di-yield.
+// Error yang layak diulang (koneksi, 5xx, 429) disambung ulang otomatis
+// dengan jeda ber-jitter; error lain dan response 4xx di-yield sebagai error
+// terakhir. options.ResponseTarget diabaikan; decode resp.Body per iterasi.
+func (c *HttpRequest) LongPoll(ctx context.Context, options RequestOptions, lp LongPollOptions) iter.Seq2[*ApiResponse, error] {
+	minBackoff := lp.MinBackoff
+	if minBackoff <= 0 {
+		minBackoff = 500 * time.Millisecond
+	}
+	maxBackoff := lp.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = 30 * time.Second
+	}
+	maxBackoff = max(maxBackoff, minBackoff)
+	options.ResponseTarget = nil
+
+	return func(yield func(*ApiResponse, error) bool) {
+		backoff := time.Duration(0)
+		for {
+			resp, err := c.longPollOnce(ctx, options, lp.Timeout)
+			if ctx.Err() != nil {
+				yield(nil, ctx.Err())
+				return
+			}
+
+			var wait time.Duration
+			switch {
+			case err != nil && lp.Timeout > 0 && Category(err) == CategoryTimeout:
+				// Server tidak membalas dalam Timeout: wajar untuk long-poll
+			case defaultRetryOn(resp, err):
+				backoff = min(max(backoff*2, minBackoff), maxBackoff)
+				wait = backoff/2 + rand.N(backoff/2+1)
+			case err != nil:
+				yield(nil, err)
+				return
+			case resp.StatusCode >= 400:
+				yield(resp, resp.Err())
+				return
+			default:
+				backoff = 0
+				if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotModified {
+					if !yield(resp, nil) {
+						return
+					}
+					next, err := lp.next(options, resp)
+					if err != nil {
+						yield(nil, fmt.Errorf("long poll: %w", err))
+						return
+					}
+					options = next
+				}
+			}
+
+			if wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					yield(nil, ctx.Err())
+					return
+				}
+			}
+		}
+	}
+}
+
+func (c *HttpRequest) longPollOnce(ctx context.Context, options RequestOptions, timeout time.Duration) (*ApiResponse, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return c.Request(ctx, options)
+}
+
+// next menyiapkan request berikutnya dengan resume token dari resp.
+func (lp LongPollOptions) next(options RequestOptions, resp *ApiResponse) (RequestOptions, error) {
+	if lp.Next != nil {
+		var next RequestOptions
+		err := safeCall("long poll next", func() (err error) {
+			next, err = lp.Next(options, resp)
+			return err
+		})
+		return next, err
+	}
+	if lp.TokenPath == "" {
+		return options, nil
+	}
+	raw, err := lookupJSONPath(resp.Body, lp.TokenPath)
+	if err != nil {
+		return options, nil
+	}
+	token, err := jsonScalarString(raw)
+	if err != nil {
+		return RequestOptions{}, fmt.Errorf("token %q %w", lp.TokenPath, err)
+	}
+	if token == "" {
+		return options, nil
+	}
+	options, _, err = withQueryParams(options, map[string]string{defaultString(lp.TokenParam, "cursor"): token})
+	return options, err
+}
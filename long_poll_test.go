@@ -0,0 +1,92 @@
+package http_request_instant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLongPoll(t *testing.T) {
+	var mu sync.Mutex
+	var cursors []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		cursors = append(cursors, r.URL.Query().Get("since"))
+		n := len(cursors)
+		mu.Unlock()
+		switch n {
+		case 2:
+			// Tidak ada event sampai client timeout
+			time.Sleep(50 * time.Millisecond)
+		case 3:
+			w.WriteHeader(http.StatusNoContent)
+		case 4:
+			w.WriteHeader(http.StatusBadGateway)
+		case 5:
+			http.Error(w, "gone", http.StatusGone)
+		}
+		fmt.Fprintf(w, `{"events":[%d],"next":%d}`, n, n*10)
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	var events []string
+	var last error
+	for resp, err := range client.LongPoll(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL + "/changes"}, LongPollOptions{
+		Timeout:    20 * time.Millisecond,
+		TokenPath:  "next",
+		TokenParam: "since",
+		MinBackoff: 5 * time.Millisecond,
+	}) {
+		if err != nil {
+			last = err
+			break
+		}
+		events = append(events, string(resp.Body))
+	}
+
+	var statusErr *StatusError
+	if !errors.As(last, &statusErr) || statusErr.StatusCode != http.StatusGone {
+		t.Errorf("expected 410 to stop long poll, got %v", last)
+	}
+	if len(events) != 1 || events[0] != `{"events":[1],"next":10}` {
+		t.Errorf("unexpected events %v", events)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if fmt.Sprint(cursors) != "[ 10 10 10 10]" {
+		t.Errorf("expected resume token reused after timeout, 204, and 502, got %q", cursors)
+	}
+}
+
+func TestLongPollStopsOnBreakAndCancel(t *testing.T) {
+	var hits int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	for range client.LongPoll(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}, LongPollOptions{}) {
+		if hits == 3 {
+			break
+		}
+	}
+	if hits != 3 {
+		t.Errorf("expected break to stop after 3 requests, got %d", hits)
+	}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	for _, err := range client.LongPoll(ctx, RequestOptions{Method: "GET", URL: ts.URL}, LongPollOptions{}) {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context canceled, got %v", err)
+		}
+	}
+}
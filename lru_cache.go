@@ -0,0 +1,132 @@
+package http_request_instant
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// LRUCacheOptions menyimpan batas LRUCacheStore. Nilai 0 berarti tidak dibatasi.
+type LRUCacheOptions struct {
+	MaxEntries int           // Jumlah entry maksimum
+	MaxBytes   int64         // Total ukuran key, header, dan body maksimum
+	TTL        time.Duration // Umur entry default sejak disimpan
+}
+
+// LRUCacheStore adalah CacheStore di memori dengan eviction LRU berdasarkan
+// jumlah entry dan ukuran, serta TTL per entry. Aman dipakai konkuren.
+type LRUCacheStore struct {
+	options LRUCacheOptions
+	now     func() time.Time
+
+	mu    sync.Mutex
+	ll    *list.List // Depan = paling baru dipakai
+	items map[string]*list.Element
+	size  int64
+}
+
+type lruCacheItem struct {
+	key     string
+	entry   *CacheEntry
+	size    int64
+	expires time.Time // Nol berarti tidak kedaluwarsa
+}
+
+// NewLRUCacheStore membuat LRUCacheStore.
+func NewLRUCacheStore(options LRUCacheOptions) *LRUCacheStore {
+	return &LRUCacheStore{
+		options: options,
+		now:     time.Now,
+		ll:      list.New(),
+		items:   make(map[string]*list.Element),
+	}
+}
+
+// Get mengimplementasikan CacheStore. Entry yang TTL-nya habis dihapus.
+func (s *LRUCacheStore) Get(key string) (*CacheEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	elem, ok := s.items[key]
+	if !ok {
+		return nil, false
+	}
+	item := elem.Value.(*lruCacheItem)
+	if !item.expires.IsZero() && !s.now().Before(item.expires) {
+		s.remove(elem)
+		return nil, false
+	}
+	s.ll.MoveToFront(elem)
+	return item.entry, true
+}
+
+// Set mengimplementasikan CacheStore dengan TTL default.
+func (s *LRUCacheStore) Set(key string, entry *CacheEntry) {
+	s.SetWithTTL(key, entry, s.options.TTL)
+}
+
+// SetWithTTL menyimpan entry dengan TTL sendiri; 0 berarti tidak kedaluwarsa.
+// Entry yang lebih besar dari MaxBytes tidak disimpan.
+func (s *LRUCacheStore) SetWithTTL(key string, entry *CacheEntry, ttl time.Duration) {
+	item := &lruCacheItem{key: key, entry: entry, size: cacheEntrySize(key, entry)}
+	if ttl > 0 {
+		item.expires = s.now().Add(ttl)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if elem, ok := s.items[key]; ok {
+		s.remove(elem)
+	}
+	if s.options.MaxBytes > 0 && item.size > s.options.MaxBytes {
+		return
+	}
+	s.items[key] = s.ll.PushFront(item)
+	s.size += item.size
+
+	for s.ll.Len() > 0 && ((s.options.MaxEntries > 0 && s.ll.Len() > s.options.MaxEntries) ||
+		(s.options.MaxBytes > 0 && s.size > s.options.MaxBytes)) {
+		s.remove(s.ll.Back())
+	}
+}
+
+// Delete mengimplementasikan CacheStore.
+func (s *LRUCacheStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if elem, ok := s.items[key]; ok {
+		s.remove(elem)
+	}
+}
+
+// Len mengembalikan jumlah entry, termasuk yang kedaluwarsa tapi belum dihapus.
+func (s *LRUCacheStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ll.Len()
+}
+
+// Size mengembalikan total ukuran entry dalam byte.
+func (s *LRUCacheStore) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size
+}
+
+// remove dipanggil dengan mu terkunci.
+func (s *LRUCacheStore) remove(elem *list.Element) {
+	item := s.ll.Remove(elem).(*lruCacheItem)
+	delete(s.items, item.key)
+	s.size -= item.size
+}
+
+// cacheEntrySize memperkirakan memori yang dipakai entry.
+func cacheEntrySize(key string, entry *CacheEntry) int64 {
+	size := int64(len(key) + len(entry.Body))
+	for k, v := range entry.Headers {
+		size += int64(len(k) + len(v))
+	}
+	for k, v := range entry.Vary {
+		size += int64(len(k) + len(v))
+	}
+	return size
+}
@@ -0,0 +1,89 @@
+package http_request_instant
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLRUCacheStoreEviction(t *testing.T) {
+	store := NewLRUCacheStore(LRUCacheOptions{MaxEntries: 2})
+	store.Set("a", &CacheEntry{})
+	store.Set("b", &CacheEntry{})
+	store.Get("a")
+	store.Set("c", &CacheEntry{})
+
+	if _, ok := store.Get("b"); ok {
+		t.Errorf("expected least recently used entry b evicted")
+	}
+	if _, ok := store.Get("a"); !ok {
+		t.Errorf("expected a kept")
+	}
+
+	bySize := NewLRUCacheStore(LRUCacheOptions{MaxBytes: 250})
+	body := []byte(strings.Repeat("x", 100))
+	bySize.Set("1", &CacheEntry{Body: body})
+	bySize.Set("2", &CacheEntry{Body: body})
+	bySize.Set("3", &CacheEntry{Body: body})
+	if _, ok := bySize.Get("1"); ok || bySize.Len() != 2 || bySize.Size() != 202 {
+		t.Errorf("unexpected state: len %d, size %d", bySize.Len(), bySize.Size())
+	}
+	bySize.Set("huge", &CacheEntry{Body: make([]byte, 300)})
+	if _, ok := bySize.Get("huge"); ok || bySize.Len() != 2 {
+		t.Errorf("expected oversized entry rejected without evicting others")
+	}
+}
+
+func TestLRUCacheStoreTTL(t *testing.T) {
+	now := time.Now()
+	store := NewLRUCacheStore(LRUCacheOptions{TTL: time.Minute})
+	store.now = func() time.Time { return now }
+
+	store.Set("default", &CacheEntry{})
+	store.SetWithTTL("short", &CacheEntry{}, time.Second)
+	store.SetWithTTL("forever", &CacheEntry{}, 0)
+
+	now = now.Add(2 * time.Second)
+	if _, ok := store.Get("short"); ok {
+		t.Errorf("expected short entry expired")
+	}
+	if _, ok := store.Get("default"); !ok {
+		t.Errorf("expected default entry alive")
+	}
+
+	now = now.Add(time.Hour)
+	if _, ok := store.Get("default"); ok {
+		t.Errorf("expected default entry expired")
+	}
+	if _, ok := store.Get("forever"); !ok {
+		t.Errorf("expected entry without TTL alive")
+	}
+	if store.Len() != 1 {
+		t.Errorf("expected expired entries removed, got %d", store.Len())
+	}
+}
+
+func TestLRUCacheStoreConcurrent(t *testing.T) {
+	store := NewLRUCacheStore(LRUCacheOptions{MaxEntries: 10, TTL: time.Minute})
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				key := fmt.Sprint(j % 20)
+				store.Set(key, &CacheEntry{Body: []byte(key)})
+				store.Get(key)
+				if j%7 == 0 {
+					store.Delete(key)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if store.Len() > 10 {
+		t.Errorf("expected at most 10 entries, got %d", store.Len())
+	}
+}
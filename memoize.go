@@ -0,0 +1,135 @@
+package http_request_instant
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// memoizeMaxEntries membatasi jumlah response yang disimpan Memoize per client.
+const memoizeMaxEntries = 1024
+
+type memoizer struct {
+	store *LRUCacheStore
+
+	mu    sync.Mutex
+	calls map[string]*memoCall // Request yang sedang berjalan per key
+}
+
+type memoCall struct {
+	done  chan struct{}
+	entry *CacheEntry // nil jika request gagal atau status bukan 2xx
+}
+
+// Memoize menjalankan Request dan menyimpan response 2xx selama ttl
+// (0 berarti selama proses berjalan), tanpa memedulikan Cache-Control.
+// Panggilan berikutnya dengan method, URL, dan body yang sama dilayani dari
+// memori, dan panggilan konkuren untuk key yang sama hanya mengirim satu
+// request. Header dan kredensial per request (BearerToken, BasicAuth,
+// ApiKey, Auth) ikut membentuk key. Hanya untuk request idempoten.
+// Paling banyak 1024 response disimpan per client.
+func (c *HttpRequest) Memoize(ctx context.Context, options RequestOptions, ttl time.Duration) (*ApiResponse, error) {
+	key, ok := memoizeKey(options)
+	if !ok {
+		return c.Request(ctx, options)
+	}
+	m := c.memoizer()
+	if entry, ok := m.store.Get(key); ok {
+		return respondFromCache(entry, options)
+	}
+
+	m.mu.Lock()
+	if call, ok := m.calls[key]; ok {
+		m.mu.Unlock()
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if call.entry == nil {
+			return c.Request(ctx, options)
+		}
+		return respondFromCache(call.entry, options)
+	}
+	call := &memoCall{done: make(chan struct{})}
+	m.calls[key] = call
+	m.mu.Unlock()
+	defer func() {
+		m.mu.Lock()
+		delete(m.calls, key)
+		m.mu.Unlock()
+		close(call.done)
+	}()
+
+	target := options.ResponseTarget
+	options.ResponseTarget = nil
+	resp, err := c.Request(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	options.ResponseTarget = target
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		now := time.Now()
+		call.entry = newCacheEntry(options, resp, now, now)
+		m.store.SetWithTTL(key, call.entry, ttl)
+	}
+	if err := decodeResponseTarget(options, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *HttpRequest) memoizer() *memoizer {
+	c.memoOnce.Do(func() {
+		c.memo = &memoizer{
+			store: NewLRUCacheStore(LRUCacheOptions{MaxEntries: memoizeMaxEntries}),
+			calls: make(map[string]*memoCall),
+		}
+	})
+	return c.memo
+}
+
+// memoizeKey membentuk key dari method, URL, serta hash body, header, dan
+// kredensial per request. false jika body streaming atau tidak bisa
+// di-marshal, sehingga request tidak di-memoize.
+func memoizeKey(options RequestOptions) (string, bool) {
+	var body []byte
+	switch v := options.RequestBody.(type) {
+	case nil:
+	case string:
+		body = []byte(v)
+	case []byte:
+		body = v
+	case io.Reader:
+		return "", false
+	default:
+		var err error
+		if body, err = json.Marshal(v); err != nil {
+			return "", false
+		}
+	}
+
+	names := make([]string, 0, len(options.Headers))
+	headers := make(map[string]string, len(options.Headers))
+	for k, v := range options.Headers {
+		name := http.CanonicalHeaderKey(k)
+		names = append(names, name)
+		headers[name] = v
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	h.Write(body)
+	h.Write([]byte("\n" + requestCredentials(options)))
+	for _, name := range names {
+		h.Write([]byte(name + ": " + headers[name] + "\n"))
+	}
+	return DefaultCacheKey(context.Background(), options) + " " + hex.EncodeToString(h.Sum(nil)[:16]), true
+}
@@ -0,0 +1,128 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoize(t *testing.T) {
+	var hits atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Cache-Control", "no-store")
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		w.Write([]byte(`{"n":1}`))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	for i := 0; i < 2; i++ {
+		var target struct {
+			N int `json:"n"`
+		}
+		resp, err := client.Memoize(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL, ResponseTarget: &target}, time.Minute)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if target.N != 1 || resp.FromCache != (i == 1) {
+			t.Errorf("call %d: target %+v, FromCache %v", i, target, resp.FromCache)
+		}
+	}
+	if hits.Load() != 1 {
+		t.Errorf("expected no-store ignored and 1 hit, got %d", hits.Load())
+	}
+
+	// Body berbeda adalah key berbeda; response 5xx tidak disimpan
+	for _, options := range []RequestOptions{
+		{Method: "POST", URL: ts.URL, RequestBody: map[string]int{"q": 1}},
+		{Method: "POST", URL: ts.URL, RequestBody: map[string]int{"q": 2}},
+		{Method: "POST", URL: ts.URL, RequestBody: map[string]int{"q": 1}},
+		{Method: "GET", URL: ts.URL + "/fail"},
+		{Method: "GET", URL: ts.URL + "/fail"},
+	} {
+		if _, err := client.Memoize(context.TODO(), options, time.Minute); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if hits.Load() != 5 {
+		t.Errorf("expected 5 hits, got %d", hits.Load())
+	}
+}
+
+func TestMemoizeExpiresAndDeduplicates(t *testing.T) {
+	var hits atomic.Int32
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		<-release
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	options := RequestOptions{Method: "GET", URL: ts.URL}
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Memoize(context.TODO(), options, 20*time.Millisecond); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if hits.Load() != 1 {
+		t.Errorf("expected concurrent calls deduplicated, got %d hits", hits.Load())
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if resp, err := client.Memoize(context.TODO(), options, time.Minute); err != nil || resp.FromCache {
+		t.Errorf("expected expired entry refetched, got %v", err)
+	}
+	if hits.Load() != 2 {
+		t.Errorf("expected 2 hits, got %d", hits.Load())
+	}
+}
+
+func TestMemoizeSeparatesCredentials(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("Authorization") + "|" + r.Header.Get("X-Tenant-Id")))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	memoize := func(options RequestOptions) *ApiResponse {
+		options.Method, options.URL = "GET", ts.URL
+		resp, err := client.Memoize(context.TODO(), options, time.Minute)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return resp
+	}
+
+	memoize(RequestOptions{BearerToken: "alice"})
+	if resp := memoize(RequestOptions{BearerToken: "bob"}); resp.FromCache || string(resp.Body) != "Bearer bob|" {
+		t.Errorf("expected bob not served alice response, got %q FromCache %v", resp.Body, resp.FromCache)
+	}
+	if resp := memoize(RequestOptions{BearerToken: "alice"}); !resp.FromCache {
+		t.Errorf("expected alice served from memo")
+	}
+
+	memoize(RequestOptions{Headers: map[string]string{"X-Tenant-Id": "t1"}})
+	if resp := memoize(RequestOptions{Headers: map[string]string{"X-Tenant-Id": "t2"}}); resp.FromCache || string(resp.Body) != "|t2" {
+		t.Errorf("expected tenant t2 not served t1 response, got %q", resp.Body)
+	}
+	if resp := memoize(RequestOptions{Headers: map[string]string{"x-tenant-id": "t1"}}); !resp.FromCache {
+		t.Errorf("expected header name case not to change the key")
+	}
+}
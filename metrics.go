@@ -0,0 +1,238 @@
+package http_request_instant
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RequestMetrics berisi hasil satu percobaan request untuk MetricsObserver.
+type RequestMetrics struct {
+	Method       string
+	Host         string
+	StatusCode   int   // 0 jika request gagal tanpa response
+	Err          error // Error transport atau baca body
+	Duration     time.Duration
+	RequestSize  int64
+	ResponseSize int64
+
+	// Byte di socket termasuk header, hanya jika EnableByteAccounting aktif
+	WireBytesSent     int64
+	WireBytesReceived int64
+}
+
+// MetricsObserver menerima event metrik untuk setiap percobaan request.
+type MetricsObserver interface {
+	RequestStarted(method, host string)
+	RequestFinished(metrics RequestMetrics)
+}
+
+// DefaultDurationBuckets adalah bucket histogram durasi (detik) default.
+var DefaultDurationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// DefaultSizeBuckets adalah bucket histogram ukuran body (byte) default.
+var DefaultSizeBuckets = []float64{100, 1000, 10000, 100000, 1000000, 10000000}
+
+// PrometheusMetrics adalah MetricsObserver yang menyimpan metrik di memori
+// dan menyajikannya dalam format teks Prometheus lewat ServeHTTP, tanpa
+// dependensi ke client_golang.
+type PrometheusMetrics struct {
+	Namespace       string    // Prefix nama metrik, default "http_client"
+	DurationBuckets []float64 // Default DefaultDurationBuckets
+	SizeBuckets     []float64 // Default DefaultSizeBuckets
+
+	mu            sync.Mutex
+	requests      map[metricLabels]float64
+	durations     map[metricLabels]*histogram
+	requestSizes  map[metricLabels]*histogram
+	responseSizes map[metricLabels]*histogram
+	inFlight      map[metricLabels]float64
+	wireSent      map[metricLabels]float64
+	wireReceived  map[metricLabels]float64
+}
+
+type metricLabels struct {
+	method, host, status string
+}
+
+type histogram struct {
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+// NewPrometheusMetrics membuat PrometheusMetrics dengan namespace tertentu.
+func NewPrometheusMetrics(namespace string) *PrometheusMetrics {
+	return &PrometheusMetrics{Namespace: namespace}
+}
+
+// RequestStarted mengimplementasikan MetricsObserver.
+func (m *PrometheusMetrics) RequestStarted(method, host string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.init()
+	m.inFlight[metricLabels{method: method, host: host}]++
+}
+
+// RequestFinished mengimplementasikan MetricsObserver.
+func (m *PrometheusMetrics) RequestFinished(metrics RequestMetrics) {
+	status := "error"
+	if metrics.Err == nil {
+		status = strconv.Itoa(metrics.StatusCode)
+	}
+	labels := metricLabels{method: metrics.Method, host: metrics.Host, status: status}
+	flight := metricLabels{method: metrics.Method, host: metrics.Host}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.init()
+
+	if m.inFlight[flight]--; m.inFlight[flight] <= 0 {
+		m.inFlight[flight] = 0
+	}
+	m.requests[labels]++
+	m.observe(m.durations, labels, m.DurationBuckets, metrics.Duration.Seconds())
+	m.observe(m.requestSizes, labels, m.SizeBuckets, float64(metrics.RequestSize))
+	m.observe(m.responseSizes, labels, m.SizeBuckets, float64(metrics.ResponseSize))
+	if metrics.WireBytesSent > 0 || metrics.WireBytesReceived > 0 {
+		m.wireSent[flight] += float64(metrics.WireBytesSent)
+		m.wireReceived[flight] += float64(metrics.WireBytesReceived)
+	}
+}
+
+func (m *PrometheusMetrics) init() {
+	if m.requests != nil {
+		return
+	}
+	if m.Namespace == "" {
+		m.Namespace = "http_client"
+	}
+	if len(m.DurationBuckets) == 0 {
+		m.DurationBuckets = DefaultDurationBuckets
+	}
+	if len(m.SizeBuckets) == 0 {
+		m.SizeBuckets = DefaultSizeBuckets
+	}
+	m.requests = make(map[metricLabels]float64)
+	m.durations = make(map[metricLabels]*histogram)
+	m.requestSizes = make(map[metricLabels]*histogram)
+	m.responseSizes = make(map[metricLabels]*histogram)
+	m.inFlight = make(map[metricLabels]float64)
+	m.wireSent = make(map[metricLabels]float64)
+	m.wireReceived = make(map[metricLabels]float64)
+}
+
+func (m *PrometheusMetrics) observe(series map[metricLabels]*histogram, labels metricLabels, buckets []float64, value float64) {
+	h, ok := series[labels]
+	if !ok {
+		h = &histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+		series[labels] = h
+	}
+	for i, bound := range h.buckets {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += value
+	h.count++
+}
+
+// ServeHTTP menyajikan metrik dalam format teks Prometheus, mis. di /metrics.
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = m.WriteTo(w)
+}
+
+// WriteTo menulis metrik dalam format teks Prometheus ke w.
+func (m *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.init()
+
+	var b strings.Builder
+	ns := m.Namespace
+
+	fmt.Fprintf(&b, "# HELP %s_requests_total Total HTTP requests by method, host, and status.\n", ns)
+	fmt.Fprintf(&b, "# TYPE %s_requests_total counter\n", ns)
+	for _, labels := range sortedLabels(m.requests) {
+		fmt.Fprintf(&b, "%s_requests_total{%s} %s\n", ns, labels.format(), formatFloat(m.requests[labels]))
+	}
+
+	fmt.Fprintf(&b, "# HELP %s_requests_in_flight HTTP requests currently in flight.\n", ns)
+	fmt.Fprintf(&b, "# TYPE %s_requests_in_flight gauge\n", ns)
+	for _, labels := range sortedLabels(m.inFlight) {
+		fmt.Fprintf(&b, "%s_requests_in_flight{%s} %s\n", ns, labels.format(), formatFloat(m.inFlight[labels]))
+	}
+
+	writeHistograms(&b, ns+"_request_duration_seconds", "HTTP request duration in seconds.", m.durations)
+	writeHistograms(&b, ns+"_request_size_bytes", "HTTP request body size in bytes.", m.requestSizes)
+	writeHistograms(&b, ns+"_response_size_bytes", "HTTP response body size in bytes.", m.responseSizes)
+	writeCounters(&b, ns+"_wire_sent_bytes_total", "Bytes written to the socket including headers.", m.wireSent)
+	writeCounters(&b, ns+"_wire_received_bytes_total", "Bytes read from the socket including headers.", m.wireReceived)
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// writeCounters menulis counter hanya jika ada data, mis. EnableByteAccounting aktif.
+func writeCounters(b *strings.Builder, name, help string, series map[metricLabels]float64) {
+	if len(series) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(b, "# TYPE %s counter\n", name)
+	for _, labels := range sortedLabels(series) {
+		fmt.Fprintf(b, "%s{%s} %s\n", name, labels.format(), formatFloat(series[labels]))
+	}
+}
+
+func writeHistograms(b *strings.Builder, name, help string, series map[metricLabels]*histogram) {
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(b, "# TYPE %s histogram\n", name)
+	for _, labels := range sortedLabels(series) {
+		h := series[labels]
+		base := labels.format()
+		for i, bound := range h.buckets {
+			fmt.Fprintf(b, "%s_bucket{%s,le=\"%s\"} %d\n", name, base, formatFloat(bound), h.counts[i])
+		}
+		fmt.Fprintf(b, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, base, h.count)
+		fmt.Fprintf(b, "%s_sum{%s} %s\n", name, base, formatFloat(h.sum))
+		fmt.Fprintf(b, "%s_count{%s} %d\n", name, base, h.count)
+	}
+}
+
+func sortedLabels[V any](series map[metricLabels]V) []metricLabels {
+	keys := make([]metricLabels, 0, len(series))
+	for k := range series {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].format() < keys[j].format()
+	})
+	return keys
+}
+
+func (l metricLabels) format() string {
+	parts := []string{
+		`host="` + escapeLabel(l.host) + `"`,
+		`method="` + escapeLabel(l.method) + `"`,
+	}
+	if l.status != "" {
+		parts = append(parts, `status="`+escapeLabel(l.status)+`"`)
+	}
+	return strings.Join(parts, ",")
+}
+
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,66 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPrometheusMetrics(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer ts.Close()
+	host := strings.TrimPrefix(ts.URL, "http://")
+
+	metrics := NewPrometheusMetrics("api")
+	client := NewHttpRequest()
+	client.Metrics = metrics
+
+	for _, path := range []string{"/ok", "/ok", "/fail"} {
+		if _, err := client.Request(context.TODO(), RequestOptions{Method: "POST", URL: ts.URL + path, RequestBody: "abc"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	_, _ = client.Request(context.TODO(), RequestOptions{Method: "GET", URL: "http://127.0.0.1:1"})
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	out := rec.Body.String()
+
+	for _, want := range []string{
+		`api_requests_total{host="` + host + `",method="POST",status="200"} 2`,
+		`api_requests_total{host="` + host + `",method="POST",status="500"} 1`,
+		`api_requests_total{host="127.0.0.1:1",method="GET",status="error"} 1`,
+		`api_requests_in_flight{host="` + host + `",method="POST"} 0`,
+		`api_request_duration_seconds_count{host="` + host + `",method="POST",status="200"} 2`,
+		`api_request_size_bytes_sum{host="` + host + `",method="POST",status="200"} 6`,
+		`api_response_size_bytes_bucket{host="` + host + `",method="POST",status="200",le="100"} 2`,
+		"# TYPE api_request_duration_seconds histogram",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestPrometheusMetricsInFlight(t *testing.T) {
+	metrics := &PrometheusMetrics{}
+	metrics.RequestStarted("GET", "svc")
+	metrics.RequestStarted("GET", "svc")
+	metrics.RequestFinished(RequestMetrics{Method: "GET", Host: "svc", StatusCode: 200})
+
+	var b strings.Builder
+	if _, err := metrics.WriteTo(&b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(b.String(), `http_client_requests_in_flight{host="svc",method="GET"} 1`) {
+		t.Errorf("unexpected in-flight gauge:\n%s", b.String())
+	}
+}
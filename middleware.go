@@ -0,0 +1,130 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// Doer mengeksekusi satu Request lengkap, termasuk decode ResponseTarget.
+type Doer interface {
+	Do(ctx context.Context, options RequestOptions) (*ApiResponse, error)
+}
+
+// DoerFunc mengubah fungsi biasa menjadi Doer.
+type DoerFunc func(ctx context.Context, options RequestOptions) (*ApiResponse, error)
+
+// Do mengimplementasikan Doer.
+func (f DoerFunc) Do(ctx context.Context, options RequestOptions) (*ApiResponse, error) {
+	return f(ctx, options)
+}
+
+// Middleware membungkus Doer berikutnya, mis. untuk logging, retry, atau
+// cache. Middleware boleh mengubah options, memanggil next lebih dari sekali,
+// atau mengembalikan response tanpa memanggil next sama sekali.
+type Middleware func(next Doer) Doer
+
+// Use menambahkan middleware ke client. Middleware pertama adalah lapisan
+// terluar, jadi dijalankan paling awal sebelum request dan paling akhir
+// setelah response. Daftarkan middleware sebelum client dipakai secara konkuren.
+func (c *HttpRequest) Use(middlewares ...Middleware) {
+	c.middlewares = append(c.middlewares, middlewares...)
+
+	var handler Doer = DoerFunc(c.safeDo)
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		handler = recoverMiddleware(c.middlewares[i])(handler)
+	}
+	c.handler = handler
+}
+
+// RequestMatcher memilih request berdasarkan options, untuk When dan UseWhen.
+type RequestMatcher func(options RequestOptions) bool
+
+// When menjalankan middleware hanya untuk request yang cocok dengan match;
+// request lain langsung diteruskan ke next.
+func When(match RequestMatcher, middleware Middleware) Middleware {
+	return func(next Doer) Doer {
+		wrapped := middleware(next)
+		return DoerFunc(func(ctx context.Context, options RequestOptions) (*ApiResponse, error) {
+			if match(options) {
+				return wrapped.Do(ctx, options)
+			}
+			return next.Do(ctx, options)
+		})
+	}
+}
+
+// UseWhen seperti Use, tapi setiap middleware hanya berlaku untuk request
+// yang cocok dengan match, mis. signing untuk satu vendor atau cache hanya GET.
+func (c *HttpRequest) UseWhen(match RequestMatcher, middlewares ...Middleware) {
+	scoped := make([]Middleware, len(middlewares))
+	for i, middleware := range middlewares {
+		scoped[i] = When(match, middleware)
+	}
+	c.Use(scoped...)
+}
+
+// OnHosts memilih request ke salah satu host (tanpa port). Pattern
+// "*.example.com" cocok dengan semua subdomain example.com. URL relatif
+// tidak punya host, jadi tidak pernah cocok.
+func OnHosts(patterns ...string) RequestMatcher {
+	return func(options RequestOptions) bool {
+		u, err := url.Parse(options.URL)
+		if err != nil {
+			return false
+		}
+		return matchAnyHost(patterns, u.Hostname())
+	}
+}
+
+// OnPathPrefix memilih request yang path-nya diawali salah satu prefix.
+func OnPathPrefix(prefixes ...string) RequestMatcher {
+	return func(options RequestOptions) bool {
+		u, err := url.Parse(options.URL)
+		if err != nil {
+			return false
+		}
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(u.Path, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// OnMethods memilih request dengan salah satu method, mis. OnMethods("GET", "HEAD").
+func OnMethods(methods ...string) RequestMatcher {
+	return func(options RequestOptions) bool {
+		for _, method := range methods {
+			if strings.EqualFold(options.Method, method) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// AnyOf memilih request jika salah satu matcher memilihnya.
+func AnyOf(matchers ...RequestMatcher) RequestMatcher {
+	return func(options RequestOptions) bool {
+		for _, match := range matchers {
+			if match(options) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// AllOf memilih request jika semua matcher memilihnya.
+func AllOf(matchers ...RequestMatcher) RequestMatcher {
+	return func(options RequestOptions) bool {
+		for _, match := range matchers {
+			if !match(options) {
+				return false
+			}
+		}
+		return true
+	}
+}
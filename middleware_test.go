@@ -0,0 +1,138 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMiddlewareChainOrderAndShortCircuit(t *testing.T) {
+	var hits atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"tenant":"` + r.Header.Get("X-Tenant") + `"}`))
+	}))
+	defer ts.Close()
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next Doer) Doer {
+			return DoerFunc(func(ctx context.Context, options RequestOptions) (*ApiResponse, error) {
+				order = append(order, name+" before")
+				resp, err := next.Do(ctx, options)
+				order = append(order, name+" after")
+				return resp, err
+			})
+		}
+	}
+	retryOnce := func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, options RequestOptions) (*ApiResponse, error) {
+			resp, err := next.Do(ctx, options)
+			if err == nil && resp.StatusCode >= 500 {
+				return next.Do(ctx, options)
+			}
+			return resp, err
+		})
+	}
+	tenant := func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, options RequestOptions) (*ApiResponse, error) {
+			options.Headers = map[string]string{"X-Tenant": "acme"}
+			return next.Do(ctx, options)
+		})
+	}
+
+	client := NewHttpRequest()
+	client.Use(trace("outer"), trace("inner"))
+	client.Use(retryOnce, tenant)
+
+	var out struct{ Tenant string }
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL, ResponseTarget: &out})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 200 || out.Tenant != "acme" || hits.Load() != 2 {
+		t.Errorf("unexpected result: status %d, tenant %q, hits %d", resp.StatusCode, out.Tenant, hits.Load())
+	}
+	want := []string{"outer before", "inner before", "inner after", "outer after"}
+	if len(order) != len(want) {
+		t.Fatalf("unexpected order: %v", order)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Errorf("unexpected order: %v", order)
+		}
+	}
+
+	cached := NewHttpRequest()
+	cached.Use(func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, options RequestOptions) (*ApiResponse, error) {
+			return &ApiResponse{StatusCode: 200, Body: []byte("cached")}, nil
+		})
+	})
+	resp, err = cached.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+	if err != nil || string(resp.Body) != "cached" || hits.Load() != 2 {
+		t.Errorf("expected short-circuit without hitting server, got %v %v", resp, err)
+	}
+}
+
+func TestUseWhenScopesMiddleware(t *testing.T) {
+	var signed []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Signed") != "" {
+			signed = append(signed, r.Method+" "+r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	sign := func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, options RequestOptions) (*ApiResponse, error) {
+			options.Headers = cloneHeaders(options.Headers)
+			options.Headers["X-Signed"] = "1"
+			return next.Do(ctx, options)
+		})
+	}
+	client := NewHttpRequest()
+	client.UseWhen(AllOf(OnHosts("*.vendor.test", "127.0.0.1"), OnPathPrefix("/v1/"), OnMethods("get", "POST")), sign)
+
+	for _, options := range []RequestOptions{
+		{Method: "GET", URL: ts.URL + "/v1/charges"},
+		{Method: "POST", URL: ts.URL + "/v1/charges"},
+		{Method: "DELETE", URL: ts.URL + "/v1/charges"},
+		{Method: "GET", URL: ts.URL + "/v2/charges"},
+	} {
+		if _, err := client.Request(context.TODO(), options); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(signed) != 2 || signed[0] != "GET /v1/charges" || signed[1] != "POST /v1/charges" {
+		t.Errorf("unexpected signed requests: %v", signed)
+	}
+}
+
+func TestRequestMatchers(t *testing.T) {
+	tests := []struct {
+		name  string
+		match RequestMatcher
+		url   string
+		want  bool
+	}{
+		{"exact host", OnHosts("api.stripe.com"), "https://API.stripe.com:443/v1", true},
+		{"wildcard host", OnHosts("*.amazonaws.com"), "https://s3.amazonaws.com/bucket", true},
+		{"wildcard excludes apex", OnHosts("*.amazonaws.com"), "https://amazonaws.com/", false},
+		{"relative url has no host", OnHosts("api.stripe.com"), "/v1/charges", false},
+		{"relative path prefix", OnPathPrefix("/v1/"), "/v1/charges", true},
+		{"any of", AnyOf(OnHosts("a.test"), OnPathPrefix("/admin")), "https://b.test/admin/users", true},
+		{"all of", AllOf(OnHosts("a.test"), OnPathPrefix("/admin")), "https://b.test/admin/users", false},
+	}
+	for _, tt := range tests {
+		if got := tt.match(RequestOptions{Method: "GET", URL: tt.url}); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
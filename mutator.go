@@ -0,0 +1,40 @@
+package http_request_instant
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// RequestMutator mengubah *http.Request sebelum kredensial dipasang dan
+// request dikirim, mis. menambah header, menulis ulang URL, atau menambah
+// metadata yang tidak bisa diungkapkan lewat RequestOptions. Error
+// membatalkan request.
+type RequestMutator func(ctx context.Context, req *http.Request) error
+
+// AddRequestMutator mendaftarkan mutator untuk semua request client.
+// Daftarkan mutator sebelum client dipakai secara konkuren.
+func (c *HttpRequest) AddRequestMutator(mutators ...RequestMutator) {
+	c.mutators = append(c.mutators, mutators...)
+}
+
+// mutateRequest menjalankan mutator client lalu mutator per request. Jika
+// mutator memindahkan URL ke host lain, header Host ikut disesuaikan.
+func (c *HttpRequest) mutateRequest(ctx context.Context, req *http.Request, options RequestOptions) error {
+	if len(c.mutators) == 0 && len(options.Mutators) == 0 {
+		return nil
+	}
+
+	host := req.URL.Host
+	for _, mutators := range [][]RequestMutator{c.mutators, options.Mutators} {
+		for _, mutate := range mutators {
+			if err := safeCall("request mutator", func() error { return mutate(ctx, req) }); err != nil {
+				return fmt.Errorf("error mutate request: %w", err)
+			}
+		}
+	}
+	if req.URL.Host != host && req.Host == host {
+		req.Host = req.URL.Host
+	}
+	return nil
+}
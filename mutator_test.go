@@ -0,0 +1,57 @@
+package http_request_instant
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestRequestMutatorsRewriteAndStamp(t *testing.T) {
+	var gotHost, gotPath, gotOrder, gotAuth string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHost, gotPath = r.Host, r.URL.Path
+		gotOrder, gotAuth = r.Header.Get("X-Order"), r.Header.Get("Authorization")
+	}))
+	defer ts.Close()
+	target, _ := url.Parse(ts.URL)
+
+	client := NewHttpRequest()
+	client.AddRequestMutator(func(ctx context.Context, req *http.Request) error {
+		// arahkan host virtual ke server test dan tambahkan prefix versi API
+		req.URL.Scheme, req.URL.Host = target.Scheme, target.Host
+		req.URL.Path = "/v2" + req.URL.Path
+		req.Header.Set("X-Order", "client")
+		return nil
+	})
+
+	_, err := client.Request(context.TODO(), RequestOptions{
+		Method:      "GET",
+		URL:         "http://orders.internal/orders",
+		BearerToken: "token",
+		Mutators: []RequestMutator{func(ctx context.Context, req *http.Request) error {
+			req.Header.Set("X-Order", req.Header.Get("X-Order")+",request")
+			return nil
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotHost != target.Host || gotPath != "/v2/orders" || gotOrder != "client,request" || gotAuth != "Bearer token" {
+		t.Errorf("unexpected request: host %q path %q order %q auth %q", gotHost, gotPath, gotOrder, gotAuth)
+	}
+
+	_, err = client.Request(context.TODO(), RequestOptions{
+		Method: "GET",
+		URL:    ts.URL,
+		Mutators: []RequestMutator{func(ctx context.Context, req *http.Request) error {
+			return errors.New("blocked")
+		}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "error mutate request: blocked") {
+		t.Errorf("expected mutator error, got %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package http_request_instant
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// SPNEGOProvider menghasilkan token SPNEGO/Kerberos untuk service principal tertentu.
+// Implementasi biasanya membungkus library Kerberos (keytab atau credential
+// cache), SSPI di Windows, atau GSSAPI.
+type SPNEGOProvider interface {
+	// InitSecContext mengembalikan token untuk spn (mis. "HTTP/intranet.corp.local").
+	// challenge berisi token dari server pada putaran berikutnya, nil pada putaran pertama.
+	InitSecContext(ctx context.Context, spn string, challenge []byte) ([]byte, error)
+}
+
+// NegotiateTransport adalah http.RoundTripper untuk autentikasi
+// "Negotiate" (SPNEGO / Kerberos, RFC 4559). Token dibuat oleh Provider.
+type NegotiateTransport struct {
+	Provider SPNEGOProvider
+	Next     http.RoundTripper // default http.DefaultTransport
+
+	// Optional: membentuk SPN dari host request, default "HTTP/<hostname>".
+	SPN func(host string) string
+	// Kirim token langsung di request pertama tanpa menunggu 401.
+	Preemptive bool
+}
+
+// SetNegotiateAuth membungkus transport client dengan NegotiateTransport.
+func (c *HttpRequest) SetNegotiateAuth(provider SPNEGOProvider) *NegotiateTransport {
+	if c.Client == nil || c.Client.Transport == nil {
+		_, _ = c.transport()
+	}
+	negotiate := &NegotiateTransport{Provider: provider, Next: c.Client.Transport}
+	c.Client.Transport = negotiate
+	return negotiate
+}
+
+// Unwrap mengembalikan RoundTripper yang dibungkus.
+func (t *NegotiateTransport) Unwrap() http.RoundTripper {
+	return t.next()
+}
+
+func (t *NegotiateTransport) rewrap(next http.RoundTripper) http.RoundTripper {
+	clone := *t
+	clone.Next = next
+	return &clone
+}
+
+func (t *NegotiateTransport) next() http.RoundTripper {
+	if t.Next == nil {
+		return http.DefaultTransport
+	}
+	return t.Next
+}
+
+// RoundTrip mengimplementasikan http.RoundTripper.
+func (t *NegotiateTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.Provider == nil {
+		return nil, errors.New("negotiate: SPNEGO provider is nil")
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return nil, errors.New("negotiate: request body must be replayable (GetBody is nil)")
+	}
+
+	spn := t.spn(req)
+	if t.Preemptive {
+		token, err := t.Provider.InitSecContext(req.Context(), spn, nil)
+		if err != nil {
+			return nil, fmt.Errorf("negotiate: error init security context: %w", err)
+		}
+		return t.next().RoundTrip(negotiateRequest(req, token))
+	}
+
+	resp, err := t.next().RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	challenge, offered := negotiateChallenge(resp.Header.Values("Www-Authenticate"))
+	if !offered {
+		return resp, nil
+	}
+	drainAndClose(resp.Body)
+
+	token, err := t.Provider.InitSecContext(req.Context(), spn, challenge)
+	if err != nil {
+		return nil, fmt.Errorf("negotiate: error init security context: %w", err)
+	}
+	return t.next().RoundTrip(negotiateRequest(req, token))
+}
+
+func (t *NegotiateTransport) spn(req *http.Request) string {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if t.SPN != nil {
+		return t.SPN(host)
+	}
+	return "HTTP/" + host
+}
+
+func negotiateRequest(req *http.Request, token []byte) *http.Request {
+	clone := req.Clone(req.Context())
+	if req.GetBody != nil {
+		clone.Body, _ = req.GetBody()
+	}
+	clone.Header.Set("Authorization", "Negotiate "+base64.StdEncoding.EncodeToString(token))
+	return clone
+}
+
+// negotiateChallenge mencari challenge "Negotiate [token]" dari WWW-Authenticate.
+func negotiateChallenge(challenges []string) ([]byte, bool) {
+	for _, challenge := range challenges {
+		fields := strings.Fields(challenge)
+		if len(fields) == 0 || !strings.EqualFold(fields[0], "Negotiate") {
+			continue
+		}
+		if len(fields) == 1 {
+			return nil, true
+		}
+		token, err := base64.StdEncoding.DecodeString(fields[1])
+		if err != nil {
+			return nil, true
+		}
+		return token, true
+	}
+	return nil, false
+}
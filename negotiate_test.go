@@ -0,0 +1,45 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type fakeSPNEGOProvider struct {
+	spn string
+}
+
+func (p *fakeSPNEGOProvider) InitSecContext(ctx context.Context, spn string, challenge []byte) ([]byte, error) {
+	p.spn = spn
+	return []byte("kerberos-ticket"), nil
+}
+
+func TestNegotiateAuth(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// "a2VyYmVyb3MtdGlja2V0" = base64("kerberos-ticket")
+		if r.Header.Get("Authorization") != "Negotiate a2VyYmVyb3MtdGlja2V0" {
+			w.Header().Set("WWW-Authenticate", "Negotiate")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(200)
+	}))
+	defer ts.Close()
+
+	provider := &fakeSPNEGOProvider{}
+	client := NewHttpRequest()
+	client.SetNegotiateAuth(provider)
+
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Errorf("expected status=200, got %d", resp.StatusCode)
+	}
+	if provider.spn != "HTTP/127.0.0.1" {
+		t.Errorf("unexpected SPN: %s", provider.spn)
+	}
+}
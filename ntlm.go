@@ -0,0 +1,319 @@
+package http_request_instant
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// NTLMAuth menyimpan kredensial NTLM (NTLMv2) untuk layanan Windows on-prem
+// seperti Exchange EWS atau SharePoint lama.
+type NTLMAuth struct {
+	Domain      string
+	Username    string
+	Password    string
+	Workstation string // Optional
+}
+
+// NTLMTransport adalah http.RoundTripper yang menjalankan handshake NTLM
+// (negotiate, challenge, authenticate) ketika server membalas 401 dengan
+// WWW-Authenticate: NTLM atau Negotiate. Handshake memerlukan koneksi
+// HTTP/1.1 keep-alive yang sama, sehingga body response di tengah
+// handshake selalu dibaca habis sebelum langkah berikutnya.
+type NTLMTransport struct {
+	Auth NTLMAuth
+	Next http.RoundTripper // default http.DefaultTransport
+}
+
+// SetNTLMAuth membungkus transport client dengan NTLMTransport.
+func (c *HttpRequest) SetNTLMAuth(auth NTLMAuth) {
+	if c.Client == nil || c.Client.Transport == nil {
+		_, _ = c.transport()
+	}
+	c.Client.Transport = &NTLMTransport{Auth: auth, Next: c.Client.Transport}
+}
+
+// Unwrap mengembalikan RoundTripper yang dibungkus.
+func (t *NTLMTransport) Unwrap() http.RoundTripper {
+	return t.next()
+}
+
+func (t *NTLMTransport) rewrap(next http.RoundTripper) http.RoundTripper {
+	clone := *t
+	clone.Next = next
+	return &clone
+}
+
+func (t *NTLMTransport) next() http.RoundTripper {
+	if t.Next == nil {
+		return http.DefaultTransport
+	}
+	return t.Next
+}
+
+// RoundTrip mengimplementasikan http.RoundTripper.
+func (t *NTLMTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return nil, errors.New("ntlm: request body must be replayable (GetBody is nil)")
+	}
+
+	resp, err := t.next().RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	scheme := ntlmScheme(resp.Header.Values("Www-Authenticate"))
+	if scheme == "" {
+		return resp, nil
+	}
+	drainAndClose(resp.Body)
+
+	// Langkah 1: negotiate
+	resp, err = t.next().RoundTrip(ntlmRequest(req, scheme, ntlmNegotiateMessage()))
+	if err != nil {
+		return nil, err
+	}
+	challenge, ok := ntlmChallenge(resp.Header.Values("Www-Authenticate"), scheme)
+	if resp.StatusCode != http.StatusUnauthorized || !ok {
+		return resp, nil
+	}
+	drainAndClose(resp.Body)
+
+	// Langkah 2: authenticate
+	authenticate, err := ntlmAuthenticateMessage(t.Auth, challenge)
+	if err != nil {
+		return nil, err
+	}
+	return t.next().RoundTrip(ntlmRequest(req, scheme, authenticate))
+}
+
+func ntlmRequest(req *http.Request, scheme string, message []byte) *http.Request {
+	clone := req.Clone(req.Context())
+	if req.GetBody != nil {
+		clone.Body, _ = req.GetBody()
+	}
+	clone.Header.Set("Authorization", scheme+" "+base64.StdEncoding.EncodeToString(message))
+	return clone
+}
+
+// ntlmScheme memilih scheme yang ditawarkan server, NTLM lebih diutamakan.
+func ntlmScheme(challenges []string) string {
+	scheme := ""
+	for _, challenge := range challenges {
+		name := strings.Fields(challenge + " ")[0]
+		switch {
+		case strings.EqualFold(name, "NTLM"):
+			return "NTLM"
+		case strings.EqualFold(name, "Negotiate"):
+			scheme = "Negotiate"
+		}
+	}
+	return scheme
+}
+
+func ntlmChallenge(challenges []string, scheme string) ([]byte, bool) {
+	for _, challenge := range challenges {
+		fields := strings.Fields(challenge)
+		if len(fields) == 2 && strings.EqualFold(fields[0], scheme) {
+			data, err := base64.StdEncoding.DecodeString(fields[1])
+			return data, err == nil
+		}
+	}
+	return nil, false
+}
+
+func drainAndClose(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, body)
+	_ = body.Close()
+}
+
+const (
+	ntlmFlagUnicode                 = 0x00000001
+	ntlmFlagOEM                     = 0x00000002
+	ntlmFlagRequestTarget           = 0x00000004
+	ntlmFlagNTLM                    = 0x00000200
+	ntlmFlagAlwaysSign              = 0x00008000
+	ntlmFlagExtendedSessionSecurity = 0x00080000
+	ntlmFlagTargetInfo              = 0x00800000
+	ntlmFlag128                     = 0x20000000
+	ntlmFlag56                      = 0x80000000
+
+	ntlmDefaultFlags = ntlmFlagUnicode | ntlmFlagOEM | ntlmFlagRequestTarget | ntlmFlagNTLM |
+		ntlmFlagAlwaysSign | ntlmFlagExtendedSessionSecurity | ntlmFlagTargetInfo | ntlmFlag128 | ntlmFlag56
+)
+
+var ntlmSignature = []byte("NTLMSSP\x00")
+
+func ntlmNegotiateMessage() []byte {
+	msg := make([]byte, 32)
+	copy(msg, ntlmSignature)
+	binary.LittleEndian.PutUint32(msg[8:], 1)
+	binary.LittleEndian.PutUint32(msg[12:], ntlmDefaultFlags)
+	return msg
+}
+
+func ntlmAuthenticateMessage(auth NTLMAuth, challenge []byte) ([]byte, error) {
+	if len(challenge) < 48 || !bytes.Equal(challenge[:8], ntlmSignature) || binary.LittleEndian.Uint32(challenge[8:]) != 2 {
+		return nil, errors.New("ntlm: invalid challenge message")
+	}
+	flags := binary.LittleEndian.Uint32(challenge[20:])
+	serverChallenge := challenge[24:32]
+
+	targetInfoLen := int(binary.LittleEndian.Uint16(challenge[40:]))
+	targetInfoOffset := int(binary.LittleEndian.Uint32(challenge[44:]))
+	if targetInfoOffset+targetInfoLen > len(challenge) {
+		return nil, errors.New("ntlm: invalid target info in challenge message")
+	}
+	targetInfo := challenge[targetInfoOffset : targetInfoOffset+targetInfoLen]
+
+	clientChallenge := make([]byte, 8)
+	if _, err := rand.Read(clientChallenge); err != nil {
+		return nil, err
+	}
+
+	ntResponse, lmResponse := ntlmV2Response(auth, serverChallenge, clientChallenge, ntlmFiletime(time.Now()), targetInfo)
+
+	encode := ntlmOEM
+	if flags&ntlmFlagUnicode != 0 {
+		encode = ntlmUTF16
+	}
+	fields := [][]byte{
+		lmResponse,
+		ntResponse,
+		encode(auth.Domain),
+		encode(auth.Username),
+		encode(auth.Workstation),
+		nil, // EncryptedRandomSessionKey, tidak dipakai tanpa key exchange
+	}
+
+	const headerLen = 64
+	msg := make([]byte, headerLen)
+	copy(msg, ntlmSignature)
+	binary.LittleEndian.PutUint32(msg[8:], 3)
+
+	offset := headerLen
+	for i, field := range fields {
+		pos := 12 + i*8
+		binary.LittleEndian.PutUint16(msg[pos:], uint16(len(field)))
+		binary.LittleEndian.PutUint16(msg[pos+2:], uint16(len(field)))
+		binary.LittleEndian.PutUint32(msg[pos+4:], uint32(offset))
+		msg = append(msg, field...)
+		offset += len(field)
+	}
+	binary.LittleEndian.PutUint32(msg[60:], flags&ntlmDefaultFlags|ntlmFlagNTLM)
+	return msg, nil
+}
+
+// ntlmV2Response menghitung NTLMv2 dan LMv2 response (MS-NLMP 3.3.2).
+func ntlmV2Response(auth NTLMAuth, serverChallenge, clientChallenge []byte, timestamp uint64, targetInfo []byte) ([]byte, []byte) {
+	key := ntowfV2(auth.Username, auth.Password, auth.Domain)
+
+	temp := make([]byte, 0, 28+len(targetInfo)+4)
+	temp = append(temp, 0x01, 0x01, 0, 0, 0, 0, 0, 0)
+	temp = binary.LittleEndian.AppendUint64(temp, timestamp)
+	temp = append(temp, clientChallenge...)
+	temp = append(temp, 0, 0, 0, 0)
+	temp = append(temp, targetInfo...)
+	temp = append(temp, 0, 0, 0, 0)
+
+	ntProof := hmacMD5(key, serverChallenge, temp)
+	lmResponse := append(hmacMD5(key, serverChallenge, clientChallenge), clientChallenge...)
+	return append(ntProof, temp...), lmResponse
+}
+
+func ntowfV2(user, password, domain string) []byte {
+	return hmacMD5(md4Sum(ntlmUTF16(password)), ntlmUTF16(strings.ToUpper(user)+domain))
+}
+
+func hmacMD5(key []byte, data ...[]byte) []byte {
+	mac := hmac.New(md5.New, key)
+	for _, d := range data {
+		mac.Write(d)
+	}
+	return mac.Sum(nil)
+}
+
+// ntlmFiletime mengubah waktu ke format Windows FILETIME (100ns sejak 1601).
+func ntlmFiletime(t time.Time) uint64 {
+	return uint64(t.UnixNano()/100) + 116444736000000000
+}
+
+func ntlmUTF16(s string) []byte {
+	encoded := utf16.Encode([]rune(s))
+	b := make([]byte, 2*len(encoded))
+	for i, r := range encoded {
+		binary.LittleEndian.PutUint16(b[2*i:], r)
+	}
+	return b
+}
+
+func ntlmOEM(s string) []byte {
+	return []byte(s)
+}
+
+// md4Sum mengimplementasikan MD4 (RFC 1320) yang hanya dibutuhkan untuk NT hash.
+func md4Sum(data []byte) []byte {
+	a, b, c, d := uint32(0x67452301), uint32(0xefcdab89), uint32(0x98badcfe), uint32(0x10325476)
+
+	length := uint64(len(data)) * 8
+	msg := append(append([]byte{}, data...), 0x80)
+	for len(msg)%64 != 56 {
+		msg = append(msg, 0)
+	}
+	msg = binary.LittleEndian.AppendUint64(msg, length)
+
+	var x [16]uint32
+	for chunk := 0; chunk < len(msg); chunk += 64 {
+		for i := range x {
+			x[i] = binary.LittleEndian.Uint32(msg[chunk+4*i:])
+		}
+		aa, bb, cc, dd := a, b, c, d
+
+		f := func(x, y, z uint32) uint32 { return (x & y) | (^x & z) }
+		g := func(x, y, z uint32) uint32 { return (x & y) | (x & z) | (y & z) }
+		h := func(x, y, z uint32) uint32 { return x ^ y ^ z }
+
+		for _, i := range []int{0, 4, 8, 12} {
+			a = bits.RotateLeft32(a+f(b, c, d)+x[i], 3)
+			d = bits.RotateLeft32(d+f(a, b, c)+x[i+1], 7)
+			c = bits.RotateLeft32(c+f(d, a, b)+x[i+2], 11)
+			b = bits.RotateLeft32(b+f(c, d, a)+x[i+3], 19)
+		}
+		for _, i := range []int{0, 1, 2, 3} {
+			a = bits.RotateLeft32(a+g(b, c, d)+x[i]+0x5a827999, 3)
+			d = bits.RotateLeft32(d+g(a, b, c)+x[i+4]+0x5a827999, 5)
+			c = bits.RotateLeft32(c+g(d, a, b)+x[i+8]+0x5a827999, 9)
+			b = bits.RotateLeft32(b+g(c, d, a)+x[i+12]+0x5a827999, 13)
+		}
+		for _, i := range []int{0, 2, 1, 3} {
+			a = bits.RotateLeft32(a+h(b, c, d)+x[i]+0x6ed9eba1, 3)
+			d = bits.RotateLeft32(d+h(a, b, c)+x[i+8]+0x6ed9eba1, 9)
+			c = bits.RotateLeft32(c+h(d, a, b)+x[i+4]+0x6ed9eba1, 11)
+			b = bits.RotateLeft32(b+h(c, d, a)+x[i+12]+0x6ed9eba1, 15)
+		}
+
+		a, b, c, d = a+aa, b+bb, c+cc, d+dd
+	}
+
+	sum := make([]byte, 16)
+	binary.LittleEndian.PutUint32(sum[0:], a)
+	binary.LittleEndian.PutUint32(sum[4:], b)
+	binary.LittleEndian.PutUint32(sum[8:], c)
+	binary.LittleEndian.PutUint32(sum[12:], d)
+	return sum
+}
+
+// String mengembalikan representasi NTLMAuth tanpa password.
+func (a NTLMAuth) String() string {
+	return fmt.Sprintf("%s\\%s", a.Domain, a.Username)
+}
@@ -0,0 +1,112 @@
+package http_request_instant
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMD4(t *testing.T) {
+	tests := map[string]string{
+		"":    "31d6cfe0d16ae931b73c59d7e0c089c0",
+		"abc": "a448017aaf21d8525fc10ae87aa6729d",
+		"12345678901234567890123456789012345678901234567890123456789012345678901234567890": "e33b4ddc9c38f2199c3e7b164fcc0536",
+	}
+	for input, want := range tests {
+		if got := hex.EncodeToString(md4Sum([]byte(input))); got != want {
+			t.Errorf("md4(%q) = %s, want %s", input, got, want)
+		}
+	}
+}
+
+// Test vector dari MS-NLMP section 4.2.4
+func TestNTLMv2Vector(t *testing.T) {
+	auth := NTLMAuth{Domain: "Domain", Username: "User", Password: "Password"}
+	if got := hex.EncodeToString(ntowfV2(auth.Username, auth.Password, auth.Domain)); got != "0c868a403bfd7a93a3001ef22ef02e3f" {
+		t.Errorf("unexpected NTOWFv2: %s", got)
+	}
+
+	serverChallenge, _ := hex.DecodeString("0123456789abcdef")
+	clientChallenge, _ := hex.DecodeString("aaaaaaaaaaaaaaaa")
+	targetInfo, _ := hex.DecodeString("02000c0044006f006d00610069006e0001000c0053006500720076006500720000000000")
+
+	ntResponse, _ := ntlmV2Response(auth, serverChallenge, clientChallenge, 0, targetInfo)
+	if got := hex.EncodeToString(ntResponse[:16]); got != "68cd0ab851e51c96aabc927bebef6a1c" {
+		t.Errorf("unexpected NTProofStr: %s", got)
+	}
+}
+
+func TestNTLMHandshake(t *testing.T) {
+	auth := NTLMAuth{Domain: "CORP", Username: "alice", Password: "s3cret"}
+	serverChallenge := []byte("12345678")
+	targetInfo := []byte{0, 0, 0, 0} // MsvAvEOL
+
+	var negotiateAddr string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if !strings.HasPrefix(header, "NTLM ") {
+			w.Header().Set("WWW-Authenticate", "NTLM")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		msg, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(header, "NTLM "))
+		switch binary.LittleEndian.Uint32(msg[8:]) {
+		case 1:
+			negotiateAddr = r.RemoteAddr
+			challenge := make([]byte, 48)
+			copy(challenge, ntlmSignature)
+			binary.LittleEndian.PutUint32(challenge[8:], 2)
+			binary.LittleEndian.PutUint32(challenge[20:], ntlmDefaultFlags)
+			copy(challenge[24:], serverChallenge)
+			binary.LittleEndian.PutUint16(challenge[40:], uint16(len(targetInfo)))
+			binary.LittleEndian.PutUint16(challenge[42:], uint16(len(targetInfo)))
+			binary.LittleEndian.PutUint32(challenge[44:], 48)
+			challenge = append(challenge, targetInfo...)
+			w.Header().Set("WWW-Authenticate", "NTLM "+base64.StdEncoding.EncodeToString(challenge))
+			w.WriteHeader(http.StatusUnauthorized)
+		case 3:
+			if r.RemoteAddr != negotiateAddr {
+				t.Errorf("handshake should reuse connection: %s != %s", r.RemoteAddr, negotiateAddr)
+			}
+			field := func(i int) []byte {
+				pos := 12 + i*8
+				length := binary.LittleEndian.Uint16(msg[pos:])
+				offset := binary.LittleEndian.Uint32(msg[pos+4:])
+				return msg[offset : offset+uint32(length)]
+			}
+			ntResponse := field(1)
+			expected := hmacMD5(ntowfV2(auth.Username, auth.Password, auth.Domain), serverChallenge, ntResponse[16:])
+			if !bytes.Equal(expected, ntResponse[:16]) || !bytes.Equal(field(3), ntlmUTF16("alice")) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			body := new(bytes.Buffer)
+			_, _ = body.ReadFrom(r.Body)
+			_, _ = w.Write([]byte("welcome " + body.String()))
+		}
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.SetNTLMAuth(auth)
+
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "POST", URL: ts.URL, RequestBody: "alice"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 200 || string(resp.Body) != "welcome alice" {
+		t.Errorf("unexpected response: %d %s", resp.StatusCode, string(resp.Body))
+	}
+
+	// transport asli tetap bisa dikonfigurasi
+	if _, err := client.transport(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
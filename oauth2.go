@@ -0,0 +1,233 @@
+package http_request_instant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenInvalidator opsional diimplementasikan TokenProvider yang menyimpan
+// cache token. Jika server membalas 401 invalid_token untuk token dari
+// provider tersebut, client memanggil InvalidateToken lalu mengulang
+// request satu kali dengan token baru.
+type TokenInvalidator interface {
+	InvalidateToken()
+}
+
+// RefreshTokenStore menyimpan refresh token supaya token hasil rotasi
+// server tidak hilang ketika proses restart.
+type RefreshTokenStore interface {
+	Load(ctx context.Context) (string, error)
+	Save(ctx context.Context, refreshToken string) error
+}
+
+// MemoryRefreshTokenStore adalah RefreshTokenStore sederhana di memori.
+type MemoryRefreshTokenStore struct {
+	mu    sync.Mutex
+	token string
+}
+
+// Load mengembalikan refresh token yang tersimpan.
+func (s *MemoryRefreshTokenStore) Load(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token, nil
+}
+
+// Save menyimpan refresh token.
+func (s *MemoryRefreshTokenStore) Save(ctx context.Context, refreshToken string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = refreshToken
+	return nil
+}
+
+// OAuth2Config menyimpan konfigurasi grant refresh_token OAuth2.
+type OAuth2Config struct {
+	TokenURL     string   // Endpoint token server
+	ClientID     string   // Client ID
+	ClientSecret string   // Client secret
+	Scopes       []string // Optional: scope yang diminta
+
+	RefreshToken string            // Refresh token awal, dipakai jika Store masih kosong
+	Store        RefreshTokenStore // Optional: menyimpan refresh token hasil rotasi
+
+	// Kirim client credentials di body form, bukan lewat Basic Auth.
+	ClientCredentialsInBody bool
+
+	// Token dianggap kadaluarsa lebih awal sebesar ExpiryDelta, default 30 detik.
+	ExpiryDelta time.Duration
+
+	// Optional: client untuk memanggil TokenURL, default NewHttpRequest().
+	Client *HttpRequest
+}
+
+// OAuth2Error merepresentasikan error dari token endpoint (RFC 6749 section 5.2).
+type OAuth2Error struct {
+	StatusCode  int
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *OAuth2Error) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("oauth2: %s: %s (status %d)", e.Code, e.Description, e.StatusCode)
+	}
+	return fmt.Sprintf("oauth2: %s (status %d)", e.Code, e.StatusCode)
+}
+
+// OAuth2TokenProvider adalah TokenProvider yang me-refresh access token
+// secara otomatis menggunakan refresh token.
+type OAuth2TokenProvider struct {
+	config OAuth2Config
+
+	mu          sync.Mutex
+	accessToken string
+	expiry      time.Time
+}
+
+// NewOAuth2TokenProvider membuat OAuth2TokenProvider baru.
+func NewOAuth2TokenProvider(config OAuth2Config) *OAuth2TokenProvider {
+	if config.Store == nil {
+		config.Store = &MemoryRefreshTokenStore{}
+	}
+	if config.ExpiryDelta <= 0 {
+		config.ExpiryDelta = 30 * time.Second
+	}
+	if config.Client == nil {
+		config.Client = NewHttpRequest()
+	}
+	return &OAuth2TokenProvider{config: config}
+}
+
+// Token mengembalikan access token yang masih berlaku, me-refresh jika perlu.
+func (p *OAuth2TokenProvider) Token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.accessToken != "" && (p.expiry.IsZero() || time.Now().Add(p.config.ExpiryDelta).Before(p.expiry)) {
+		return p.accessToken, nil
+	}
+	if err := p.refresh(ctx); err != nil {
+		return "", err
+	}
+	return p.accessToken, nil
+}
+
+// InvalidateToken membuang access token di cache sehingga Token berikutnya melakukan refresh.
+func (p *OAuth2TokenProvider) InvalidateToken() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.accessToken = ""
+	p.expiry = time.Time{}
+}
+
+func (p *OAuth2TokenProvider) refresh(ctx context.Context) error {
+	refreshToken, err := p.config.Store.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("error load refresh token: %w", err)
+	}
+	if refreshToken == "" {
+		refreshToken = p.config.RefreshToken
+	}
+	if refreshToken == "" {
+		return fmt.Errorf("oauth2: no refresh token available")
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", refreshToken)
+	if len(p.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(p.config.Scopes, " "))
+	}
+
+	options := RequestOptions{
+		Method:      "POST",
+		URL:         p.config.TokenURL,
+		ContentType: "application/x-www-form-urlencoded",
+		Headers:     map[string]string{"Accept": "application/json"},
+	}
+	if p.config.ClientCredentialsInBody {
+		form.Set("client_id", p.config.ClientID)
+		if p.config.ClientSecret != "" {
+			form.Set("client_secret", p.config.ClientSecret)
+		}
+	} else if p.config.ClientID != "" {
+		options.BasicAuth = &BasicAuth{
+			Username: url.QueryEscape(p.config.ClientID),
+			Password: url.QueryEscape(p.config.ClientSecret),
+		}
+	}
+	options.RequestBody = form.Encode()
+
+	token, err := requestOAuth2Token(ctx, p.config.Client, options)
+	if err != nil {
+		return err
+	}
+
+	// Simpan refresh token baru jika server melakukan rotasi
+	if token.RefreshToken != "" && token.RefreshToken != refreshToken {
+		if err := p.config.Store.Save(ctx, token.RefreshToken); err != nil {
+			return fmt.Errorf("error save refresh token: %w", err)
+		}
+	} else if token.RefreshToken == "" {
+		if err := p.config.Store.Save(ctx, refreshToken); err != nil {
+			return fmt.Errorf("error save refresh token: %w", err)
+		}
+	}
+
+	p.accessToken = token.AccessToken
+	p.expiry = token.expiry()
+	return nil
+}
+
+// oauth2Token adalah response sukses dari token endpoint.
+type oauth2Token struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	IDToken      string `json:"id_token"`
+}
+
+func (t oauth2Token) expiry() time.Time {
+	if t.ExpiresIn <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(time.Duration(t.ExpiresIn) * time.Second)
+}
+
+// requestOAuth2Token mengirim request ke token endpoint dan mem-parsing response-nya.
+func requestOAuth2Token(ctx context.Context, client *HttpRequest, options RequestOptions) (*oauth2Token, error) {
+	resp, err := client.Request(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("error request token: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		oauthErr := &OAuth2Error{StatusCode: resp.StatusCode}
+		if json.Unmarshal(resp.Body, oauthErr) != nil || oauthErr.Code == "" {
+			oauthErr.Code = "token_request_failed"
+		}
+		return nil, oauthErr
+	}
+
+	var token oauth2Token
+	if err := json.Unmarshal(resp.Body, &token); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal token response: %w", err)
+	}
+	if token.AccessToken == "" && token.IDToken == "" {
+		return nil, fmt.Errorf("oauth2: token response has no access_token")
+	}
+	return &token, nil
+}
+
+// isInvalidTokenChallenge mengecek apakah 401 layak dicoba ulang dengan token baru:
+// header WWW-Authenticate tidak ada, atau melaporkan error="invalid_token".
+func isInvalidTokenChallenge(challenge string) bool {
+	return challenge == "" || strings.Contains(challenge, "invalid_token")
+}
@@ -0,0 +1,112 @@
+package http_request_instant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func newOAuth2TokenServer(t *testing.T, issued *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		user, pass, _ := r.BasicAuth()
+		if user != "client" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		if r.Form.Get("grant_type") != "refresh_token" {
+			t.Errorf("unexpected grant_type: %s", r.Form.Get("grant_type"))
+		}
+
+		n := issued.Add(1)
+		if r.Form.Get("refresh_token") != fmt.Sprintf("refresh-%d", n-1) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"refresh token reused"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  fmt.Sprintf("access-%d", n),
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+			"refresh_token": fmt.Sprintf("refresh-%d", n),
+		})
+	}))
+}
+
+func TestOAuth2RefreshAndRotation(t *testing.T) {
+	var issued atomic.Int32
+	tokenServer := newOAuth2TokenServer(t, &issued)
+	defer tokenServer.Close()
+
+	// API menolak token pertama sebagai invalid_token
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer access-1" {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer api.Close()
+
+	store := &MemoryRefreshTokenStore{}
+	provider := NewOAuth2TokenProvider(OAuth2Config{
+		TokenURL:     tokenServer.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RefreshToken: "refresh-0",
+		Store:        store,
+	})
+
+	client := NewHttpRequest()
+	client.TokenProvider = provider
+
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: api.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(resp.Body) != "Bearer access-2" {
+		t.Errorf("expected refreshed token, got %s (status %d)", string(resp.Body), resp.StatusCode)
+	}
+
+	saved, _ := store.Load(context.TODO())
+	if saved != "refresh-2" {
+		t.Errorf("expected rotated refresh token to be saved, got %s", saved)
+	}
+
+	// token masih berlaku, tidak ada refresh tambahan
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: api.URL}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if issued.Load() != 2 {
+		t.Errorf("expected 2 token requests, got %d", issued.Load())
+	}
+}
+
+func TestOAuth2Error(t *testing.T) {
+	var issued atomic.Int32
+	tokenServer := newOAuth2TokenServer(t, &issued)
+	defer tokenServer.Close()
+
+	provider := NewOAuth2TokenProvider(OAuth2Config{
+		TokenURL:     tokenServer.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RefreshToken: "stale",
+	})
+
+	_, err := provider.Token(context.TODO())
+	var oauthErr *OAuth2Error
+	if !errors.As(err, &oauthErr) || oauthErr.Code != "invalid_grant" {
+		t.Fatalf("expected invalid_grant error, got %v", err)
+	}
+}
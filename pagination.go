@@ -0,0 +1,417 @@
+package http_request_instant
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Page adalah satu halaman hasil Paginator.
+type Page struct {
+	Number   int               // Halaman ke-n, mulai dari 1
+	Options  RequestOptions    // Request yang menghasilkan halaman ini
+	Response *ApiResponse      // Response halaman ini
+	Items    []json.RawMessage // Item di PaginatorOptions.ItemsPath, nil jika body bukan array
+
+	more bool // Strategy menemukan halaman berikutnya
+}
+
+// PageStrategy menentukan request halaman pertama dan halaman berikutnya.
+type PageStrategy interface {
+	// FirstPage menyiapkan request halaman pertama, mis. memasang limit.
+	FirstPage(options RequestOptions) (RequestOptions, error)
+	// NextPage mengembalikan request halaman berikutnya, atau false jika habis.
+	NextPage(page *Page) (RequestOptions, bool, error)
+}
+
+// PaginatorOptions menyimpan konfigurasi Paginator.
+type PaginatorOptions struct {
+	Strategy PageStrategy
+
+	// Path bertitik ke array item di body, mis. "data" atau "result.items".
+	// Kosong berarti body sendiri adalah array.
+	ItemsPath string
+}
+
+// Paginator mengikuti halaman demi halaman dari satu endpoint list.
+type Paginator struct {
+	client  HttpRequestInf
+	options RequestOptions
+	paging  PaginatorOptions
+}
+
+// NewPaginator membuat Paginator untuk request halaman pertama options.
+// options.ResponseTarget diabaikan; pakai Items atau PageItems untuk decode.
+func NewPaginator(client HttpRequestInf, options RequestOptions, paging PaginatorOptions) *Paginator {
+	options.ResponseTarget = nil
+	return &Paginator{client: client, options: options, paging: paging}
+}
+
+// Pages mengiterasi halaman sampai habis, error, atau ctx dibatalkan.
+// Response 4xx/5xx dikembalikan sebagai *StatusError. Setiap iterasi baru
+// dimulai lagi dari halaman pertama.
+func (p *Paginator) Pages(ctx context.Context) iter.Seq2[*Page, error] {
+	return func(yield func(*Page, error) bool) {
+		options, err := p.paging.Strategy.FirstPage(p.options)
+		if err != nil {
+			yield(nil, fmt.Errorf("paginate: %w", err))
+			return
+		}
+		for number := 1; ; number++ {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+			page, err := p.fetch(ctx, number, options)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			next, more, nextErr := p.paging.Strategy.NextPage(page)
+			page.more = more && nextErr == nil
+			if !yield(page, nil) {
+				return
+			}
+			if nextErr != nil {
+				yield(nil, fmt.Errorf("paginate: %w", nextErr))
+				return
+			}
+			if !more {
+				return
+			}
+			options = next
+		}
+	}
+}
+
+// Items mengiterasi item mentah dari semua halaman.
+func (p *Paginator) Items(ctx context.Context) iter.Seq2[json.RawMessage, error] {
+	return func(yield func(json.RawMessage, error) bool) {
+		for page, err := range p.Pages(ctx) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if page.Items == nil {
+				yield(nil, fmt.Errorf("paginate: page %d has no item array", page.Number))
+				return
+			}
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// PageItems mengiterasi item semua halaman yang sudah di-decode ke T.
+func PageItems[T any](ctx context.Context, p *Paginator) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		for raw, err := range p.Items(ctx) {
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			var item T
+			if err := json.Unmarshal(raw, &item); err != nil {
+				yield(zero, &DecodeError{ContentType: "application/json", Err: fmt.Errorf("paginate: %w", err)})
+				return
+			}
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
+
+// ErrPaginationLimit dikembalikan FetchAll jika hasil melebihi MaxPages atau MaxItems.
+var ErrPaginationLimit = errors.New("paginate: result exceeds limit")
+
+// FetchAllOptions membatasi jumlah data yang dikumpulkan FetchAll.
+type FetchAllOptions struct {
+	MaxPages int // Default 100
+	MaxItems int // Default 10000
+}
+
+// FetchAll mengikuti semua halaman dan mengembalikan item yang sudah
+// di-decode ke T dalam satu slice. Jika masih ada data setelah MaxPages
+// halaman atau MaxItems item, FetchAll berhenti dan mengembalikan item yang
+// sudah terkumpul beserta error yang membungkus ErrPaginationLimit. Untuk
+// hasil besar, pakai PageItems.
+func FetchAll[T any](ctx context.Context, p *Paginator, limits FetchAllOptions) ([]T, error) {
+	maxPages := limits.MaxPages
+	if maxPages <= 0 {
+		maxPages = 100
+	}
+	maxItems := limits.MaxItems
+	if maxItems <= 0 {
+		maxItems = 10000
+	}
+
+	var all []T
+	for page, err := range p.Pages(ctx) {
+		if err != nil {
+			return all, err
+		}
+		if page.Items == nil {
+			return all, fmt.Errorf("paginate: page %d has no item array", page.Number)
+		}
+		for _, raw := range page.Items {
+			if len(all) == maxItems {
+				return all, fmt.Errorf("%w: more than %d items", ErrPaginationLimit, maxItems)
+			}
+			var item T
+			if err := json.Unmarshal(raw, &item); err != nil {
+				return all, &DecodeError{ContentType: "application/json", Err: fmt.Errorf("paginate: %w", err)}
+			}
+			all = append(all, item)
+		}
+		if len(all) == maxItems && page.more {
+			return all, fmt.Errorf("%w: more than %d items", ErrPaginationLimit, maxItems)
+		}
+		if page.Number == maxPages && page.more {
+			return all, fmt.Errorf("%w: more than %d pages", ErrPaginationLimit, maxPages)
+		}
+	}
+	return all, nil
+}
+
+func (p *Paginator) fetch(ctx context.Context, number int, options RequestOptions) (*Page, error) {
+	resp, err := p.client.Request(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	if err := resp.Err(); err != nil {
+		return nil, err
+	}
+
+	page := &Page{Number: number, Options: options, Response: resp}
+	if len(bytes.TrimSpace(resp.Body)) == 0 {
+		page.Items = []json.RawMessage{}
+		return page, nil
+	}
+	raw, err := lookupJSONPath(resp.Body, p.paging.ItemsPath)
+	if err == nil {
+		err = json.Unmarshal(raw, &page.Items)
+	}
+	if err == nil && page.Items == nil {
+		// Array null diperlakukan sebagai halaman kosong
+		page.Items = []json.RawMessage{}
+	}
+	if err != nil && p.paging.ItemsPath != "" {
+		return nil, &DecodeError{ContentType: resp.Headers["Content-Type"], Err: fmt.Errorf("paginate items %w", err)}
+	}
+	return page, nil
+}
+
+// LinkHeaderPaging mengikuti URL rel="next" di header Link (RFC 8288),
+// seperti API GitHub.
+type LinkHeaderPaging struct{}
+
+// FirstPage mengimplementasikan PageStrategy.
+func (LinkHeaderPaging) FirstPage(options RequestOptions) (RequestOptions, error) {
+	return options, nil
+}
+
+// NextPage mengimplementasikan PageStrategy.
+func (LinkHeaderPaging) NextPage(page *Page) (RequestOptions, bool, error) {
+	next := parseLinkHeader(page.Response.Headers["Link"])["next"]
+	if next == "" {
+		return RequestOptions{}, false, nil
+	}
+	base, err := url.Parse(page.Options.URL)
+	if err != nil {
+		return RequestOptions{}, false, err
+	}
+	ref, err := url.Parse(next)
+	if err != nil {
+		return RequestOptions{}, false, fmt.Errorf("invalid next link %q: %w", next, err)
+	}
+	options := page.Options
+	options.URL = base.ResolveReference(ref).String()
+	return options, true, nil
+}
+
+// CursorPaging membaca cursor halaman berikutnya dari body, lalu
+// mengirimnya sebagai query param. Cursor kosong atau null berarti habis.
+type CursorPaging struct {
+	CursorPath string // Path bertitik ke cursor, mis. "meta.next_cursor"
+	Param      string // Query param cursor, default "cursor"
+}
+
+// FirstPage mengimplementasikan PageStrategy.
+func (s CursorPaging) FirstPage(options RequestOptions) (RequestOptions, error) {
+	return options, nil
+}
+
+// NextPage mengimplementasikan PageStrategy.
+func (s CursorPaging) NextPage(page *Page) (RequestOptions, bool, error) {
+	raw, err := lookupJSONPath(page.Response.Body, s.CursorPath)
+	if err != nil {
+		// Banyak API menghilangkan field cursor di halaman terakhir
+		return RequestOptions{}, false, nil
+	}
+	cursor, err := jsonScalarString(raw)
+	if err != nil {
+		return RequestOptions{}, false, fmt.Errorf("cursor %q %w", s.CursorPath, err)
+	}
+
+	param := defaultString(s.Param, "cursor")
+	if cursor == "" || cursor == queryParam(page.Options.URL, param) {
+		return RequestOptions{}, false, nil
+	}
+	return withQueryParams(page.Options, map[string]string{param: cursor})
+}
+
+// PageNumberPaging menaikkan nomor halaman di query param sampai halaman
+// kosong atau berisi kurang dari Size item.
+type PageNumberPaging struct {
+	Param     string // Query param nomor halaman, default "page"
+	Start     int    // Nomor halaman pertama jika URL belum memuatnya, default 1
+	SizeParam string // Optional: query param ukuran halaman, mis. "per_page"
+	Size      int    // Optional: ukuran halaman yang diminta
+}
+
+// FirstPage mengimplementasikan PageStrategy.
+func (s PageNumberPaging) FirstPage(options RequestOptions) (RequestOptions, error) {
+	params := map[string]string{}
+	param := defaultString(s.Param, "page")
+	if queryParam(options.URL, param) == "" {
+		params[param] = strconv.Itoa(max(s.Start, 1))
+	}
+	if s.SizeParam != "" && s.Size > 0 {
+		params[s.SizeParam] = strconv.Itoa(s.Size)
+	}
+	options, _, err := withQueryParams(options, params)
+	return options, err
+}
+
+// NextPage mengimplementasikan PageStrategy.
+func (s PageNumberPaging) NextPage(page *Page) (RequestOptions, bool, error) {
+	if lastPage(page, s.Size) {
+		return RequestOptions{}, false, nil
+	}
+	param := defaultString(s.Param, "page")
+	current, err := strconv.Atoi(queryParam(page.Options.URL, param))
+	if err != nil {
+		return RequestOptions{}, false, fmt.Errorf("invalid page number in %s", param)
+	}
+	return withQueryParams(page.Options, map[string]string{param: strconv.Itoa(current + 1)})
+}
+
+// OffsetPaging menggeser offset sebanyak item yang diterima sampai halaman
+// kosong atau berisi kurang dari Limit item.
+type OffsetPaging struct {
+	OffsetParam string // Default "offset"
+	LimitParam  string // Default "limit"
+	Limit       int    // Optional: jumlah item per halaman yang diminta
+}
+
+// FirstPage mengimplementasikan PageStrategy.
+func (s OffsetPaging) FirstPage(options RequestOptions) (RequestOptions, error) {
+	params := map[string]string{}
+	if offsetParam := defaultString(s.OffsetParam, "offset"); queryParam(options.URL, offsetParam) == "" {
+		params[offsetParam] = "0"
+	}
+	if s.Limit > 0 {
+		params[defaultString(s.LimitParam, "limit")] = strconv.Itoa(s.Limit)
+	}
+	options, _, err := withQueryParams(options, params)
+	return options, err
+}
+
+// NextPage mengimplementasikan PageStrategy.
+func (s OffsetPaging) NextPage(page *Page) (RequestOptions, bool, error) {
+	if lastPage(page, s.Limit) {
+		return RequestOptions{}, false, nil
+	}
+	offsetParam := defaultString(s.OffsetParam, "offset")
+	offset, err := strconv.Atoi(queryParam(page.Options.URL, offsetParam))
+	if err != nil {
+		return RequestOptions{}, false, fmt.Errorf("invalid offset in %s", offsetParam)
+	}
+	return withQueryParams(page.Options, map[string]string{offsetParam: strconv.Itoa(offset + len(page.Items))})
+}
+
+// jsonScalarString mengubah string atau angka JSON menjadi string; null
+// menjadi string kosong.
+func jsonScalarString(raw json.RawMessage) (string, error) {
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return "", err
+	}
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case nil:
+		return "", nil
+	default:
+		return "", errors.New("is not a string or number")
+	}
+}
+
+// lastPage mengecek apakah halaman kosong atau lebih kecil dari ukuran yang diminta.
+func lastPage(page *Page, size int) bool {
+	return len(page.Items) == 0 || (size > 0 && len(page.Items) < size)
+}
+
+// parseLinkHeader mengurai header Link menjadi URL per rel.
+func parseLinkHeader(header string) map[string]string {
+	links := make(map[string]string)
+	for _, link := range strings.Split(header, ",") {
+		parts := strings.Split(link, ";")
+		target := strings.TrimSpace(parts[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+		for _, param := range parts[1:] {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(key, "rel") {
+				for _, rel := range strings.Fields(strings.Trim(value, `"`)) {
+					links[strings.ToLower(rel)] = target[1 : len(target)-1]
+				}
+			}
+		}
+	}
+	return links
+}
+
+// withQueryParams mengganti query param di options.URL.
+func withQueryParams(options RequestOptions, params map[string]string) (RequestOptions, bool, error) {
+	u, err := url.Parse(options.URL)
+	if err != nil {
+		return RequestOptions{}, false, err
+	}
+	query := u.Query()
+	for name, value := range params {
+		query.Set(name, value)
+	}
+	u.RawQuery = query.Encode()
+	options.URL = u.String()
+	return options, true, nil
+}
+
+func queryParam(rawURL, name string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Query().Get(name)
+}
+
+func defaultString(v, fallback string) string {
+	if v == "" {
+		return fallback
+	}
+	return v
+}
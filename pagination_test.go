@@ -0,0 +1,170 @@
+package http_request_instant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+type paginationItem struct {
+	ID int `json:"id"`
+}
+
+// paginationServer melayani item 1..total dengan gaya pagination berbeda per path.
+func paginationServer(total int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		items := func(from, n int) []paginationItem {
+			var out []paginationItem
+			for id := from; id < from+n && id <= total; id++ {
+				out = append(out, paginationItem{ID: id})
+			}
+			return out
+		}
+
+		switch r.URL.Path {
+		case "/link":
+			page, _ := strconv.Atoi(query.Get("page"))
+			page = max(page, 1)
+			if page*2 < total {
+				w.Header().Set("Link", fmt.Sprintf(`</link?page=%d>; rel="next", </link?page=99>; rel="last"`, page+1))
+			}
+			json.NewEncoder(w).Encode(items((page-1)*2+1, 2))
+		case "/cursor":
+			from, _ := strconv.Atoi(query.Get("after"))
+			body := map[string]any{"data": items(from+1, 2), "meta": map[string]any{"next": nil}}
+			if from+2 < total {
+				body["meta"] = map[string]any{"next": strconv.Itoa(from + 2)}
+			}
+			json.NewEncoder(w).Encode(body)
+		case "/pages":
+			page, _ := strconv.Atoi(query.Get("page"))
+			size, _ := strconv.Atoi(query.Get("per_page"))
+			json.NewEncoder(w).Encode(map[string]any{"items": items((page-1)*size+1, size)})
+		case "/offset":
+			offset, _ := strconv.Atoi(query.Get("offset"))
+			limit, _ := strconv.Atoi(query.Get("limit"))
+			json.NewEncoder(w).Encode(items(offset+1, limit))
+		case "/error":
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+}
+
+func TestPaginatorStrategies(t *testing.T) {
+	ts := paginationServer(5)
+	defer ts.Close()
+	client := NewHttpRequest()
+
+	tests := []struct {
+		name   string
+		path   string
+		paging PaginatorOptions
+		pages  int
+	}{
+		{"link header", "/link", PaginatorOptions{Strategy: LinkHeaderPaging{}}, 3},
+		{"cursor", "/cursor", PaginatorOptions{Strategy: CursorPaging{CursorPath: "meta.next", Param: "after"}, ItemsPath: "data"}, 3},
+		{"page number", "/pages", PaginatorOptions{Strategy: PageNumberPaging{SizeParam: "per_page", Size: 2}, ItemsPath: "items"}, 3},
+		{"page number exact", "/pages", PaginatorOptions{Strategy: PageNumberPaging{SizeParam: "per_page", Size: 5}, ItemsPath: "items"}, 2},
+		{"offset", "/offset", PaginatorOptions{Strategy: OffsetPaging{Limit: 2}}, 3},
+	}
+	for _, tt := range tests {
+		paginator := NewPaginator(client, RequestOptions{Method: "GET", URL: ts.URL + tt.path}, tt.paging)
+
+		pages := 0
+		for _, err := range paginator.Pages(context.TODO()) {
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			pages++
+		}
+		var ids []int
+		for item, err := range PageItems[paginationItem](context.TODO(), paginator) {
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			ids = append(ids, item.ID)
+		}
+		if pages != tt.pages || fmt.Sprint(ids) != "[1 2 3 4 5]" {
+			t.Errorf("%s: got %d pages, ids %v", tt.name, pages, ids)
+		}
+	}
+}
+
+func TestPaginatorStopsEarlyAndOnErrors(t *testing.T) {
+	ts := paginationServer(100)
+	defer ts.Close()
+	client := NewHttpRequest()
+
+	var requests int
+	client.OnBeforeRequest(func(ctx context.Context, req *http.Request, info AttemptInfo) error {
+		requests++
+		return nil
+	})
+	paginator := NewPaginator(client, RequestOptions{Method: "GET", URL: ts.URL + "/offset"}, PaginatorOptions{Strategy: OffsetPaging{Limit: 10}})
+	for item, err := range paginator.Items(context.TODO()) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var decoded paginationItem
+		json.Unmarshal(item, &decoded)
+		if decoded.ID == 15 {
+			break
+		}
+	}
+	if requests != 2 {
+		t.Errorf("expected break to stop fetching after 2 pages, got %d", requests)
+	}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	for _, err := range paginator.Pages(ctx) {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context canceled, got %v", err)
+		}
+	}
+
+	failing := NewPaginator(client, RequestOptions{Method: "GET", URL: ts.URL + "/error"}, PaginatorOptions{Strategy: LinkHeaderPaging{}})
+	for _, err := range failing.Pages(context.TODO()) {
+		var statusErr *StatusError
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadGateway {
+			t.Errorf("expected *StatusError 502, got %v", err)
+		}
+	}
+}
+
+func TestParseLinkHeader(t *testing.T) {
+	links := parseLinkHeader(`<https://api.test/items?page=2>; rel="next", <https://api.test/items?page=1>; rel="prev first"`)
+	if links["next"] != "https://api.test/items?page=2" || links["prev"] != links["first"] || links["first"] == "" {
+		t.Errorf("unexpected links: %v", links)
+	}
+}
+
+func TestFetchAll(t *testing.T) {
+	ts := paginationServer(7)
+	defer ts.Close()
+	client := NewHttpRequest()
+	paginator := NewPaginator(client, RequestOptions{Method: "GET", URL: ts.URL + "/offset"}, PaginatorOptions{Strategy: OffsetPaging{Limit: 3}})
+
+	items, err := FetchAll[paginationItem](context.TODO(), paginator, FetchAllOptions{})
+	if err != nil || len(items) != 7 || items[6].ID != 7 {
+		t.Fatalf("expected 7 items, got %v, %v", items, err)
+	}
+	if items, err := FetchAll[paginationItem](context.TODO(), paginator, FetchAllOptions{MaxItems: 7}); err != nil || len(items) != 7 {
+		t.Errorf("expected exact MaxItems to succeed, got %d, %v", len(items), err)
+	}
+
+	items, err = FetchAll[paginationItem](context.TODO(), paginator, FetchAllOptions{MaxItems: 5})
+	if !errors.Is(err, ErrPaginationLimit) || len(items) != 5 {
+		t.Errorf("expected item limit with 5 partial items, got %d, %v", len(items), err)
+	}
+	items, err = FetchAll[paginationItem](context.TODO(), paginator, FetchAllOptions{MaxPages: 2})
+	if !errors.Is(err, ErrPaginationLimit) || len(items) != 6 {
+		t.Errorf("expected page limit with 6 partial items, got %d, %v", len(items), err)
+	}
+}
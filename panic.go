@@ -0,0 +1,74 @@
+package http_request_instant
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError dikembalikan jika hook, mutator, transformer, codec, marshaler,
+// signer, atau middleware panic. Panic diubah menjadi error supaya satu
+// plugin yang rusak tidak mematikan seluruh service.
+type PanicError struct {
+	Source string // Asal panic, mis. "before request hook" atau "middleware"
+	Value  any    // Nilai yang diberikan ke panic
+	Stack  []byte // Stack trace goroutine saat panic
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap mengembalikan nilai panic jika berupa error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Category mengimplementasikan CategorizedError.
+func (e *PanicError) Category() ErrorCategory {
+	return CategoryOther
+}
+
+// recoverPanic, dipanggil dengan defer, mengubah panic menjadi *PanicError di err.
+func recoverPanic(source string, err *error) {
+	if v := recover(); v != nil {
+		*err = newPanicError(source, v)
+	}
+}
+
+func newPanicError(source string, v any) *PanicError {
+	return &PanicError{Source: source, Value: v, Stack: debug.Stack()}
+}
+
+// safeCall menjalankan fn dan mengubah panic di dalamnya menjadi *PanicError.
+func safeCall(source string, fn func() error) (err error) {
+	defer recoverPanic(source, &err)
+	return fn()
+}
+
+// safeDo menjalankan do dengan pemulihan panic, sebagai lapisan terdalam
+// rantai middleware.
+func (c *HttpRequest) safeDo(ctx context.Context, options RequestOptions) (resp *ApiResponse, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			resp, err = nil, newPanicError("request", v)
+		}
+	}()
+	return c.do(ctx, options)
+}
+
+// recoverMiddleware mengubah panic di middleware menjadi *PanicError.
+func recoverMiddleware(middleware Middleware) Middleware {
+	return func(next Doer) Doer {
+		wrapped := middleware(next)
+		return DoerFunc(func(ctx context.Context, options RequestOptions) (resp *ApiResponse, err error) {
+			defer func() {
+				if v := recover(); v != nil {
+					resp, err = nil, newPanicError("middleware", v)
+				}
+			}()
+			return wrapped.Do(ctx, options)
+		})
+	}
+}
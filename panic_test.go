@@ -0,0 +1,100 @@
+package http_request_instant
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type panicMarshaler struct{}
+
+func (panicMarshaler) MarshalJSON() ([]byte, error) {
+	panic("marshal boom")
+}
+
+func TestPanicRecoveryConvertsToError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name   string
+		setup  func(c *HttpRequest, options *RequestOptions)
+		source string
+	}{
+		{"before hook", func(c *HttpRequest, options *RequestOptions) {
+			c.OnBeforeRequest(func(ctx context.Context, req *http.Request, info AttemptInfo) error { panic("hook boom") })
+		}, "before request hook"},
+		{"after hook", func(c *HttpRequest, options *RequestOptions) {
+			c.OnAfterResponse(func(ctx context.Context, req *http.Request, resp *ApiResponse, info AttemptInfo) error {
+				var m map[string]int
+				m["x"]++
+				return nil
+			})
+		}, "after response hook"},
+		{"marshaler", func(c *HttpRequest, options *RequestOptions) {
+			options.Method, options.RequestBody = "POST", panicMarshaler{}
+		}, "marshal"},
+		{"mutator", func(c *HttpRequest, options *RequestOptions) {
+			c.AddRequestMutator(func(ctx context.Context, req *http.Request) error { panic("mutator boom") })
+		}, "request mutator"},
+		{"transformer", func(c *HttpRequest, options *RequestOptions) {
+			options.Transformers = []ResponseTransformer{func(ctx context.Context, resp *ApiResponse) ([]byte, error) { panic("transform boom") }}
+		}, "response transformer"},
+		{"middleware", func(c *HttpRequest, options *RequestOptions) {
+			c.Use(func(next Doer) Doer {
+				return DoerFunc(func(ctx context.Context, options RequestOptions) (*ApiResponse, error) { panic("middleware boom") })
+			})
+		}, "middleware"},
+		{"auth provider", func(c *HttpRequest, options *RequestOptions) {
+			options.Auth = AuthProviderFunc(func(ctx context.Context, req *http.Request) error { panic("auth boom") })
+		}, "request"},
+	}
+	for _, tt := range tests {
+		client := NewHttpRequest()
+		client.Logger = NopLogger
+		options := RequestOptions{Method: "GET", URL: ts.URL}
+		tt.setup(client, &options)
+
+		resp, err := client.Request(context.TODO(), options)
+		var panicErr *PanicError
+		if !errors.As(err, &panicErr) {
+			t.Errorf("%s: expected *PanicError, got %v", tt.name, err)
+			continue
+		}
+		if resp != nil {
+			t.Errorf("%s: expected nil response", tt.name)
+		}
+		if panicErr.Source != tt.source || !strings.Contains(string(panicErr.Stack), "panic_test.go") {
+			t.Errorf("%s: unexpected source %q or stack:\n%s", tt.name, panicErr.Source, panicErr.Stack)
+		}
+	}
+}
+
+func TestPanicRecoveryErrorHookIsLogged(t *testing.T) {
+	logger := &recordLogger{}
+	client := NewHttpRequest()
+	client.Logger = logger
+	client.OnError(func(ctx context.Context, req *http.Request, err error, info AttemptInfo) { panic("error hook boom") })
+	client.OnBeforeRequest(func(ctx context.Context, req *http.Request, info AttemptInfo) error { return errors.New("denied") })
+
+	_, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: "http://127.0.0.1:1"})
+	if err == nil || !strings.Contains(err.Error(), "denied") {
+		t.Fatalf("expected original error, got %v", err)
+	}
+	if !strings.Contains(strings.Join(logger.entries, "\n"), "error hook panic: error hook boom") {
+		t.Errorf("expected panic logged, got %v", logger.entries)
+	}
+}
+
+func TestPanicErrorUnwrapsErrorValue(t *testing.T) {
+	cause := errors.New("cause")
+	err := safeCall("test", func() error { panic(cause) })
+	if !errors.Is(err, cause) || err.Error() != "panic: cause" || Category(err) != CategoryOther {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
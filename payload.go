@@ -0,0 +1,299 @@
+package http_request_instant
+
+import (
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+)
+
+// PayloadCodec mengubah body request setelah di-marshal dan body response
+// sebelum di-unmarshal, mis. untuk enkripsi JWE atau tanda tangan JWS.
+type PayloadCodec interface {
+	// EncodePayload mengubah body request. Header boleh diubah, mis. Content-Type.
+	EncodePayload(ctx context.Context, body []byte, header http.Header) ([]byte, error)
+	// DecodePayload mengembalikan body response asli dari body yang diterima.
+	DecodePayload(ctx context.Context, body []byte, header http.Header) ([]byte, error)
+}
+
+// ChainPayloadCodecs menggabungkan beberapa codec. Encode dijalankan sesuai
+// urutan, decode dengan urutan terbalik, mis. ChainPayloadCodecs(jws, jwe)
+// untuk sign-then-encrypt.
+func ChainPayloadCodecs(codecs ...PayloadCodec) PayloadCodec {
+	return payloadChain(codecs)
+}
+
+type payloadChain []PayloadCodec
+
+func (p payloadChain) EncodePayload(ctx context.Context, body []byte, header http.Header) ([]byte, error) {
+	var err error
+	for _, codec := range p {
+		if body, err = codec.EncodePayload(ctx, body, header); err != nil {
+			return nil, err
+		}
+	}
+	return body, nil
+}
+
+func (p payloadChain) DecodePayload(ctx context.Context, body []byte, header http.Header) ([]byte, error) {
+	var err error
+	for i := len(p) - 1; i >= 0; i-- {
+		if body, err = p[i].DecodePayload(ctx, body, header); err != nil {
+			return nil, err
+		}
+	}
+	return body, nil
+}
+
+// joseContentType adalah Content-Type default untuk payload JOSE.
+const joseContentType = "application/jose"
+
+// JWSPayload menandatangani body request sebagai JWS compact dan
+// memverifikasi body response yang ditandatangani server.
+type JWSPayload struct {
+	SigningKey  crypto.Signer    // Optional: private key untuk menandatangani request
+	Algorithm   string           // RS256, PS256, atau ES256; default mengikuti tipe key
+	KeyID       string           // Optional: header "kid"
+	VerifyKey   crypto.PublicKey // Optional: public key server untuk verifikasi response
+	ContentType string           // Content-Type request, default "application/jose"
+}
+
+// EncodePayload mengimplementasikan PayloadCodec.
+func (j *JWSPayload) EncodePayload(ctx context.Context, body []byte, header http.Header) ([]byte, error) {
+	if j.SigningKey == nil {
+		return body, nil
+	}
+	alg := j.Algorithm
+	if alg == "" {
+		var err error
+		if alg, err = jwtAlgorithm(j.SigningKey); err != nil {
+			return nil, err
+		}
+	}
+
+	protected := map[string]string{"alg": alg}
+	if j.KeyID != "" {
+		protected["kid"] = j.KeyID
+	}
+	if cty := header.Get("Content-Type"); cty != "" {
+		protected["cty"] = cty
+	}
+	headerJSON, err := json.Marshal(protected)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := signCompactJWS(j.SigningKey, alg, headerJSON, body)
+	if err != nil {
+		return nil, err
+	}
+	header.Set("Content-Type", joseContent(j.ContentType))
+	return []byte(token), nil
+}
+
+// DecodePayload mengimplementasikan PayloadCodec. Response JSON biasa
+// (mis. body error) dilewatkan tanpa verifikasi.
+func (j *JWSPayload) DecodePayload(ctx context.Context, body []byte, header http.Header) ([]byte, error) {
+	if j.VerifyKey == nil || skipJOSEDecode(body, header) {
+		return body, nil
+	}
+	parts := strings.Split(strings.TrimSpace(string(body)), ".")
+	if len(parts) != 3 {
+		return nil, errors.New("jws: invalid compact serialization")
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("jws: invalid header: %w", err)
+	}
+	var protected struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(headerJSON, &protected); err != nil {
+		return nil, fmt.Errorf("jws: invalid header: %w", err)
+	}
+	if j.Algorithm != "" && protected.Alg != j.Algorithm {
+		return nil, fmt.Errorf("jws: unexpected alg %q", protected.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("jws: invalid signature encoding: %w", err)
+	}
+	if err := verifyJWS(j.VerifyKey, protected.Alg, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("jws: invalid payload: %w", err)
+	}
+	return payload, nil
+}
+
+func verifyJWS(key crypto.PublicKey, alg, signingInput string, signature []byte) error {
+	digest := sha256.Sum256([]byte(signingInput))
+	var err error
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		switch alg {
+		case "RS256":
+			err = rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], signature)
+		case "PS256":
+			err = rsa.VerifyPSS(k, crypto.SHA256, digest[:], signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		default:
+			return fmt.Errorf("jws: alg %q does not match RSA key", alg)
+		}
+	case *ecdsa.PublicKey:
+		if alg != "ES256" || len(signature) != 64 {
+			return fmt.Errorf("jws: alg %q does not match ECDSA key", alg)
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(k, digest[:], r, s) {
+			err = errors.New("invalid signature")
+		}
+	default:
+		return fmt.Errorf("jws: unsupported public key type %T", key)
+	}
+	if err != nil {
+		return fmt.Errorf("jws: verification failed: %w", err)
+	}
+	return nil
+}
+
+// JWEPayload mengenkripsi body request sebagai JWE compact
+// (RSA-OAEP-256 + A256GCM) dan mendekripsi body response.
+type JWEPayload struct {
+	RecipientKey *rsa.PublicKey  // Optional: public key server untuk enkripsi request
+	KeyID        string          // Optional: header "kid" milik RecipientKey
+	DecryptKey   *rsa.PrivateKey // Optional: private key untuk dekripsi response
+	ContentType  string          // Content-Type request, default "application/jose"
+}
+
+// EncodePayload mengimplementasikan PayloadCodec.
+func (j *JWEPayload) EncodePayload(ctx context.Context, body []byte, header http.Header) ([]byte, error) {
+	if j.RecipientKey == nil {
+		return body, nil
+	}
+
+	protected := map[string]string{"alg": "RSA-OAEP-256", "enc": "A256GCM"}
+	if j.KeyID != "" {
+		protected["kid"] = j.KeyID
+	}
+	if cty := header.Get("Content-Type"); cty != "" {
+		protected["cty"] = cty
+	}
+	headerJSON, err := json.Marshal(protected)
+	if err != nil {
+		return nil, err
+	}
+	encodedHeader := base64.RawURLEncoding.EncodeToString(headerJSON)
+
+	cek := make([]byte, 32)
+	if _, err := rand.Read(cek); err != nil {
+		return nil, err
+	}
+	encryptedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, j.RecipientKey, cek, nil)
+	if err != nil {
+		return nil, fmt.Errorf("jwe: error encrypt key: %w", err)
+	}
+
+	gcm, err := newAESGCM(cek)
+	if err != nil {
+		return nil, err
+	}
+	iv := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+	sealed := gcm.Seal(nil, iv, body, []byte(encodedHeader))
+	ciphertext, tag := sealed[:len(sealed)-gcm.Overhead()], sealed[len(sealed)-gcm.Overhead():]
+
+	token := strings.Join([]string{
+		encodedHeader,
+		base64.RawURLEncoding.EncodeToString(encryptedKey),
+		base64.RawURLEncoding.EncodeToString(iv),
+		base64.RawURLEncoding.EncodeToString(ciphertext),
+		base64.RawURLEncoding.EncodeToString(tag),
+	}, ".")
+	header.Set("Content-Type", joseContent(j.ContentType))
+	return []byte(token), nil
+}
+
+// DecodePayload mengimplementasikan PayloadCodec. Response JSON biasa
+// (mis. body error) dilewatkan tanpa dekripsi.
+func (j *JWEPayload) DecodePayload(ctx context.Context, body []byte, header http.Header) ([]byte, error) {
+	if j.DecryptKey == nil || skipJOSEDecode(body, header) {
+		return body, nil
+	}
+	parts := strings.Split(strings.TrimSpace(string(body)), ".")
+	if len(parts) != 5 {
+		return nil, errors.New("jwe: invalid compact serialization")
+	}
+
+	decoded := make([][]byte, 5)
+	for i, part := range parts {
+		var err error
+		if decoded[i], err = base64.RawURLEncoding.DecodeString(part); err != nil {
+			return nil, fmt.Errorf("jwe: invalid encoding in part %d: %w", i, err)
+		}
+	}
+	var protected struct {
+		Alg string `json:"alg"`
+		Enc string `json:"enc"`
+	}
+	if err := json.Unmarshal(decoded[0], &protected); err != nil {
+		return nil, fmt.Errorf("jwe: invalid header: %w", err)
+	}
+	if protected.Alg != "RSA-OAEP-256" || protected.Enc != "A256GCM" {
+		return nil, fmt.Errorf("jwe: unsupported alg/enc %s/%s", protected.Alg, protected.Enc)
+	}
+
+	cek, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, j.DecryptKey, decoded[1], nil)
+	if err != nil {
+		return nil, fmt.Errorf("jwe: error decrypt key: %w", err)
+	}
+	gcm, err := newAESGCM(cek)
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded[2]) != gcm.NonceSize() {
+		return nil, errors.New("jwe: invalid iv length")
+	}
+	plaintext, err := gcm.Open(nil, decoded[2], append(decoded[3], decoded[4]...), []byte(parts[0]))
+	if err != nil {
+		return nil, fmt.Errorf("jwe: error decrypt payload: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("jwe: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+func joseContent(contentType string) string {
+	if contentType == "" {
+		return joseContentType
+	}
+	return contentType
+}
+
+// skipJOSEDecode bernilai true untuk body kosong atau response JSON biasa.
+func skipJOSEDecode(body []byte, header http.Header) bool {
+	return len(body) == 0 || strings.Contains(header.Get("Content-Type"), "json")
+}
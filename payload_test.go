@@ -0,0 +1,131 @@
+package http_request_instant
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestJWEJWSPayloadRoundTrip(t *testing.T) {
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	serverKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// server: dekripsi pakai key sendiri, verifikasi tanda tangan client,
+	// lalu balas terenkripsi ke client
+	clientEncKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	serverCodec := ChainPayloadCodecs(
+		&JWSPayload{VerifyKey: &clientKey.PublicKey},
+		&JWEPayload{DecryptKey: serverKey, RecipientKey: &clientEncKey.PublicKey},
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/jose" {
+			t.Errorf("expected application/jose, got %q", ct)
+		}
+		raw, _ := io.ReadAll(r.Body)
+		plain, err := serverCodec.DecodePayload(r.Context(), raw, r.Header)
+		if err != nil {
+			t.Errorf("server decode: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if string(plain) != `{"amount":100}` {
+			t.Errorf("unexpected plaintext: %s", plain)
+		}
+		header := http.Header{"Content-Type": []string{"application/json"}}
+		sealed, err := (&JWEPayload{RecipientKey: &clientEncKey.PublicKey}).EncodePayload(r.Context(), []byte(`{"status":"ok"}`), header)
+		if err != nil {
+			t.Errorf("server encode: %v", err)
+		}
+		w.Header().Set("Content-Type", header.Get("Content-Type"))
+		_, _ = w.Write(sealed)
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.PayloadCodec = ChainPayloadCodecs(
+		&JWSPayload{SigningKey: clientKey, KeyID: "client-1"},
+		&JWEPayload{RecipientKey: &serverKey.PublicKey, DecryptKey: clientEncKey},
+	)
+
+	var result struct {
+		Status string `json:"status"`
+	}
+	_, err = client.Request(context.TODO(), RequestOptions{
+		Method:         "POST",
+		URL:            ts.URL,
+		ContentType:    "application/json",
+		RequestBody:    map[string]int{"amount": 100},
+		ResponseTarget: &result,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != "ok" {
+		t.Errorf("expected decrypted response, got %+v", result)
+	}
+}
+
+func TestJWSPayloadRejectsTamperedResponse(t *testing.T) {
+	serverKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// ditandatangani key yang salah
+		signed, _ := (&JWSPayload{SigningKey: otherKey, Algorithm: "PS256"}).EncodePayload(r.Context(), []byte(`{}`), http.Header{})
+		w.Header().Set("Content-Type", "application/jose")
+		_, _ = w.Write(signed)
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.PayloadCodec = &JWSPayload{VerifyKey: &serverKey.PublicKey}
+
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err == nil {
+		t.Fatal("expected verification error")
+	}
+}
+
+func TestJWSPayloadSkipsPlainJSONErrors(t *testing.T) {
+	serverKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"bad"}`))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.PayloadCodec = &JWSPayload{VerifyKey: &serverKey.PublicKey}
+
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(resp.Body) != `{"error":"bad"}` {
+		t.Errorf("unexpected body: %s", resp.Body)
+	}
+}
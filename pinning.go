@@ -0,0 +1,136 @@
+package http_request_instant
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// PinningOptions menyimpan konfigurasi certificate pinning berbasis
+// hash SPKI (format "sha256/<base64>", sama dengan HPKP).
+type PinningOptions struct {
+	// Pin per host. Key bisa host persis ("api.bank.com") atau wildcard
+	// satu level ("*.bank.com"), tidak peka huruf besar/kecil. Host yang tidak terdaftar tidak di-pin.
+	// Host dicocokkan dari SNI, sehingga target berupa alamat IP tidak bisa di-pin.
+	Pins map[string][]string
+
+	// Jika true, mismatch hanya dilaporkan tanpa menggagalkan koneksi.
+	ReportOnly bool
+
+	// Optional: dipanggil setiap kali terjadi mismatch. Jika nil dan
+	// ReportOnly aktif, mismatch dicatat lewat Logger client.
+	OnMismatch func(err *PinMismatchError)
+}
+
+// PinMismatchError dikembalikan ketika tidak ada certificate server
+// yang cocok dengan pin yang dikonfigurasi.
+type PinMismatchError struct {
+	Host     string
+	Expected []string
+	Got      []string
+}
+
+func (e *PinMismatchError) Error() string {
+	return fmt.Sprintf("certificate pin mismatch for %s: got [%s]", e.Host, strings.Join(e.Got, ", "))
+}
+
+// SPKIPin menghitung pin "sha256/<base64>" dari public key certificate.
+func SPKIPin(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return "sha256/" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// SetCertificatePins mengaktifkan certificate pinning. Pin dicek setelah
+// verifikasi certificate normal; cukup satu certificate di chain yang sudah
+// diverifikasi yang cocok.
+func (c *HttpRequest) SetCertificatePins(options PinningOptions) error {
+	// SNI dicocokkan dalam huruf kecil, jadi key juga dinormalisasi; map
+	// milik caller tidak diubah
+	pinsByHost := make(map[string][]string, len(options.Pins))
+	for host, pins := range options.Pins {
+		for _, pin := range pins {
+			if !strings.HasPrefix(pin, "sha256/") {
+				return fmt.Errorf("invalid pin for %s: %q must start with sha256/", host, pin)
+			}
+		}
+		host = strings.ToLower(host)
+		pinsByHost[host] = append(pinsByHost[host], pins...)
+	}
+	options.Pins = pinsByHost
+
+	config, err := c.tlsConfig()
+	if err != nil {
+		return err
+	}
+	if len(options.Pins) == 0 {
+		config.VerifyConnection = nil
+		return nil
+	}
+	config.VerifyConnection = func(cs tls.ConnectionState) error {
+		return verifyPins(c.logger(), options, cs)
+	}
+	return nil
+}
+
+func verifyPins(logger Logger, options PinningOptions, cs tls.ConnectionState) error {
+	expected := lookupPins(options.Pins, cs.ServerName)
+	if len(expected) == 0 {
+		return nil
+	}
+
+	allowed := make(map[string]bool, len(expected))
+	for _, pin := range expected {
+		allowed[pin] = true
+	}
+
+	// Hanya certificate yang sudah diverifikasi yang dicek: PeerCertificates
+	// dikirim server apa adanya, jadi pin publik bisa ditempel ke chain lain.
+	// Tanpa verifikasi (InsecureSkipVerify) hanya leaf yang dipercaya.
+	var certs []*x509.Certificate
+	for _, chain := range cs.VerifiedChains {
+		certs = append(certs, chain...)
+	}
+	if len(cs.VerifiedChains) == 0 && len(cs.PeerCertificates) > 0 {
+		certs = cs.PeerCertificates[:1]
+	}
+
+	var got []string
+	seen := make(map[string]bool)
+	for _, cert := range certs {
+		pin := SPKIPin(cert)
+		if allowed[pin] {
+			return nil
+		}
+		if !seen[pin] {
+			seen[pin] = true
+			got = append(got, pin)
+		}
+	}
+
+	mismatch := &PinMismatchError{Host: cs.ServerName, Expected: expected, Got: got}
+	if options.OnMismatch != nil {
+		options.OnMismatch(mismatch)
+	} else if options.ReportOnly {
+		logger.Errorf("[CERTIFICATE PIN] report-only: %v", mismatch)
+	}
+	if options.ReportOnly {
+		return nil
+	}
+	return mismatch
+}
+
+func lookupPins(pins map[string][]string, host string) []string {
+	host = strings.ToLower(host)
+	if p, ok := pins[host]; ok {
+		return p
+	}
+	if i := strings.IndexByte(host, '.'); i > 0 {
+		if p, ok := pins["*"+host[i:]]; ok {
+			return p
+		}
+	}
+	return nil
+}
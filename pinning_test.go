@@ -0,0 +1,126 @@
+package http_request_instant
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newPinningTestClient membuat client yang mengarahkan semua koneksi ke server test.
+// Certificate httptest berlaku untuk example.com, dan pin butuh hostname (SNI).
+func newPinningTestClient(ts *httptest.Server) *HttpRequest {
+	client := NewHttpRequest()
+	_ = client.AddRootCAPEM(certToPEM(ts.Certificate()))
+	transport, _ := client.transport()
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, ts.Listener.Addr().String())
+	}
+	return client
+}
+
+func TestCertificatePinning(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	defer ts.Close()
+
+	url := "https://example.com"
+	goodPin := SPKIPin(ts.Certificate())
+	host := "example.com"
+
+	client := newPinningTestClient(ts)
+	if err := client.SetCertificatePins(PinningOptions{Pins: map[string][]string{host: {"sha256/bad", goodPin}}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: url}); err != nil {
+		t.Fatalf("unexpected error with matching pin: %v", err)
+	}
+
+	pinned := newPinningTestClient(ts)
+	_ = pinned.SetCertificatePins(PinningOptions{Pins: map[string][]string{host: {"sha256/bad"}}})
+	_, err := pinned.Request(context.TODO(), RequestOptions{Method: "GET", URL: url})
+	var mismatch *PinMismatchError
+	if !errors.As(err, &mismatch) || mismatch.Got[0] != goodPin {
+		t.Fatalf("expected pin mismatch error, got %v", err)
+	}
+}
+
+func TestCertificatePinningIgnoresUnverifiedCerts(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	ts.StartTLS()
+	defer ts.Close()
+
+	// Server dengan certificate valid menempelkan certificate yang di-pin
+	// (publik, milik pihak lain) ke chain yang dikirim
+	pinnedCert, _, _ := generateTestCertificate(t, "pinned.example.com")
+	leaf := &ts.TLS.Certificates[0]
+	leaf.Certificate = append(leaf.Certificate, pinnedCert.Leaf.Raw)
+
+	client := newPinningTestClient(ts)
+	_ = client.SetCertificatePins(PinningOptions{Pins: map[string][]string{"example.com": {SPKIPin(pinnedCert.Leaf)}}})
+	_, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: "https://example.com"})
+	var mismatch *PinMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected pin mismatch for unverified certificate, got %v", err)
+	}
+	for _, pin := range mismatch.Got {
+		if pin == SPKIPin(pinnedCert.Leaf) {
+			t.Errorf("unverified certificate must not be considered, got %v", mismatch.Got)
+		}
+	}
+}
+
+func TestCertificatePinningReportOnly(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	defer ts.Close()
+
+	var reported *PinMismatchError
+	client := newPinningTestClient(ts)
+	err := client.SetCertificatePins(PinningOptions{
+		Pins:       map[string][]string{"*.com": {"sha256/bad"}},
+		ReportOnly: true,
+		OnMismatch: func(err *PinMismatchError) { reported = err },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: "https://example.com"}); err != nil {
+		t.Fatalf("report-only should not fail the request: %v", err)
+	}
+	if reported == nil {
+		t.Fatal("expected mismatch to be reported")
+	}
+
+	if err := client.SetCertificatePins(PinningOptions{Pins: map[string][]string{"x": {"md5/abc"}}}); err == nil {
+		t.Error("expected error for invalid pin format, got nil")
+	}
+}
+
+func TestCertificatePinningHostCaseInsensitive(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	defer ts.Close()
+
+	client := newPinningTestClient(ts)
+	pins := map[string][]string{"Example.COM": {"sha256/bad"}}
+	if err := client.SetCertificatePins(PinningOptions{Pins: pins}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: "https://example.com"})
+	var mismatch *PinMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected mixed-case pin key to apply, got %v", err)
+	}
+	if _, ok := pins["Example.COM"]; !ok || len(pins) != 1 {
+		t.Errorf("expected caller's map to be unchanged, got %v", pins)
+	}
+}
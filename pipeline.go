@@ -0,0 +1,146 @@
+package http_request_instant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// PipelineStep adalah satu request dalam Pipeline.
+//
+// URL, nilai Headers, dan RequestBody bertipe string atau []byte boleh berisi
+// placeholder {{step.path}}, yang diganti dengan field path (bertitik) dari
+// body JSON response step bernama step, mis. {{login.access_token}} atau
+// {{create.data.id}}. {{step}} saja berarti seluruh body. Di URL nilainya
+// di-escape dengan url.PathEscape.
+type PipelineStep struct {
+	Name    string // Optional: nama yang dirujuk step berikutnya
+	Options RequestOptions
+
+	// Optional: membentuk request dari hasil step sebelumnya, untuk kasus
+	// yang tidak cukup dengan placeholder. Jika diisi, Options diabaikan.
+	Build func(ctx context.Context, results PipelineResults) (RequestOptions, error)
+}
+
+// PipelineResults menyimpan response step yang sudah selesai per nama.
+type PipelineResults map[string]*ApiResponse
+
+// Value mengembalikan nilai placeholder "step.path" dari results. String JSON
+// dikembalikan tanpa tanda kutip; nilai lain sebagai JSON apa adanya.
+func (r PipelineResults) Value(ref string) (string, error) {
+	name, path, _ := strings.Cut(ref, ".")
+	resp, ok := r[name]
+	if !ok {
+		return "", fmt.Errorf("pipeline: unknown step %q", name)
+	}
+	raw, err := lookupJSONPath(resp.Body, path)
+	if err != nil {
+		return "", fmt.Errorf("pipeline: step %s %w", name, err)
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s, nil
+	}
+	return strings.TrimSpace(string(raw)), nil
+}
+
+// PipelineError menandai step Pipeline yang gagal.
+type PipelineError struct {
+	Index int    // Urutan step, mulai dari 0
+	Step  string // Nama step, kosong jika tidak diberi nama
+	Err   error
+}
+
+func (e *PipelineError) Error() string {
+	if e.Step != "" {
+		return fmt.Sprintf("pipeline step %d (%s): %v", e.Index, e.Step, e.Err)
+	}
+	return fmt.Sprintf("pipeline step %d: %v", e.Index, e.Err)
+}
+
+func (e *PipelineError) Unwrap() error {
+	return e.Err
+}
+
+// Pipeline menjalankan steps satu per satu dengan ctx yang sama. Step yang
+// gagal, termasuk response 4xx/5xx (*StatusError), menghentikan pipeline dan
+// dikembalikan sebagai *PipelineError bersama hasil step sebelumnya.
+func (c *HttpRequest) Pipeline(ctx context.Context, steps ...PipelineStep) (PipelineResults, error) {
+	results := make(PipelineResults)
+	for i, step := range steps {
+		resp, err := c.runPipelineStep(ctx, step, results)
+		if err != nil {
+			return results, &PipelineError{Index: i, Step: step.Name, Err: err}
+		}
+		if step.Name != "" {
+			results[step.Name] = resp
+		}
+	}
+	return results, nil
+}
+
+func (c *HttpRequest) runPipelineStep(ctx context.Context, step PipelineStep, results PipelineResults) (*ApiResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var options RequestOptions
+	var err error
+	if step.Build != nil {
+		err = safeCall("pipeline build", func() (err error) {
+			options, err = step.Build(ctx, results)
+			return err
+		})
+	} else {
+		options, err = expandPipelineOptions(step.Options, results)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Request(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	if err := resp.Err(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+var pipelinePlaceholder = regexp.MustCompile(`\{\{\s*([^{}\s]+)\s*\}\}`)
+
+// expandPipelineOptions mengganti placeholder di salinan options.
+func expandPipelineOptions(options RequestOptions, results PipelineResults) (RequestOptions, error) {
+	var err error
+	expand := func(s string, escape func(string) string) string {
+		return pipelinePlaceholder.ReplaceAllStringFunc(s, func(match string) string {
+			value, verr := results.Value(pipelinePlaceholder.FindStringSubmatch(match)[1])
+			if verr != nil && err == nil {
+				err = verr
+			}
+			if escape != nil {
+				return escape(value)
+			}
+			return value
+		})
+	}
+
+	options.URL = expand(options.URL, url.PathEscape)
+	if len(options.Headers) > 0 {
+		headers := make(map[string]string, len(options.Headers))
+		for name, value := range options.Headers {
+			headers[name] = expand(value, nil)
+		}
+		options.Headers = headers
+	}
+	switch body := options.RequestBody.(type) {
+	case string:
+		options.RequestBody = expand(body, nil)
+	case []byte:
+		options.RequestBody = []byte(expand(string(body), nil))
+	}
+	return options, err
+}
@@ -0,0 +1,96 @@
+package http_request_instant
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestPipeline(t *testing.T) {
+	var calls []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		calls = append(calls, r.Method+" "+r.URL.RequestURI()+" "+r.Header.Get("Authorization")+" "+string(body))
+		switch r.URL.Path {
+		case "/login":
+			w.Write([]byte(`{"access_token":"tok-1"}`))
+		case "/projects":
+			w.Write([]byte(`{"data":{"id":"p 1","quota":5}}`))
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	auth := map[string]string{"Authorization": "Bearer {{login.access_token}}"}
+	results, err := client.Pipeline(context.TODO(),
+		PipelineStep{Name: "login", Options: RequestOptions{Method: "POST", URL: ts.URL + "/login"}},
+		PipelineStep{Name: "project", Options: RequestOptions{Method: "POST", URL: ts.URL + "/projects", Headers: auth}},
+		PipelineStep{Options: RequestOptions{
+			Method:      "PUT",
+			URL:         ts.URL + "/projects/{{project.data.id}}/quota",
+			Headers:     auth,
+			RequestBody: `{"quota":{{ project.data.quota }}}`,
+		}},
+		PipelineStep{Name: "built", Build: func(ctx context.Context, results PipelineResults) (RequestOptions, error) {
+			id, err := results.Value("project.data.id")
+			return RequestOptions{Method: "GET", URL: ts.URL + "/check?id=" + url.QueryEscape(id)}, err
+		}},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 3 || auth["Authorization"] != "Bearer {{login.access_token}}" {
+		t.Errorf("unexpected results %v or mutated headers %v", results, auth)
+	}
+	want := []string{
+		"POST /login  ",
+		"POST /projects Bearer tok-1 ",
+		`PUT /projects/p%201/quota Bearer tok-1 {"quota":5}`,
+		"GET /check?id=p+1  ",
+	}
+	for i := range want {
+		if i >= len(calls) || calls[i] != want[i] {
+			t.Errorf("call %d: expected %q, got %q", i, want[i], calls)
+		}
+	}
+}
+
+func TestPipelineAbortsOnFailure(t *testing.T) {
+	var hits int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		w.Write([]byte(`{"id":1}`))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	results, err := client.Pipeline(context.TODO(),
+		PipelineStep{Name: "first", Options: RequestOptions{Method: "GET", URL: ts.URL}},
+		PipelineStep{Name: "second", Options: RequestOptions{Method: "GET", URL: ts.URL + "/missing"}},
+		PipelineStep{Options: RequestOptions{Method: "GET", URL: ts.URL}},
+	)
+	var pipelineErr *PipelineError
+	var statusErr *StatusError
+	if !errors.As(err, &pipelineErr) || pipelineErr.Index != 1 || pipelineErr.Step != "second" || !errors.As(err, &statusErr) {
+		t.Fatalf("expected step 1 StatusError, got %v", err)
+	}
+	if hits != 2 || results["first"] == nil {
+		t.Errorf("expected abort after 2 hits with partial results, got %d, %v", hits, results)
+	}
+
+	_, err = client.Pipeline(context.TODO(), PipelineStep{Options: RequestOptions{Method: "GET", URL: ts.URL + "/{{nope.id}}"}})
+	if !errors.As(err, &pipelineErr) || hits != 2 {
+		t.Errorf("expected unknown step reference to fail before sending, got %v", err)
+	}
+}
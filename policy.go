@@ -0,0 +1,189 @@
+package http_request_instant
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// PolicyRule adalah aturan OutboundPolicy yang dilanggar.
+type PolicyRule string
+
+const (
+	PolicyRuleHost      PolicyRule = "allowed_hosts"    // Host tujuan tidak ada di AllowedHosts
+	PolicyRuleHeader    PolicyRule = "required_headers" // Header wajib tidak ada
+	PolicyRulePlaintext PolicyRule = "plaintext_http"   // Request http:// saat ForbidPlaintext aktif
+	PolicyRuleBodySize  PolicyRule = "max_body_bytes"   // Body request melebihi MaxBodyBytes
+)
+
+// OutboundPolicy adalah guardrail egress yang dicek tepat sebelum setiap
+// request dan redirect meninggalkan proses, setelah hook, mutator, dan signer
+// selesai mengubah request.
+type OutboundPolicy struct {
+	// Host tujuan yang diizinkan, berupa nama host persis atau wildcard
+	// "*.example.com". Kosong berarti semua host diizinkan.
+	AllowedHosts []string
+
+	// Header yang wajib ada di setiap request, mis. "X-Tenant-Id".
+	RequiredHeaders []string
+
+	// Tolak request dengan skema http://. Host di PlaintextHosts dikecualikan,
+	// mis. "localhost" untuk sidecar.
+	ForbidPlaintext bool
+	PlaintextHosts  []string
+
+	// Batas ukuran body request dalam byte, 0 berarti tidak dibatasi. Body
+	// yang panjangnya tidak diketahui dihentikan begitu melewati batas.
+	MaxBodyBytes int64
+
+	// Jika true, pelanggaran hanya dilaporkan tanpa menggagalkan request.
+	ReportOnly bool
+
+	// Optional: dipanggil setiap kali terjadi pelanggaran. Jika nil dan
+	// ReportOnly aktif, pelanggaran dicatat ke Logger.
+	OnViolation func(violation *PolicyViolation)
+}
+
+// PolicyViolation dikembalikan ketika request melanggar OutboundPolicy.
+type PolicyViolation struct {
+	Rule   PolicyRule
+	Method string
+	URL    string // URL dengan query rahasia disamarkan
+	Detail string
+}
+
+func (e *PolicyViolation) Error() string {
+	return fmt.Sprintf("outbound policy violation (%s): %s %s: %s", e.Rule, e.Method, e.URL, e.Detail)
+}
+
+// Category mengimplementasikan CategorizedError.
+func (e *PolicyViolation) Category() ErrorCategory {
+	return CategoryPolicy
+}
+
+// SetOutboundPolicy memasang OutboundPolicy untuk semua request client,
+// termasuk redirect yang diikuti http.Client. Panggil sebelum client dipakai
+// secara konkuren.
+func (c *HttpRequest) SetOutboundPolicy(policy OutboundPolicy) {
+	c.policy = &policy
+
+	checkRedirect := c.Client.CheckRedirect
+	c.Client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if err := c.enforcePolicy(req); err != nil {
+			return err
+		}
+		if checkRedirect != nil {
+			return checkRedirect(req, via)
+		}
+		if len(via) >= 10 {
+			return fmt.Errorf("stopped after 10 redirects")
+		}
+		return nil
+	}
+}
+
+// enforcePolicy mengecek req terhadap OutboundPolicy client. Error berupa
+// *PolicyViolation, atau nil jika tidak ada pelanggaran atau ReportOnly aktif.
+func (c *HttpRequest) enforcePolicy(req *http.Request) error {
+	policy := c.policy
+	if policy == nil {
+		return nil
+	}
+	violation := c.checkPolicy(policy, req)
+	if violation == nil {
+		if policy.MaxBodyBytes > 0 && req.ContentLength < 0 && req.Body != nil {
+			req.Body = &policyBodyReader{ReadCloser: req.Body, client: c, req: req, remaining: policy.MaxBodyBytes}
+		}
+		return nil
+	}
+	return c.reportViolation(violation)
+}
+
+// enforceDestinationPolicy seperti enforcePolicy, tetapi hanya mengecek
+// aturan tujuan (AllowedHosts dan ForbidPlaintext). Dipakai untuk koneksi
+// yang dibuka tanpa request, seperti Preconnect.
+func (c *HttpRequest) enforceDestinationPolicy(req *http.Request) error {
+	if c.policy == nil {
+		return nil
+	}
+	if violation := c.checkDestination(c.policy, req); violation != nil {
+		return c.reportViolation(violation)
+	}
+	return nil
+}
+
+func (c *HttpRequest) checkPolicy(policy *OutboundPolicy, req *http.Request) *PolicyViolation {
+	if violation := c.checkDestination(policy, req); violation != nil {
+		return violation
+	}
+	violation := c.policyViolation(req)
+	for _, name := range policy.RequiredHeaders {
+		if req.Header.Get(name) == "" {
+			return violation(PolicyRuleHeader, "missing required header %s", name)
+		}
+	}
+	if policy.MaxBodyBytes > 0 && req.ContentLength > policy.MaxBodyBytes {
+		return violation(PolicyRuleBodySize, "body %d bytes exceeds %d", req.ContentLength, policy.MaxBodyBytes)
+	}
+	return nil
+}
+
+func (c *HttpRequest) checkDestination(policy *OutboundPolicy, req *http.Request) *PolicyViolation {
+	violation := c.policyViolation(req)
+	hostname := req.URL.Hostname()
+	if len(policy.AllowedHosts) > 0 && !matchAnyHost(policy.AllowedHosts, hostname) {
+		return violation(PolicyRuleHost, "host %q is not allowed", hostname)
+	}
+	if policy.ForbidPlaintext && req.URL.Scheme == "http" && !matchAnyHost(policy.PlaintextHosts, hostname) {
+		return violation(PolicyRulePlaintext, "plaintext http is forbidden")
+	}
+	return nil
+}
+
+// policyViolation mengembalikan konstruktor PolicyViolation untuk req.
+func (c *HttpRequest) policyViolation(req *http.Request) func(rule PolicyRule, format string, args ...any) *PolicyViolation {
+	return func(rule PolicyRule, format string, args ...any) *PolicyViolation {
+		return &PolicyViolation{Rule: rule, Method: req.Method, URL: c.redactURL(req.URL, c.requestApiKey(req)), Detail: fmt.Sprintf(format, args...)}
+	}
+}
+
+// reportViolation memanggil OnViolation atau mencatat pelanggaran ReportOnly.
+func (c *HttpRequest) reportViolation(violation *PolicyViolation) error {
+	if c.policy.OnViolation != nil {
+		c.policy.OnViolation(violation)
+	} else if c.policy.ReportOnly {
+		c.logger().Errorf("[OUTBOUND POLICY] report-only: %v", violation)
+	}
+	if c.policy.ReportOnly {
+		return nil
+	}
+	return violation
+}
+
+// policyBodyReader menghentikan upload body tanpa Content-Length begitu
+// melewati OutboundPolicy.MaxBodyBytes.
+type policyBodyReader struct {
+	io.ReadCloser
+	client    *HttpRequest
+	req       *http.Request
+	remaining int64
+	reported  bool
+}
+
+func (r *policyBodyReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.remaining -= int64(n)
+	if r.remaining < 0 && !r.reported {
+		r.reported = true
+		violation := &PolicyViolation{
+			Rule:   PolicyRuleBodySize,
+			Method: r.req.Method,
+			URL:    r.client.redactURL(r.req.URL, r.client.requestApiKey(r.req)),
+			Detail: fmt.Sprintf("body exceeds %d bytes", r.client.policy.MaxBodyBytes),
+		}
+		if err := r.client.reportViolation(violation); err != nil {
+			return 0, err
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,104 @@
+package http_request_instant
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOutboundPolicyViolations(t *testing.T) {
+	var hits int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name    string
+		policy  OutboundPolicy
+		options RequestOptions
+		rule    PolicyRule
+	}{
+		{"allowed", OutboundPolicy{AllowedHosts: []string{"127.0.0.1"}, RequiredHeaders: []string{"X-Tenant"}, MaxBodyBytes: 16},
+			RequestOptions{Method: "POST", URL: ts.URL, Headers: map[string]string{"X-Tenant": "a"}, RequestBody: "small"}, ""},
+		{"host", OutboundPolicy{AllowedHosts: []string{"*.vendor.test"}},
+			RequestOptions{Method: "GET", URL: ts.URL}, PolicyRuleHost},
+		{"header", OutboundPolicy{RequiredHeaders: []string{"X-Tenant"}},
+			RequestOptions{Method: "GET", URL: ts.URL}, PolicyRuleHeader},
+		{"plaintext", OutboundPolicy{ForbidPlaintext: true},
+			RequestOptions{Method: "GET", URL: ts.URL}, PolicyRulePlaintext},
+		{"plaintext exception", OutboundPolicy{ForbidPlaintext: true, PlaintextHosts: []string{"127.0.0.1"}},
+			RequestOptions{Method: "GET", URL: ts.URL}, ""},
+		{"body size", OutboundPolicy{MaxBodyBytes: 4},
+			RequestOptions{Method: "POST", URL: ts.URL, RequestBody: "too large"}, PolicyRuleBodySize},
+	}
+	for _, tt := range tests {
+		hits = 0
+		client := NewHttpRequest()
+		client.SetOutboundPolicy(tt.policy)
+		_, err := client.Request(context.TODO(), tt.options)
+
+		if tt.rule == "" {
+			if err != nil || hits != 1 {
+				t.Errorf("%s: expected request sent, got %v (hits %d)", tt.name, err, hits)
+			}
+			continue
+		}
+		var violation *PolicyViolation
+		if !errors.As(err, &violation) || violation.Rule != tt.rule {
+			t.Errorf("%s: expected %s violation, got %v", tt.name, tt.rule, err)
+			continue
+		}
+		if hits != 0 || Category(err) != CategoryPolicy {
+			t.Errorf("%s: request left the process or wrong category %q", tt.name, Category(err))
+		}
+	}
+}
+
+func TestOutboundPolicyChecksFinalRequestAndRedirects(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("redirect to disallowed host was followed")
+	}))
+	defer other.Close()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, strings.Replace(other.URL, "127.0.0.1", "localhost", 1), http.StatusFound)
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.AddRequestMutator(func(ctx context.Context, req *http.Request) error {
+		req.Header.Set("X-Tenant", "mutated")
+		return nil
+	})
+	client.SetOutboundPolicy(OutboundPolicy{AllowedHosts: []string{"127.0.0.1"}, RequiredHeaders: []string{"X-Tenant"}})
+
+	_, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+	var violation *PolicyViolation
+	if !errors.As(err, &violation) || violation.Rule != PolicyRuleHost || !strings.Contains(violation.Detail, "localhost") {
+		t.Errorf("expected redirect host violation, got %v", err)
+	}
+}
+
+func TestOutboundPolicyReportOnly(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	logger := &recordLogger{}
+	client := NewHttpRequest()
+	client.Logger = logger
+	client.SetOutboundPolicy(OutboundPolicy{RequiredHeaders: []string{"X-Tenant"}, ReportOnly: true})
+
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL + "?token=secret"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logged := strings.Join(logger.entries, "\n")
+	if !strings.Contains(logged, "[OUTBOUND POLICY] report-only") || !strings.Contains(logged, "X-Tenant") || strings.Contains(logged, "secret") {
+		t.Errorf("unexpected log: %s", logged)
+	}
+}
@@ -0,0 +1,80 @@
+package http_request_instant
+
+import (
+	"context"
+	"time"
+)
+
+// pollMaxBackoff membatasi jeda Poll setelah error berturut-turut.
+const pollMaxBackoff = time.Minute
+
+// Poll mengulang request setiap interval (default 1 detik) sampai until
+// mengembalikan true, lalu mengembalikan response terakhir. Batas waktu diatur
+// lewat ctx; jika ctx selesai lebih dulu, response terakhir dikembalikan
+// bersama ctx.Err().
+//
+// Error yang layak diulang (timeout, 5xx, 429) tidak diteruskan ke until;
+// jedanya digandakan sampai 1 menit atau Retry-After dari server, lalu
+// kembali ke interval setelah response normal. Error lain dan response 4xx
+// diteruskan apa adanya: error menghentikan Poll, response 4xx diperiksa until.
+// ResponseTarget hanya di-decode dari response yang memenuhi until.
+func (c *HttpRequest) Poll(ctx context.Context, options RequestOptions, interval time.Duration, until func(*ApiResponse) bool) (*ApiResponse, error) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	target := options.ResponseTarget
+	options.ResponseTarget = nil
+
+	var last *ApiResponse
+	wait := interval
+	for {
+		resp, err := c.Request(ctx, options)
+		switch {
+		case ctx.Err() != nil:
+			return last, ctx.Err()
+		case defaultRetryOn(resp, err):
+			if resp != nil {
+				last = resp
+			}
+			wait = min(max(wait*2, interval), max(pollMaxBackoff, interval))
+			if retryAt, ok := parseRetryAfter(headerValue(responseHeaders(resp), "Retry-After"), time.Now()); ok {
+				wait = max(wait, time.Until(retryAt))
+			}
+		case err != nil:
+			return last, err
+		default:
+			last = resp
+			var done bool
+			err := safeCall("poll condition", func() error {
+				done = until(resp)
+				return nil
+			})
+			if err != nil {
+				return resp, err
+			}
+			if done {
+				options.ResponseTarget = target
+				if err := decodeResponseTarget(options, resp); err != nil {
+					return nil, err
+				}
+				return resp, nil
+			}
+			wait = interval
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return last, ctx.Err()
+		}
+	}
+}
+
+func responseHeaders(resp *ApiResponse) map[string]string {
+	if resp == nil {
+		return nil
+	}
+	return resp.Headers
+}
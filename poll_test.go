@@ -0,0 +1,65 @@
+package http_request_instant
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPoll(t *testing.T) {
+	var hits int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		switch hits {
+		case 1:
+			w.Write([]byte(`{"status":"queued"}`))
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte(`{"status":"done"}`))
+		}
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	var job struct {
+		Status string `json:"status"`
+	}
+	start := time.Now()
+	resp, err := client.Poll(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL, ResponseTarget: &job}, 10*time.Millisecond, func(resp *ApiResponse) bool {
+		if resp.StatusCode == http.StatusServiceUnavailable {
+			t.Errorf("expected retryable 503 not passed to until")
+		}
+		return string(resp.Body) == `{"status":"done"}`
+	})
+	if err != nil || resp.StatusCode != http.StatusOK || job.Status != "done" || hits != 3 {
+		t.Fatalf("expected done after 3 hits, got %v, %+v, %d hits", err, job, hits)
+	}
+	// 10ms setelah queued, lalu 20ms backoff setelah 503
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("expected backoff after 503, took %v", elapsed)
+	}
+}
+
+func TestPollStopsOnDeadlineAndErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"running"}`))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+	defer cancel()
+	resp, err := client.Poll(ctx, RequestOptions{Method: "GET", URL: ts.URL}, 10*time.Millisecond, func(*ApiResponse) bool { return false })
+	if !errors.Is(err, context.DeadlineExceeded) || resp == nil || string(resp.Body) != `{"status":"running"}` {
+		t.Errorf("expected deadline with last response, got %v, %v", resp, err)
+	}
+
+	_, err = client.Poll(context.TODO(), RequestOptions{Method: "GET", URL: "http://%zz"}, 10*time.Millisecond, func(*ApiResponse) bool { return true })
+	if err == nil {
+		t.Errorf("expected non-retryable error to stop polling")
+	}
+}
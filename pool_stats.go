@@ -0,0 +1,180 @@
+package http_request_instant
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sort"
+	"sync"
+)
+
+// HostPoolStats adalah statistik koneksi untuk satu alamat yang di-dial
+// (host:port tujuan, atau alamat proxy jika request lewat proxy).
+type HostPoolStats struct {
+	Addr          string
+	Open          int64 // Koneksi yang sedang terbuka
+	InUse         int64 // Request yang sedang memakai koneksi
+	Idle          int64 // Koneksi terbuka yang tidak dipakai request
+	Opened        int64 // Total koneksi baru yang berhasil di-dial
+	Closed        int64 // Total koneksi yang sudah ditutup
+	DialErrors    int64 // Total dial yang gagal
+	Reused        int64 // Total request yang memakai koneksi dari pool
+	TLSHandshakes int64 // Total TLS handshake untuk koneksi baru
+}
+
+// PoolStats adalah snapshot statistik connection pool client.
+type PoolStats struct {
+	Hosts []HostPoolStats // Diurutkan berdasarkan Addr
+	Total HostPoolStats   // Jumlah semua host, Addr kosong
+}
+
+// EnablePoolStats mulai mencatat statistik koneksi per host: koneksi dibuka,
+// ditutup, dipakai ulang, idle, dan TLS handshake. Berguna untuk melihat
+// connection churn. Error jika transport client bukan *http.Transport.
+func (c *HttpRequest) EnablePoolStats() error {
+	t, err := c.transport()
+	if err != nil {
+		return err
+	}
+	if c.pool == nil {
+		c.pool = &poolStats{hosts: make(map[string]*HostPoolStats)}
+	}
+	t.DialContext = c.dialContext
+	return nil
+}
+
+// PoolStats mengembalikan statistik connection pool. Nilai kosong jika
+// EnablePoolStats belum dipanggil.
+func (c *HttpRequest) PoolStats() PoolStats {
+	if c.pool == nil {
+		return PoolStats{}
+	}
+	return c.pool.snapshot()
+}
+
+type poolStats struct {
+	mu    sync.Mutex
+	hosts map[string]*HostPoolStats
+}
+
+// host mengembalikan statistik untuk addr. Dipanggil dengan mu terkunci.
+func (p *poolStats) host(addr string) *HostPoolStats {
+	h, ok := p.hosts[addr]
+	if !ok {
+		h = &HostPoolStats{Addr: addr}
+		p.hosts[addr] = h
+	}
+	return h
+}
+
+func (p *poolStats) update(addr string, fn func(h *HostPoolStats)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fn(p.host(addr))
+}
+
+// wrap mencatat hasil dial dan membungkus koneksi supaya penutupannya tercatat.
+func (p *poolStats) wrap(addr string, conn net.Conn, err error) (net.Conn, error) {
+	if err != nil {
+		p.update(addr, func(h *HostPoolStats) { h.DialErrors++ })
+		return nil, err
+	}
+	p.update(addr, func(h *HostPoolStats) {
+		h.Opened++
+		h.Open++
+	})
+	return &poolConn{Conn: conn, addr: addr, pool: p}, nil
+}
+
+// trace memasang ClientTrace yang mencatat koneksi yang dipakai req. Fungsi
+// done wajib dipanggil setelah request selesai.
+func (p *poolStats) trace(req *http.Request) (*http.Request, func()) {
+	var mu sync.Mutex
+	var addr string
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			conn := unwrapPoolConn(info.Conn)
+			if conn == nil {
+				return
+			}
+			_, isTLS := info.Conn.(*tls.Conn)
+			p.update(conn.addr, func(h *HostPoolStats) {
+				h.InUse++
+				if info.Reused {
+					h.Reused++
+				} else if isTLS {
+					h.TLSHandshakes++
+				}
+			})
+			mu.Lock()
+			addr = conn.addr
+			mu.Unlock()
+		},
+	}
+	done := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if addr != "" {
+			p.update(addr, func(h *HostPoolStats) { h.InUse-- })
+			addr = ""
+		}
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace)), done
+}
+
+func (p *poolStats) snapshot() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var stats PoolStats
+	for _, h := range p.hosts {
+		host := *h
+		host.Idle = max(host.Open-host.InUse, 0)
+		stats.Hosts = append(stats.Hosts, host)
+
+		stats.Total.Open += host.Open
+		stats.Total.InUse += host.InUse
+		stats.Total.Idle += host.Idle
+		stats.Total.Opened += host.Opened
+		stats.Total.Closed += host.Closed
+		stats.Total.DialErrors += host.DialErrors
+		stats.Total.Reused += host.Reused
+		stats.Total.TLSHandshakes += host.TLSHandshakes
+	}
+	sort.Slice(stats.Hosts, func(i, j int) bool { return stats.Hosts[i].Addr < stats.Hosts[j].Addr })
+	return stats
+}
+
+// poolConn mencatat penutupan koneksi ke poolStats.
+type poolConn struct {
+	net.Conn
+	addr string
+	pool *poolStats
+	once sync.Once
+}
+
+func (c *poolConn) Close() error {
+	c.once.Do(func() {
+		c.pool.update(c.addr, func(h *HostPoolStats) {
+			h.Open--
+			h.Closed++
+		})
+	})
+	return c.Conn.Close()
+}
+
+// unwrapPoolConn mencari poolConn di balik koneksi, mis. di dalam *tls.Conn.
+func unwrapPoolConn(conn net.Conn) *poolConn {
+	for conn != nil {
+		switch v := conn.(type) {
+		case *poolConn:
+			return v
+		case interface{ NetConn() net.Conn }:
+			conn = v.NetConn()
+		default:
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPoolStatsCountsReuseAndHandshakes(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.Client.Transport = ts.Client().Transport
+	if err := client.EnablePoolStats(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	stats := client.PoolStats()
+	if len(stats.Hosts) != 1 || stats.Hosts[0].Addr != strings.TrimPrefix(ts.URL, "https://") {
+		t.Fatalf("unexpected hosts: %+v", stats.Hosts)
+	}
+	h := stats.Hosts[0]
+	if h.Opened != 1 || h.Open != 1 || h.Reused != 2 || h.TLSHandshakes != 1 || h.InUse != 0 || h.Idle != 1 {
+		t.Errorf("unexpected pool stats: %+v", h)
+	}
+
+	client.Client.CloseIdleConnections()
+	if h := client.PoolStats().Total; h.Open != 0 || h.Closed != 1 {
+		t.Errorf("expected idle connection closed, got %+v", h)
+	}
+
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: "http://127.0.0.1:1"}); err == nil {
+		t.Fatalf("expected dial error")
+	}
+	if stats := client.PoolStats(); stats.Total.DialErrors != 1 {
+		t.Errorf("expected dial error counted, got %+v", stats.Total)
+	}
+}
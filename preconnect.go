@@ -0,0 +1,180 @@
+package http_request_instant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Preconnect membuka koneksi ke setiap host lebih awal, mis. saat startup
+// setelah deploy, supaya request pertama pengguna tidak menanggung latency
+// DNS dan TCP connect. host berupa "api.example.com" (https) atau URL dengan
+// skema seperti "http://10.0.0.5:8080". Aturan tujuan OutboundPolicy
+// (AllowedHosts, ForbidPlaintext) dan proteksi SSRF diperiksa seperti request
+// biasa, lalu koneksi di-dial lewat dialer client (termasuk proxy dan proxy
+// chain) tanpa mengirim request apa pun. Transport memakai koneksi tersebut
+// untuk dial berikutnya ke alamat yang sama; TLS handshake terjadi saat itu.
+// Koneksi yang tidak terpakai ditutup setelah IdleConnTimeout transport.
+// Semua host dihubungi paralel; error digabung.
+func (c *HttpRequest) Preconnect(ctx context.Context, hosts ...string) error {
+	errs := make([]error, len(hosts))
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = c.preconnect(ctx, host)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+func (c *HttpRequest) preconnect(ctx context.Context, host string) error {
+	target := host
+	if !strings.Contains(target, "://") {
+		target = "https://" + target
+	}
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("error preconnect %s: invalid host", host)
+	}
+
+	// Request hanya dipakai untuk pemeriksaan policy, SSRF, dan pemilihan
+	// proxy; tidak pernah dikirim
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u.Scheme+"://"+u.Host+"/", nil)
+	if err != nil {
+		return fmt.Errorf("error preconnect %s: %w", host, err)
+	}
+	if err := c.enforceDestinationPolicy(req); err != nil {
+		return fmt.Errorf("error preconnect %s: %w", host, err)
+	}
+	if err := c.checkSSRF(req); err != nil {
+		return fmt.Errorf("error preconnect %s: %w", host, err)
+	}
+	t, err := c.transport()
+	if err != nil {
+		return fmt.Errorf("error preconnect %s: %w", host, err)
+	}
+	t.DialContext = c.dialContext
+	if c.unixSocket != "" {
+		// Semua koneksi ke socket yang sama; tidak ada handshake jaringan
+		return nil
+	}
+
+	addr := canonicalAddr(u)
+	if t.Proxy != nil {
+		proxy, err := t.Proxy(req)
+		if err != nil {
+			return fmt.Errorf("error preconnect %s: %w", host, err)
+		}
+		if proxy != nil {
+			addr = proxyAddr(proxy)
+		}
+	}
+	conn, err := c.dialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("error preconnect %s: %w", host, wrapTransportError(err))
+	}
+	ttl := t.IdleConnTimeout
+	if ttl <= 0 {
+		ttl = 90 * time.Second
+	}
+	c.warm.put(addr, conn, ttl)
+	return nil
+}
+
+// proxyAddr mengembalikan alamat yang di-dial transport untuk proxy.
+func proxyAddr(u *url.URL) string {
+	if u.Port() == "" && strings.HasPrefix(u.Scheme, "socks5") {
+		return net.JoinHostPort(u.Hostname(), "1080")
+	}
+	return canonicalAddr(u)
+}
+
+// warmConns menyimpan koneksi dari Preconnect sampai transport men-dial
+// alamat yang sama.
+type warmConns struct {
+	mu    sync.Mutex
+	conns map[string][]net.Conn
+}
+
+// put menyimpan conn untuk addr; conn ditutup jika belum dipakai setelah ttl.
+func (w *warmConns) put(addr string, conn net.Conn, ttl time.Duration) {
+	w.mu.Lock()
+	if w.conns == nil {
+		w.conns = make(map[string][]net.Conn)
+	}
+	w.conns[addr] = append(w.conns[addr], conn)
+	w.mu.Unlock()
+
+	time.AfterFunc(ttl, func() {
+		if w.remove(addr, conn) {
+			conn.Close()
+		}
+	})
+}
+
+// take mengambil koneksi yang masih hidup untuk addr, atau nil.
+func (w *warmConns) take(addr string) net.Conn {
+	for {
+		w.mu.Lock()
+		conns := w.conns[addr]
+		if len(conns) == 0 {
+			w.mu.Unlock()
+			return nil
+		}
+		conn := conns[0]
+		if len(conns) == 1 {
+			delete(w.conns, addr)
+		} else {
+			w.conns[addr] = conns[1:]
+		}
+		w.mu.Unlock()
+
+		if connAlive(conn) {
+			return conn
+		}
+		conn.Close()
+	}
+}
+
+func (w *warmConns) remove(addr string, conn net.Conn) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	conns := w.conns[addr]
+	for i, c := range conns {
+		if c == conn {
+			conns = append(conns[:i:i], conns[i+1:]...)
+			if len(conns) == 0 {
+				delete(w.conns, addr)
+			} else {
+				w.conns[addr] = conns
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// connAlive memeriksa bahwa koneksi yang menganggur belum ditutup server.
+// Server tidak boleh mengirim apa pun sebelum request, jadi read yang tidak
+// timeout berarti koneksi sudah tidak bisa dipakai.
+func connAlive(conn net.Conn) bool {
+	if err := conn.SetReadDeadline(time.Now()); err != nil {
+		return false
+	}
+	var buf [1]byte
+	_, err := conn.Read(buf[:])
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		return false
+	}
+	return conn.SetReadDeadline(time.Time{}) == nil
+}
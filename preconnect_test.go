@@ -0,0 +1,85 @@
+package http_request_instant
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestPreconnect(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer plain.Close()
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+
+	client := NewHttpRequest()
+	client.SetRootCAs(pool)
+	client.EnablePoolStats()
+	if err := client.Preconnect(context.TODO(), strings.TrimPrefix(ts.URL, "https://"), plain.URL); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stats := client.PoolStats()
+	if stats.Total.Opened != 2 || stats.Total.Idle != 2 {
+		t.Fatalf("expected 2 idle connections, got %+v", stats.Total)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Fatalf("expected preconnect to send no request, server got %d", n)
+	}
+
+	for _, url := range []string{ts.URL, plain.URL} {
+		if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: url}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if stats := client.PoolStats(); stats.Total.Opened != 2 || stats.Total.DialErrors != 0 {
+		t.Errorf("expected requests to use preconnected connections, got %+v", stats.Total)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("expected 2 requests, server got %d", n)
+	}
+
+	if err := client.Preconnect(context.TODO(), "127.0.0.1:1", "http://"); err == nil || !strings.Contains(err.Error(), "invalid host") {
+		t.Errorf("expected joined errors, got %v", err)
+	}
+}
+
+func TestPreconnectChecksPolicyAndSSRF(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.EnablePoolStats()
+	client.SetOutboundPolicy(OutboundPolicy{AllowedHosts: []string{"api.example.com"}, RequiredHeaders: []string{"X-Tenant-Id"}})
+	var violation *PolicyViolation
+	if err := client.Preconnect(context.TODO(), ts.URL); !errors.As(err, &violation) || violation.Rule != PolicyRuleHost {
+		t.Errorf("expected host policy violation, got %v", err)
+	}
+
+	client.SetOutboundPolicy(OutboundPolicy{ForbidPlaintext: true})
+	if err := client.Preconnect(context.TODO(), ts.URL); !errors.As(err, &violation) || violation.Rule != PolicyRulePlaintext {
+		t.Errorf("expected plaintext policy violation, got %v", err)
+	}
+
+	client.SetOutboundPolicy(OutboundPolicy{RequiredHeaders: []string{"X-Tenant-Id"}})
+	client.EnableSSRFProtection(SSRFOptions{})
+	var ssrfErr *SSRFError
+	if err := client.Preconnect(context.TODO(), ts.URL); !errors.As(err, &ssrfErr) {
+		t.Errorf("expected *SSRFError, got %v", err)
+	}
+	if stats := client.PoolStats(); stats.Total.Opened != 0 {
+		t.Errorf("expected no connection to be dialed, got %+v", stats.Total)
+	}
+}
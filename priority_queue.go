@@ -0,0 +1,173 @@
+package http_request_instant
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// Priority menentukan kelas prioritas request di dalam antrian client.
+type Priority int
+
+const (
+	// PriorityInteractive untuk request yang ditunggu user (default).
+	PriorityInteractive Priority = iota
+	// PriorityBatch untuk traffic background seperti sync atau export.
+	PriorityBatch
+
+	priorityCount
+)
+
+// String mengembalikan nama kelas prioritas.
+func (p Priority) String() string {
+	switch p {
+	case PriorityInteractive:
+		return "interactive"
+	case PriorityBatch:
+		return "batch"
+	default:
+		return "unknown"
+	}
+}
+
+// QueueClassStats menyimpan statistik antrian untuk satu kelas prioritas.
+type QueueClassStats struct {
+	Priority  Priority
+	Waiting   int           // Jumlah request yang sedang menunggu slot
+	Served    int64         // Jumlah request yang sudah mendapat slot
+	TotalWait time.Duration // Akumulasi waktu tunggu
+	MaxWait   time.Duration // Waktu tunggu terlama
+}
+
+// AverageWait mengembalikan rata-rata waktu tunggu.
+func (s QueueClassStats) AverageWait() time.Duration {
+	if s.Served == 0 {
+		return 0
+	}
+	return s.TotalWait / time.Duration(s.Served)
+}
+
+// QueueStats menyimpan snapshot kondisi antrian request.
+type QueueStats struct {
+	MaxConcurrent int
+	InFlight      int
+	Classes       []QueueClassStats
+}
+
+// SetRequestQueue mengaktifkan antrian internal dengan maksimal maxConcurrent
+// request berjalan bersamaan. Slot yang kosong selalu diberikan ke kelas
+// prioritas tertinggi lebih dulu, sehingga traffic batch tidak bisa membuat
+// request interactive kelaparan. maxConcurrent <= 0 menonaktifkan antrian.
+func (c *HttpRequest) SetRequestQueue(maxConcurrent int) {
+	if maxConcurrent <= 0 {
+		c.queue = nil
+		return
+	}
+	c.queue = newRequestQueue(maxConcurrent)
+}
+
+// QueueStats mengembalikan statistik antrian. Nilai kosong jika antrian tidak aktif.
+func (c *HttpRequest) QueueStats() QueueStats {
+	if c.queue == nil {
+		return QueueStats{}
+	}
+	return c.queue.stats()
+}
+
+type requestQueue struct {
+	mu       sync.Mutex
+	max      int
+	inFlight int
+	waiters  [priorityCount]*list.List
+	classes  [priorityCount]QueueClassStats
+}
+
+func newRequestQueue(max int) *requestQueue {
+	q := &requestQueue{max: max}
+	for i := range q.waiters {
+		q.waiters[i] = list.New()
+		q.classes[i].Priority = Priority(i)
+	}
+	return q
+}
+
+// acquire menunggu slot sesuai prioritas. Fungsi release wajib dipanggil setelah request selesai.
+func (q *requestQueue) acquire(ctx context.Context, priority Priority) (func(), error) {
+	if priority < 0 || priority >= priorityCount {
+		priority = PriorityBatch
+	}
+
+	start := time.Now()
+	q.mu.Lock()
+	if q.inFlight < q.max {
+		q.inFlight++
+		q.record(priority, 0)
+		q.mu.Unlock()
+		return q.release, nil
+	}
+
+	ready := make(chan struct{})
+	elem := q.waiters[priority].PushBack(ready)
+	q.mu.Unlock()
+
+	select {
+	case <-ready:
+		q.mu.Lock()
+		q.record(priority, time.Since(start))
+		q.mu.Unlock()
+		return q.release, nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		select {
+		case <-ready:
+			// slot sudah terlanjur diberikan, kembalikan lagi
+			q.mu.Unlock()
+			q.release()
+		default:
+			q.waiters[priority].Remove(elem)
+			q.mu.Unlock()
+		}
+		return nil, ctx.Err()
+	}
+}
+
+func (q *requestQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	// serahkan slot langsung ke waiter dengan prioritas tertinggi
+	for _, waiters := range q.waiters {
+		if front := waiters.Front(); front != nil {
+			waiters.Remove(front)
+			close(front.Value.(chan struct{}))
+			return
+		}
+	}
+	q.inFlight--
+}
+
+func (q *requestQueue) record(priority Priority, wait time.Duration) {
+	class := &q.classes[priority]
+	class.Served++
+	class.TotalWait += wait
+	if wait > class.MaxWait {
+		class.MaxWait = wait
+	}
+}
+
+func (q *requestQueue) stats() QueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stats := QueueStats{
+		MaxConcurrent: q.max,
+		InFlight:      q.inFlight,
+		Classes:       make([]QueueClassStats, 0, len(q.classes)),
+	}
+	for i, class := range q.classes {
+		class.Waiting = q.waiters[i].Len()
+		stats.Classes = append(stats.Classes, class)
+	}
+	return stats
+}
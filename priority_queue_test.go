@@ -0,0 +1,97 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPriorityQueueServesInteractiveFirst(t *testing.T) {
+	unblock := make(chan struct{})
+	var mu sync.Mutex
+	var order []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("name")
+		if name == "first" {
+			<-unblock
+		}
+		mu.Lock()
+		order = append(order, name)
+		mu.Unlock()
+		w.WriteHeader(200)
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.SetRequestQueue(1)
+
+	var wg sync.WaitGroup
+	send := func(name string, priority Priority) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.Request(context.Background(), RequestOptions{
+				Method:   "GET",
+				URL:      ts.URL + "?name=" + name,
+				Priority: priority,
+			})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	waitFor := func(cond func(QueueStats) bool) {
+		deadline := time.Now().Add(2 * time.Second)
+		for !cond(client.QueueStats()) {
+			if time.Now().After(deadline) {
+				t.Fatalf("timeout waiting for queue state: %+v", client.QueueStats())
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	send("first", PriorityBatch)
+	waitFor(func(s QueueStats) bool { return s.InFlight == 1 })
+	send("batch", PriorityBatch)
+	waitFor(func(s QueueStats) bool { return s.Classes[PriorityBatch].Waiting == 1 })
+	send("interactive", PriorityInteractive)
+	waitFor(func(s QueueStats) bool { return s.Classes[PriorityInteractive].Waiting == 1 })
+
+	close(unblock)
+	wg.Wait()
+
+	if len(order) != 3 || order[1] != "interactive" || order[2] != "batch" {
+		t.Errorf("unexpected serve order: %v", order)
+	}
+
+	stats := client.QueueStats()
+	if stats.InFlight != 0 || stats.Classes[PriorityBatch].Served != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if stats.Classes[PriorityInteractive].AverageWait() <= 0 {
+		t.Errorf("expected interactive wait time to be recorded, got %+v", stats.Classes[PriorityInteractive])
+	}
+}
+
+func TestPriorityQueueContextCancel(t *testing.T) {
+	q := newRequestQueue(1)
+	release, err := q.acquire(context.Background(), PriorityInteractive)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := q.acquire(ctx, PriorityBatch); err == nil {
+		t.Fatal("expected context error, got nil")
+	}
+
+	release()
+	if stats := q.stats(); stats.InFlight != 0 || stats.Classes[PriorityBatch].Waiting != 0 {
+		t.Errorf("unexpected stats after cancel: %+v", stats)
+	}
+}
@@ -0,0 +1,135 @@
+package http_request_instant
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// TraceContext adalah identitas trace yang dipropagasikan ke server tujuan.
+type TraceContext struct {
+	TraceID    [16]byte
+	SpanID     [8]byte
+	Sampled    bool
+	TraceState string // Optional: header tracestate W3C apa adanya
+}
+
+// IsValid bernilai true jika TraceID dan SpanID tidak nol.
+func (tc TraceContext) IsValid() bool {
+	return tc.TraceID != [16]byte{} && tc.SpanID != [8]byte{}
+}
+
+// Traceparent mengembalikan nilai header traceparent W3C.
+func (tc TraceContext) Traceparent() string {
+	flags := "00"
+	if tc.Sampled {
+		flags = "01"
+	}
+	return "00-" + hex.EncodeToString(tc.TraceID[:]) + "-" + hex.EncodeToString(tc.SpanID[:]) + "-" + flags
+}
+
+// ParseTraceparent mem-parsing header traceparent W3C, mis. dari request
+// masuk supaya bisa diteruskan lewat ContextWithTraceContext.
+func ParseTraceparent(value string) (TraceContext, error) {
+	var tc TraceContext
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" ||
+		len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return tc, errors.New("invalid traceparent")
+	}
+	if _, err := hex.Decode(tc.TraceID[:], []byte(parts[1])); err != nil {
+		return tc, errors.New("invalid traceparent trace-id")
+	}
+	if _, err := hex.Decode(tc.SpanID[:], []byte(parts[2])); err != nil {
+		return tc, errors.New("invalid traceparent parent-id")
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return tc, errors.New("invalid traceparent flags")
+	}
+	tc.Sampled = flags[0]&1 == 1
+	if !tc.IsValid() {
+		return tc, errors.New("invalid traceparent: zero trace-id or parent-id")
+	}
+	return tc, nil
+}
+
+type traceContextKey struct{}
+
+// ContextWithTraceContext menyimpan TraceContext di ctx supaya header trace
+// dipasang ke request yang memakai ctx tersebut.
+func ContextWithTraceContext(ctx context.Context, tc TraceContext) context.Context {
+	return context.WithValue(ctx, traceContextKey{}, tc)
+}
+
+// TraceContextFromContext mengambil TraceContext dari ctx.
+func TraceContextFromContext(ctx context.Context) (TraceContext, bool) {
+	tc, ok := ctx.Value(traceContextKey{}).(TraceContext)
+	return tc, ok && tc.IsValid()
+}
+
+// TraceContextSpan opsional diimplementasikan Span supaya span per percobaan
+// menjadi parent di server tujuan.
+type TraceContextSpan interface {
+	TraceContext() TraceContext
+}
+
+// TracePropagation menentukan format header trace yang dipasang ke request.
+type TracePropagation int
+
+const (
+	// PropagateW3C memasang traceparent dan tracestate (default).
+	PropagateW3C TracePropagation = 1 << iota
+	// PropagateB3 memasang header B3 multi (X-B3-TraceId, X-B3-SpanId, X-B3-Sampled).
+	PropagateB3
+	// PropagateB3Single memasang header B3 single ("b3").
+	PropagateB3Single
+	// PropagateNone mematikan propagasi header trace.
+	PropagateNone
+)
+
+// injectTraceHeaders memasang header trace dari ctx. Header yang sudah
+// diisi pemanggil tidak ditimpa.
+func (c *HttpRequest) injectTraceHeaders(ctx context.Context, header http.Header) {
+	mode := c.TracePropagation
+	if mode&PropagateNone != 0 {
+		return
+	}
+	if mode == 0 {
+		mode = PropagateW3C
+	}
+	tc, ok := TraceContextFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	setIfEmpty := func(name, value string) {
+		if header.Get(name) == "" {
+			header.Set(name, value)
+		}
+	}
+
+	traceID := hex.EncodeToString(tc.TraceID[:])
+	spanID := hex.EncodeToString(tc.SpanID[:])
+	sampled := "0"
+	if tc.Sampled {
+		sampled = "1"
+	}
+
+	if mode&PropagateW3C != 0 {
+		setIfEmpty("Traceparent", tc.Traceparent())
+		if tc.TraceState != "" {
+			setIfEmpty("Tracestate", tc.TraceState)
+		}
+	}
+	if mode&PropagateB3 != 0 {
+		setIfEmpty("X-B3-Traceid", traceID)
+		setIfEmpty("X-B3-Spanid", spanID)
+		setIfEmpty("X-B3-Sampled", sampled)
+	}
+	if mode&PropagateB3Single != 0 {
+		setIfEmpty("B3", traceID+"-"+spanID+"-"+sampled)
+	}
+}
@@ -0,0 +1,111 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTraceEchoServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, name := range []string{"Traceparent", "Tracestate", "X-B3-Traceid", "X-B3-Spanid", "X-B3-Sampled", "B3"} {
+			if v := r.Header.Get(name); v != "" {
+				w.Header().Set("Echo-"+name, v)
+			}
+		}
+	}))
+}
+
+func TestTraceparentFromContext(t *testing.T) {
+	ts := newTraceEchoServer()
+	defer ts.Close()
+
+	tc, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tc.TraceState = "vendor=abc"
+	ctx := ContextWithTraceContext(context.TODO(), tc)
+
+	client := NewHttpRequest()
+	resp, err := client.Request(ctx, RequestOptions{Method: "GET", URL: ts.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Headers["Echo-Traceparent"] != "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" {
+		t.Errorf("unexpected traceparent: %q", resp.Headers["Echo-Traceparent"])
+	}
+	if resp.Headers["Echo-Tracestate"] != "vendor=abc" {
+		t.Errorf("unexpected tracestate: %q", resp.Headers["Echo-Tracestate"])
+	}
+	if resp.Headers["Echo-X-B3-Traceid"] != "" {
+		t.Errorf("B3 should be off by default")
+	}
+}
+
+func TestB3Propagation(t *testing.T) {
+	ts := newTraceEchoServer()
+	defer ts.Close()
+
+	tc, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	ctx := ContextWithTraceContext(context.TODO(), tc)
+
+	client := NewHttpRequest()
+	client.TracePropagation = PropagateB3 | PropagateB3Single
+	resp, err := client.Request(ctx, RequestOptions{Method: "GET", URL: ts.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Headers["Echo-Traceparent"] != "" {
+		t.Errorf("W3C should be off when only B3 is selected")
+	}
+	if resp.Headers["Echo-X-B3-Traceid"] != "4bf92f3577b34da6a3ce929d0e0e4736" || resp.Headers["Echo-X-B3-Sampled"] != "0" {
+		t.Errorf("unexpected B3 headers: %v", resp.Headers)
+	}
+	if resp.Headers["Echo-B3"] != "4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-0" {
+		t.Errorf("unexpected b3 single header: %q", resp.Headers["Echo-B3"])
+	}
+}
+
+// traceSpan adalah span yang membawa TraceContext sendiri.
+type traceSpan struct {
+	recordSpan
+	tc TraceContext
+}
+
+func (s *traceSpan) TraceContext() TraceContext { return s.tc }
+
+type traceTracer struct{}
+
+func (traceTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	parent, _ := TraceContextFromContext(ctx)
+	span := &traceSpan{recordSpan: recordSpan{attrs: map[string]any{}}, tc: parent}
+	span.tc.SpanID = [8]byte{1, 2, 3, 4, 5, 6, 7, 8}
+	return ctx, span
+}
+
+func TestTracerSpanBecomesParent(t *testing.T) {
+	ts := newTraceEchoServer()
+	defer ts.Close()
+
+	tc, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	client := NewHttpRequest()
+	client.Tracer = traceTracer{}
+
+	resp, err := client.Request(ContextWithTraceContext(context.TODO(), tc), RequestOptions{Method: "GET", URL: ts.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Headers["Echo-Traceparent"] != "00-4bf92f3577b34da6a3ce929d0e0e4736-0102030405060708-01" {
+		t.Errorf("expected attempt span as parent, got %q", resp.Headers["Echo-Traceparent"])
+	}
+}
+
+func TestParseTraceparentInvalid(t *testing.T) {
+	for _, value := range []string{"", "00-abc-def-01", "00-00000000000000000000000000000000-00f067aa0ba902b7-01"} {
+		if _, err := ParseTraceparent(value); err == nil {
+			t.Errorf("expected error for %q", value)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package http_request_instant
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ProxyDirect dipakai di RequestOptions.Proxy untuk melewati proxy client.
+const ProxyDirect = "direct"
+
+type proxyContextKey struct{}
+
+// SetProxy memakai satu proxy untuk semua request. proxyURL kosong
+// mengembalikan perilaku default (dari environment).
+func (c *HttpRequest) SetProxy(proxyURL string) error {
+	if proxyURL == "" {
+		return c.SetProxySelector(nil)
+	}
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return fmt.Errorf("error parse proxy URL: %w", err)
+	}
+	return c.SetProxySelector(http.ProxyURL(u))
+}
+
+// SetProxyFromEnvironment memakai HTTP_PROXY, HTTPS_PROXY, dan NO_PROXY.
+func (c *HttpRequest) SetProxyFromEnvironment() error {
+	return c.SetProxySelector(http.ProxyFromEnvironment)
+}
+
+// SetProxySelector memilih proxy per request, mis. berdasarkan host tujuan.
+// Selector boleh mengembalikan nil untuk koneksi langsung. Selector nil
+// mengembalikan perilaku default (dari environment).
+func (c *HttpRequest) SetProxySelector(selector func(req *http.Request) (*url.URL, error)) error {
+	t, err := c.transport()
+	if err != nil {
+		return err
+	}
+	c.proxy = selector
+	t.Proxy = c.proxyFunc
+	return nil
+}
+
+// proxyFunc dipasang sebagai Transport.Proxy. Kredensial Basic dari
+// ProxyAuth dipasang di salinan URL proxy; req tidak diubah.
+func (c *HttpRequest) proxyFunc(req *http.Request) (*url.URL, error) {
+	u, err := c.selectProxy(req)
+	if err != nil || u == nil || c.proxyAuth == nil {
+		return u, err
+	}
+	return c.proxyAuth.apply(u), nil
+}
+
+// selectProxy memilih proxy untuk req. Urutan prioritas: proxy per request
+// (RequestOptions.Proxy), selector client, lalu environment. Koneksi ke Unix
+// socket dan host yang memakai SetProxyChain tidak lewat proxy ini.
+func (c *HttpRequest) selectProxy(req *http.Request) (*url.URL, error) {
+	if c.unixSocketPath(req.Context()) != "" || c.proxyChainFor(req.Context(), req.URL.Hostname()) != nil {
+		return nil, nil
+	}
+	if override, ok := req.Context().Value(proxyContextKey{}).(*url.URL); ok {
+		return override, nil
+	}
+	if c.proxy != nil {
+		return c.proxy(req)
+	}
+	return http.ProxyFromEnvironment(req)
+}
+
+// setProxyBearer memasang Bearer ProxyAuth ke request HTTP biasa yang lewat
+// proxy. Dipanggil saat request dibangun, bukan dari Transport.Proxy, karena
+// RoundTripper tidak boleh mengubah request. Untuk tujuan HTTPS, Bearer
+// dikirim lewat header CONNECT supaya tidak ikut terkirim ke server tujuan.
+func (c *HttpRequest) setProxyBearer(req *http.Request) {
+	if c.proxyAuth == nil || c.proxyAuth.BearerToken == "" {
+		return
+	}
+	req.Header.Del("Proxy-Authorization")
+	if req.URL.Scheme != "http" {
+		return
+	}
+	if u, err := c.selectProxy(req); err == nil && u != nil {
+		req.Header.Set("Proxy-Authorization", "Bearer "+c.proxyAuth.BearerToken)
+	}
+}
+
+// ProxyAuth menyimpan kredensial untuk proxy (Proxy-Authorization).
+// Isi Username/Password untuk Basic, atau BearerToken untuk Bearer.
+type ProxyAuth struct {
+	Username    string
+	Password    string
+	BearerToken string
+}
+
+// SetProxyAuth memasang kredensial proxy untuk semua proxy yang dipakai client.
+// Kredensial yang sudah ada di URL proxy tetap diutamakan.
+func (c *HttpRequest) SetProxyAuth(auth *ProxyAuth) error {
+	t, err := c.transport()
+	if err != nil {
+		return err
+	}
+	c.proxyAuth = auth
+	t.Proxy = c.proxyFunc
+	t.GetProxyConnectHeader = c.proxyConnectHeader
+
+	// http.Client menyalin header ke request redirect, jadi Bearer proxy
+	// diputuskan ulang untuk tiap hop (mis. redirect ke HTTPS)
+	checkRedirect := c.Client.CheckRedirect
+	c.Client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		c.setProxyBearer(req)
+		if checkRedirect != nil {
+			return checkRedirect(req, via)
+		}
+		if len(via) >= 10 {
+			return fmt.Errorf("stopped after 10 redirects")
+		}
+		return nil
+	}
+	return nil
+}
+
+// SetProxyConnectHeaders memasang header tambahan pada request CONNECT
+// ke proxy (hanya untuk tujuan HTTPS).
+func (c *HttpRequest) SetProxyConnectHeaders(headers map[string]string) error {
+	t, err := c.transport()
+	if err != nil {
+		return err
+	}
+	c.proxyConnectHeaders = cloneHeaders(headers)
+	t.GetProxyConnectHeader = c.proxyConnectHeader
+	return nil
+}
+
+// proxyConnectHeader dipasang sebagai Transport.GetProxyConnectHeader.
+func (c *HttpRequest) proxyConnectHeader(ctx context.Context, proxyURL *url.URL, target string) (http.Header, error) {
+	header := make(http.Header)
+	for k, v := range c.proxyConnectHeaders {
+		header.Set(k, v)
+	}
+	if c.proxyAuth != nil && c.proxyAuth.BearerToken != "" {
+		header.Set("Proxy-Authorization", "Bearer "+c.proxyAuth.BearerToken)
+	}
+	return header, nil
+}
+
+// apply memasang kredensial Basic ke salinan proxy URL.
+func (a *ProxyAuth) apply(u *url.URL) *url.URL {
+	if a.Username != "" && u.User == nil {
+		withUser := *u
+		withUser.User = url.UserPassword(a.Username, a.Password)
+		u = &withUser
+	}
+	return u
+}
+
+// withRequestProxy menyimpan proxy per request di context.
+func withRequestProxy(ctx context.Context, proxy string) (context.Context, error) {
+	if proxy == "" {
+		return ctx, nil
+	}
+	if proxy == ProxyDirect {
+		return context.WithValue(ctx, proxyContextKey{}, (*url.URL)(nil)), nil
+	}
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("error parse proxy URL: %w", err)
+	}
+	return context.WithValue(ctx, proxyContextKey{}, u), nil
+}
@@ -0,0 +1,232 @@
+package http_request_instant
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ProxyHop adalah satu proxy dalam rantai SetProxyChain.
+type ProxyHop struct {
+	URL     string            // Mis. "http://proxy-a.dc:3128" atau "https://proxy-b.dc:443"; kredensial di URL dipakai sebagai Basic
+	Auth    *ProxyAuth        // Optional: kredensial khusus hop ini
+	Headers map[string]string // Optional: header tambahan pada CONNECT ke hop ini
+}
+
+type proxyHop struct {
+	url    *url.URL
+	addr   string // host:port proxy
+	header http.Header
+}
+
+type proxyChainEntry struct {
+	pattern string
+	hops    []proxyHop
+}
+
+// SetProxyChain menyambungkan request ke host yang cocok dengan pattern
+// lewat beberapa proxy berurutan: klien CONNECT ke hop pertama, lewat
+// tunnel itu CONNECT ke hop berikutnya, dan seterusnya sampai host tujuan.
+// pattern berupa nama host persis, wildcard "*.example.com", atau kosong
+// untuk semua host; pattern persis lebih diutamakan daripada wildcard.
+// Rantai menggantikan proxy biasa (SetProxy, environment), kecuali request
+// yang mengisi RequestOptions.Proxy. Tanpa hops, rantai untuk pattern dihapus.
+func (c *HttpRequest) SetProxyChain(pattern string, hops ...ProxyHop) error {
+	t, err := c.transport()
+	if err != nil {
+		return err
+	}
+	pattern = strings.ToLower(pattern)
+	c.proxyChains = deleteProxyChain(c.proxyChains, pattern)
+	if len(hops) == 0 {
+		return nil
+	}
+
+	parsed := make([]proxyHop, 0, len(hops))
+	for _, hop := range hops {
+		u, err := url.Parse(hop.URL)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("invalid proxy hop URL %q", hop.URL)
+		}
+		header := make(http.Header)
+		for k, v := range hop.Headers {
+			header.Set(k, v)
+		}
+		switch {
+		case u.User != nil:
+			password, _ := u.User.Password()
+			header.Set("Proxy-Authorization", basicAuthValue(u.User.Username(), password))
+		case hop.Auth != nil && hop.Auth.BearerToken != "":
+			header.Set("Proxy-Authorization", "Bearer "+hop.Auth.BearerToken)
+		case hop.Auth != nil && hop.Auth.Username != "":
+			header.Set("Proxy-Authorization", basicAuthValue(hop.Auth.Username, hop.Auth.Password))
+		}
+		parsed = append(parsed, proxyHop{url: u, addr: canonicalAddr(u), header: header})
+	}
+
+	c.proxyChains = append(c.proxyChains, proxyChainEntry{pattern: pattern, hops: parsed})
+	t.Proxy = c.proxyFunc
+	t.DialContext = c.dialContext
+	return nil
+}
+
+func deleteProxyChain(entries []proxyChainEntry, pattern string) []proxyChainEntry {
+	for i, entry := range entries {
+		if entry.pattern == pattern {
+			return append(entries[:i:i], entries[i+1:]...)
+		}
+	}
+	return entries
+}
+
+func basicAuthValue(username, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+}
+
+// canonicalAddr menambahkan port default skema jika URL tidak punya port.
+func canonicalAddr(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// proxyChainFor mencari rantai proxy untuk hostname. Proxy per request
+// di ctx (RequestOptions.Proxy) mengalahkan rantai.
+func (c *HttpRequest) proxyChainFor(ctx context.Context, hostname string) []proxyHop {
+	if len(c.proxyChains) == 0 {
+		return nil
+	}
+	if _, ok := ctx.Value(proxyContextKey{}).(*url.URL); ok {
+		return nil
+	}
+	hostname = strings.ToLower(hostname)
+	var wildcard, fallback []proxyHop
+	for _, entry := range c.proxyChains {
+		switch {
+		case entry.pattern == hostname:
+			return entry.hops
+		case entry.pattern == "":
+			fallback = entry.hops
+		case wildcard == nil && strings.HasPrefix(entry.pattern, "*") && matchHostPattern(entry.pattern, hostname):
+			wildcard = entry.hops
+		}
+	}
+	if wildcard != nil {
+		return wildcard
+	}
+	return fallback
+}
+
+// dialProxyChain membuka tunnel berlapis melalui hops sampai addr.
+func (c *HttpRequest) dialProxyChain(ctx context.Context, hops []proxyHop, addr string) (net.Conn, error) {
+	if c.ssrf != nil {
+		// Yang di-dial adalah proxy, bukan tujuan; tujuan sudah dicek checkSSRF
+		ctx = ssrfDialContext(ctx, hops[0].addr)
+	}
+	conn, err := c.dialFamily(ctx, "tcp", hops[0].addr)
+	if err != nil {
+		return nil, fmt.Errorf("error dial proxy %s: %w", hops[0].url.Host, err)
+	}
+	for i, hop := range hops {
+		if hop.url.Scheme == "https" {
+			if conn, err = c.proxyTLS(ctx, conn, hop); err != nil {
+				return nil, err
+			}
+		}
+		target := addr
+		if i+1 < len(hops) {
+			target = hops[i+1].addr
+		}
+		if conn, err = connectTunnel(ctx, conn, hop, target); err != nil {
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// proxyTLS melakukan handshake TLS ke hop https memakai root CA client.
+func (c *HttpRequest) proxyTLS(ctx context.Context, conn net.Conn, hop proxyHop) (net.Conn, error) {
+	config := &tls.Config{}
+	if t, err := c.transport(); err == nil && t.TLSClientConfig != nil {
+		config = t.TLSClientConfig.Clone()
+	}
+	config.ServerName = hop.url.Hostname()
+	config.NextProtos = nil
+	tlsConn := tls.Client(conn, config)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error TLS handshake with proxy %s: %w", hop.url.Host, err)
+	}
+	return tlsConn, nil
+}
+
+// connectTunnel mengirim CONNECT target ke hop lewat conn. conn ditutup
+// jika gagal.
+func connectTunnel(ctx context.Context, conn net.Conn, hop proxyHop, target string) (net.Conn, error) {
+	// Batalkan baca/tulis yang menggantung jika ctx selesai
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Unix(1, 0)) })
+	defer stop()
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: target},
+		Host:   target,
+		Header: hop.header.Clone(),
+	}
+	br := bufio.NewReader(conn)
+	err := req.Write(conn)
+	var resp *http.Response
+	if err == nil {
+		resp, err = http.ReadResponse(br, req)
+	}
+	if err != nil {
+		conn.Close()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("error CONNECT %s via proxy %s: %w", target, hop.url.Host, err)
+	}
+	// Body tidak ditutup: untuk CONNECT yang berhasil, sisa stream adalah
+	// tunnel dan Close akan membacanya sampai EOF
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("error CONNECT %s via proxy %s: %s", target, hop.url.Host, resp.Status)
+	}
+	if !stop() {
+		// ctx selesai tepat setelah CONNECT berhasil; deadline sudah terpasang
+		conn.Close()
+		return nil, ctx.Err()
+	}
+	if br.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, r: br}, nil
+	}
+	return conn, nil
+}
+
+// bufferedConn membaca sisa byte yang sudah ter-buffer saat membaca
+// response CONNECT sebelum membaca dari koneksi.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (b *bufferedConn) Read(p []byte) (int, error) {
+	return b.r.Read(p)
+}
+
+// NetConn mengembalikan koneksi yang dibungkus, seperti tls.Conn.NetConn.
+func (b *bufferedConn) NetConn() net.Conn {
+	return b.Conn
+}
@@ -0,0 +1,110 @@
+package http_request_instant
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newChainProxy membuat proxy CONNECT yang mewajibkan Proxy-Authorization
+// tertentu dan mencatat tujuan setiap CONNECT.
+func newChainProxy(name, wantAuth string, mu *sync.Mutex, hops *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		*hops = append(*hops, name+"->"+r.Host)
+		mu.Unlock()
+		if r.Method != http.MethodConnect {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if r.Header.Get("Proxy-Authorization") != wantAuth {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		conn, buf, _ := w.(http.Hijacker).Hijack()
+		go func() {
+			_, _ = io.Copy(upstream, buf)
+			upstream.Close()
+		}()
+		_, _ = io.Copy(conn, upstream)
+		conn.Close()
+	}))
+}
+
+func TestSetProxyChain(t *testing.T) {
+	target := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("target:" + r.Header.Get("Proxy-Authorization")))
+	}))
+	defer target.Close()
+
+	var mu sync.Mutex
+	var hops []string
+	proxyA := newChainProxy("a", "Basic dXNlcjpwYXNz", &mu, &hops)
+	defer proxyA.Close()
+	proxyB := newChainProxy("b", "Bearer token-b", &mu, &hops)
+	defer proxyB.Close()
+
+	client := NewHttpRequest()
+	_ = client.AddRootCAPEM(certToPEM(target.Certificate()))
+	// Proxy biasa tidak boleh dipakai untuk host yang punya rantai
+	plain := newTestProxy("plain")
+	defer plain.Close()
+	_ = client.SetProxy(plain.URL)
+	err := client.SetProxyChain("127.0.0.1",
+		ProxyHop{URL: strings.Replace(proxyA.URL, "http://", "http://user:pass@", 1)},
+		ProxyHop{URL: proxyB.URL, Auth: &ProxyAuth{BearerToken: "token-b"}},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: target.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(resp.Body) != "target:" {
+		t.Errorf("unexpected body: %s", resp.Body)
+	}
+	want := []string{"a->" + proxyB.Listener.Addr().String(), "b->" + target.Listener.Addr().String()}
+	mu.Lock()
+	if strings.Join(hops, ",") != strings.Join(want, ",") {
+		t.Errorf("expected hops %v, got %v", want, hops)
+	}
+	mu.Unlock()
+
+	// Hop yang menolak kredensial menggagalkan request dengan nama hop-nya
+	client = NewHttpRequest()
+	_ = client.AddRootCAPEM(certToPEM(target.Certificate()))
+	_ = client.SetProxyChain("", ProxyHop{URL: proxyA.URL, Auth: &ProxyAuth{Username: "user", Password: "pass"}}, ProxyHop{URL: proxyB.URL})
+	_, err = client.Request(context.TODO(), RequestOptions{Method: "GET", URL: target.URL})
+	if err == nil || !strings.Contains(err.Error(), "407") || !strings.Contains(err.Error(), proxyB.Listener.Addr().String()) {
+		t.Errorf("expected 407 from second hop, got %v", err)
+	}
+
+	// Host lain tidak lewat rantai
+	direct := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("direct"))
+	}))
+	defer direct.Close()
+	client = NewHttpRequest()
+	_ = client.SetProxyChain("*.example.com", ProxyHop{URL: proxyA.URL})
+	resp, err = client.Request(context.TODO(), RequestOptions{Method: "GET", URL: direct.URL})
+	if err != nil || string(resp.Body) != "direct" {
+		t.Errorf("expected direct request, got %v %v", resp, err)
+	}
+
+	if err := client.SetProxyChain("", ProxyHop{URL: "socks5://proxy:1080"}); err == nil {
+		t.Errorf("expected invalid hop URL error")
+	}
+}
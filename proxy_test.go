@@ -0,0 +1,199 @@
+package http_request_instant
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// newTestProxy membuat proxy HTTP sederhana yang menjawab sendiri semua request.
+func newTestProxy(name string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(name + " " + r.URL.String()))
+	}))
+}
+
+func TestClientProxy(t *testing.T) {
+	proxy := newTestProxy("proxy")
+	defer proxy.Close()
+
+	client := NewHttpRequest()
+	if err := client.SetProxy(proxy.URL); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: "http://upstream.example/items"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(resp.Body) != "proxy http://upstream.example/items" {
+		t.Errorf("unexpected body: %s", string(resp.Body))
+	}
+}
+
+func TestProxySelectorAndPerRequestOverride(t *testing.T) {
+	proxyA := newTestProxy("A")
+	defer proxyA.Close()
+	proxyB := newTestProxy("B")
+	defer proxyB.Close()
+	direct := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("direct"))
+	}))
+	defer direct.Close()
+
+	urlA, _ := url.Parse(proxyA.URL)
+	client := NewHttpRequest()
+	err := client.SetProxySelector(func(req *http.Request) (*url.URL, error) {
+		if req.URL.Hostname() == "vendor-a.example" {
+			return urlA, nil
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		url, proxy, want string
+	}{
+		{"http://vendor-a.example/x", "", "A http://vendor-a.example/x"},
+		{"http://vendor-a.example/x", proxyB.URL, "B http://vendor-a.example/x"},
+		{direct.URL, "", "direct"},
+		{direct.URL, ProxyDirect, "direct"},
+	}
+	for _, tt := range tests {
+		resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: tt.url, Proxy: tt.proxy})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(resp.Body) != tt.want {
+			t.Errorf("url=%s proxy=%s: expected %q, got %q", tt.url, tt.proxy, tt.want, string(resp.Body))
+		}
+	}
+}
+
+func TestProxyAuthPlainHTTP(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("Proxy-Authorization")))
+	}))
+	defer proxy.Close()
+
+	tests := []struct {
+		auth ProxyAuth
+		want string
+	}{
+		{ProxyAuth{Username: "user", Password: "pass"}, "Basic dXNlcjpwYXNz"},
+		{ProxyAuth{BearerToken: "proxy-token"}, "Bearer proxy-token"},
+	}
+	for _, tt := range tests {
+		client := NewHttpRequest()
+		_ = client.SetProxy(proxy.URL)
+		if err := client.SetProxyAuth(&tt.auth); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: "http://upstream.example/"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(resp.Body) != tt.want {
+			t.Errorf("expected Proxy-Authorization=%q, got %q", tt.want, string(resp.Body))
+		}
+	}
+}
+
+func TestProxyAuthBearerOnBuiltRequest(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("target:" + r.Header.Get("Proxy-Authorization")))
+	}))
+	defer target.Close()
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Proxy-Authorization") != "Bearer proxy-token" {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		http.Redirect(w, r, target.URL, http.StatusFound)
+	}))
+	defer proxy.Close()
+	proxyURL, _ := url.Parse(proxy.URL)
+
+	client := NewHttpRequest()
+	_ = client.SetProxySelector(func(req *http.Request) (*url.URL, error) {
+		if req.URL.Hostname() == "upstream.example" {
+			return proxyURL, nil
+		}
+		return nil, nil
+	})
+	_ = client.SetProxyAuth(&ProxyAuth{BearerToken: "proxy-token"})
+
+	// Transport.Proxy tidak boleh mengubah request milik pemanggil
+	req, _ := http.NewRequest("GET", "http://upstream.example/", nil)
+	if u, err := client.proxyFunc(req); err != nil || u.String() != proxy.URL {
+		t.Fatalf("unexpected proxy %v %v", u, err)
+	}
+	if got := req.Header.Get("Proxy-Authorization"); got != "" {
+		t.Errorf("expected request header untouched, got %q", got)
+	}
+
+	// Redirect ke host tanpa proxy tidak membawa Bearer proxy
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: "http://upstream.example/"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(resp.Body) != "target:" {
+		t.Errorf("expected no Proxy-Authorization after redirect, got %q", string(resp.Body))
+	}
+}
+
+func TestProxyConnectHeaders(t *testing.T) {
+	target := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// header proxy tidak boleh bocor ke server tujuan
+		_, _ = w.Write([]byte("target:" + r.Header.Get("Proxy-Authorization")))
+	}))
+	defer target.Close()
+
+	var connectHeaders http.Header
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		connectHeaders = r.Header.Clone()
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		conn, buf, _ := w.(http.Hijacker).Hijack()
+		go func() {
+			_, _ = io.Copy(upstream, buf)
+			upstream.Close()
+		}()
+		_, _ = io.Copy(conn, upstream)
+		conn.Close()
+	}))
+	defer proxy.Close()
+
+	client := NewHttpRequest()
+	_ = client.AddRootCAPEM(certToPEM(target.Certificate()))
+	_ = client.SetProxy(proxy.URL)
+	_ = client.SetProxyAuth(&ProxyAuth{BearerToken: "proxy-token"})
+	if err := client.SetProxyConnectHeaders(map[string]string{"X-Egress-Zone": "dc1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: target.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(resp.Body) != "target:" {
+		t.Errorf("proxy credentials leaked to target: %s", string(resp.Body))
+	}
+	if connectHeaders.Get("Proxy-Authorization") != "Bearer proxy-token" || connectHeaders.Get("X-Egress-Zone") != "dc1" {
+		t.Errorf("unexpected CONNECT headers: %v", connectHeaders)
+	}
+}
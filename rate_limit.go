@@ -0,0 +1,260 @@
+package http_request_instant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRateLimitWait dikembalikan jika menunggu kuota rate limit melebihi
+// RateLimitOptions.MaxWait.
+var ErrRateLimitWait = errors.New("rate limit: required wait exceeds MaxWait")
+
+// RateLimitOptions menyimpan konfigurasi pacing berdasarkan header rate limit.
+type RateLimitOptions struct {
+	// Sisa kuota yang disisihkan; request ditahan sampai reset begitu
+	// Remaining <= Reserve. Default 0, artinya kuota dipakai habis.
+	Reserve int
+
+	// Sebar sisa kuota merata sampai waktu reset, bukan menghabiskannya
+	// secepat mungkin lalu menunggu.
+	Spread bool
+
+	// Optional: batas waktu menunggu kuota. Jika jeda yang dibutuhkan lebih
+	// lama, Request gagal dengan ErrRateLimitWait. 0 berarti hanya dibatasi ctx.
+	MaxWait time.Duration
+}
+
+// RateLimitState adalah kondisi kuota terakhir yang dilaporkan server untuk satu host.
+type RateLimitState struct {
+	Limit     int       // Kuota per window, 0 jika server tidak mengirimnya
+	Remaining int       // Sisa kuota, dikurangi lokal setiap request dikirim
+	Reset     time.Time // Waktu kuota dipulihkan
+	Updated   time.Time // Waktu header rate limit terakhir diterima
+}
+
+// SetRateLimitPacing mengaktifkan pacing per host berdasarkan header
+// X-RateLimit-*, X-Rate-Limit-*, RateLimit-*, RateLimit, dan Retry-After.
+// Request berikutnya ke host yang sama ditunda sampai kuota tersedia,
+// sehingga server tidak perlu membalas 429.
+func (c *HttpRequest) SetRateLimitPacing(options RateLimitOptions) {
+	c.rateLimit = newRateLimiter()
+	c.rateLimit.defaults = &options
+}
+
+// RateLimitState mengembalikan kondisi kuota terakhir untuk host (host[:port]).
+func (c *HttpRequest) RateLimitState(host string) (RateLimitState, bool) {
+	if c.rateLimit == nil {
+		return RateLimitState{}, false
+	}
+	c.rateLimit.mu.Lock()
+	defer c.rateLimit.mu.Unlock()
+	h, ok := c.rateLimit.hosts[host]
+	if !ok {
+		return RateLimitState{}, false
+	}
+	return h.state, true
+}
+
+// RateLimitStates mengembalikan kondisi kuota semua host yang pernah
+// mengirim header rate limit.
+func (c *HttpRequest) RateLimitStates() map[string]RateLimitState {
+	states := make(map[string]RateLimitState)
+	if c.rateLimit == nil {
+		return states
+	}
+	c.rateLimit.mu.Lock()
+	defer c.rateLimit.mu.Unlock()
+	for host, h := range c.rateLimit.hosts {
+		states[host] = h.state
+	}
+	return states
+}
+
+type rateLimitHost struct {
+	state RateLimitState
+	next  time.Time // Paling cepat request berikutnya jika Spread aktif
+}
+
+type rateLimiter struct {
+	defaults *RateLimitOptions // nil jika pacing hanya aktif lewat HostConfig.RateLimit
+
+	mu    sync.Mutex
+	hosts map[string]*rateLimitHost
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{hosts: make(map[string]*rateLimitHost)}
+}
+
+// wait menahan request ke host sampai kuota tersedia, lalu memesan satu kuota.
+// Lama menunggu dikembalikan juga saat gagal.
+func (l *rateLimiter) wait(ctx context.Context, host string, options RateLimitOptions) (time.Duration, error) {
+	var waited time.Duration
+	for {
+		l.mu.Lock()
+		delay := l.reserve(host, time.Now(), options)
+		l.mu.Unlock()
+		if delay <= 0 {
+			return waited, nil
+		}
+		if options.MaxWait > 0 && delay > options.MaxWait {
+			return waited, fmt.Errorf("%w: %s needs %s", ErrRateLimitWait, host, delay.Round(time.Millisecond))
+		}
+
+		start := time.Now()
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+			waited += time.Since(start)
+		case <-ctx.Done():
+			timer.Stop()
+			return waited + time.Since(start), ctx.Err()
+		}
+	}
+}
+
+// reserve mengembalikan jeda yang dibutuhkan, atau memesan satu kuota jika
+// request boleh langsung dikirim. Dipanggil dengan mu terkunci.
+func (l *rateLimiter) reserve(host string, now time.Time, options RateLimitOptions) time.Duration {
+	h, ok := l.hosts[host]
+	if !ok || !now.Before(h.state.Reset) {
+		// Kuota tidak diketahui atau window sudah reset; tunggu header baru
+		return 0
+	}
+
+	if h.state.Remaining <= options.Reserve {
+		return h.state.Reset.Sub(now)
+	}
+	if options.Spread && now.Before(h.next) {
+		return h.next.Sub(now)
+	}
+
+	if options.Spread {
+		available := h.state.Remaining - options.Reserve
+		h.next = now.Add(h.state.Reset.Sub(now) / time.Duration(available))
+	}
+	h.state.Remaining--
+	return 0
+}
+
+// update menyimpan kuota dari header response.
+func (l *rateLimiter) update(host string, resp *http.Response, now time.Time) {
+	state, ok := parseRateLimit(resp.StatusCode, resp.Header, now)
+	if !ok {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if h, exists := l.hosts[host]; exists {
+		h.state = state
+		return
+	}
+	l.hosts[host] = &rateLimitHost{state: state}
+}
+
+// rateLimitPrefixes adalah variasi nama header rate limit dari berbagai vendor.
+var rateLimitPrefixes = []string{"X-RateLimit-", "X-Rate-Limit-", "RateLimit-"}
+
+// parseRateLimit membaca kuota dari header. Retry-After pada 429/503
+// dianggap kuota habis sampai waktu yang diminta.
+func parseRateLimit(statusCode int, header http.Header, now time.Time) (RateLimitState, bool) {
+	state := RateLimitState{Updated: now}
+	found := false
+
+	for _, prefix := range rateLimitPrefixes {
+		remaining, err := strconv.Atoi(strings.TrimSpace(header.Get(prefix + "Remaining")))
+		if err != nil {
+			continue
+		}
+		state.Remaining = remaining
+		state.Limit, _ = strconv.Atoi(strings.TrimSpace(header.Get(prefix + "Limit")))
+		if reset, ok := parseRateLimitReset(header.Get(prefix+"Reset"), now); ok {
+			state.Reset = reset
+		} else if reset, ok := parseRateLimitReset(header.Get(prefix+"Reset-After"), now); ok {
+			state.Reset = reset
+		}
+		found = true
+		break
+	}
+
+	// Draft IETF terbaru: RateLimit: limit=100, remaining=50, reset=30
+	if !found {
+		if v := header.Get("RateLimit"); v != "" {
+			for _, param := range strings.Split(v, ",") {
+				key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+				switch strings.ToLower(key) {
+				case "limit":
+					state.Limit, _ = strconv.Atoi(value)
+				case "remaining":
+					if remaining, err := strconv.Atoi(value); err == nil {
+						state.Remaining = remaining
+						found = true
+					}
+				case "reset":
+					state.Reset, _ = parseRateLimitReset(value, now)
+				}
+			}
+		}
+	}
+
+	if statusCode == http.StatusTooManyRequests || statusCode == http.StatusServiceUnavailable {
+		if retryAfter, ok := parseRetryAfter(header.Get("Retry-After"), now); ok {
+			state.Remaining = 0
+			if retryAfter.After(state.Reset) {
+				state.Reset = retryAfter
+			}
+			found = true
+		}
+	}
+
+	if !found {
+		return RateLimitState{}, false
+	}
+	if state.Reset.IsZero() && state.Remaining <= 0 {
+		// Tanpa waktu reset, tahan sebentar daripada mengirim ke kuota kosong
+		state.Reset = now.Add(time.Second)
+	}
+	return state, true
+}
+
+// parseRateLimitReset menerima detik relatif (boleh pecahan), epoch detik,
+// atau epoch milidetik.
+func parseRateLimitReset(v string, now time.Time) (time.Time, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return time.Time{}, false
+	}
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil || n < 0 {
+		return time.Time{}, false
+	}
+	switch {
+	case n > 1e12:
+		return time.UnixMilli(int64(n)), true
+	case n > 1e9:
+		return time.Unix(int64(n), 0), true
+	default:
+		return now.Add(time.Duration(n * float64(time.Second))), true
+	}
+}
+
+// parseRetryAfter menerima detik relatif atau HTTP-date.
+func parseRetryAfter(v string, now time.Time) (time.Time, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return time.Time{}, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return now.Add(time.Duration(seconds) * time.Second), true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,100 @@
+package http_request_instant
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimitPacingWaitsForReset(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "10")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "0.2")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.SetRateLimitPacing(RateLimitOptions{})
+	options := RequestOptions{Method: "GET", URL: ts.URL}
+
+	if _, err := client.Request(context.TODO(), options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	host := strings.TrimPrefix(ts.URL, "http://")
+	state, ok := client.RateLimitState(host)
+	if !ok || state.Limit != 10 || state.Remaining != 0 || state.Reset.IsZero() {
+		t.Fatalf("unexpected state: %+v (ok %v)", state, ok)
+	}
+
+	start := time.Now()
+	if _, err := client.Request(context.TODO(), options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("expected request to wait for reset, took %s", elapsed)
+	}
+
+	client.SetRateLimitPacing(RateLimitOptions{MaxWait: 10 * time.Millisecond})
+	if _, err := client.Request(context.TODO(), options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Request(context.TODO(), options); !errors.Is(err, ErrRateLimitWait) {
+		t.Errorf("expected ErrRateLimitWait, got %v", err)
+	}
+}
+
+func TestParseRateLimitVariants(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		name      string
+		status    int
+		header    http.Header
+		remaining int
+		reset     time.Time
+	}{
+		{
+			name:      "github epoch reset",
+			status:    200,
+			header:    http.Header{"X-Ratelimit-Remaining": {"42"}, "X-Ratelimit-Reset": {"1700000060"}},
+			remaining: 42,
+			reset:     time.Unix(1700000060, 0),
+		},
+		{
+			name:      "ietf delta reset",
+			status:    200,
+			header:    http.Header{"Ratelimit-Remaining": {"5"}, "Ratelimit-Reset": {"30"}},
+			remaining: 5,
+			reset:     now.Add(30 * time.Second),
+		},
+		{
+			name:      "ietf structured",
+			status:    200,
+			header:    http.Header{"Ratelimit": {"limit=100, remaining=7, reset=12"}},
+			remaining: 7,
+			reset:     now.Add(12 * time.Second),
+		},
+		{
+			name:      "retry after on 429",
+			status:    429,
+			header:    http.Header{"X-Rate-Limit-Remaining": {"3"}, "Retry-After": {"9"}},
+			remaining: 0,
+			reset:     now.Add(9 * time.Second),
+		},
+	}
+	for _, tt := range tests {
+		state, ok := parseRateLimit(tt.status, tt.header, now)
+		if !ok || state.Remaining != tt.remaining || !state.Reset.Equal(tt.reset) {
+			t.Errorf("%s: unexpected state %+v (ok %v)", tt.name, state, ok)
+		}
+	}
+
+	if _, ok := parseRateLimit(200, http.Header{}, now); ok {
+		t.Errorf("expected no state without rate limit headers")
+	}
+}
@@ -0,0 +1,180 @@
+package http_request_instant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrClientClosed dikembalikan Request setelah Shutdown dipanggil.
+var ErrClientClosed = errors.New("http client is shut down")
+
+// ClientDefaults adalah default per nama client di Registry.
+type ClientDefaults struct {
+	BaseURL string            // URL relatif di RequestOptions.URL digabung dengan BaseURL
+	Headers map[string]string // Header default; RequestOptions.Headers yang menang
+	Timeout time.Duration     // Client.Timeout, 0 berarti tidak diubah
+}
+
+// Registry menyimpan client per nama upstream, mis. "github" atau
+// "payment", supaya aplikasi bisa mengelola dan mematikan semuanya dari
+// satu tempat.
+type Registry struct {
+	mu      sync.RWMutex
+	clients map[string]*HttpRequest
+}
+
+// NewRegistry membuat Registry kosong.
+func NewRegistry() *Registry {
+	return &Registry{clients: make(map[string]*HttpRequest)}
+}
+
+// DefaultRegistry dipakai fungsi Register, Get, dan ShutdownAll.
+var DefaultRegistry = NewRegistry()
+
+// Register mendaftarkan client ke DefaultRegistry.
+func Register(name string, client *HttpRequest) error {
+	return DefaultRegistry.Register(name, client)
+}
+
+// Get mengambil client dari DefaultRegistry.
+func Get(name string) (*HttpRequest, bool) {
+	return DefaultRegistry.Get(name)
+}
+
+// ShutdownAll mematikan semua client di DefaultRegistry.
+func ShutdownAll(ctx context.Context) error {
+	return DefaultRegistry.Shutdown(ctx)
+}
+
+// Register mendaftarkan client dengan nama name. Error jika nama sudah dipakai.
+func (r *Registry) Register(name string, client *HttpRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.clients[name]; exists {
+		return fmt.Errorf("client %q already registered", name)
+	}
+	r.clients[name] = client
+	return nil
+}
+
+// RegisterWithDefaults mendaftarkan client lalu memasang defaults lewat
+// middleware, sehingga pemanggil cukup menulis path relatif.
+func (r *Registry) RegisterWithDefaults(name string, client *HttpRequest, defaults ClientDefaults) error {
+	if err := r.Register(name, client); err != nil {
+		return err
+	}
+	if defaults.Timeout > 0 {
+		client.Client.Timeout = defaults.Timeout
+	}
+	if defaults.BaseURL != "" || len(defaults.Headers) > 0 {
+		client.Use(defaultsMiddleware(defaults))
+	}
+	return nil
+}
+
+// Get mengambil client berdasarkan nama.
+func (r *Registry) Get(name string) (*HttpRequest, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	client, ok := r.clients[name]
+	return client, ok
+}
+
+// MustGet seperti Get, tapi panic jika nama belum terdaftar. Cocok untuk
+// wiring saat startup.
+func (r *Registry) MustGet(name string) *HttpRequest {
+	client, ok := r.Get(name)
+	if !ok {
+		panic(fmt.Sprintf("http_request_instant: client %q not registered", name))
+	}
+	return client
+}
+
+// Names mengembalikan nama semua client terdaftar, terurut.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.clients))
+	for name := range r.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Shutdown mematikan semua client secara paralel dan menunggu request
+// yang sedang berjalan selesai atau ctx habis.
+func (r *Registry) Shutdown(ctx context.Context) error {
+	r.mu.RLock()
+	clients := make(map[string]*HttpRequest, len(r.clients))
+	for name, client := range r.clients {
+		clients[name] = client
+	}
+	r.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+	for name, client := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := client.Shutdown(ctx); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Shutdown menolak request baru dengan ErrClientClosed, menunggu request
+// yang sedang berjalan selesai, lalu menutup koneksi idle. Jika ctx habis
+// lebih dulu, ctx.Err() dikembalikan.
+func (c *HttpRequest) Shutdown(ctx context.Context) error {
+	atomic.StoreInt32(&c.closed, 1)
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for atomic.LoadInt64(&c.inFlight) > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	if c.Client != nil {
+		c.Client.CloseIdleConnections()
+	}
+	return nil
+}
+
+// defaultsMiddleware menerapkan BaseURL dan Headers dari ClientDefaults.
+func defaultsMiddleware(defaults ClientDefaults) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, options RequestOptions) (*ApiResponse, error) {
+			if defaults.BaseURL != "" {
+				if u, err := url.Parse(options.URL); err == nil && !u.IsAbs() {
+					options.URL = strings.TrimRight(defaults.BaseURL, "/") + "/" + strings.TrimLeft(options.URL, "/")
+				}
+			}
+			if len(defaults.Headers) > 0 {
+				headers := cloneHeaders(defaults.Headers)
+				for k, v := range options.Headers {
+					headers[k] = v
+				}
+				options.Headers = headers
+			}
+			return next.Do(ctx, options)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package http_request_instant
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRegistryDefaultsAndLookup(t *testing.T) {
+	var path, agent, accept string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, agent, accept = r.URL.Path, r.Header.Get("User-Agent"), r.Header.Get("Accept")
+	}))
+	defer ts.Close()
+
+	registry := NewRegistry()
+	err := registry.RegisterWithDefaults("github", NewHttpRequest(), ClientDefaults{
+		BaseURL: ts.URL + "/api/",
+		Headers: map[string]string{"User-Agent": "checkout/1.0", "Accept": "application/json"},
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := registry.Register("github", NewHttpRequest()); err == nil {
+		t.Errorf("expected duplicate name error")
+	}
+	if err := registry.Register("payment", NewHttpRequest()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client := registry.MustGet("github")
+	_, err = client.Request(context.TODO(), RequestOptions{
+		Method:  "GET",
+		URL:     "/repos/ojipoji",
+		Headers: map[string]string{"Accept": "application/vnd.github+json"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if path != "/api/repos/ojipoji" || agent != "checkout/1.0" || accept != "application/vnd.github+json" {
+		t.Errorf("unexpected request: path %q, agent %q, accept %q", path, agent, accept)
+	}
+	if client.Client.Timeout != 5*time.Second {
+		t.Errorf("expected default timeout applied, got %s", client.Client.Timeout)
+	}
+	if names := registry.Names(); len(names) != 2 || names[0] != "github" || names[1] != "payment" {
+		t.Errorf("unexpected names: %v", names)
+	}
+	if _, ok := registry.Get("missing"); ok {
+		t.Errorf("expected missing client")
+	}
+}
+
+func TestRegistryShutdownWaitsForInFlight(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}))
+	defer ts.Close()
+
+	registry := NewRegistry()
+	client := NewHttpRequest()
+	_ = registry.Register("slow", client)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+		done <- err
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if err := registry.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected shutdown to time out while request in flight, got %v", err)
+	}
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("expected ErrClientClosed after shutdown, got %v", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("in-flight request should complete, got %v", err)
+	}
+	if err := registry.Shutdown(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
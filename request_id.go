@@ -0,0 +1,128 @@
+package http_request_instant
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// RequestIDOptions menyimpan konfigurasi injeksi request ID.
+type RequestIDOptions struct {
+	Header         string        // Header request ID, default X-Request-ID
+	ResponseHeader string        // Header request ID dari server, default sama dengan Header
+	Generate       func() string // Optional: generator ID, default UUID v4
+
+	// Bungkus error dari Request menjadi *RequestIDError yang membawa
+	// request ID (dari server jika ada) supaya mudah dikorelasikan.
+	WrapErrors bool
+}
+
+// RequestIDError membungkus error dengan request ID terkait.
+type RequestIDError struct {
+	RequestID string
+	Err       error
+}
+
+func (e *RequestIDError) Error() string {
+	return fmt.Sprintf("request_id=%s: %v", e.RequestID, e.Err)
+}
+
+func (e *RequestIDError) Unwrap() error {
+	return e.Err
+}
+
+// SetRequestID mengaktifkan request ID otomatis pada setiap request. ID
+// diambil dari ctx (ContextWithRequestID) atau dibuat baru, dan dipakai
+// sama untuk semua percobaan dalam satu Request.
+func (c *HttpRequest) SetRequestID(options RequestIDOptions) {
+	if options.Header == "" {
+		options.Header = "X-Request-ID"
+	}
+	if options.ResponseHeader == "" {
+		options.ResponseHeader = options.Header
+	}
+	if options.Generate == nil {
+		options.Generate = newUUID
+	}
+	c.requestID = &options
+}
+
+type requestIDKey struct{}
+
+// ContextWithRequestID menyimpan request ID di ctx, mis. ID dari request masuk.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext mengambil request ID dari ctx.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// requestIDState menyimpan ID yang dikirim dan ID balasan server untuk satu Request.
+type requestIDState struct {
+	sent   string
+	server string
+}
+
+type requestIDStateKey struct{}
+
+// withRequestID menyiapkan request ID untuk satu panggilan Request.
+func (c *HttpRequest) withRequestID(ctx context.Context) (context.Context, *requestIDState) {
+	id, ok := RequestIDFromContext(ctx)
+	if !ok {
+		id = c.requestID.Generate()
+		ctx = ContextWithRequestID(ctx, id)
+	}
+	state := &requestIDState{sent: id}
+	return context.WithValue(ctx, requestIDStateKey{}, state), state
+}
+
+// applyRequestID memasang header request ID jika belum diisi pemanggil.
+func (c *HttpRequest) applyRequestID(ctx context.Context, req *http.Request) {
+	if c.requestID == nil || req.Header.Get(c.requestID.Header) != "" {
+		return
+	}
+	if id, ok := RequestIDFromContext(ctx); ok {
+		req.Header.Set(c.requestID.Header, id)
+	}
+}
+
+// responseRequestID mengembalikan request ID dari server, atau ID yang dikirim.
+func (c *HttpRequest) responseRequestID(ctx context.Context, req *http.Request, resp *http.Response) string {
+	if c.requestID == nil {
+		return ""
+	}
+	id := resp.Header.Get(c.requestID.ResponseHeader)
+	if id == "" {
+		id = req.Header.Get(c.requestID.Header)
+	}
+	if state, ok := ctx.Value(requestIDStateKey{}).(*requestIDState); ok {
+		state.server = resp.Header.Get(c.requestID.ResponseHeader)
+	}
+	return id
+}
+
+func (s *requestIDState) wrap(err error) error {
+	var idErr *RequestIDError
+	if errors.As(err, &idErr) {
+		return err
+	}
+	id := s.server
+	if id == "" {
+		id = s.sent
+	}
+	return &RequestIDError{RequestID: id, Err: err}
+}
+
+// newUUID membuat UUID versi 4 acak.
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
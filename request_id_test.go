@@ -0,0 +1,66 @@
+package http_request_instant
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+func TestRequestIDGenerated(t *testing.T) {
+	var seen []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Get("X-Request-ID"))
+		if len(seen) == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.SetRequestID(RequestIDOptions{})
+	client.OnUnauthorized = func(ctx context.Context, options *RequestOptions) error { return nil }
+
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(resp.RequestID) {
+		t.Errorf("expected UUID v4, got %q", resp.RequestID)
+	}
+	if len(seen) != 2 || seen[0] != resp.RequestID || seen[1] != resp.RequestID {
+		t.Errorf("expected same ID on every attempt, got %v", seen)
+	}
+}
+
+func TestRequestIDFromContextAndServerError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Correlation-Id") != "incoming-123" {
+			t.Errorf("expected ID from context, got %q", r.Header.Get("X-Correlation-Id"))
+		}
+		w.Header().Set("X-Server-Request-Id", "srv-999")
+		_, _ = w.Write([]byte("not json"))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.SetRequestID(RequestIDOptions{
+		Header:         "X-Correlation-Id",
+		ResponseHeader: "X-Server-Request-Id",
+		WrapErrors:     true,
+	})
+
+	var target map[string]any
+	ctx := ContextWithRequestID(context.TODO(), "incoming-123")
+	_, err := client.Request(ctx, RequestOptions{Method: "GET", URL: ts.URL, ResponseTarget: &target})
+
+	var idErr *RequestIDError
+	if !errors.As(err, &idErr) {
+		t.Fatalf("expected RequestIDError, got %v", err)
+	}
+	if idErr.RequestID != "srv-999" {
+		t.Errorf("expected server request ID, got %q", idErr.RequestID)
+	}
+}
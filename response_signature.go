@@ -0,0 +1,160 @@
+package http_request_instant
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+)
+
+// ErrResponseSignature adalah error dasar untuk semua kegagalan verifikasi
+// signature response, bisa dicek dengan errors.Is.
+var ErrResponseSignature = errors.New("response signature verification failed")
+
+// ResponseSignatureError dikembalikan jika signature response tidak ada atau tidak cocok.
+type ResponseSignatureError struct {
+	StatusCode int    // Status code response yang ditolak
+	Header     string // Header signature yang diperiksa
+	Reason     string // Penyebab, mis. "missing signature" atau "signature mismatch"
+}
+
+func (e *ResponseSignatureError) Error() string {
+	return fmt.Sprintf("%s: %s (header %s, status %d)", ErrResponseSignature, e.Reason, e.Header, e.StatusCode)
+}
+
+// Is membuat errors.Is(err, ErrResponseSignature) bernilai true.
+func (e *ResponseSignatureError) Is(target error) bool {
+	return target == ErrResponseSignature
+}
+
+// ResponseVerifier memverifikasi keaslian response sebelum body diproses.
+type ResponseVerifier interface {
+	VerifyResponse(ctx context.Context, resp *http.Response, body []byte) error
+}
+
+// HMACResponseVerifier memverifikasi signature HMAC atas body response.
+type HMACResponseVerifier struct {
+	Key  []byte           // Secret key
+	Hash func() hash.Hash // Algoritma digest, default sha256.New
+
+	SignatureHeader string // Nama header signature, default X-Signature
+	SignaturePrefix string // Optional: prefix nilai signature, mis. "sha256="
+
+	// Optional: decoding signature, default hex.
+	Decode func(signature string) ([]byte, error)
+	// Optional: data yang ditandatangani, default body response.
+	SignedData func(resp *http.Response, body []byte) []byte
+}
+
+// VerifyResponse mengimplementasikan ResponseVerifier.
+func (v *HMACResponseVerifier) VerifyResponse(ctx context.Context, resp *http.Response, body []byte) error {
+	header := v.SignatureHeader
+	if header == "" {
+		header = "X-Signature"
+	}
+	signature, err := responseSignature(resp, header, v.SignaturePrefix, v.Decode)
+	if err != nil {
+		return err
+	}
+
+	newHash := v.Hash
+	if newHash == nil {
+		newHash = sha256.New
+	}
+	data := body
+	if v.SignedData != nil {
+		data = v.SignedData(resp, body)
+	}
+	mac := hmac.New(newHash, v.Key)
+	mac.Write(data)
+	if !hmac.Equal(mac.Sum(nil), signature) {
+		return &ResponseSignatureError{StatusCode: resp.StatusCode, Header: header, Reason: "signature mismatch"}
+	}
+	return nil
+}
+
+// PublicKeyResponseVerifier memverifikasi signature RSA (PKCS#1 v1.5 atau PSS)
+// atau ECDSA atas digest SHA-256 body response.
+type PublicKeyResponseVerifier struct {
+	PublicKey crypto.PublicKey // *rsa.PublicKey atau *ecdsa.PublicKey
+	PSS       bool             // Pakai RSA-PSS, bukan PKCS#1 v1.5
+
+	SignatureHeader string // Nama header signature, default X-Signature
+	SignaturePrefix string // Optional: prefix nilai signature
+
+	// Optional: decoding signature, default base64 standar.
+	Decode func(signature string) ([]byte, error)
+	// Optional: data yang ditandatangani, default body response.
+	SignedData func(resp *http.Response, body []byte) []byte
+}
+
+// VerifyResponse mengimplementasikan ResponseVerifier.
+func (v *PublicKeyResponseVerifier) VerifyResponse(ctx context.Context, resp *http.Response, body []byte) error {
+	header := v.SignatureHeader
+	if header == "" {
+		header = "X-Signature"
+	}
+	decode := v.Decode
+	if decode == nil {
+		decode = base64.StdEncoding.DecodeString
+	}
+	signature, err := responseSignature(resp, header, v.SignaturePrefix, decode)
+	if err != nil {
+		return err
+	}
+
+	data := body
+	if v.SignedData != nil {
+		data = v.SignedData(resp, body)
+	}
+	digest := sha256.Sum256(data)
+
+	valid := false
+	switch key := v.PublicKey.(type) {
+	case *rsa.PublicKey:
+		if v.PSS {
+			valid = rsa.VerifyPSS(key, crypto.SHA256, digest[:], signature, nil) == nil
+		} else {
+			valid = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil
+		}
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(key, digest[:], signature)
+	default:
+		return fmt.Errorf("response verifier: unsupported public key type %T", v.PublicKey)
+	}
+	if !valid {
+		return &ResponseSignatureError{StatusCode: resp.StatusCode, Header: header, Reason: "signature mismatch"}
+	}
+	return nil
+}
+
+// responseSignature membaca dan men-decode signature dari header response.
+func responseSignature(resp *http.Response, header, prefix string, decode func(string) ([]byte, error)) ([]byte, error) {
+	value := strings.TrimSpace(resp.Header.Get(header))
+	if value == "" {
+		return nil, &ResponseSignatureError{StatusCode: resp.StatusCode, Header: header, Reason: "missing signature"}
+	}
+	if prefix != "" {
+		if !strings.HasPrefix(value, prefix) {
+			return nil, &ResponseSignatureError{StatusCode: resp.StatusCode, Header: header, Reason: "unexpected signature prefix"}
+		}
+		value = strings.TrimPrefix(value, prefix)
+	}
+	if decode == nil {
+		decode = hex.DecodeString
+	}
+	signature, err := decode(value)
+	if err != nil {
+		return nil, &ResponseSignatureError{StatusCode: resp.StatusCode, Header: header, Reason: "malformed signature"}
+	}
+	return signature, nil
+}
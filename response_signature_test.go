@@ -0,0 +1,94 @@
+package http_request_instant
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHMACResponseVerifier(t *testing.T) {
+	key := []byte("webhook-secret")
+	tamper := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := []byte(`{"paid":true}`)
+		mac := hmac.New(sha256.New, key)
+		mac.Write(body)
+		w.Header().Set("X-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		if tamper {
+			body = []byte(`{"paid":false}`)
+		}
+		_, _ = w.Write(body)
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.Verifier = &HMACResponseVerifier{Key: key, SignaturePrefix: "sha256="}
+
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tamper = true
+	_, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+	var sigErr *ResponseSignatureError
+	if !errors.As(err, &sigErr) || !errors.Is(err, ErrResponseSignature) {
+		t.Fatalf("expected ResponseSignatureError, got %v", err)
+	}
+	if sigErr.Reason != "signature mismatch" || sigErr.StatusCode != http.StatusOK {
+		t.Errorf("unexpected error detail: %+v", sigErr)
+	}
+}
+
+func TestResponseVerifierMissingSignature(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	_, err := client.Request(context.TODO(), RequestOptions{
+		Method:   "GET",
+		URL:      ts.URL,
+		Verifier: &HMACResponseVerifier{Key: []byte("k")},
+	})
+	var sigErr *ResponseSignatureError
+	if !errors.As(err, &sigErr) || sigErr.Reason != "missing signature" {
+		t.Fatalf("expected missing signature error, got %v", err)
+	}
+}
+
+func TestPublicKeyResponseVerifier(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := []byte(`{"status":"settled"}`)
+		digest := sha256.Sum256(body)
+		sig, _ := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		w.Header().Set("X-Payment-Signature", base64.StdEncoding.EncodeToString(sig))
+		_, _ = w.Write(body)
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.Verifier = &PublicKeyResponseVerifier{PublicKey: &key.PublicKey, SignatureHeader: "X-Payment-Signature"}
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+	client.Verifier = &PublicKeyResponseVerifier{PublicKey: &other.PublicKey, SignatureHeader: "X-Payment-Signature"}
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); !errors.Is(err, ErrResponseSignature) {
+		t.Fatalf("expected signature error, got %v", err)
+	}
+}
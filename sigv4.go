@@ -0,0 +1,265 @@
+package http_request_instant
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RequestSigner menandatangani request tepat sebelum dikirim,
+// setelah semua header, auth, dan body terpasang.
+type RequestSigner interface {
+	SignRequest(ctx context.Context, req *http.Request) error
+}
+
+// AWSCredentials menyimpan kredensial AWS.
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // Optional: untuk kredensial sementara (STS)
+}
+
+// AWSCredentialsProvider menyediakan kredensial AWS untuk setiap request.
+type AWSCredentialsProvider interface {
+	Retrieve(ctx context.Context) (AWSCredentials, error)
+}
+
+// StaticAWSCredentials adalah AWSCredentialsProvider dengan kredensial tetap.
+type StaticAWSCredentials AWSCredentials
+
+// Retrieve mengembalikan kredensial statis.
+func (c StaticAWSCredentials) Retrieve(ctx context.Context) (AWSCredentials, error) {
+	return AWSCredentials(c), nil
+}
+
+// EnvAWSCredentials membaca kredensial dari AWS_ACCESS_KEY_ID,
+// AWS_SECRET_ACCESS_KEY, dan AWS_SESSION_TOKEN.
+type EnvAWSCredentials struct{}
+
+// Retrieve membaca kredensial dari environment variable.
+func (EnvAWSCredentials) Retrieve(ctx context.Context) (AWSCredentials, error) {
+	creds := AWSCredentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return AWSCredentials{}, fmt.Errorf("AWS_ACCESS_KEY_ID or AWS_SECRET_ACCESS_KEY is not set")
+	}
+	return creds, nil
+}
+
+const (
+	sigV4Algorithm       = "AWS4-HMAC-SHA256"
+	sigV4TimeFormat      = "20060102T150405Z"
+	sigV4DateFormat      = "20060102"
+	sigV4UnsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// SigV4Signer menandatangani request dengan AWS Signature Version 4.
+type SigV4Signer struct {
+	Region      string                 // Mis. ap-southeast-1
+	Service     string                 // Mis. s3, execute-api
+	Credentials AWSCredentialsProvider // Sumber kredensial
+
+	// Kirim X-Amz-Content-Sha256: UNSIGNED-PAYLOAD tanpa hashing body (khusus S3).
+	UnsignedPayload bool
+
+	// Optional: sumber waktu, default time.Now.
+	Now func() time.Time
+}
+
+// SignRequest mengimplementasikan RequestSigner.
+func (s *SigV4Signer) SignRequest(ctx context.Context, req *http.Request) error {
+	if s.Credentials == nil {
+		return fmt.Errorf("sigv4: credentials provider is nil")
+	}
+	creds, err := s.Credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("sigv4: error retrieve credentials: %w", err)
+	}
+
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	t := now().UTC()
+	amzDate := t.Format(sigV4TimeFormat)
+	date := t.Format(sigV4DateFormat)
+
+	payloadHash := sigV4UnsignedPayload
+	if !s.UnsignedPayload {
+		payloadHash, err = hashRequestBody(req)
+		if err != nil {
+			return fmt.Errorf("sigv4: error hash payload: %w", err)
+		}
+	}
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+	if s.Service == "s3" || s.UnsignedPayload {
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+
+	signedHeaders, canonicalHeaders := sigV4CanonicalHeaders(req)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		sigV4CanonicalURI(req, s.Service == "s3"),
+		sigV4CanonicalQuery(req),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, s.Region, s.Service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		amzDate,
+		scope,
+		hexSHA256([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, s.Service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, creds.AccessKeyID, scope, signedHeaders, signature))
+	return nil
+}
+
+// sigV4IgnoredHeaders tidak ikut ditandatangani karena bisa diubah oleh transport atau proxy.
+var sigV4IgnoredHeaders = map[string]bool{
+	"authorization":   true,
+	"user-agent":      true,
+	"x-amzn-trace-id": true,
+	"expect":          true,
+}
+
+func sigV4CanonicalHeaders(req *http.Request) (string, string) {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	values := map[string]string{"host": host}
+	for name, v := range req.Header {
+		lower := strings.ToLower(name)
+		if sigV4IgnoredHeaders[lower] {
+			continue
+		}
+		trimmed := make([]string, len(v))
+		for i, value := range v {
+			trimmed[i] = strings.Join(strings.Fields(value), " ")
+		}
+		values[lower] = strings.Join(trimmed, ",")
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonical strings.Builder
+	for _, name := range names {
+		canonical.WriteString(name)
+		canonical.WriteByte(':')
+		canonical.WriteString(values[name])
+		canonical.WriteByte('\n')
+	}
+	return strings.Join(names, ";"), canonical.String()
+}
+
+func sigV4CanonicalURI(req *http.Request, singleEncode bool) string {
+	path := req.URL.Path
+	if path == "" {
+		return "/"
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		encoded := awsURIEncode(segment)
+		if !singleEncode {
+			encoded = awsURIEncode(encoded)
+		}
+		segments[i] = encoded
+	}
+	return strings.Join(segments, "/")
+}
+
+func sigV4CanonicalQuery(req *http.Request) string {
+	query := req.URL.Query()
+	pairs := make([]string, 0, len(query))
+	for key, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, awsURIEncode(key)+"="+awsURIEncode(value))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// awsURIEncode meng-encode semua karakter kecuali unreserved (RFC 3986).
+func awsURIEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+// hashRequestBody menghitung SHA-256 hex dari body request tanpa
+// mengonsumsi body yang akan dikirim.
+func hashRequestBody(req *http.Request) (string, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return "", err
+	}
+	return hexSHA256(body), nil
+}
+
+// readRequestBody membaca salinan body request lewat GetBody.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody == nil {
+		return nil, fmt.Errorf("request body cannot be re-read")
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
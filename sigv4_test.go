@@ -0,0 +1,85 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test vector dari AWS SigV4 test suite
+func newTestSigV4Signer() *SigV4Signer {
+	return &SigV4Signer{
+		Region:  "us-east-1",
+		Service: "service",
+		Credentials: StaticAWSCredentials{
+			AccessKeyID:     "AKIDEXAMPLE",
+			SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		},
+		Now: func() time.Time { return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC) },
+	}
+}
+
+func TestSigV4Vectors(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		signature string
+	}{
+		{"get-vanilla", "https://example.amazonaws.com/", "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"},
+		{"get-vanilla-query-order-key-case", "https://example.amazonaws.com/?Param2=value2&Param1=value1", "b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			if err := newTestSigV4Signer().SignRequest(context.TODO(), req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			expected := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+				"SignedHeaders=host;x-amz-date, Signature=" + tt.signature
+			if got := req.Header.Get("Authorization"); got != expected {
+				t.Errorf("unexpected Authorization:\n got: %s\nwant: %s", got, expected)
+			}
+		})
+	}
+}
+
+func TestSigV4SignsRequestBody(t *testing.T) {
+	var auth, token, contentHash string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		token = r.Header.Get("X-Amz-Security-Token")
+		contentHash = r.Header.Get("X-Amz-Content-Sha256")
+		w.WriteHeader(200)
+	}))
+	defer ts.Close()
+
+	signer := newTestSigV4Signer()
+	signer.Service = "s3"
+	signer.Credentials = StaticAWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "session"}
+
+	client := NewHttpRequest()
+	client.Signer = signer
+	_, err := client.Request(context.TODO(), RequestOptions{
+		Method:      "PUT",
+		URL:         ts.URL + "/bucket/key",
+		RequestBody: "hello",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(auth, "SignedHeaders=host;x-amz-content-sha256;x-amz-date;x-amz-security-token") {
+		t.Errorf("unexpected signed headers: %s", auth)
+	}
+	if token != "session" {
+		t.Errorf("expected session token header, got %q", token)
+	}
+	if contentHash != hexSHA256([]byte("hello")) {
+		t.Errorf("unexpected payload hash: %s", contentHash)
+	}
+}
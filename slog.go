@@ -0,0 +1,96 @@
+package http_request_instant
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// SlogLogger adalah adapter Logger dan EventLogger untuk *slog.Logger.
+// Event request/response dicatat di level Debug, slow request di level Warn,
+// dan event error di level Error.
+type SlogLogger struct {
+	Logger *slog.Logger
+}
+
+// NewSlogLogger membuat SlogLogger, default slog.Default() jika logger nil.
+func NewSlogLogger(logger *slog.Logger) *SlogLogger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SlogLogger{Logger: logger}
+}
+
+// Debugf mengimplementasikan Logger.
+func (l *SlogLogger) Debugf(format string, args ...any) {
+	l.Logger.Debug(fmt.Sprintf(format, args...))
+}
+
+// Infof mengimplementasikan Logger.
+func (l *SlogLogger) Infof(format string, args ...any) {
+	l.Logger.Info(fmt.Sprintf(format, args...))
+}
+
+// Errorf mengimplementasikan Logger.
+func (l *SlogLogger) Errorf(format string, args ...any) {
+	l.Logger.Error(fmt.Sprintf(format, args...))
+}
+
+// LogEvent mengimplementasikan EventLogger.
+func (l *SlogLogger) LogEvent(ctx context.Context, event LogEvent) {
+	level := slog.LevelDebug
+	switch event.Kind {
+	case LogEventError:
+		level = slog.LevelError
+	case LogEventSlow:
+		level = slog.LevelWarn
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", event.Method),
+		slog.String("url", event.URL),
+		slog.Int("attempt", event.Attempt),
+	}
+	if event.StatusCode != 0 {
+		attrs = append(attrs, slog.Int("status", event.StatusCode))
+	}
+	if event.Duration > 0 {
+		attrs = append(attrs, slog.Duration("duration", event.Duration))
+	}
+	if len(event.Headers) > 0 {
+		names := make([]string, 0, len(event.Headers))
+		for name := range event.Headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		headers := make([]any, 0, len(names))
+		for _, name := range names {
+			headers = append(headers, slog.String(name, strings.Join(event.Headers[name], ", ")))
+		}
+		attrs = append(attrs, slog.Group("headers", headers...))
+	}
+	if event.Kind == LogEventRequest || event.Kind == LogEventResponse {
+		attrs = append(attrs, slog.Int("body_size", event.BodySize))
+	}
+	if event.Body != "" {
+		attrs = append(attrs, slog.String("body", event.Body))
+	}
+	if event.Err != nil {
+		attrs = append(attrs, slog.String("error", event.Err.Error()))
+	}
+	if t := event.Timings; t != nil {
+		attrs = append(attrs, slog.Group("timings",
+			slog.Duration("dns", t.DNS),
+			slog.Duration("connect", t.Connect),
+			slog.Duration("tls_handshake", t.TLSHandshake),
+			slog.Duration("wait_for_conn", t.WaitForConn),
+			slog.Duration("first_byte", t.FirstByte),
+			slog.Duration("download", t.Download),
+			slog.Bool("conn_reused", t.ConnReused),
+		))
+	}
+
+	l.Logger.LogAttrs(ctx, level, "http "+event.Kind, attrs...)
+}
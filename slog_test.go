@@ -0,0 +1,71 @@
+package http_request_instant
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSlogLoggerStructuredEvents(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	client := NewHttpRequest()
+	client.SetDebug(true)
+	client.Logger = NewSlogLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	_, err := client.Request(context.TODO(), RequestOptions{
+		Method:      "POST",
+		URL:         ts.URL + "/items",
+		RequestBody: `{"name":"a"}`,
+		BearerToken: "secret",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d: %s", len(lines), buf.String())
+	}
+
+	var req, resp map[string]any
+	_ = json.Unmarshal([]byte(lines[0]), &req)
+	_ = json.Unmarshal([]byte(lines[1]), &resp)
+	if req["msg"] != "http request" || req["method"] != "POST" || req["attempt"] != float64(1) {
+		t.Errorf("unexpected request event: %v", req)
+	}
+	if headers, _ := req["headers"].(map[string]any); headers["Authorization"] != "Bearer [REDACTED]" {
+		t.Errorf("expected redacted Authorization, got %v", req["headers"])
+	}
+	if resp["msg"] != "http response" || resp["status"] != float64(201) || resp["duration"] == nil {
+		t.Errorf("unexpected response event: %v", resp)
+	}
+}
+
+func TestSlogLoggerErrorEventWithoutDebug(t *testing.T) {
+	var buf bytes.Buffer
+	client := NewHttpRequest()
+	client.Logger = NewSlogLogger(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: "http://127.0.0.1:1"}); err == nil {
+		t.Fatal("expected connection error")
+	}
+
+	var event map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &event); err != nil {
+		t.Fatalf("expected one JSON event, got %q", buf.String())
+	}
+	if event["level"] != "ERROR" || event["msg"] != "http error" || event["error"] == nil {
+		t.Errorf("unexpected error event: %v", event)
+	}
+}
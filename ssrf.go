@@ -0,0 +1,288 @@
+package http_request_instant
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+)
+
+// SSRFOptions mengatur proteksi SSRF untuk client yang meneruskan URL dari
+// pengguna. Secara default alamat loopback, privat, link-local, metadata
+// cloud, CGNAT, multicast, dan reserved diblokir.
+type SSRFOptions struct {
+	// Range yang tetap diizinkan walaupun termasuk range terblokir, mis.
+	// "10.20.0.0/16". IP tunggal tanpa prefix juga diterima.
+	AllowedCIDRs []string
+
+	// Range tambahan yang diblokir, dengan format yang sama.
+	DeniedCIDRs []string
+
+	// Host (persis atau wildcard "*.example.com") yang dilewatkan tanpa cek
+	// IP. Proxy yang ada di jaringan privat perlu didaftarkan di sini.
+	AllowedHosts []string
+
+	// Host yang selalu ditolak, apa pun IP-nya.
+	DeniedHosts []string
+}
+
+// SSRFError dikembalikan ketika tujuan request diblokir proteksi SSRF.
+type SSRFError struct {
+	Host   string     // Host tujuan, kosong jika hanya IP yang diketahui
+	IP     netip.Addr // IP yang diblokir; tidak valid jika diblokir karena host
+	Reason string     // Mis. "loopback", "private", "cloud metadata"
+}
+
+func (e *SSRFError) Error() string {
+	if !e.IP.IsValid() {
+		return fmt.Sprintf("ssrf protection: host %s is blocked: %s", e.Host, e.Reason)
+	}
+	if e.Host == "" {
+		return fmt.Sprintf("ssrf protection: address %s is blocked: %s", e.IP, e.Reason)
+	}
+	return fmt.Sprintf("ssrf protection: %s resolves to blocked address %s: %s", e.Host, e.IP, e.Reason)
+}
+
+// Category mengimplementasikan CategorizedError.
+func (e *SSRFError) Category() ErrorCategory {
+	return CategoryPolicy
+}
+
+type ssrfRange struct {
+	prefix netip.Prefix
+	reason string
+}
+
+// ssrfBlockedRanges diurutkan dari yang paling spesifik supaya Reason tepat.
+var ssrfBlockedRanges = []ssrfRange{
+	{netip.MustParsePrefix("169.254.169.254/32"), "cloud metadata"},
+	{netip.MustParsePrefix("fd00:ec2::254/128"), "cloud metadata"},
+	{netip.MustParsePrefix("0.0.0.0/8"), "unspecified"},
+	{netip.MustParsePrefix("::/128"), "unspecified"},
+	{netip.MustParsePrefix("127.0.0.0/8"), "loopback"},
+	{netip.MustParsePrefix("::1/128"), "loopback"},
+	{netip.MustParsePrefix("10.0.0.0/8"), "private"},
+	{netip.MustParsePrefix("172.16.0.0/12"), "private"},
+	{netip.MustParsePrefix("192.168.0.0/16"), "private"},
+	{netip.MustParsePrefix("fc00::/7"), "private"},
+	{netip.MustParsePrefix("169.254.0.0/16"), "link-local"},
+	{netip.MustParsePrefix("fe80::/10"), "link-local"},
+	{netip.MustParsePrefix("100.64.0.0/10"), "carrier-grade NAT"},
+	{netip.MustParsePrefix("224.0.0.0/4"), "multicast"},
+	{netip.MustParsePrefix("ff00::/8"), "multicast"},
+	{netip.MustParsePrefix("192.0.0.0/24"), "reserved"},
+	{netip.MustParsePrefix("198.18.0.0/15"), "reserved"},
+	{netip.MustParsePrefix("240.0.0.0/4"), "reserved"},
+}
+
+type ssrfGuard struct {
+	allowed      []netip.Prefix
+	denied       []netip.Prefix
+	allowedHosts []string
+	deniedHosts  []string
+}
+
+// ssrfDialHostKey membawa host tujuan dari dialContext ke control.
+type ssrfDialHostKey struct{}
+
+// EnableSSRFProtection memblokir request ke alamat internal. Tujuan dicek
+// dua kali: host di-resolve sebelum request dikirim (termasuk redirect dan
+// request lewat proxy), lalu IP yang benar-benar di-dial dicek lagi supaya
+// DNS rebinding tidak lolos. Pelanggaran dikembalikan sebagai *SSRFError.
+// Panggil sebelum client dipakai secara konkuren.
+func (c *HttpRequest) EnableSSRFProtection(options SSRFOptions) error {
+	allowed, err := parseSSRFPrefixes(options.AllowedCIDRs)
+	if err != nil {
+		return err
+	}
+	denied, err := parseSSRFPrefixes(options.DeniedCIDRs)
+	if err != nil {
+		return err
+	}
+	t, err := c.transport()
+	if err != nil {
+		return err
+	}
+
+	guard := &ssrfGuard{
+		allowed:      allowed,
+		denied:       denied,
+		allowedHosts: options.AllowedHosts,
+		deniedHosts:  options.DeniedHosts,
+	}
+	c.netDialer().ControlContext = guard.control
+	t.DialContext = c.dialContext
+	c.ssrf = guard
+
+	checkRedirect := c.Client.CheckRedirect
+	c.Client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if err := c.checkSSRF(req); err != nil {
+			return err
+		}
+		if checkRedirect != nil {
+			return checkRedirect(req, via)
+		}
+		if len(via) >= 10 {
+			return fmt.Errorf("stopped after 10 redirects")
+		}
+		return nil
+	}
+	return nil
+}
+
+func parseSSRFPrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, v := range values {
+		if !strings.Contains(v, "/") {
+			addr, err := netip.ParseAddr(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid SSRF CIDR %q: %w", v, err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SSRF CIDR %q: %w", v, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// checkAddr mengembalikan *SSRFError jika addr diblokir.
+func (g *ssrfGuard) checkAddr(host string, addr netip.Addr) error {
+	// ::ffff:127.0.0.1 diperlakukan sama dengan 127.0.0.1
+	addr = addr.Unmap().WithZone("")
+	// Alamat NAT64 dan 6to4 dicek berdasarkan IPv4 di dalamnya
+	check := embeddedIPv4(addr)
+	for _, prefix := range g.allowed {
+		if prefix.Contains(addr) || prefix.Contains(check) {
+			return nil
+		}
+	}
+	for _, prefix := range g.denied {
+		if prefix.Contains(addr) || prefix.Contains(check) {
+			return &SSRFError{Host: host, IP: addr, Reason: "denied range " + prefix.String()}
+		}
+	}
+	for _, r := range ssrfBlockedRanges {
+		if r.prefix.Contains(check) {
+			return &SSRFError{Host: host, IP: addr, Reason: r.reason}
+		}
+	}
+	return nil
+}
+
+var (
+	nat64Prefix     = netip.MustParsePrefix("64:ff9b::/96")
+	sixToFourPrefix = netip.MustParsePrefix("2002::/16")
+)
+
+// embeddedIPv4 mengembalikan IPv4 yang dibawa alamat NAT64 (64:ff9b::/96,
+// RFC 6052) atau 6to4 (2002::/16, RFC 3056), atau addr apa adanya.
+func embeddedIPv4(addr netip.Addr) netip.Addr {
+	b := addr.As16()
+	switch {
+	case nat64Prefix.Contains(addr):
+		return netip.AddrFrom4([4]byte(b[12:16]))
+	case sixToFourPrefix.Contains(addr):
+		return netip.AddrFrom4([4]byte(b[2:6]))
+	}
+	return addr
+}
+
+// checkHost mengecek daftar host. exempt true berarti host dilewatkan
+// tanpa cek IP.
+func (g *ssrfGuard) checkHost(host string) (exempt bool, err error) {
+	if matchAnyHost(g.deniedHosts, host) {
+		return false, &SSRFError{Host: host, Reason: "host is denied"}
+	}
+	return matchAnyHost(g.allowedHosts, host), nil
+}
+
+// control dipasang sebagai net.Dialer.ControlContext dan melihat IP final
+// yang akan di-connect, setelah semua resolusi DNS. Socket Unix dari URL
+// sudah ditolak checkSSRF dan dialContext.
+func (g *ssrfGuard) control(ctx context.Context, network, address string, _ syscall.RawConn) error {
+	if network == "unix" || network == "unixgram" || network == "unixpacket" {
+		return nil
+	}
+	host, _ := ctx.Value(ssrfDialHostKey{}).(string)
+	if host != "" {
+		if exempt, err := g.checkHost(host); exempt || err != nil {
+			return err
+		}
+	}
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return &SSRFError{Host: host, Reason: "unparseable address " + address}
+	}
+	return g.checkAddr(host, addrPort.Addr())
+}
+
+// unixSocketURLError mengembalikan *SSRFError jika ctx membawa socket dari
+// URL uds:// atau unix://.
+func unixSocketURLError(ctx context.Context) error {
+	if socket, ok := ctx.Value(unixSocketContextKey{}).(string); ok {
+		return &SSRFError{Host: socket, Reason: "unix socket URL"}
+	}
+	return nil
+}
+
+// ssrfDialContext menandai ctx dial dengan host tujuan untuk control.
+func ssrfDialContext(ctx context.Context, addr string) context.Context {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	return context.WithValue(ctx, ssrfDialHostKey{}, host)
+}
+
+// checkSSRF me-resolve host tujuan req dan menolak jika salah satu IP-nya
+// diblokir. Gagal resolve dibiarkan supaya error DNS asli muncul saat dial.
+// URL uds:// selalu ditolak karena path socket berasal dari URL; hanya
+// socket dari SetUnixSocket yang dilewatkan.
+func (c *HttpRequest) checkSSRF(req *http.Request) error {
+	guard := c.ssrf
+	if guard == nil {
+		return nil
+	}
+	if err := unixSocketURLError(req.Context()); err != nil {
+		return err
+	}
+	if c.unixSocket != "" {
+		return nil
+	}
+	host := req.URL.Hostname()
+	if exempt, err := guard.checkHost(host); exempt || err != nil {
+		return err
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return guard.checkAddr(host, addr)
+	}
+
+	var addrs []string
+	var err error
+	if c.dnsCache != nil {
+		addrs, err = c.dnsCache.lookup(req.Context(), host)
+	} else {
+		addrs, err = net.DefaultResolver.LookupHost(req.Context(), host)
+	}
+	if err != nil {
+		return nil
+	}
+	for _, a := range addrs {
+		addr, err := netip.ParseAddr(a)
+		if err != nil {
+			continue
+		}
+		if err := guard.checkAddr(host, addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package http_request_instant
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+func TestSSRFProtection(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+			return
+		}
+		w.Write([]byte("internal"))
+	}))
+	defer ts.Close()
+	localhostURL := strings.Replace(ts.URL, "127.0.0.1", "localhost", 1)
+
+	client := NewHttpRequest()
+	if err := client.EnableSSRFProtection(SSRFOptions{DeniedHosts: []string{"*.internal"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, url := range []string{ts.URL, localhostURL, "http://[::ffff:127.0.0.1]/", "http://metadata.internal/"} {
+		_, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: url})
+		var ssrfErr *SSRFError
+		if !errors.As(err, &ssrfErr) || Category(err) != CategoryPolicy {
+			t.Errorf("%s: expected *SSRFError, got %v", url, err)
+		}
+	}
+
+	allowed := NewHttpRequest()
+	allowed.EnableSSRFProtection(SSRFOptions{AllowedCIDRs: []string{"127.0.0.0/8"}})
+	if resp, err := allowed.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err != nil || string(resp.Body) != "internal" {
+		t.Errorf("expected allowlisted CIDR to pass, got %v", err)
+	}
+	// Redirect ke metadata cloud tetap diblokir
+	_, err := allowed.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL + "/redirect"})
+	var ssrfErr *SSRFError
+	if !errors.As(err, &ssrfErr) || ssrfErr.Reason != "cloud metadata" {
+		t.Errorf("expected redirect to metadata to be blocked, got %v", err)
+	}
+
+	byHost := NewHttpRequest()
+	byHost.EnableSSRFProtection(SSRFOptions{AllowedHosts: []string{"localhost"}})
+	if _, err := byHost.Request(context.TODO(), RequestOptions{Method: "GET", URL: localhostURL}); err != nil {
+		t.Errorf("expected allowlisted host to pass, got %v", err)
+	}
+
+	if err := NewHttpRequest().EnableSSRFProtection(SSRFOptions{AllowedCIDRs: []string{"10.0.0.0/33"}}); err == nil {
+		t.Errorf("expected invalid CIDR error")
+	}
+}
+
+func TestSSRFDialControl(t *testing.T) {
+	guard := &ssrfGuard{}
+	ctx := ssrfDialContext(context.TODO(), "rebind.example.com:443")
+
+	// IP yang di-dial dicek lagi walaupun resolusi sebelumnya publik
+	err := guard.control(ctx, "tcp4", "10.1.2.3:443", nil)
+	var ssrfErr *SSRFError
+	if !errors.As(err, &ssrfErr) || ssrfErr.Host != "rebind.example.com" || ssrfErr.Reason != "private" {
+		t.Errorf("expected private address to be blocked, got %v", err)
+	}
+	if err := guard.control(ctx, "tcp6", "[fe80::1%eth0]:443", nil); err == nil {
+		t.Errorf("expected link-local address to be blocked")
+	}
+	if err := guard.control(ctx, "tcp4", "93.184.216.34:443", nil); err != nil {
+		t.Errorf("expected public address to pass, got %v", err)
+	}
+	if err := guard.control(ctx, "unix", "/run/app.sock", nil); err != nil {
+		t.Errorf("expected unix socket to pass, got %v", err)
+	}
+}
+
+func TestSSRFUnixSocketURL(t *testing.T) {
+	socket := serveUnixSocket(t, "daemon.sock")
+
+	client := NewHttpRequest()
+	client.EnableSSRFProtection(SSRFOptions{})
+	for _, url := range []string{"uds://" + socket + "/containers/json", "unix://" + socket + "/"} {
+		_, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: url})
+		var ssrfErr *SSRFError
+		if !errors.As(err, &ssrfErr) || ssrfErr.Reason != "unix socket URL" {
+			t.Errorf("%s: expected *SSRFError, got %v", url, err)
+		}
+	}
+
+	// Socket yang dipilih operator lewat SetUnixSocket tetap boleh
+	operator := NewHttpRequest()
+	operator.SetUnixSocket(socket)
+	operator.EnableSSRFProtection(SSRFOptions{})
+	if resp, err := operator.Request(context.TODO(), RequestOptions{Method: "GET", URL: "http://docker/containers/json"}); err != nil || !strings.HasPrefix(string(resp.Body), "daemon.sock") {
+		t.Errorf("expected SetUnixSocket to pass, got %v", err)
+	}
+	// URL uds:// tetap ditolak walaupun client punya socket sendiri
+	if _, err := operator.Request(context.TODO(), RequestOptions{Method: "GET", URL: "uds://" + socket + "/"}); err == nil {
+		t.Errorf("expected unix socket URL to be blocked")
+	}
+}
+
+func TestSSRFEmbeddedIPv4(t *testing.T) {
+	guard := &ssrfGuard{}
+	for addr, reason := range map[string]string{
+		"64:ff9b::a9fe:a9fe":     "cloud metadata",
+		"64:ff9b::7f00:1":        "loopback",
+		"2002:a00:1::1":          "private",
+		"2002:a9fe:a9fe::":       "cloud metadata",
+		"::ffff:169.254.169.254": "cloud metadata",
+	} {
+		err := guard.checkAddr("example.com", netip.MustParseAddr(addr))
+		var ssrfErr *SSRFError
+		if !errors.As(err, &ssrfErr) || ssrfErr.Reason != reason {
+			t.Errorf("%s: expected %q, got %v", addr, reason, err)
+		}
+	}
+	for _, addr := range []string{"64:ff9b::808:808", "2002:808:808::1"} {
+		if err := guard.checkAddr("example.com", netip.MustParseAddr(addr)); err != nil {
+			t.Errorf("%s: expected public IPv4 to pass, got %v", addr, err)
+		}
+	}
+
+	allowed := &ssrfGuard{allowed: []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}}
+	if err := allowed.checkAddr("example.com", netip.MustParseAddr("64:ff9b::7f00:1")); err != nil {
+		t.Errorf("expected AllowedCIDRs to apply to embedded IPv4, got %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package http_request_instant
+
+import (
+	"expvar"
+	"sync/atomic"
+	"time"
+)
+
+// Stats adalah snapshot counter runtime sebuah client.
+type Stats struct {
+	Requests      int64 // Total percobaan request yang dikirim
+	Errors        int64 // Percobaan yang gagal tanpa response lengkap
+	BytesSent     int64 // Total body request yang dikirim
+	BytesReceived int64 // Total body response yang diterima
+	Active        int64 // Request yang sedang berjalan
+	Retries       int64 // Percobaan ulang oleh RetryPolicy
+
+	// Total waktu request ditahan menunggu kuota rate limit
+	RateLimitWait time.Duration
+}
+
+// clientStats menyimpan counter atomik milik HttpRequest.
+type clientStats struct {
+	requests      int64
+	errors        int64
+	bytesSent     int64
+	bytesReceived int64
+	active        int64
+	retries       int64
+	rateLimitWait int64 // nanodetik
+}
+
+// Stats mengembalikan snapshot counter runtime client.
+func (c *HttpRequest) Stats() Stats {
+	return Stats{
+		Requests:      atomic.LoadInt64(&c.stats.requests),
+		Errors:        atomic.LoadInt64(&c.stats.errors),
+		BytesSent:     atomic.LoadInt64(&c.stats.bytesSent),
+		BytesReceived: atomic.LoadInt64(&c.stats.bytesReceived),
+		Active:        atomic.LoadInt64(&c.stats.active),
+		Retries:       atomic.LoadInt64(&c.stats.retries),
+		RateLimitWait: time.Duration(atomic.LoadInt64(&c.stats.rateLimitWait)),
+	}
+}
+
+// PublishExpvar mendaftarkan Stats client ke expvar dengan nama name,
+// sehingga tampil di /debug/vars. Seperti expvar.Publish, panic jika
+// nama sudah dipakai.
+func (c *HttpRequest) PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return c.Stats()
+	}))
+}
+
+func (s *clientStats) start(bytesSent int64) {
+	atomic.AddInt64(&s.requests, 1)
+	atomic.AddInt64(&s.active, 1)
+	if bytesSent > 0 {
+		atomic.AddInt64(&s.bytesSent, bytesSent)
+	}
+}
+
+func (s *clientStats) finish(bytesReceived int64, err error) {
+	atomic.AddInt64(&s.active, -1)
+	atomic.AddInt64(&s.bytesReceived, bytesReceived)
+	if err != nil {
+		atomic.AddInt64(&s.errors, 1)
+	}
+}
+
+func (s *clientStats) retry() {
+	atomic.AddInt64(&s.retries, 1)
+}
+
+func (s *clientStats) waitedRateLimit(d time.Duration) {
+	if d > 0 {
+		atomic.AddInt64(&s.rateLimitWait, int64(d))
+	}
+}
@@ -0,0 +1,74 @@
+package http_request_instant
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientStats(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("12345"))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	for i := 0; i < 2; i++ {
+		if _, err := client.Request(context.TODO(), RequestOptions{Method: "POST", URL: ts.URL, RequestBody: "abc"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	_, _ = client.Request(context.TODO(), RequestOptions{Method: "GET", URL: "http://127.0.0.1:1"})
+
+	stats := client.Stats()
+	want := Stats{Requests: 3, Errors: 1, BytesSent: 6, BytesReceived: 10, Active: 0}
+	if stats != want {
+		t.Errorf("expected %+v, got %+v", want, stats)
+	}
+
+	client.PublishExpvar("http_request_instant_test_stats")
+	var published Stats
+	if err := json.Unmarshal([]byte(expvar.Get("http_request_instant_test_stats").String()), &published); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if published != want {
+		t.Errorf("expected expvar %+v, got %+v", want, published)
+	}
+}
+
+func TestClientStatsRetriesAndRateLimitWait(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "0.1")
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.SetHostConfig("127.0.0.1", HostConfig{
+		Retry:     &RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond},
+		RateLimit: &RateLimitOptions{},
+	})
+	for i := 0; i < 2; i++ {
+		if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	stats := client.Stats()
+	if stats.Retries != 1 || stats.Requests != 3 {
+		t.Errorf("expected 1 retry over 3 attempts, got %+v", stats)
+	}
+	if stats.RateLimitWait < 50*time.Millisecond {
+		t.Errorf("expected rate limit wait recorded, got %v", stats.RateLimitWait)
+	}
+}
@@ -0,0 +1,143 @@
+package http_request_instant
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StatsReporter adalah abstraksi backend metrik sederhana (StatsD, Datadog,
+// dll.). Tag berformat "key:value".
+type StatsReporter interface {
+	Incr(name string, tags []string)
+	Timing(name string, duration time.Duration, tags []string)
+	Gauge(name string, value float64, tags []string)
+}
+
+// NewStatsReporterMetrics membuat MetricsObserver yang melaporkan metrik
+// request ke StatsReporter dengan prefix nama, mis. "payment_client.".
+//
+// Metrik: requests (counter), request.duration (timing), requests.in_flight
+// (gauge), dengan tag method, host, dan status.
+func NewStatsReporterMetrics(reporter StatsReporter, prefix string) MetricsObserver {
+	return &statsReporterMetrics{reporter: reporter, prefix: prefix, inFlight: make(map[string]float64)}
+}
+
+type statsReporterMetrics struct {
+	reporter StatsReporter
+	prefix   string
+
+	mu       sync.Mutex
+	inFlight map[string]float64
+}
+
+func (m *statsReporterMetrics) RequestStarted(method, host string) {
+	m.gaugeInFlight(method, host, 1)
+}
+
+func (m *statsReporterMetrics) RequestFinished(metrics RequestMetrics) {
+	m.gaugeInFlight(metrics.Method, metrics.Host, -1)
+
+	status := "error"
+	if metrics.Err == nil {
+		status = strconv.Itoa(metrics.StatusCode)
+	}
+	tags := []string{"method:" + metrics.Method, "host:" + metrics.Host, "status:" + status}
+	m.reporter.Incr(m.prefix+"requests", tags)
+	m.reporter.Timing(m.prefix+"request.duration", metrics.Duration, tags)
+}
+
+func (m *statsReporterMetrics) gaugeInFlight(method, host string, delta float64) {
+	key := method + " " + host
+	m.mu.Lock()
+	m.inFlight[key] += delta
+	value := m.inFlight[key]
+	m.mu.Unlock()
+	m.reporter.Gauge(m.prefix+"requests.in_flight", value, []string{"method:" + method, "host:" + host})
+}
+
+// MultiMetricsObserver meneruskan event metrik ke beberapa observer,
+// mis. PrometheusMetrics dan StatsD sekaligus.
+func MultiMetricsObserver(observers ...MetricsObserver) MetricsObserver {
+	return multiMetrics(observers)
+}
+
+type multiMetrics []MetricsObserver
+
+func (m multiMetrics) RequestStarted(method, host string) {
+	for _, o := range m {
+		o.RequestStarted(method, host)
+	}
+}
+
+func (m multiMetrics) RequestFinished(metrics RequestMetrics) {
+	for _, o := range m {
+		o.RequestFinished(metrics)
+	}
+}
+
+// StatsDFormat menentukan format baris StatsD yang dikirim.
+type StatsDFormat int
+
+const (
+	// StatsDPlain adalah format StatsD standar; tag tidak dikirim.
+	StatsDPlain StatsDFormat = iota
+	// StatsDDatadog adalah format DogStatsD dengan tag "|#k:v,...".
+	StatsDDatadog
+)
+
+// StatsDOptions menyimpan konfigurasi StatsDReporter.
+type StatsDOptions struct {
+	Format StatsDFormat // Default StatsDPlain
+	Tags   []string     // Optional: tag global untuk semua metrik (Datadog)
+}
+
+// StatsDReporter adalah StatsReporter yang mengirim metrik lewat UDP.
+// Error kirim diabaikan, sesuai sifat StatsD yang fire-and-forget.
+type StatsDReporter struct {
+	conn    net.Conn
+	options StatsDOptions
+}
+
+// NewStatsDReporter membuat StatsDReporter ke addr, mis. "127.0.0.1:8125".
+func NewStatsDReporter(addr string, options StatsDOptions) (*StatsDReporter, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("statsd: %w", err)
+	}
+	return &StatsDReporter{conn: conn, options: options}, nil
+}
+
+// Incr mengimplementasikan StatsReporter.
+func (s *StatsDReporter) Incr(name string, tags []string) {
+	s.send(name, "1", "c", tags)
+}
+
+// Timing mengimplementasikan StatsReporter.
+func (s *StatsDReporter) Timing(name string, duration time.Duration, tags []string) {
+	s.send(name, strconv.FormatFloat(float64(duration)/float64(time.Millisecond), 'f', -1, 64), "ms", tags)
+}
+
+// Gauge mengimplementasikan StatsReporter.
+func (s *StatsDReporter) Gauge(name string, value float64, tags []string) {
+	s.send(name, strconv.FormatFloat(value, 'f', -1, 64), "g", tags)
+}
+
+// Close menutup koneksi UDP.
+func (s *StatsDReporter) Close() error {
+	return s.conn.Close()
+}
+
+func (s *StatsDReporter) send(name, value, kind string, tags []string) {
+	line := name + ":" + value + "|" + kind
+	if s.options.Format == StatsDDatadog {
+		all := append(append([]string{}, s.options.Tags...), tags...)
+		if len(all) > 0 {
+			line += "|#" + strings.Join(all, ",")
+		}
+	}
+	_, _ = s.conn.Write([]byte(line))
+}
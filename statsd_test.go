@@ -0,0 +1,79 @@
+package http_request_instant
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatsDReporterDatadog(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer pc.Close()
+
+	reporter, err := NewStatsDReporter(pc.LocalAddr().String(), StatsDOptions{Format: StatsDDatadog, Tags: []string{"env:test"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer reporter.Close()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+	host := strings.TrimPrefix(ts.URL, "http://")
+
+	client := NewHttpRequest()
+	client.Metrics = NewStatsReporterMetrics(reporter, "api.")
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var lines []string
+	buf := make([]byte, 1024)
+	_ = pc.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for len(lines) < 4 {
+		n, _, err := pc.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("expected 4 packets, got %v: %v", lines, err)
+		}
+		lines = append(lines, string(buf[:n]))
+	}
+
+	tags := "method:GET,host:" + host
+	if lines[0] != "api.requests.in_flight:1|g|#env:test,"+tags ||
+		lines[1] != "api.requests.in_flight:0|g|#env:test,"+tags ||
+		lines[2] != "api.requests:1|c|#env:test,"+tags+",status:200" ||
+		!strings.HasPrefix(lines[3], "api.request.duration:") || !strings.HasSuffix(lines[3], "|ms|#env:test,"+tags+",status:200") {
+		t.Errorf("unexpected statsd lines: %q", lines)
+	}
+}
+
+func TestStatsDReporterPlainDropsTags(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer pc.Close()
+
+	reporter, err := NewStatsDReporter(pc.LocalAddr().String(), StatsDOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer reporter.Close()
+	reporter.Incr("hits", []string{"a:b"})
+
+	buf := make([]byte, 128)
+	_ = pc.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(buf[:n]) != "hits:1|c" {
+		t.Errorf("unexpected line: %q", buf[:n])
+	}
+}
@@ -0,0 +1,121 @@
+package http_request_instant
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// StreamWriter adalah tujuan tulis generator StreamBody.
+type StreamWriter interface {
+	io.Writer
+	// Flush mengirim data yang sudah ditulis sebagai satu chunk sekarang juga.
+	Flush() error
+}
+
+// StreamBodyOptions mengatur kapan data StreamBody dikirim sebagai chunk.
+type StreamBodyOptions struct {
+	FlushBytes    int           // Chunk dikirim setiap data terkumpul sebanyak ini, default 32 KiB
+	FlushInterval time.Duration // Optional: chunk dikirim paling lambat setiap interval walaupun belum penuh
+}
+
+// StreamBody membuat body request yang diisi produce saat request dikirim,
+// untuk data yang ukurannya tidak diketahui di awal. Pakai sebagai
+// RequestOptions.RequestBody; body dikirim dengan chunked transfer encoding
+// tanpa ditampung seluruhnya di memori. Error dari produce menggagalkan
+// request. Body hanya bisa dikirim sekali, sehingga tidak di-retry.
+func StreamBody(produce func(w StreamWriter) error, options StreamBodyOptions) io.ReadCloser {
+	if options.FlushBytes <= 0 {
+		options.FlushBytes = 32 << 10
+	}
+	pr, pw := io.Pipe()
+	return &streamBody{produce: produce, options: options, pr: pr, pw: pw}
+}
+
+type streamBody struct {
+	produce func(w StreamWriter) error
+	options StreamBodyOptions
+	pr      *io.PipeReader
+	pw      *io.PipeWriter
+	once    sync.Once
+}
+
+// Read menjalankan produce pada pembacaan pertama.
+func (b *streamBody) Read(p []byte) (int, error) {
+	b.once.Do(func() { go b.run() })
+	return b.pr.Read(p)
+}
+
+// Close menghentikan produce; Write berikutnya mengembalikan io.ErrClosedPipe.
+func (b *streamBody) Close() error {
+	return b.pr.Close()
+}
+
+func (b *streamBody) run() {
+	w := &streamWriter{pw: b.pw, flushBytes: b.options.FlushBytes}
+	done := make(chan struct{})
+	if b.options.FlushInterval > 0 {
+		go func() {
+			ticker := time.NewTicker(b.options.FlushInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					w.Flush()
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	err := safeCall("stream body", func() error { return b.produce(w) })
+	close(done)
+	if err == nil {
+		err = w.Flush()
+	}
+	b.pw.CloseWithError(err)
+}
+
+// streamWriter menampung tulisan sampai flushBytes lalu menulisnya ke pipe
+// sekaligus, sehingga setiap flush menjadi satu chunk di transport.
+type streamWriter struct {
+	mu         sync.Mutex
+	buf        []byte
+	pw         *io.PipeWriter
+	flushBytes int
+}
+
+func (w *streamWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	if len(w.buf) >= w.flushBytes {
+		if err := w.flushLocked(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (w *streamWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.flushLocked()
+}
+
+func (w *streamWriter) flushLocked() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	_, err := w.pw.Write(w.buf)
+	w.buf = w.buf[:0]
+	return err
+}
+
+// replayableBody mengecek apakah body request bisa dikirim ulang untuk
+// retry atau replay setelah 401. Body io.Reader hanya bisa dibaca sekali.
+func replayableBody(options RequestOptions) bool {
+	_, streaming := options.RequestBody.(io.Reader)
+	return !streaming
+}
@@ -0,0 +1,91 @@
+package http_request_instant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStreamBody(t *testing.T) {
+	received := make(chan string, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/interactive" {
+			buf := make([]byte, 5)
+			io.ReadFull(r.Body, buf)
+			received <- string(buf)
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, "%v %d %d", r.TransferEncoding, r.ContentLength, len(body))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	body := StreamBody(func(w StreamWriter) error {
+		for i := 0; i < 1000; i++ {
+			fmt.Fprintf(w, "row %04d\n", i)
+		}
+		return nil
+	}, StreamBodyOptions{FlushBytes: 1024})
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "POST", URL: ts.URL, RequestBody: body})
+	if err != nil || string(resp.Body) != "[chunked] -1 9000" {
+		t.Fatalf("expected chunked 9000 bytes, got %q, %v", resp.Body, err)
+	}
+
+	// FlushInterval mengirim data walaupun FlushBytes belum tercapai
+	body = StreamBody(func(w StreamWriter) error {
+		w.Write([]byte("hello"))
+		select {
+		case <-received:
+			return nil
+		case <-time.After(2 * time.Second):
+			return errors.New("server did not receive flushed data")
+		}
+	}, StreamBodyOptions{FlushInterval: 10 * time.Millisecond})
+	resp, err = client.Request(context.TODO(), RequestOptions{Method: "POST", URL: ts.URL + "/interactive", RequestBody: body})
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Errorf("expected interval flush, got %v, %v", resp, err)
+	}
+}
+
+func TestStreamBodyErrors(t *testing.T) {
+	var hits atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if body, _ := io.ReadAll(r.Body); string(body) == "data" {
+			hits.Add(1)
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	failing := StreamBody(func(w StreamWriter) error {
+		w.Write([]byte("partial"))
+		w.Flush()
+		return errors.New("generator failed")
+	}, StreamBodyOptions{})
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "POST", URL: ts.URL, RequestBody: failing}); err == nil || !strings.Contains(err.Error(), "generator failed") {
+		t.Errorf("expected generator error, got %v", err)
+	}
+
+	// Body streaming tidak di-retry karena sudah terbaca
+	client.SetHostConfig("127.0.0.1", HostConfig{Retry: &RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}})
+	body := StreamBody(func(w StreamWriter) error {
+		_, err := w.Write([]byte("data"))
+		return err
+	}, StreamBodyOptions{})
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "POST", URL: ts.URL, RequestBody: body})
+	if err != nil || resp.StatusCode != http.StatusServiceUnavailable || hits.Load() != 1 {
+		t.Errorf("expected single attempt, got %v, %v, %d hits", resp, err, hits.Load())
+	}
+}
@@ -0,0 +1,99 @@
+package http_request_instant
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timings berisi durasi per fase satu percobaan request. Fase yang tidak
+// terjadi (mis. DNS dan Connect saat koneksi dipakai ulang) bernilai nol.
+type Timings struct {
+	DNS          time.Duration // Resolusi DNS
+	Connect      time.Duration // TCP connect
+	TLSHandshake time.Duration // TLS handshake
+	WaitForConn  time.Duration // Dari mulai sampai mendapat koneksi (termasuk DNS/Connect/TLS)
+	FirstByte    time.Duration // Dari request selesai ditulis sampai byte pertama response (waktu server)
+	Download     time.Duration // Dari byte pertama sampai body selesai dibaca
+	Total        time.Duration // Dari mulai sampai body selesai dibaca
+	ConnReused   bool          // Koneksi diambil dari pool
+}
+
+// timingsRecorder mengisi Timings dari callback httptrace.
+type timingsRecorder struct {
+	mu sync.Mutex
+
+	start, wroteRequest, firstByte   time.Time
+	dnsStart, connectStart, tlsStart time.Time
+	timings                          Timings
+}
+
+func newTimingsRecorder() *timingsRecorder {
+	return &timingsRecorder{start: time.Now()}
+}
+
+// trace memasang ClientTrace ke request.
+func (r *timingsRecorder) trace(req *http.Request) *http.Request {
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			r.locked(func(now time.Time) { r.dnsStart = now })
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			r.locked(func(now time.Time) { r.timings.DNS = now.Sub(r.dnsStart) })
+		},
+		ConnectStart: func(network, addr string) {
+			r.locked(func(now time.Time) { r.connectStart = now })
+		},
+		ConnectDone: func(network, addr string, err error) {
+			r.locked(func(now time.Time) { r.timings.Connect = now.Sub(r.connectStart) })
+		},
+		TLSHandshakeStart: func() {
+			r.locked(func(now time.Time) { r.tlsStart = now })
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			r.locked(func(now time.Time) { r.timings.TLSHandshake = now.Sub(r.tlsStart) })
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			r.locked(func(now time.Time) {
+				r.timings.ConnReused = info.Reused
+				r.timings.WaitForConn = now.Sub(r.start)
+			})
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			r.locked(func(now time.Time) { r.wroteRequest = now })
+		},
+		GotFirstResponseByte: func() {
+			r.locked(func(now time.Time) {
+				r.firstByte = now
+				if !r.wroteRequest.IsZero() {
+					r.timings.FirstByte = now.Sub(r.wroteRequest)
+				}
+			})
+		},
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+}
+
+// locked menjalankan fn di bawah lock; callback httptrace bisa datang dari
+// goroutine berbeda (mis. dial paralel Happy Eyeballs).
+func (r *timingsRecorder) locked(fn func(now time.Time)) {
+	now := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fn(now)
+}
+
+// finish menutup pengukuran setelah body selesai dibaca.
+func (r *timingsRecorder) finish() *Timings {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	r.timings.Total = now.Sub(r.start)
+	if !r.firstByte.IsZero() {
+		r.timings.Download = now.Sub(r.firstByte)
+	}
+	timings := r.timings
+	return &timings
+}
@@ -0,0 +1,58 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCaptureTimings(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.Client = ts.Client()
+	client.CaptureTimings = true
+
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tm := resp.Timings
+	if tm == nil {
+		t.Fatal("expected timings")
+	}
+	if tm.Connect <= 0 || tm.TLSHandshake <= 0 || tm.ConnReused {
+		t.Errorf("expected fresh TLS connection timings, got %+v", tm)
+	}
+	if tm.FirstByte < 20*time.Millisecond || tm.Total < tm.FirstByte {
+		t.Errorf("expected TTFB to include server delay, got %+v", tm)
+	}
+
+	// request kedua memakai koneksi dari pool
+	resp, err = client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Timings.ConnReused || resp.Timings.TLSHandshake != 0 {
+		t.Errorf("expected reused connection, got %+v", resp.Timings)
+	}
+}
+
+func TestTimingsDisabledByDefault(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	resp, err := NewHttpRequest().Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Timings != nil {
+		t.Errorf("expected no timings, got %+v", resp.Timings)
+	}
+}
@@ -0,0 +1,192 @@
+package http_request_instant
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// InsecureTLSEnv adalah environment variable yang bisa mengizinkan
+// InsecureSkipVerify tanpa acknowledge eksplisit di kode, mis. di CI atau dev.
+const InsecureTLSEnv = "HTTP_REQUEST_INSTANT_ALLOW_INSECURE_TLS"
+
+// ErrInsecureTLSNotAcknowledged dikembalikan ketika InsecureSkipVerify
+// diminta tanpa acknowledge maupun environment variable InsecureTLSEnv.
+var ErrInsecureTLSNotAcknowledged = errors.New("insecure TLS requested but not acknowledged: pass acknowledge=true or set " + InsecureTLSEnv + "=1")
+
+// SetClientCertificateFile memuat client certificate dan private key PEM
+// dari file untuk mutual TLS.
+func (c *HttpRequest) SetClientCertificateFile(certFile, keyFile string) error {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return fmt.Errorf("error load client certificate: %w", err)
+	}
+	return c.SetClientCertificates(cert)
+}
+
+// SetClientCertificatePEM memuat client certificate dan private key dari bytes PEM.
+func (c *HttpRequest) SetClientCertificatePEM(certPEM, keyPEM []byte) error {
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return fmt.Errorf("error parse client certificate: %w", err)
+	}
+	return c.SetClientCertificates(cert)
+}
+
+// SetClientCertificates memasang client certificate untuk mutual TLS.
+// Certificate sebelumnya akan diganti.
+func (c *HttpRequest) SetClientCertificates(certs ...tls.Certificate) error {
+	config, err := c.tlsConfig()
+	if err != nil {
+		return err
+	}
+	config.Certificates = certs
+	return nil
+}
+
+// AddRootCAFile menambahkan root CA dari file PEM di atas CA bawaan sistem.
+func (c *HttpRequest) AddRootCAFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error read CA file: %w", err)
+	}
+	return c.AddRootCAPEM(data)
+}
+
+// AddRootCAPEM menambahkan root CA dari bytes PEM di atas CA bawaan sistem.
+func (c *HttpRequest) AddRootCAPEM(pemCerts []byte) error {
+	config, err := c.tlsConfig()
+	if err != nil {
+		return err
+	}
+
+	pool := config.RootCAs
+	if pool == nil {
+		if pool, err = x509.SystemCertPool(); err != nil {
+			pool = x509.NewCertPool()
+		}
+	}
+	if !pool.AppendCertsFromPEM(pemCerts) {
+		return fmt.Errorf("no valid CA certificate found in PEM")
+	}
+	config.RootCAs = pool
+	return nil
+}
+
+// SetRootCAs mengganti seluruh root CA yang dipercaya client dengan pool.
+func (c *HttpRequest) SetRootCAs(pool *x509.CertPool) error {
+	config, err := c.tlsConfig()
+	if err != nil {
+		return err
+	}
+	config.RootCAs = pool
+	return nil
+}
+
+// TLSOptions menyimpan batasan versi dan algoritma TLS.
+// Field bernilai nol berarti memakai default Go.
+type TLSOptions struct {
+	MinVersion       uint16        // Mis. tls.VersionTLS12
+	MaxVersion       uint16        // Mis. tls.VersionTLS13
+	CipherSuites     []uint16      // Hanya berlaku untuk TLS 1.2 ke bawah
+	CurvePreferences []tls.CurveID // Mis. tls.X25519, tls.CurveP256
+}
+
+// SetTLSOptions mengatur versi TLS, cipher suite, dan curve yang diizinkan.
+// Cipher suite yang tidak dikenal ditolak supaya salah ketik tidak lolos diam-diam.
+func (c *HttpRequest) SetTLSOptions(options TLSOptions) error {
+	if options.MinVersion != 0 && options.MaxVersion != 0 && options.MinVersion > options.MaxVersion {
+		return fmt.Errorf("TLS MinVersion %s is greater than MaxVersion %s",
+			tls.VersionName(options.MinVersion), tls.VersionName(options.MaxVersion))
+	}
+
+	known := make(map[uint16]bool)
+	for _, suite := range tls.CipherSuites() {
+		known[suite.ID] = true
+	}
+	for _, suite := range tls.InsecureCipherSuites() {
+		known[suite.ID] = true
+	}
+	for _, id := range options.CipherSuites {
+		if !known[id] {
+			return fmt.Errorf("unknown TLS cipher suite: 0x%04x", id)
+		}
+	}
+
+	config, err := c.tlsConfig()
+	if err != nil {
+		return err
+	}
+	config.MinVersion = options.MinVersion
+	config.MaxVersion = options.MaxVersion
+	config.CipherSuites = options.CipherSuites
+	config.CurvePreferences = options.CurvePreferences
+	return nil
+}
+
+// EnableInsecureSkipVerify mematikan verifikasi certificate server untuk client ini saja.
+// Hanya aktif jika acknowledge bernilai true atau InsecureTLSEnv bernilai true,
+// supaya konfigurasi dev/test tidak terbawa diam-diam ke production.
+// Setiap aktivasi dicatat sebagai peringatan lewat Logger.
+func (c *HttpRequest) EnableInsecureSkipVerify(acknowledge bool) error {
+	if !acknowledge {
+		allowed, _ := strconv.ParseBool(os.Getenv(InsecureTLSEnv))
+		if !allowed {
+			return ErrInsecureTLSNotAcknowledged
+		}
+	}
+
+	config, err := c.tlsConfig()
+	if err != nil {
+		return err
+	}
+	config.InsecureSkipVerify = true
+
+	c.logger().Errorf("%s", strings.Join([]string{
+		"!!! [INSECURE TLS] ===========================================",
+		"!!! TLS certificate verification is DISABLED for this client.",
+		"!!! Connections are vulnerable to man-in-the-middle attacks.",
+		"!!! Never use this in production.",
+		"!!! =========================================================",
+	}, "\n"))
+	return nil
+}
+
+// DisableInsecureSkipVerify mengaktifkan kembali verifikasi certificate server.
+func (c *HttpRequest) DisableInsecureSkipVerify() error {
+	config, err := c.tlsConfig()
+	if err != nil {
+		return err
+	}
+	config.InsecureSkipVerify = false
+	return nil
+}
+
+// SetTLSServerName mengirim name sebagai SNI dan memverifikasi certificate
+// server terhadap name, bukan terhadap host di URL. Berguna untuk menguji
+// load balancer lewat IP-nya; pasangkan dengan RequestOptions.Host. Berlaku
+// untuk semua request https client ini; string kosong kembali memakai host URL.
+func (c *HttpRequest) SetTLSServerName(name string) error {
+	config, err := c.tlsConfig()
+	if err != nil {
+		return err
+	}
+	config.ServerName = name
+	return nil
+}
+
+// tlsConfig mengembalikan tls.Config milik transport client, membuatnya jika belum ada.
+func (c *HttpRequest) tlsConfig() (*tls.Config, error) {
+	t, err := c.transport()
+	if err != nil {
+		return nil, err
+	}
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{}
+	}
+	return t.TLSClientConfig, nil
+}
@@ -0,0 +1,262 @@
+package http_request_instant
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// generateTestCertificate membuat self-signed certificate beserta PEM-nya.
+func generateTestCertificate(t *testing.T, commonName string) (tls.Certificate, []byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{commonName},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	keyDER, _ := x509.MarshalPKCS8PrivateKey(key)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return cert, certPEM, keyPEM
+}
+
+func TestMutualTLS(t *testing.T) {
+	clientCert, certPEM, keyPEM := generateTestCertificate(t, "client")
+
+	pool := x509.NewCertPool()
+	pool.AddCert(clientCert.Leaf)
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	ts.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+	ts.StartTLS()
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	if err := client.SetClientCertificatePEM(certPEM, keyPEM); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.AddRootCAPEM(certToPEM(ts.Certificate())); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(resp.Body) != "client" {
+		t.Errorf("expected client cert CN=client, got %s", string(resp.Body))
+	}
+
+	// tanpa client certificate handshake harus gagal
+	plain := NewHttpRequest()
+	_ = plain.AddRootCAPEM(certToPEM(ts.Certificate()))
+	if _, err := plain.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err == nil {
+		t.Fatal("expected handshake error without client certificate, got nil")
+	}
+}
+
+func TestSetClientCertificateFileMissing(t *testing.T) {
+	client := NewHttpRequest()
+	if err := client.SetClientCertificateFile("missing.crt", "missing.key"); err == nil {
+		t.Fatal("expected error for missing files, got nil")
+	}
+}
+
+func certToPEM(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+}
+
+func TestCustomRootCA(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	defer ts.Close()
+
+	// tanpa CA tambahan certificate server tidak dipercaya
+	client := NewHttpRequest()
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err == nil {
+		t.Fatal("expected unknown authority error, got nil")
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, certToPEM(ts.Certificate()), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.AddRootCAFile(caFile); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSetRootCAsAndInvalidPEM(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	defer ts.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+
+	client := NewHttpRequest()
+	if err := client.SetRootCAs(pool); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.AddRootCAPEM([]byte("not a certificate")); err == nil {
+		t.Fatal("expected error for invalid PEM, got nil")
+	}
+}
+
+func TestTLSOptions(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(tls.CipherSuiteName(r.TLS.CipherSuite)))
+	}))
+	ts.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	ts.StartTLS()
+	defer ts.Close()
+
+	// server hanya TLS 1.2, client mewajibkan TLS 1.3
+	strict := NewHttpRequest()
+	_ = strict.AddRootCAPEM(certToPEM(ts.Certificate()))
+	if err := strict.SetTLSOptions(TLSOptions{MinVersion: tls.VersionTLS13}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := strict.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err == nil {
+		t.Fatal("expected protocol version error, got nil")
+	}
+
+	client := NewHttpRequest()
+	_ = client.AddRootCAPEM(certToPEM(ts.Certificate()))
+	err := client.SetTLSOptions(TLSOptions{
+		MinVersion:       tls.VersionTLS12,
+		CipherSuites:     []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+		CurvePreferences: []tls.CurveID{tls.CurveP256},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(resp.Body) != "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256" {
+		t.Errorf("unexpected cipher suite: %s", string(resp.Body))
+	}
+}
+
+func TestTLSOptionsValidation(t *testing.T) {
+	client := NewHttpRequest()
+	if err := client.SetTLSOptions(TLSOptions{MinVersion: tls.VersionTLS13, MaxVersion: tls.VersionTLS12}); err == nil {
+		t.Error("expected error for min > max, got nil")
+	}
+	if err := client.SetTLSOptions(TLSOptions{CipherSuites: []uint16{0xffff}}); err == nil {
+		t.Error("expected error for unknown cipher suite, got nil")
+	}
+}
+
+func TestInsecureSkipVerifyGuardrail(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	defer ts.Close()
+
+	t.Setenv(InsecureTLSEnv, "")
+	client := NewHttpRequest()
+	if err := client.EnableInsecureSkipVerify(false); !errors.Is(err, ErrInsecureTLSNotAcknowledged) {
+		t.Fatalf("expected ErrInsecureTLSNotAcknowledged, got %v", err)
+	}
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err == nil {
+		t.Fatal("expected certificate error while insecure mode is refused, got nil")
+	}
+
+	if err := client.EnableInsecureSkipVerify(true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// environment variable juga bisa menjadi acknowledge
+	t.Setenv(InsecureTLSEnv, "1")
+	other := NewHttpRequest()
+	if err := other.EnableInsecureSkipVerify(false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := other.DisableInsecureSkipVerify(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := other.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err == nil {
+		t.Fatal("expected certificate error after disabling insecure mode, got nil")
+	}
+}
+
+func TestHostAndServerNameOverride(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.ServerName + " " + r.Host))
+	}))
+	defer ts.Close()
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+
+	// Certificate httptest berlaku untuk example.com; koneksi tetap ke 127.0.0.1
+	client := NewHttpRequest()
+	client.SetRootCAs(pool)
+	if err := client.SetTLSServerName("example.com"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := client.Request(context.Background(), RequestOptions{Method: "GET", URL: ts.URL, Host: "api.example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(resp.Body) != "example.com api.example.com" {
+		t.Errorf("expected SNI example.com and Host api.example.com, got %q", resp.Body)
+	}
+
+	resp, err = client.Request(context.Background(), RequestOptions{Method: "GET", URL: ts.URL, Headers: map[string]string{"Host": "vhost.internal"}})
+	if err != nil || string(resp.Body) != "example.com vhost.internal" {
+		t.Errorf("expected Host header from Headers, got %v, %v", resp, err)
+	}
+
+	mismatched := NewHttpRequest()
+	mismatched.SetRootCAs(pool)
+	mismatched.SetTLSServerName("other.test")
+	if _, err := mismatched.Request(context.Background(), RequestOptions{Method: "GET", URL: ts.URL}); err == nil {
+		t.Errorf("expected certificate verification to fail for other.test")
+	}
+}
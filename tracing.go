@@ -0,0 +1,74 @@
+package http_request_instant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// Tracer membuat span untuk request, mis. adapter tipis ke OpenTelemetry
+// (trace.Tracer). Library ini tidak bergantung langsung pada SDK tracing.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span adalah satu unit kerja yang di-trace.
+type Span interface {
+	SetAttribute(key string, value any)
+	RecordError(err error)
+	End()
+}
+
+// SpanStatusSetter opsional diimplementasikan Span untuk menandai span error.
+type SpanStatusSetter interface {
+	SetError(description string)
+}
+
+// setRequestSpanAttributes mengisi atribut request sesuai semantic
+// convention HTTP OpenTelemetry. Query string tidak ikut dicatat.
+func setRequestSpanAttributes(span Span, options RequestOptions) {
+	span.SetAttribute("http.request.method", options.Method)
+	if u, err := url.Parse(options.URL); err == nil {
+		u.RawQuery = ""
+		u.User = nil
+		span.SetAttribute("url.full", u.String())
+		span.SetAttribute("server.address", u.Hostname())
+		if port := u.Port(); port != "" {
+			span.SetAttribute("server.port", port)
+		}
+	}
+}
+
+// endSpan mencatat status code atau error hasil request ke span.
+func endSpan(span Span, resp *ApiResponse, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetAttribute("error.type", errorType(err))
+		if setter, ok := span.(SpanStatusSetter); ok {
+			setter.SetError(err.Error())
+		}
+		return
+	}
+	span.SetAttribute("http.response.status_code", resp.StatusCode)
+	if resp.StatusCode >= 500 {
+		span.SetAttribute("error.type", fmt.Sprint(resp.StatusCode))
+		if setter, ok := span.(SpanStatusSetter); ok {
+			setter.SetError(fmt.Sprintf("HTTP %d", resp.StatusCode))
+		}
+	}
+}
+
+func errorType(err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	}
+	// Pakai tipe error paling dalam, mis. *net.OpError
+	for next := errors.Unwrap(err); next != nil; next = errors.Unwrap(err) {
+		err = next
+	}
+	return fmt.Sprintf("%T", err)
+}
@@ -0,0 +1,133 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordTracer menyimpan span beserta parent-nya.
+type recordTracer struct {
+	mu    sync.Mutex
+	spans []*recordSpan
+}
+
+type recordSpan struct {
+	name   string
+	parent *recordSpan
+	attrs  map[string]any
+	errs   []error
+	ended  bool
+}
+
+type spanKey struct{}
+
+func (t *recordTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	parent, _ := ctx.Value(spanKey{}).(*recordSpan)
+	span := &recordSpan{name: name, parent: parent, attrs: map[string]any{}}
+	t.mu.Lock()
+	t.spans = append(t.spans, span)
+	t.mu.Unlock()
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (s *recordSpan) SetAttribute(key string, value any) { s.attrs[key] = value }
+func (s *recordSpan) RecordError(err error)              { s.errs = append(s.errs, err) }
+func (s *recordSpan) End()                               { s.ended = true }
+
+func TestTracerSpanPerAttempt(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	tracer := &recordTracer{}
+	client := NewHttpRequest()
+	client.Tracer = tracer
+	client.OnUnauthorized = func(ctx context.Context, options *RequestOptions) error { return nil }
+
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL + "/users?secret=1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(tracer.spans) != 3 {
+		t.Fatalf("expected parent + 2 attempt spans, got %d", len(tracer.spans))
+	}
+	parent, first, retry := tracer.spans[0], tracer.spans[1], tracer.spans[2]
+	if parent.parent != nil || first.parent != parent || retry.parent != parent {
+		t.Errorf("attempt spans should be children of the request span")
+	}
+	if parent.attrs["http.response.status_code"] != 200 || first.attrs["http.response.status_code"] != 401 {
+		t.Errorf("unexpected status attributes: %v / %v", parent.attrs, first.attrs)
+	}
+	if retry.attrs["http.request.resend_count"] != 1 {
+		t.Errorf("expected resend_count on retry span, got %v", retry.attrs)
+	}
+	if parent.attrs["url.full"] != ts.URL+"/users" || parent.attrs["http.request.method"] != "GET" {
+		t.Errorf("unexpected request attributes: %v", parent.attrs)
+	}
+	for _, span := range tracer.spans {
+		if !span.ended {
+			t.Errorf("span %s not ended", span.name)
+		}
+	}
+}
+
+func TestTracerRetryPolicyAttemptsShareRequestSpan(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	tracer := &recordTracer{}
+	client := NewHttpRequest()
+	client.Tracer = tracer
+	client.SetHostConfig("127.0.0.1", HostConfig{Retry: &RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}})
+
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tracer.spans) != 4 {
+		t.Fatalf("expected parent + 3 attempt spans, got %d", len(tracer.spans))
+	}
+	parent := tracer.spans[0]
+	if parent.parent != nil || parent.attrs["http.response.status_code"] != 200 {
+		t.Errorf("unexpected request span: %+v", parent)
+	}
+	for i, span := range tracer.spans[1:] {
+		if span.parent != parent {
+			t.Errorf("attempt %d should be a child of the request span", i+1)
+		}
+		if i > 0 && span.attrs["http.request.resend_count"] != i {
+			t.Errorf("attempt %d: expected resend_count %d, got %v", i+1, i, span.attrs)
+		}
+	}
+}
+
+func TestTracerRecordsError(t *testing.T) {
+	tracer := &recordTracer{}
+	client := NewHttpRequest()
+	client.Tracer = tracer
+
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: "http://127.0.0.1:1"}); err == nil {
+		t.Fatal("expected connection error")
+	}
+	if len(tracer.spans[0].errs) != 1 || tracer.spans[0].attrs["error.type"] == nil {
+		t.Errorf("expected error recorded on span, got %+v", tracer.spans[0])
+	}
+}
@@ -0,0 +1,73 @@
+package http_request_instant
+
+import (
+	"errors"
+	"io"
+	"net/http"
+)
+
+// applyRequestTrailers mendeklarasikan trailer request dan memaksa body
+// dikirim chunked, karena trailer hanya bisa dikirim setelah chunk terakhir.
+// Nilai trailer dibaca dari map setelah body habis, jadi producer StreamBody
+// boleh mengisinya selama streaming, mis. checksum body.
+func applyRequestTrailers(req *http.Request, trailers map[string]string) error {
+	if len(trailers) == 0 {
+		return nil
+	}
+	if req.Body == nil || req.Body == http.NoBody {
+		return errors.New("error set trailers: request trailers require a request body")
+	}
+
+	req.Trailer = make(http.Header, len(trailers))
+	for key := range trailers {
+		req.Trailer[http.CanonicalHeaderKey(key)] = nil
+	}
+	req.ContentLength = -1
+
+	wrap := func(body io.ReadCloser) io.ReadCloser {
+		return &trailerBody{ReadCloser: body, trailer: req.Trailer, values: trailers}
+	}
+	req.Body = wrap(req.Body)
+	if getBody := req.GetBody; getBody != nil {
+		req.GetBody = func() (io.ReadCloser, error) {
+			body, err := getBody()
+			if err != nil {
+				return nil, err
+			}
+			return wrap(body), nil
+		}
+	}
+	return nil
+}
+
+// trailerBody mengisi nilai trailer saat body mencapai EOF.
+type trailerBody struct {
+	io.ReadCloser
+	trailer http.Header
+	values  map[string]string
+}
+
+func (b *trailerBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		for key, value := range b.values {
+			b.trailer.Set(key, value)
+		}
+	}
+	return n, err
+}
+
+// responseTrailers menyalin trailer response ke map seperti Headers.
+// Trailer baru lengkap setelah body dibaca sampai habis.
+func responseTrailers(resp *http.Response) map[string]string {
+	if len(resp.Trailer) == 0 {
+		return nil
+	}
+	trailers := make(map[string]string, len(resp.Trailer))
+	for k, v := range resp.Trailer {
+		if len(v) > 0 {
+			trailers[k] = v[0]
+		}
+	}
+	return trailers
+}
@@ -0,0 +1,65 @@
+package http_request_instant
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTrailers(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sum := sha256.Sum256(body)
+		w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+		fmt.Fprintf(w, "%v|%s|%s", r.TransferEncoding, r.Trailer.Get("X-Checksum"), hex.EncodeToString(sum[:]))
+		w.Header().Set("Grpc-Status", "5")
+		w.Header().Set("Grpc-Message", "not found")
+	}))
+	defer ts.Close()
+	client := NewHttpRequest()
+
+	resp, err := client.Request(context.TODO(), RequestOptions{
+		Method:      "POST",
+		URL:         ts.URL,
+		RequestBody: "payload",
+		Trailers:    map[string]string{"x-checksum": "static"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sum := sha256.Sum256([]byte("payload"))
+	if want := "[chunked]|static|" + hex.EncodeToString(sum[:]); string(resp.Body) != want {
+		t.Errorf("expected body %q, got %q", want, resp.Body)
+	}
+	if resp.Trailers["Grpc-Status"] != "5" || resp.Trailers["Grpc-Message"] != "not found" {
+		t.Errorf("expected grpc trailers, got %v", resp.Trailers)
+	}
+
+	// Nilai trailer diisi producer setelah seluruh body ditulis
+	trailers := map[string]string{"X-Checksum": ""}
+	body := StreamBody(func(w StreamWriter) error {
+		hash := sha256.New()
+		out := io.MultiWriter(w, hash)
+		for i := 0; i < 100; i++ {
+			fmt.Fprintf(out, "line %d\n", i)
+		}
+		trailers["X-Checksum"] = hex.EncodeToString(hash.Sum(nil))
+		return nil
+	}, StreamBodyOptions{FlushBytes: 128})
+	resp, err = client.Request(context.TODO(), RequestOptions{Method: "POST", URL: ts.URL, RequestBody: body, Trailers: trailers})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "[chunked]|" + trailers["X-Checksum"] + "|" + trailers["X-Checksum"]; string(resp.Body) != want {
+		t.Errorf("expected checksum trailer to match body, got %q", resp.Body)
+	}
+
+	if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL, Trailers: map[string]string{"X-Checksum": "x"}}); err == nil {
+		t.Errorf("expected error for trailers without body")
+	}
+}
@@ -0,0 +1,76 @@
+package http_request_instant
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ResponseTransformer mengolah body response mentah sebelum di-decode ke
+// ResponseTarget, mis. membuka envelope {"data": ...} milik vendor. Body
+// yang dikembalikan menggantikan ApiResponse.Body.
+type ResponseTransformer func(ctx context.Context, resp *ApiResponse) ([]byte, error)
+
+// AddResponseTransformer mendaftarkan transformer untuk semua response client.
+// Daftarkan transformer sebelum client dipakai secara konkuren.
+func (c *HttpRequest) AddResponseTransformer(transformers ...ResponseTransformer) {
+	c.transformers = append(c.transformers, transformers...)
+}
+
+// UnwrapJSONEnvelope mengganti body dengan nilai pada path bertitik, mis.
+// "data" atau "result.items". Response 4xx/5xx dan body kosong dibiarkan
+// apa adanya supaya pesan error vendor tetap terbaca.
+func UnwrapJSONEnvelope(path string) ResponseTransformer {
+	return func(ctx context.Context, resp *ApiResponse) ([]byte, error) {
+		if resp.StatusCode >= 400 || len(bytes.TrimSpace(resp.Body)) == 0 {
+			return resp.Body, nil
+		}
+
+		body, err := lookupJSONPath(resp.Body, path)
+		if err != nil {
+			return nil, fmt.Errorf("envelope %w", err)
+		}
+		return body, nil
+	}
+}
+
+// lookupJSONPath mengambil nilai pada path bertitik, mis. "result.items".
+// Path kosong mengembalikan body apa adanya.
+func lookupJSONPath(body []byte, path string) (json.RawMessage, error) {
+	value := json.RawMessage(body)
+	if path == "" {
+		return value, nil
+	}
+	for _, key := range strings.Split(path, ".") {
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(value, &object); err != nil {
+			return nil, fmt.Errorf("%q: %w", path, err)
+		}
+		field, ok := object[key]
+		if !ok {
+			return nil, fmt.Errorf("%q: field %q not found", path, key)
+		}
+		value = field
+	}
+	return value, nil
+}
+
+// transformResponse menjalankan transformer client lalu transformer per request.
+func (c *HttpRequest) transformResponse(ctx context.Context, resp *ApiResponse, options RequestOptions) error {
+	for _, transformers := range [][]ResponseTransformer{c.transformers, options.Transformers} {
+		for _, transform := range transformers {
+			var body []byte
+			err := safeCall("response transformer", func() (err error) {
+				body, err = transform(ctx, resp)
+				return err
+			})
+			if err != nil {
+				return &DecodeError{ContentType: resp.Headers["Content-Type"], Err: fmt.Errorf("error transform response: %w", err)}
+			}
+			resp.Body = body
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResponseTransformersUnwrapEnvelope(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"meta":{"page":1},"data":{"result":{"id":7,"name":"widget"}}}`))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.AddResponseTransformer(UnwrapJSONEnvelope("data"))
+
+	var item struct {
+		ID   int
+		Name string
+	}
+	resp, err := client.Request(context.TODO(), RequestOptions{
+		Method:         "GET",
+		URL:            ts.URL + "/items/7",
+		ResponseTarget: &item,
+		Transformers:   []ResponseTransformer{UnwrapJSONEnvelope("result")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if item.ID != 7 || item.Name != "widget" || string(resp.Body) != `{"id":7,"name":"widget"}` {
+		t.Errorf("unexpected unwrapped response: %+v, body %s", item, resp.Body)
+	}
+
+	resp, err = client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL + "/missing"})
+	if err != nil || string(resp.Body) != `{"error":"not found"}` {
+		t.Errorf("expected error response left untouched, got %v %v", resp, err)
+	}
+
+	_, err = client.Request(context.TODO(), RequestOptions{
+		Method:       "GET",
+		URL:          ts.URL,
+		Transformers: []ResponseTransformer{UnwrapJSONEnvelope("payload")},
+	})
+	if Category(err) != CategoryDecode {
+		t.Errorf("expected decode error for missing envelope field, got %v", err)
+	}
+}
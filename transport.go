@@ -0,0 +1,274 @@
+package http_request_instant
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+)
+
+var errCustomTransport = errors.New("client transport is not an *http.Transport")
+
+// Timeouts menyimpan konfigurasi timeout per fase request.
+// Nilai 0 berarti fase tersebut tidak dibatasi.
+type Timeouts struct {
+	Dial           time.Duration // DNS lookup + TCP connect
+	TLSHandshake   time.Duration // TLS handshake
+	ResponseHeader time.Duration // Menunggu header response setelah request terkirim
+	Total          time.Duration // Keseluruhan request termasuk membaca body (http.Client.Timeout)
+}
+
+// SetTimeouts mengatur timeout per fase. Dengan Total = 0, download besar
+// tetap bisa selesai walaupun fase connect dibatasi singkat.
+func (c *HttpRequest) SetTimeouts(timeouts Timeouts) error {
+	t, err := c.transport()
+	if err != nil {
+		return err
+	}
+
+	c.netDialer().Timeout = timeouts.Dial
+	t.DialContext = c.dialContext
+	t.TLSHandshakeTimeout = timeouts.TLSHandshake
+	t.ResponseHeaderTimeout = timeouts.ResponseHeader
+	c.Client.Timeout = timeouts.Total
+	return nil
+}
+
+// TCPKeepAliveOptions mengatur probe TCP keep-alive pada koneksi baru,
+// supaya koneksi yang putus diam-diam (NAT, load balancer) cepat terdeteksi.
+// Nilai 0 memakai default Go/OS.
+type TCPKeepAliveOptions struct {
+	Disabled bool          // Matikan probe keep-alive
+	Idle     time.Duration // Lama koneksi idle sebelum probe pertama, default 15 detik
+	Interval time.Duration // Jeda antar probe, default 15 detik
+	Count    int           // Probe tanpa balasan sebelum koneksi ditutup, default 9
+}
+
+// SetTCPKeepAlive mengatur probe TCP keep-alive tanpa harus membuat
+// Transport sendiri. Berbeda dengan keep-alive HTTP (pemakaian ulang koneksi).
+func (c *HttpRequest) SetTCPKeepAlive(options TCPKeepAliveOptions) error {
+	t, err := c.transport()
+	if err != nil {
+		return err
+	}
+
+	dialer := c.netDialer()
+	if options.Disabled {
+		dialer.KeepAlive = -1
+		dialer.KeepAliveConfig = net.KeepAliveConfig{}
+	} else {
+		dialer.KeepAliveConfig = net.KeepAliveConfig{
+			Enable:   true,
+			Idle:     options.Idle,
+			Interval: options.Interval,
+			Count:    options.Count,
+		}
+	}
+	t.DialContext = c.dialContext
+	return nil
+}
+
+// SetMultipathTCP mengaktifkan Multipath TCP (RFC 8684) untuk koneksi baru
+// supaya satu koneksi bisa memakai beberapa uplink sekaligus. Di platform
+// atau kernel tanpa dukungan MPTCP, atau jika server tidak mendukungnya,
+// koneksi otomatis turun ke TCP biasa.
+func (c *HttpRequest) SetMultipathTCP(enabled bool) error {
+	t, err := c.transport()
+	if err != nil {
+		return err
+	}
+	c.netDialer().SetMultipathTCP(enabled)
+	t.DialContext = c.dialContext
+	return nil
+}
+
+// NewHttpRequestWithTransport membuat HttpRequest dengan RoundTripper
+// sendiri, mis. transport korporat atau transport caching. Jika rt nil,
+// sama dengan NewHttpRequest.
+func NewHttpRequestWithTransport(rt http.RoundTripper) *HttpRequest {
+	c := NewHttpRequest()
+	if rt != nil {
+		c.Client.Transport = rt
+	}
+	return c
+}
+
+// WrapTransport membungkus transport client, mis. dengan otelhttp.NewTransport.
+// Wrapper tidak perlu punya method Unwrap: setter transport lain (SetTimeouts,
+// SetProxy, dll.) tetap bisa menemukan *http.Transport di bawahnya.
+func (c *HttpRequest) WrapTransport(wrap func(next http.RoundTripper) http.RoundTripper) {
+	if c.Client == nil || c.Client.Transport == nil {
+		// pasang transport milik client supaya wrapper tidak membungkus transport global
+		_, _ = c.transport()
+	}
+	next := c.Client.Transport
+	c.Client.Transport = &wrappedTransport{RoundTripper: wrap(next), next: next, wrap: wrap}
+}
+
+// wrappedTransport menyimpan RoundTripper yang dibungkus WrapTransport
+// supaya transport() tetap bisa menelusurinya.
+type wrappedTransport struct {
+	http.RoundTripper
+	next http.RoundTripper
+	wrap func(next http.RoundTripper) http.RoundTripper
+}
+
+// Unwrap mengembalikan RoundTripper sebelum dibungkus.
+func (t *wrappedTransport) Unwrap() http.RoundTripper {
+	return t.next
+}
+
+func (t *wrappedTransport) rewrap(next http.RoundTripper) http.RoundTripper {
+	return &wrappedTransport{RoundTripper: t.wrap(next), next: next, wrap: t.wrap}
+}
+
+// rewrappedTransport menjalankan wrapper yang menyimpan state (mis.
+// FaultInjector) di atas next yang berbeda.
+type rewrappedTransport struct {
+	next      http.RoundTripper
+	roundTrip func(next http.RoundTripper, req *http.Request) (*http.Response, error)
+}
+
+// RoundTrip mengimplementasikan http.RoundTripper.
+func (t *rewrappedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.roundTrip(t.next, req)
+}
+
+// Unwrap mengembalikan RoundTripper yang dibungkus.
+func (t *rewrappedTransport) Unwrap() http.RoundTripper {
+	return t.next
+}
+
+func (t *rewrappedTransport) rewrap(next http.RoundTripper) http.RoundTripper {
+	return &rewrappedTransport{next: next, roundTrip: t.roundTrip}
+}
+
+// rewrapper adalah wrapper transport client yang bisa dipasang ulang di
+// atas RoundTripper lain, mis. clone transport untuk TransportProfile.
+type rewrapper interface {
+	rewrap(next http.RoundTripper) http.RoundTripper
+}
+
+// rewrapTransport memasang ulang wrapper transport client (WrapTransport,
+// fault injection, cassette, NTLM, Negotiate) di atas base dengan urutan
+// yang sama. Wrapper lain yang hanya punya Unwrap dilewati.
+func (c *HttpRequest) rewrapTransport(base http.RoundTripper) http.RoundTripper {
+	var layers []rewrapper
+	rt := c.Client.Transport
+	for {
+		if w, ok := rt.(rewrapper); ok {
+			layers = append(layers, w)
+		}
+		u, ok := rt.(interface{ Unwrap() http.RoundTripper })
+		if !ok {
+			break
+		}
+		rt = u.Unwrap()
+	}
+	for i := len(layers) - 1; i >= 0; i-- {
+		base = layers[i].rewrap(base)
+	}
+	return base
+}
+
+// transport mengembalikan *http.Transport milik client. Jika belum ada,
+// clone dari http.DefaultTransport dipasang. Transport yang dibungkus
+// RoundTripper lain tetap bisa ditemukan selama wrapper punya method Unwrap.
+func (c *HttpRequest) transport() (*http.Transport, error) {
+	if c.Client == nil {
+		c.Client = &http.Client{}
+	}
+
+	rt := c.Client.Transport
+	if rt == nil {
+		t := c.newTransport()
+		c.Client.Transport = t
+		return t, nil
+	}
+
+	for {
+		switch v := rt.(type) {
+		case *http.Transport:
+			return v, nil
+		case interface{ Unwrap() http.RoundTripper }:
+			rt = v.Unwrap()
+		default:
+			return nil, errCustomTransport
+		}
+	}
+}
+
+// newTransport membuat clone http.DefaultTransport dengan proxy dan dialer
+// yang bisa diatur per client maupun per request.
+func (c *HttpRequest) newTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = c.proxyFunc
+	t.DialContext = c.dialContext
+	return t
+}
+
+// netDialer mengembalikan net.Dialer milik client dengan default
+// yang sama seperti http.DefaultTransport.
+func (c *HttpRequest) netDialer() *net.Dialer {
+	// Dial pertama bisa terjadi bersamaan di beberapa goroutine
+	c.dialerOnce.Do(func() {
+		c.dialer = &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}
+	})
+	return c.dialer
+}
+
+// dialContext dipasang sebagai Transport.DialContext.
+func (c *HttpRequest) dialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	if socket := c.unixSocketPath(ctx); socket != "" {
+		if c.ssrf != nil {
+			if err := unixSocketURLError(ctx); err != nil {
+				return nil, err
+			}
+		}
+		network, addr = "unix", socket
+	} else {
+		if conn := c.warm.take(addr); conn != nil {
+			// Koneksi dari Preconnect sudah melewati dial di bawah
+			return conn, nil
+		}
+		if c.ssrf != nil {
+			ctx = ssrfDialContext(ctx, addr)
+		}
+	}
+	var conn net.Conn
+	var err error
+	host, _, _ := net.SplitHostPort(addr)
+	if hops := c.proxyChainFor(ctx, host); hops != nil && network != "unix" {
+		conn, err = c.dialProxyChain(ctx, hops, addr)
+	} else {
+		conn, err = c.dialFamily(ctx, network, addr)
+	}
+	if c.pool != nil {
+		conn, err = c.pool.wrap(addr, conn, err)
+	}
+	if c.countBytes && err == nil {
+		conn = &countingConn{Conn: conn}
+	}
+	if c.recycle != nil && err == nil {
+		conn = newRecycledConn(conn, *c.recycle)
+	}
+	return conn, err
+}
+
+// dialAddr men-dial addr lewat cache DNS jika aktif.
+func (c *HttpRequest) dialAddr(ctx context.Context, network, addr string) (net.Conn, error) {
+	if network == "unix" && c.netDialer().LocalAddr != nil {
+		// LocalAddr TCP dari SetLocalAddr tidak berlaku untuk Unix socket
+		dialer := *c.netDialer()
+		dialer.LocalAddr = nil
+		return dialer.DialContext(ctx, network, addr)
+	}
+	if c.dnsCache != nil {
+		return c.dnsCache.dial(ctx, c.netDialer(), network, addr)
+	}
+	return c.netDialer().DialContext(ctx, network, addr)
+}
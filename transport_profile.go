@@ -0,0 +1,72 @@
+package http_request_instant
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// TransportProfile adalah transport alternatif bernama yang bisa dipilih per
+// request lewat RequestOptions.TransportProfile, mis. "no-proxy" atau
+// "long-download", tanpa membuat client kedua.
+type TransportProfile struct {
+	// Optional: RoundTripper sendiri. Jika nil, transport client di-clone
+	// lalu diubah lewat Configure, sehingga dialer, cache DNS, proteksi SSRF,
+	// dan TLS config client tetap berlaku. Wrapper transport client
+	// (WrapTransport, EnableFaultInjection, UseCassette, NTLM, Negotiate)
+	// dipasang ulang di atas clone tersebut.
+	Transport http.RoundTripper
+
+	// Optional: mengubah clone transport client, mis. t.Proxy = nil.
+	Configure func(t *http.Transport)
+
+	// Batas waktu total request. 0 berarti ikut Client.Timeout, negatif
+	// berarti tanpa batas total.
+	Timeout time.Duration
+}
+
+// SetTransportProfile mendaftarkan profile dengan nama name. Clone transport
+// dibuat saat pendaftaran, jadi setter transport yang dipanggil setelahnya
+// tidak ikut berlaku untuk profile ini. Error jika Transport nil dan
+// transport client bukan *http.Transport.
+func (c *HttpRequest) SetTransportProfile(name string, profile TransportProfile) error {
+	rt := profile.Transport
+	if rt == nil {
+		t, err := c.transport()
+		if err != nil {
+			return err
+		}
+		clone := t.Clone()
+		if profile.Configure != nil {
+			profile.Configure(clone)
+		}
+		rt = c.rewrapTransport(clone)
+	}
+
+	client := *c.Client
+	client.Transport = rt
+	switch {
+	case profile.Timeout > 0:
+		client.Timeout = profile.Timeout
+	case profile.Timeout < 0:
+		client.Timeout = 0
+	}
+	if c.transportProfiles == nil {
+		c.transportProfiles = make(map[string]*http.Client)
+	}
+	c.transportProfiles[name] = &client
+	return nil
+}
+
+// httpClient mengembalikan http.Client untuk profile request, atau
+// c.Client jika profile kosong.
+func (c *HttpRequest) httpClient(options RequestOptions) (*http.Client, error) {
+	if options.TransportProfile == "" {
+		return c.Client, nil
+	}
+	client, ok := c.transportProfiles[options.TransportProfile]
+	if !ok {
+		return nil, fmt.Errorf("unknown transport profile %q", options.TransportProfile)
+	}
+	return client, nil
+}
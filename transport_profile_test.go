@@ -0,0 +1,101 @@
+package http_request_instant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTransportProfile(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.Write([]byte("direct"))
+	}))
+	defer ts.Close()
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("proxied"))
+	}))
+	defer proxy.Close()
+
+	client := NewHttpRequest()
+	client.SetProxy(proxy.URL)
+	client.Client.Timeout = 100 * time.Millisecond
+	if err := client.SetTransportProfile("no-proxy", TransportProfile{Configure: func(t *http.Transport) { t.Proxy = nil }}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.SetTransportProfile("long-download", TransportProfile{Configure: func(t *http.Transport) { t.Proxy = nil }, Timeout: -1})
+	client.SetTransportProfile("stub", TransportProfile{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusTeapot, Body: http.NoBody, Header: http.Header{}}, nil
+	})})
+
+	get := func(path, profile string) (*ApiResponse, error) {
+		return client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL + path, TransportProfile: profile})
+	}
+	if resp, err := get("/", ""); err != nil || string(resp.Body) != "proxied" {
+		t.Errorf("expected default transport to use proxy, got %v, %v", resp, err)
+	}
+	if resp, err := get("/", "no-proxy"); err != nil || string(resp.Body) != "direct" {
+		t.Errorf("expected no-proxy profile to go direct, got %v, %v", resp, err)
+	}
+	if _, err := get("/slow", "no-proxy"); err == nil {
+		t.Errorf("expected client timeout to apply to no-proxy profile")
+	}
+	if resp, err := get("/slow", "long-download"); err != nil || string(resp.Body) != "direct" {
+		t.Errorf("expected long-download profile without timeout, got %v, %v", resp, err)
+	}
+	if resp, err := get("/", "stub"); err != nil || resp.StatusCode != http.StatusTeapot {
+		t.Errorf("expected custom RoundTripper profile, got %v, %v", resp, err)
+	}
+	if _, err := get("/", "missing"); err == nil || !strings.Contains(err.Error(), `unknown transport profile "missing"`) {
+		t.Errorf("expected unknown profile error, got %v", err)
+	}
+}
+
+func TestTransportProfileKeepsClientWrappers(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	var wrapped []string
+	client.WrapTransport(func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			wrapped = append(wrapped, r.URL.Path)
+			return next.RoundTrip(r)
+		})
+	})
+	injector := client.EnableFaultInjection(FaultConfig{
+		ErrorProbability: 1,
+		Match:            func(r *http.Request) bool { return r.URL.Path == "/fault" },
+	})
+	if err := client.SetTransportProfile("no-proxy", TransportProfile{Configure: func(t *http.Transport) { t.Proxy = nil }}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	get := func(path string) (*ApiResponse, error) {
+		return client.Request(context.TODO(), RequestOptions{Method: "GET", URL: ts.URL + path, TransportProfile: "no-proxy"})
+	}
+	if resp, err := get("/ok"); err != nil || string(resp.Body) != "ok" {
+		t.Fatalf("unexpected response: %v, %v", resp, err)
+	}
+	if resp, err := get("/fault"); err != nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected fault injector to handle profile request, got %v, %v", resp, err)
+	}
+	if stats := injector.Stats(); stats.Errors != 1 {
+		t.Errorf("expected injector stats to count profile request, got %+v", stats)
+	}
+	if strings.Join(wrapped, ",") != "/ok" {
+		t.Errorf("expected wrapper to see profile request below fault injector, got %v", wrapped)
+	}
+
+	injector.SetEnabled(false)
+	if resp, err := get("/fault"); err != nil || resp.StatusCode != http.StatusOK {
+		t.Errorf("expected disabled injector to pass profile request, got %v, %v", resp, err)
+	}
+}
@@ -0,0 +1,188 @@
+package http_request_instant
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestResponseHeaderTimeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(200)
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	if err := client.SetTimeouts(Timeouts{ResponseHeader: 50 * time.Millisecond}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err := client.Request(context.Background(), RequestOptions{Method: "GET", URL: ts.URL})
+	if err == nil {
+		t.Fatal("expected response header timeout error, got nil")
+	}
+}
+
+func TestSlowBodyAllowedWithoutTotalTimeout(t *testing.T) {
+	// header dikirim cepat, body lambat
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		w.(http.Flusher).Flush()
+		time.Sleep(150 * time.Millisecond)
+		_, _ = w.Write([]byte("done"))
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	err := client.SetTimeouts(Timeouts{
+		Dial:           time.Second,
+		TLSHandshake:   time.Second,
+		ResponseHeader: 50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, err := client.Request(context.Background(), RequestOptions{Method: "GET", URL: ts.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(resp.Body) != "done" {
+		t.Errorf("expected body=done, got %s", string(resp.Body))
+	}
+}
+
+func TestSetTimeoutsCustomTransport(t *testing.T) {
+	client := NewHttpRequest()
+	client.Client.Transport = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, nil
+	})
+	if err := client.SetTimeouts(Timeouts{Dial: time.Second}); err == nil {
+		t.Fatal("expected error for custom transport, got nil")
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// headerTransport menambah header tanpa method Unwrap, seperti otelhttp.
+type headerTransport struct {
+	next http.RoundTripper
+}
+
+func (t headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("X-Wrapped", "yes")
+	return t.next.RoundTrip(req)
+}
+
+func TestWrapTransportKeepsTransportSetters(t *testing.T) {
+	var wrapped string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wrapped = r.Header.Get("X-Wrapped")
+	}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	client.WrapTransport(func(next http.RoundTripper) http.RoundTripper {
+		return headerTransport{next: next}
+	})
+	if err := client.SetTimeouts(Timeouts{Dial: time.Second}); err != nil {
+		t.Fatalf("expected setters to reach wrapped *http.Transport, got %v", err)
+	}
+	if _, err := client.Request(context.Background(), RequestOptions{Method: "GET", URL: ts.URL}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if wrapped != "yes" {
+		t.Errorf("expected request to pass through wrapper")
+	}
+
+	custom := NewHttpRequestWithTransport(headerTransport{next: http.DefaultTransport})
+	wrapped = ""
+	if _, err := custom.Request(context.Background(), RequestOptions{Method: "GET", URL: ts.URL}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if wrapped != "yes" {
+		t.Errorf("expected injected transport to be used")
+	}
+	if err := custom.SetTimeouts(Timeouts{Dial: time.Second}); err == nil {
+		t.Errorf("expected error configuring opaque injected transport")
+	}
+}
+
+func TestTLSHandshakeTimeout(t *testing.T) {
+	// Server menerima koneksi TCP tetapi tidak pernah membalas handshake
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	client := NewHttpRequest()
+	client.SetTimeouts(Timeouts{TLSHandshake: 100 * time.Millisecond})
+	start := time.Now()
+	_, err = client.Request(context.Background(), RequestOptions{Method: "GET", URL: "https://" + ln.Addr().String()})
+	if err == nil || time.Since(start) > time.Second {
+		t.Errorf("expected TLS handshake timeout, got %v after %s", err, time.Since(start))
+	}
+}
+
+func TestSetTCPKeepAlive(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	err := client.SetTCPKeepAlive(TCPKeepAliveOptions{Idle: 5 * time.Second, Interval: time.Second, Count: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg := client.netDialer().KeepAliveConfig; !cfg.Enable || cfg.Idle != 5*time.Second || cfg.Count != 3 {
+		t.Errorf("unexpected keep-alive config: %+v", cfg)
+	}
+	if _, err := client.Request(context.Background(), RequestOptions{Method: "GET", URL: ts.URL}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client.SetTCPKeepAlive(TCPKeepAliveOptions{Disabled: true})
+	if d := client.netDialer(); d.KeepAlive >= 0 || d.KeepAliveConfig.Enable {
+		t.Errorf("expected keep-alive disabled, got %v %+v", d.KeepAlive, d.KeepAliveConfig)
+	}
+}
+
+func TestSetMultipathTCP(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	if err := client.SetMultipathTCP(true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !client.netDialer().MultipathTCP() {
+		t.Errorf("expected MPTCP enabled on dialer")
+	}
+	// Tanpa dukungan kernel koneksi turun ke TCP biasa
+	if _, err := client.Request(context.Background(), RequestOptions{Method: "GET", URL: ts.URL}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client.SetMultipathTCP(false)
+	if client.netDialer().MultipathTCP() {
+		t.Errorf("expected MPTCP disabled on dialer")
+	}
+}
@@ -0,0 +1,84 @@
+package http_request_instant
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+type unixSocketContextKey struct{}
+
+// SetUnixSocket mengarahkan semua koneksi client ke Unix socket di path,
+// mis. "/var/run/docker.sock". URL request tetap menentukan path, query, dan
+// header Host, mis. http://docker/v1.43/containers/json. path kosong
+// mengembalikan koneksi TCP biasa.
+//
+// Untuk request tertentu saja, pakai URL uds:// (atau unix://) yang berisi
+// path socket diikuti path HTTP, mis. uds:///var/run/app.sock/v1/status.
+func (c *HttpRequest) SetUnixSocket(path string) error {
+	t, err := c.transport()
+	if err != nil {
+		return err
+	}
+	c.unixSocket = path
+	t.DialContext = c.dialContext
+	return nil
+}
+
+// unixSocketPath mengembalikan socket tujuan dari URL uds:// di ctx atau
+// dari SetUnixSocket; kosong berarti koneksi TCP.
+func (c *HttpRequest) unixSocketPath(ctx context.Context) string {
+	if socket, ok := ctx.Value(unixSocketContextKey{}).(string); ok {
+		return socket
+	}
+	return c.unixSocket
+}
+
+// withUnixSocketURL mengubah URL uds:// menjadi URL http:// dan menyimpan
+// path socket di ctx. Host URL hasilnya diturunkan dari path socket supaya
+// koneksi ke socket berbeda tidak tercampur di pool transport.
+func withUnixSocketURL(ctx context.Context, rawURL string) (context.Context, string, error) {
+	if !strings.HasPrefix(rawURL, "uds:") && !strings.HasPrefix(rawURL, "unix:") {
+		return ctx, rawURL, nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("error parse unix socket URL: %w", err)
+	}
+	if u.Host != "" {
+		return nil, "", fmt.Errorf("unix socket URL %q must not have a host, use %s:///path/to.sock/...", rawURL, u.Scheme)
+	}
+
+	socket, path, ok := splitUnixSocketPath(u.Path)
+	if !ok {
+		return nil, "", fmt.Errorf("no unix socket found in %q", u.Path)
+	}
+	sum := sha256.Sum256([]byte(socket))
+	u.Scheme = "http"
+	u.Host = hex.EncodeToString(sum[:8]) + ".sock"
+	u.Path = path
+	u.RawPath = ""
+	return context.WithValue(ctx, unixSocketContextKey{}, socket), u.String(), nil
+}
+
+// splitUnixSocketPath memisahkan path socket (awalan terpendek yang berupa
+// socket di filesystem) dari path HTTP sisanya.
+func splitUnixSocketPath(path string) (socket, rest string, ok bool) {
+	for i := 1; i <= len(path); i++ {
+		if i < len(path) && path[i] != '/' {
+			continue
+		}
+		if info, err := os.Stat(path[:i]); err == nil && info.Mode()&os.ModeSocket != 0 {
+			rest = path[i:]
+			if rest == "" {
+				rest = "/"
+			}
+			return path[:i], rest, true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,65 @@
+package http_request_instant
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+func serveUnixSocket(t *testing.T, name string) string {
+	path := filepath.Join(t.TempDir(), name)
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("unix socket not available: %v", err)
+	}
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(name + " " + r.Host + " " + r.URL.RequestURI()))
+	})}
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+	return path
+}
+
+func TestUnixSocketURL(t *testing.T) {
+	app := serveUnixSocket(t, "app.sock")
+	other := serveUnixSocket(t, "other.sock")
+
+	client := NewHttpRequest()
+	for url, want := range map[string]string{
+		"uds://" + app + "/v1/status?verbose=1": "app.sock localhost /v1/status?verbose=1",
+		"unix://" + other + "/v1/status":        "other.sock localhost /v1/status",
+		"uds://" + app:                          "app.sock localhost /",
+	} {
+		resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: url})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", url, err)
+		}
+		if string(resp.Body) != want {
+			t.Errorf("%s: expected %q, got %q", url, want, resp.Body)
+		}
+	}
+
+	for _, url := range []string{"uds://" + filepath.Dir(app) + "/missing.sock/v1", "uds://host" + app} {
+		if _, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: url}); err == nil {
+			t.Errorf("%s: expected error", url)
+		}
+	}
+}
+
+func TestSetUnixSocket(t *testing.T) {
+	socket := serveUnixSocket(t, "docker.sock")
+
+	client := NewHttpRequest()
+	if err := client.SetUnixSocket(socket); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := client.Request(context.TODO(), RequestOptions{Method: "GET", URL: "http://docker/v1.43/containers/json"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(resp.Body) != "docker.sock docker /v1.43/containers/json" {
+		t.Errorf("unexpected response %q", resp.Body)
+	}
+}
@@ -0,0 +1,402 @@
+package http_request_instant
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+// websocketGUID adalah konstanta handshake dari RFC 6455 bagian 1.3.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// defaultWebSocketReadLimit membatasi ukuran satu pesan yang dibaca.
+const defaultWebSocketReadLimit = 32 << 20
+
+// WebSocketMessageType adalah jenis pesan data WebSocket.
+type WebSocketMessageType int
+
+const (
+	WebSocketText   WebSocketMessageType = 1
+	WebSocketBinary WebSocketMessageType = 2
+)
+
+const (
+	wsOpContinuation = 0x0
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xA
+)
+
+// Close code umum dari RFC 6455 bagian 7.4.1.
+const (
+	WebSocketCloseNormal        = 1000
+	WebSocketCloseGoingAway     = 1001
+	WebSocketCloseProtocolError = 1002
+	WebSocketCloseNoStatus      = 1005
+	WebSocketCloseInvalidData   = 1007
+	WebSocketCloseTooBig        = 1009
+)
+
+// WebSocketCloseError dikembalikan ReadMessage setelah server menutup koneksi.
+type WebSocketCloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *WebSocketCloseError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("websocket closed: %d %s", e.Code, e.Reason)
+	}
+	return fmt.Sprintf("websocket closed: %d", e.Code)
+}
+
+// WebSocketConn adalah koneksi WebSocket hasil DialWebSocket. Satu goroutine
+// boleh membaca dan goroutine lain menulis secara bersamaan; method tulis
+// aman dipanggil dari banyak goroutine.
+type WebSocketConn struct {
+	conn        io.ReadWriteCloser
+	br          *bufio.Reader
+	subprotocol string
+	readLimit   int64
+
+	writeMu sync.Mutex
+	closed  atomic.Bool // Close frame sudah dikirim
+}
+
+// DialWebSocket melakukan handshake WebSocket (RFC 6455) ke options.URL
+// (ws://, wss://, http://, atau https://) lewat http.Client milik client,
+// sehingga proxy, TLS, header, HostConfig, dan kredensial yang sama ikut
+// dipakai, begitu juga hook sebelum request, signer, dan OutboundPolicy.
+// Subprotokol diminta lewat header Sec-WebSocket-Protocol. ctx hanya membatasi
+// handshake. Jika server tidak membalas 101, status 4xx/5xx dikembalikan
+// sebagai *StatusError.
+func (c *HttpRequest) DialWebSocket(ctx context.Context, options RequestOptions) (*WebSocketConn, error) {
+	if atomic.LoadInt32(&c.closed) != 0 {
+		return nil, ErrClientClosed
+	}
+	ctx, options, cancel := applyContextOptions(ctx, options)
+	defer cancel()
+
+	u, err := url.Parse(options.URL)
+	if err != nil {
+		return nil, fmt.Errorf("websocket: %w", err)
+	}
+	switch u.Scheme {
+	case "ws":
+		u.Scheme = "http"
+	case "wss":
+		u.Scheme = "https"
+	case "http", "https":
+	default:
+		return nil, fmt.Errorf("websocket: unsupported scheme %q", u.Scheme)
+	}
+	options.URL = u.String()
+	options.Method = http.MethodGet
+	options.RequestBody = nil
+	if config, ok := c.hostConfig(u.Hostname()); ok {
+		options = config.apply(options)
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("websocket: %w", err)
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+	options.Headers = cloneHeaders(options.Headers)
+	if options.Headers == nil {
+		options.Headers = make(map[string]string)
+	}
+	options.Headers["Connection"] = "Upgrade"
+	options.Headers["Upgrade"] = "websocket"
+	options.Headers["Sec-WebSocket-Version"] = "13"
+	options.Headers["Sec-WebSocket-Key"] = key
+
+	// http.Client.Timeout membungkus body sehingga koneksi hasil upgrade tidak
+	// bisa ditulis; pakai timeout itu lewat ctx untuk handshake saja. Koneksi
+	// tetap hidup walaupun ctx selesai setelah 101.
+	base, err := c.httpClient(options)
+	if err != nil {
+		return nil, err
+	}
+	client := *base
+	if client.Timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, client.Timeout)
+		defer cancelTimeout()
+		client.Timeout = 0
+	}
+
+	req, _, err := c.buildRequest(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.prepareRequest(ctx, req, options, AttemptInfo{Attempt: 1, Start: time.Now()}); err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, wrapTransportError(err)
+	}
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		headers := make(map[string]string)
+		for k, v := range resp.Header {
+			headers[k] = v[0]
+		}
+		if resp.StatusCode >= 400 {
+			return nil, &StatusError{StatusCode: resp.StatusCode, Body: body, Headers: headers}
+		}
+		return nil, fmt.Errorf("websocket: server did not switch protocols (status %d)", resp.StatusCode)
+	}
+	conn, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok {
+		resp.Body.Close()
+		return nil, errors.New("websocket: transport does not support protocol upgrade")
+	}
+	if !strings.EqualFold(resp.Header.Get("Upgrade"), "websocket") ||
+		resp.Header.Get("Sec-WebSocket-Accept") != websocketAccept(key) {
+		conn.Close()
+		return nil, errors.New("websocket: invalid handshake response")
+	}
+
+	return &WebSocketConn{
+		conn:        conn,
+		br:          bufio.NewReader(conn),
+		subprotocol: resp.Header.Get("Sec-WebSocket-Protocol"),
+		readLimit:   defaultWebSocketReadLimit,
+	}, nil
+}
+
+func websocketAccept(key string) string {
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// Subprotocol mengembalikan subprotokol yang dipilih server, jika ada.
+func (ws *WebSocketConn) Subprotocol() string {
+	return ws.subprotocol
+}
+
+// SetReadLimit mengubah ukuran maksimal satu pesan, default 32 MiB. Pesan
+// yang lebih besar menutup koneksi dengan code 1009.
+func (ws *WebSocketConn) SetReadLimit(n int64) {
+	ws.readLimit = n
+}
+
+// ReadMessage membaca satu pesan data utuh. Ping dibalas pong secara
+// otomatis. Setelah server mengirim close frame, ReadMessage membalas close
+// lalu mengembalikan *WebSocketCloseError.
+func (ws *WebSocketConn) ReadMessage() (WebSocketMessageType, []byte, error) {
+	var messageType WebSocketMessageType
+	var message []byte
+	for {
+		fin, opcode, payload, err := ws.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch opcode {
+		case wsOpPing:
+			if err := ws.writeFrame(wsOpPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case wsOpPong:
+			continue
+		case wsOpClose:
+			closeErr := &WebSocketCloseError{Code: WebSocketCloseNoStatus}
+			if len(payload) >= 2 {
+				closeErr.Code = int(binary.BigEndian.Uint16(payload))
+				closeErr.Reason = string(payload[2:])
+			}
+			ws.CloseWithCode(WebSocketCloseNormal, "")
+			return 0, nil, closeErr
+		case wsOpContinuation:
+			if messageType == 0 {
+				return 0, nil, ws.fail(WebSocketCloseProtocolError, "unexpected continuation frame")
+			}
+		case byte(WebSocketText), byte(WebSocketBinary):
+			if messageType != 0 {
+				return 0, nil, ws.fail(WebSocketCloseProtocolError, "expected continuation frame")
+			}
+			messageType = WebSocketMessageType(opcode)
+		default:
+			return 0, nil, ws.fail(WebSocketCloseProtocolError, fmt.Sprintf("unknown opcode %d", opcode))
+		}
+
+		if int64(len(message)+len(payload)) > ws.readLimit {
+			return 0, nil, ws.fail(WebSocketCloseTooBig, "message exceeds read limit")
+		}
+		message = append(message, payload...)
+		if fin {
+			if messageType == WebSocketText && !utf8.Valid(message) {
+				return 0, nil, ws.fail(WebSocketCloseInvalidData, "invalid UTF-8 in text message")
+			}
+			return messageType, message, nil
+		}
+	}
+}
+
+// ReadJSON membaca satu pesan lalu meng-unmarshal-nya ke v.
+func (ws *WebSocketConn) ReadJSON(v any) error {
+	_, message, err := ws.ReadMessage()
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(message, v); err != nil {
+		return &DecodeError{ContentType: "application/json", Err: err}
+	}
+	return nil
+}
+
+// WriteMessage mengirim satu pesan dalam satu frame.
+func (ws *WebSocketConn) WriteMessage(messageType WebSocketMessageType, data []byte) error {
+	if messageType != WebSocketText && messageType != WebSocketBinary {
+		return fmt.Errorf("websocket: invalid message type %d", messageType)
+	}
+	return ws.writeFrame(byte(messageType), data)
+}
+
+// WriteText mengirim pesan teks.
+func (ws *WebSocketConn) WriteText(text string) error {
+	return ws.WriteMessage(WebSocketText, []byte(text))
+}
+
+// WriteJSON meng-marshal v lalu mengirimnya sebagai pesan teks.
+func (ws *WebSocketConn) WriteJSON(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("websocket: %w", err)
+	}
+	return ws.WriteMessage(WebSocketText, data)
+}
+
+// Ping mengirim ping frame; pong dari server diabaikan ReadMessage.
+func (ws *WebSocketConn) Ping(data []byte) error {
+	return ws.writeFrame(wsOpPing, data)
+}
+
+// Close mengirim close frame 1000 lalu menutup koneksi.
+func (ws *WebSocketConn) Close() error {
+	return ws.CloseWithCode(WebSocketCloseNormal, "")
+}
+
+// CloseWithCode mengirim close frame dengan code dan reason lalu menutup
+// koneksi. Pemanggilan berikutnya tidak mengirim apa pun.
+func (ws *WebSocketConn) CloseWithCode(code int, reason string) error {
+	if ws.closed.Swap(true) {
+		return nil
+	}
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+	payload = append(payload, reason...)
+	ws.writeMu.Lock()
+	err := ws.writeFrameLocked(wsOpClose, payload)
+	ws.writeMu.Unlock()
+	if closeErr := ws.conn.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// fail menutup koneksi karena pelanggaran protokol dari server.
+func (ws *WebSocketConn) fail(code int, reason string) error {
+	ws.CloseWithCode(code, reason)
+	return fmt.Errorf("websocket: %s", reason)
+}
+
+func (ws *WebSocketConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(ws.br, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = header[0]&0x80 != 0
+	opcode = header[0] & 0x0F
+	if header[0]&0x70 != 0 {
+		return false, 0, nil, ws.fail(WebSocketCloseProtocolError, "reserved bits set")
+	}
+	if header[1]&0x80 != 0 {
+		return false, 0, nil, ws.fail(WebSocketCloseProtocolError, "server frame is masked")
+	}
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(ws.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(ws.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if opcode >= wsOpClose && (!fin || length > 125) {
+		return false, 0, nil, ws.fail(WebSocketCloseProtocolError, "invalid control frame")
+	}
+	if length > uint64(ws.readLimit) {
+		return false, 0, nil, ws.fail(WebSocketCloseTooBig, "message exceeds read limit")
+	}
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(ws.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	return fin, opcode, payload, nil
+}
+
+func (ws *WebSocketConn) writeFrame(opcode byte, payload []byte) error {
+	if ws.closed.Load() {
+		return net.ErrClosed
+	}
+	ws.writeMu.Lock()
+	defer ws.writeMu.Unlock()
+	return ws.writeFrameLocked(opcode, payload)
+}
+
+// writeFrameLocked menulis satu frame FIN dengan payload di-mask, sesuai
+// kewajiban client di RFC 6455 bagian 5.3.
+func (ws *WebSocketConn) writeFrameLocked(opcode byte, payload []byte) error {
+	frame := make([]byte, 0, len(payload)+14)
+	frame = append(frame, 0x80|opcode)
+	switch n := len(payload); {
+	case n <= 125:
+		frame = append(frame, 0x80|byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, 0x80|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, 0x80|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+
+	var mask [4]byte
+	if _, err := rand.Read(mask[:]); err != nil {
+		return err
+	}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	_, err := ws.conn.Write(frame)
+	return err
+}
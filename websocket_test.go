@@ -0,0 +1,139 @@
+package http_request_instant
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// websocketEchoServer membalas setiap pesan teks, didahului ping. Pesan
+// "close" dibalas close frame 4000.
+func websocketEchoServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("Sec-WebSocket-Version") != "13" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Upgrade", "websocket")
+		w.Header().Set("Connection", "Upgrade")
+		w.Header().Set("Sec-WebSocket-Accept", websocketAccept(r.Header.Get("Sec-WebSocket-Key")))
+		w.Header().Set("Sec-WebSocket-Protocol", "chat.v1")
+		w.WriteHeader(http.StatusSwitchingProtocols)
+		conn, brw, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			t.Errorf("hijack: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		writeFrame := func(header byte, payload []byte) {
+			conn.Write(append([]byte{header, byte(len(payload))}, payload...))
+		}
+		for {
+			opcode, payload, err := readClientFrame(brw.Reader)
+			if err != nil {
+				return
+			}
+			switch {
+			case opcode == wsOpClose:
+				return
+			case opcode == wsOpPong:
+				continue
+			case string(payload) == "close":
+				writeFrame(0x80|wsOpClose, append(binary.BigEndian.AppendUint16(nil, 4000), "bye"...))
+			default:
+				writeFrame(0x80|wsOpPing, []byte("p"))
+				// Balas dalam dua fragmen untuk menguji continuation frame
+				half := len(payload) / 2
+				writeFrame(opcode, payload[:half])
+				writeFrame(0x80|wsOpContinuation, payload[half:])
+			}
+		}
+	}))
+}
+
+func readClientFrame(r *bufio.Reader) (byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	if header[1]&0x80 == 0 {
+		return 0, nil, errors.New("client frame not masked")
+	}
+	length := int(header[1] & 0x7F)
+	if length == 126 {
+		var ext [2]byte
+		io.ReadFull(r, ext[:])
+		length = int(binary.BigEndian.Uint16(ext[:]))
+	}
+	var mask [4]byte
+	io.ReadFull(r, mask[:])
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return header[0] & 0x0F, payload, nil
+}
+
+func TestDialWebSocket(t *testing.T) {
+	ts := websocketEchoServer(t)
+	defer ts.Close()
+
+	client := NewHttpRequest()
+	wsURL := strings.Replace(ts.URL, "http://", "ws://", 1)
+	ws, err := client.DialWebSocket(context.TODO(), RequestOptions{
+		URL:         wsURL + "/realtime",
+		BearerToken: "secret",
+		Headers:     map[string]string{"Sec-WebSocket-Protocol": "chat.v1"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer ws.Close()
+	if ws.Subprotocol() != "chat.v1" {
+		t.Errorf("expected subprotocol chat.v1, got %q", ws.Subprotocol())
+	}
+
+	if err := ws.WriteJSON(map[string]string{"type": "subscribe", "channel": strings.Repeat("x", 200)}); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	var echoed map[string]string
+	if err := ws.ReadJSON(&echoed); err != nil || echoed["type"] != "subscribe" || len(echoed["channel"]) != 200 {
+		t.Fatalf("expected echoed JSON, got %v, %v", echoed, err)
+	}
+
+	ws.WriteText("close")
+	_, _, err = ws.ReadMessage()
+	var closeErr *WebSocketCloseError
+	if !errors.As(err, &closeErr) || closeErr.Code != 4000 || closeErr.Reason != "bye" {
+		t.Errorf("expected close 4000 bye, got %v", err)
+	}
+	if err := ws.WriteText("after close"); err == nil {
+		t.Errorf("expected write after close to fail")
+	}
+}
+
+func TestDialWebSocketHandshakeErrors(t *testing.T) {
+	ts := websocketEchoServer(t)
+	defer ts.Close()
+	client := NewHttpRequest()
+
+	_, err := client.DialWebSocket(context.TODO(), RequestOptions{URL: ts.URL})
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 StatusError, got %v", err)
+	}
+
+	if _, err := client.DialWebSocket(context.TODO(), RequestOptions{URL: "ftp://example.com"}); err == nil {
+		t.Errorf("expected unsupported scheme error")
+	}
+}